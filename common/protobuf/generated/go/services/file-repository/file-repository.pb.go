@@ -24,44 +24,130 @@ var File_services_file_repository_file_repository_proto protoreflect.FileDescrip
 
 const file_services_file_repository_file_repository_proto_rawDesc = "" +
 	"\n" +
-	".services/file-repository/file-repository.proto\x12\x0ffile_repository\x1a$services/file-repository/types.proto2\x9c\x04\n" +
+	".services/file-repository/file-repository.proto\x12\x0ffile_repository\x1a$services/file-repository/types.proto2\x83\x12\n" +
 	"\x15FileRepositoryService\x12X\n" +
 	"\vHealthCheck\x12#.file_repository.HealthCheckRequest\x1a$.file_repository.HealthCheckResponse\x12T\n" +
-	"\rGetFileByPath\x12%.file_repository.GetFileByPathRequest\x1a\x1a.file_repository.FileChunk0\x01\x12G\n" +
+	"\rGetFileByPath\x12%.file_repository.GetFileByPathRequest\x1a\x1a.file_repository.FileChunk0\x01\x12^\n" +
+	"\rListDirectory\x12%.file_repository.ListDirectoryRequest\x1a&.file_repository.ListDirectoryResponse\x12f\n" +
+	"\x11ListUploadedParts\x12%.file_repository.UploadSessionRequest\x1a*.file_repository.ListUploadedPartsResponse\x12O\n" +
+	"\bStatFile\x12 .file_repository.StatFileRequest\x1a!.file_repository.StatFileResponse\x12_\n" +
+	"\x12CreatePresignedURL\x12*.file_repository.CreatePresignedURLRequest\x1a\x1d.file_repository.PresignedURL\x12G\n" +
 	"\x05Mkdir\x12\x1d.file_repository.MkdirRequest\x1a\x1f.file_repository.StatusResponse\x12V\n" +
 	"\n" +
 	"UploadFile\x12#.file_repository.FileContentRequest\x1a\x1f.file_repository.StatusResponse(\x010\x01\x12]\n" +
-	"\x11UpdateFileContent\x12#.file_repository.FileContentRequest\x1a\x1f.file_repository.StatusResponse(\x010\x01\x12S\n" +
-	"\vDeleteFiles\x12#.file_repository.DeleteFilesRequest\x1a\x1f.file_repository.StatusResponseBPZNgithub.com/abaxoth0/Vega/common/protobuf/generated/go/services/file-repositoryb\x06proto3"
+	"\x11UpdateFileContent\x12#.file_repository.FileContentRequest\x1a\x1f.file_repository.StatusResponse(\x010\x01\x12X\n" +
+	"\vDeleteFiles\x12#.file_repository.DeleteFilesRequest\x1a$.file_repository.DeleteFilesResponse\x12Z\n" +
+	"\tCopyFiles\x12%.file_repository.RelocateFilesRequest\x1a&.file_repository.RelocateFilesResponse\x12Z\n" +
+	"\tMoveFiles\x12%.file_repository.RelocateFilesRequest\x1a&.file_repository.RelocateFilesResponse\x12U\n" +
+	"\fCreateBucket\x12$.file_repository.CreateBucketRequest\x1a\x1f.file_repository.StatusResponse\x12U\n" +
+	"\fDeleteBucket\x12$.file_repository.DeleteBucketRequest\x1a\x1f.file_repository.StatusResponse\x12X\n" +
+	"\vListBuckets\x12#.file_repository.ListBucketsRequest\x1a$.file_repository.ListBucketsResponse\x12S\n" +
+	"\rGetBucketInfo\x12%.file_repository.GetBucketInfoRequest\x1a\x1b.file_repository.BucketInfo\x12c\n" +
+	"\x13SetBucketVersioning\x12+.file_repository.SetBucketVersioningRequest\x1a\x1f.file_repository.StatusResponse\x12S\n" +
+	"\rGetQuotaUsage\x12%.file_repository.GetQuotaUsageRequest\x1a\x1b.file_repository.QuotaUsage\x12g\n" +
+	"\x10ListFileVersions\x12(.file_repository.ListFileVersionsRequest\x1a).file_repository.ListFileVersionsResponse\x12f\n" +
+	"\x12RestoreFileVersion\x12#.file_repository.FileVersionRequest\x1a+.file_repository.RestoreFileVersionResponse\x12Y\n" +
+	"\x11DeleteFileVersion\x12#.file_repository.FileVersionRequest\x1a\x1f.file_repository.StatusResponse\x12a\n" +
+	"\x0eInitiateUpload\x12&.file_repository.InitiateUploadRequest\x1a'.file_repository.InitiateUploadResponse\x12W\n" +
+	"\n" +
+	"UploadPart\x12\".file_repository.UploadPartRequest\x1a#.file_repository.UploadPartResponse(\x01\x12X\n" +
+	"\x0eCompleteUpload\x12%.file_repository.UploadSessionRequest\x1a\x1f.file_repository.StatusResponse\x12U\n" +
+	"\vAbortUpload\x12%.file_repository.UploadSessionRequest\x1a\x1f.file_repository.StatusResponseBPZNgithub.com/abaxoth0/Vega/common/protobuf/generated/go/services/file-repositoryb\x06proto3"
 
 var file_services_file_repository_file_repository_proto_goTypes = []any{
-	(*HealthCheckRequest)(nil),   // 0: file_repository.HealthCheckRequest
-	(*GetFileByPathRequest)(nil), // 1: file_repository.GetFileByPathRequest
-	(*MkdirRequest)(nil),         // 2: file_repository.MkdirRequest
-	(*FileContentRequest)(nil),   // 3: file_repository.FileContentRequest
-	(*DeleteFilesRequest)(nil),   // 4: file_repository.DeleteFilesRequest
-	(*HealthCheckResponse)(nil),  // 5: file_repository.HealthCheckResponse
-	(*FileChunk)(nil),            // 6: file_repository.FileChunk
-	(*StatusResponse)(nil),       // 7: file_repository.StatusResponse
+	(*HealthCheckRequest)(nil),         // 0: file_repository.HealthCheckRequest
+	(*GetFileByPathRequest)(nil),       // 1: file_repository.GetFileByPathRequest
+	(*ListDirectoryRequest)(nil),       // 2: file_repository.ListDirectoryRequest
+	(*UploadSessionRequest)(nil),       // 3: file_repository.UploadSessionRequest
+	(*StatFileRequest)(nil),            // 4: file_repository.StatFileRequest
+	(*CreatePresignedURLRequest)(nil),  // 5: file_repository.CreatePresignedURLRequest
+	(*MkdirRequest)(nil),               // 6: file_repository.MkdirRequest
+	(*FileContentRequest)(nil),         // 7: file_repository.FileContentRequest
+	(*DeleteFilesRequest)(nil),         // 8: file_repository.DeleteFilesRequest
+	(*RelocateFilesRequest)(nil),       // 9: file_repository.RelocateFilesRequest
+	(*CreateBucketRequest)(nil),        // 10: file_repository.CreateBucketRequest
+	(*DeleteBucketRequest)(nil),        // 11: file_repository.DeleteBucketRequest
+	(*ListBucketsRequest)(nil),         // 12: file_repository.ListBucketsRequest
+	(*GetBucketInfoRequest)(nil),       // 13: file_repository.GetBucketInfoRequest
+	(*SetBucketVersioningRequest)(nil), // 14: file_repository.SetBucketVersioningRequest
+	(*GetQuotaUsageRequest)(nil),       // 15: file_repository.GetQuotaUsageRequest
+	(*ListFileVersionsRequest)(nil),    // 16: file_repository.ListFileVersionsRequest
+	(*FileVersionRequest)(nil),         // 17: file_repository.FileVersionRequest
+	(*InitiateUploadRequest)(nil),      // 18: file_repository.InitiateUploadRequest
+	(*UploadPartRequest)(nil),          // 19: file_repository.UploadPartRequest
+	(*HealthCheckResponse)(nil),        // 20: file_repository.HealthCheckResponse
+	(*FileChunk)(nil),                  // 21: file_repository.FileChunk
+	(*ListDirectoryResponse)(nil),      // 22: file_repository.ListDirectoryResponse
+	(*ListUploadedPartsResponse)(nil),  // 23: file_repository.ListUploadedPartsResponse
+	(*StatFileResponse)(nil),           // 24: file_repository.StatFileResponse
+	(*PresignedURL)(nil),               // 25: file_repository.PresignedURL
+	(*StatusResponse)(nil),             // 26: file_repository.StatusResponse
+	(*DeleteFilesResponse)(nil),        // 27: file_repository.DeleteFilesResponse
+	(*RelocateFilesResponse)(nil),      // 28: file_repository.RelocateFilesResponse
+	(*ListBucketsResponse)(nil),        // 29: file_repository.ListBucketsResponse
+	(*BucketInfo)(nil),                 // 30: file_repository.BucketInfo
+	(*QuotaUsage)(nil),                 // 31: file_repository.QuotaUsage
+	(*ListFileVersionsResponse)(nil),   // 32: file_repository.ListFileVersionsResponse
+	(*RestoreFileVersionResponse)(nil), // 33: file_repository.RestoreFileVersionResponse
+	(*InitiateUploadResponse)(nil),     // 34: file_repository.InitiateUploadResponse
+	(*UploadPartResponse)(nil),         // 35: file_repository.UploadPartResponse
 }
 var file_services_file_repository_file_repository_proto_depIdxs = []int32{
-	0, // 0: file_repository.FileRepositoryService.HealthCheck:input_type -> file_repository.HealthCheckRequest
-	1, // 1: file_repository.FileRepositoryService.GetFileByPath:input_type -> file_repository.GetFileByPathRequest
-	2, // 2: file_repository.FileRepositoryService.Mkdir:input_type -> file_repository.MkdirRequest
-	3, // 3: file_repository.FileRepositoryService.UploadFile:input_type -> file_repository.FileContentRequest
-	3, // 4: file_repository.FileRepositoryService.UpdateFileContent:input_type -> file_repository.FileContentRequest
-	4, // 5: file_repository.FileRepositoryService.DeleteFiles:input_type -> file_repository.DeleteFilesRequest
-	5, // 6: file_repository.FileRepositoryService.HealthCheck:output_type -> file_repository.HealthCheckResponse
-	6, // 7: file_repository.FileRepositoryService.GetFileByPath:output_type -> file_repository.FileChunk
-	7, // 8: file_repository.FileRepositoryService.Mkdir:output_type -> file_repository.StatusResponse
-	7, // 9: file_repository.FileRepositoryService.UploadFile:output_type -> file_repository.StatusResponse
-	7, // 10: file_repository.FileRepositoryService.UpdateFileContent:output_type -> file_repository.StatusResponse
-	7, // 11: file_repository.FileRepositoryService.DeleteFiles:output_type -> file_repository.StatusResponse
-	6, // [6:12] is the sub-list for method output_type
-	0, // [0:6] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	0,  // 0: file_repository.FileRepositoryService.HealthCheck:input_type -> file_repository.HealthCheckRequest
+	1,  // 1: file_repository.FileRepositoryService.GetFileByPath:input_type -> file_repository.GetFileByPathRequest
+	2,  // 2: file_repository.FileRepositoryService.ListDirectory:input_type -> file_repository.ListDirectoryRequest
+	3,  // 3: file_repository.FileRepositoryService.ListUploadedParts:input_type -> file_repository.UploadSessionRequest
+	4,  // 4: file_repository.FileRepositoryService.StatFile:input_type -> file_repository.StatFileRequest
+	5,  // 5: file_repository.FileRepositoryService.CreatePresignedURL:input_type -> file_repository.CreatePresignedURLRequest
+	6,  // 6: file_repository.FileRepositoryService.Mkdir:input_type -> file_repository.MkdirRequest
+	7,  // 7: file_repository.FileRepositoryService.UploadFile:input_type -> file_repository.FileContentRequest
+	7,  // 8: file_repository.FileRepositoryService.UpdateFileContent:input_type -> file_repository.FileContentRequest
+	8,  // 9: file_repository.FileRepositoryService.DeleteFiles:input_type -> file_repository.DeleteFilesRequest
+	9,  // 10: file_repository.FileRepositoryService.CopyFiles:input_type -> file_repository.RelocateFilesRequest
+	9,  // 11: file_repository.FileRepositoryService.MoveFiles:input_type -> file_repository.RelocateFilesRequest
+	10, // 12: file_repository.FileRepositoryService.CreateBucket:input_type -> file_repository.CreateBucketRequest
+	11, // 13: file_repository.FileRepositoryService.DeleteBucket:input_type -> file_repository.DeleteBucketRequest
+	12, // 14: file_repository.FileRepositoryService.ListBuckets:input_type -> file_repository.ListBucketsRequest
+	13, // 15: file_repository.FileRepositoryService.GetBucketInfo:input_type -> file_repository.GetBucketInfoRequest
+	14, // 16: file_repository.FileRepositoryService.SetBucketVersioning:input_type -> file_repository.SetBucketVersioningRequest
+	15, // 17: file_repository.FileRepositoryService.GetQuotaUsage:input_type -> file_repository.GetQuotaUsageRequest
+	16, // 18: file_repository.FileRepositoryService.ListFileVersions:input_type -> file_repository.ListFileVersionsRequest
+	17, // 19: file_repository.FileRepositoryService.RestoreFileVersion:input_type -> file_repository.FileVersionRequest
+	17, // 20: file_repository.FileRepositoryService.DeleteFileVersion:input_type -> file_repository.FileVersionRequest
+	18, // 21: file_repository.FileRepositoryService.InitiateUpload:input_type -> file_repository.InitiateUploadRequest
+	19, // 22: file_repository.FileRepositoryService.UploadPart:input_type -> file_repository.UploadPartRequest
+	3,  // 23: file_repository.FileRepositoryService.CompleteUpload:input_type -> file_repository.UploadSessionRequest
+	3,  // 24: file_repository.FileRepositoryService.AbortUpload:input_type -> file_repository.UploadSessionRequest
+	20, // 25: file_repository.FileRepositoryService.HealthCheck:output_type -> file_repository.HealthCheckResponse
+	21, // 26: file_repository.FileRepositoryService.GetFileByPath:output_type -> file_repository.FileChunk
+	22, // 27: file_repository.FileRepositoryService.ListDirectory:output_type -> file_repository.ListDirectoryResponse
+	23, // 28: file_repository.FileRepositoryService.ListUploadedParts:output_type -> file_repository.ListUploadedPartsResponse
+	24, // 29: file_repository.FileRepositoryService.StatFile:output_type -> file_repository.StatFileResponse
+	25, // 30: file_repository.FileRepositoryService.CreatePresignedURL:output_type -> file_repository.PresignedURL
+	26, // 31: file_repository.FileRepositoryService.Mkdir:output_type -> file_repository.StatusResponse
+	26, // 32: file_repository.FileRepositoryService.UploadFile:output_type -> file_repository.StatusResponse
+	26, // 33: file_repository.FileRepositoryService.UpdateFileContent:output_type -> file_repository.StatusResponse
+	27, // 34: file_repository.FileRepositoryService.DeleteFiles:output_type -> file_repository.DeleteFilesResponse
+	28, // 35: file_repository.FileRepositoryService.CopyFiles:output_type -> file_repository.RelocateFilesResponse
+	28, // 36: file_repository.FileRepositoryService.MoveFiles:output_type -> file_repository.RelocateFilesResponse
+	26, // 37: file_repository.FileRepositoryService.CreateBucket:output_type -> file_repository.StatusResponse
+	26, // 38: file_repository.FileRepositoryService.DeleteBucket:output_type -> file_repository.StatusResponse
+	29, // 39: file_repository.FileRepositoryService.ListBuckets:output_type -> file_repository.ListBucketsResponse
+	30, // 40: file_repository.FileRepositoryService.GetBucketInfo:output_type -> file_repository.BucketInfo
+	26, // 41: file_repository.FileRepositoryService.SetBucketVersioning:output_type -> file_repository.StatusResponse
+	31, // 42: file_repository.FileRepositoryService.GetQuotaUsage:output_type -> file_repository.QuotaUsage
+	32, // 43: file_repository.FileRepositoryService.ListFileVersions:output_type -> file_repository.ListFileVersionsResponse
+	33, // 44: file_repository.FileRepositoryService.RestoreFileVersion:output_type -> file_repository.RestoreFileVersionResponse
+	26, // 45: file_repository.FileRepositoryService.DeleteFileVersion:output_type -> file_repository.StatusResponse
+	34, // 46: file_repository.FileRepositoryService.InitiateUpload:output_type -> file_repository.InitiateUploadResponse
+	35, // 47: file_repository.FileRepositoryService.UploadPart:output_type -> file_repository.UploadPartResponse
+	26, // 48: file_repository.FileRepositoryService.CompleteUpload:output_type -> file_repository.StatusResponse
+	26, // 49: file_repository.FileRepositoryService.AbortUpload:output_type -> file_repository.StatusResponse
+	25, // [25:50] is the sub-list for method output_type
+	0,  // [0:25] is the sub-list for method input_type
+	0,  // [0:0] is the sub-list for extension type_name
+	0,  // [0:0] is the sub-list for extension extendee
+	0,  // [0:0] is the sub-list for field type_name
 }
 
 func init() { file_services_file_repository_file_repository_proto_init() }
@@ -19,12 +19,31 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	FileRepositoryService_HealthCheck_FullMethodName       = "/file_repository.FileRepositoryService/HealthCheck"
-	FileRepositoryService_GetFileByPath_FullMethodName     = "/file_repository.FileRepositoryService/GetFileByPath"
-	FileRepositoryService_Mkdir_FullMethodName             = "/file_repository.FileRepositoryService/Mkdir"
-	FileRepositoryService_UploadFile_FullMethodName        = "/file_repository.FileRepositoryService/UploadFile"
-	FileRepositoryService_UpdateFileContent_FullMethodName = "/file_repository.FileRepositoryService/UpdateFileContent"
-	FileRepositoryService_DeleteFiles_FullMethodName       = "/file_repository.FileRepositoryService/DeleteFiles"
+	FileRepositoryService_HealthCheck_FullMethodName         = "/file_repository.FileRepositoryService/HealthCheck"
+	FileRepositoryService_GetFileByPath_FullMethodName       = "/file_repository.FileRepositoryService/GetFileByPath"
+	FileRepositoryService_ListDirectory_FullMethodName       = "/file_repository.FileRepositoryService/ListDirectory"
+	FileRepositoryService_ListUploadedParts_FullMethodName   = "/file_repository.FileRepositoryService/ListUploadedParts"
+	FileRepositoryService_StatFile_FullMethodName            = "/file_repository.FileRepositoryService/StatFile"
+	FileRepositoryService_CreatePresignedURL_FullMethodName  = "/file_repository.FileRepositoryService/CreatePresignedURL"
+	FileRepositoryService_Mkdir_FullMethodName               = "/file_repository.FileRepositoryService/Mkdir"
+	FileRepositoryService_UploadFile_FullMethodName          = "/file_repository.FileRepositoryService/UploadFile"
+	FileRepositoryService_UpdateFileContent_FullMethodName   = "/file_repository.FileRepositoryService/UpdateFileContent"
+	FileRepositoryService_DeleteFiles_FullMethodName         = "/file_repository.FileRepositoryService/DeleteFiles"
+	FileRepositoryService_CopyFiles_FullMethodName           = "/file_repository.FileRepositoryService/CopyFiles"
+	FileRepositoryService_MoveFiles_FullMethodName           = "/file_repository.FileRepositoryService/MoveFiles"
+	FileRepositoryService_CreateBucket_FullMethodName        = "/file_repository.FileRepositoryService/CreateBucket"
+	FileRepositoryService_DeleteBucket_FullMethodName        = "/file_repository.FileRepositoryService/DeleteBucket"
+	FileRepositoryService_ListBuckets_FullMethodName         = "/file_repository.FileRepositoryService/ListBuckets"
+	FileRepositoryService_GetBucketInfo_FullMethodName       = "/file_repository.FileRepositoryService/GetBucketInfo"
+	FileRepositoryService_SetBucketVersioning_FullMethodName = "/file_repository.FileRepositoryService/SetBucketVersioning"
+	FileRepositoryService_GetQuotaUsage_FullMethodName       = "/file_repository.FileRepositoryService/GetQuotaUsage"
+	FileRepositoryService_ListFileVersions_FullMethodName    = "/file_repository.FileRepositoryService/ListFileVersions"
+	FileRepositoryService_RestoreFileVersion_FullMethodName  = "/file_repository.FileRepositoryService/RestoreFileVersion"
+	FileRepositoryService_DeleteFileVersion_FullMethodName   = "/file_repository.FileRepositoryService/DeleteFileVersion"
+	FileRepositoryService_InitiateUpload_FullMethodName      = "/file_repository.FileRepositoryService/InitiateUpload"
+	FileRepositoryService_UploadPart_FullMethodName          = "/file_repository.FileRepositoryService/UploadPart"
+	FileRepositoryService_CompleteUpload_FullMethodName      = "/file_repository.FileRepositoryService/CompleteUpload"
+	FileRepositoryService_AbortUpload_FullMethodName         = "/file_repository.FileRepositoryService/AbortUpload"
 )
 
 // FileRepositoryServiceClient is the client API for FileRepositoryService service.
@@ -34,11 +53,38 @@ type FileRepositoryServiceClient interface {
 	HealthCheck(ctx context.Context, in *HealthCheckRequest, opts ...grpc.CallOption) (*HealthCheckResponse, error)
 	// Queries
 	GetFileByPath(ctx context.Context, in *GetFileByPathRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[FileChunk], error)
+	// Missing directory is listed as empty one, since in object storages directories are just prefixes of the keys
+	ListDirectory(ctx context.Context, in *ListDirectoryRequest, opts ...grpc.CallOption) (*ListDirectoryResponse, error)
+	ListUploadedParts(ctx context.Context, in *UploadSessionRequest, opts ...grpc.CallOption) (*ListUploadedPartsResponse, error)
+	// Returns info about files without their content, missing files aren't treated as errors
+	StatFile(ctx context.Context, in *StatFileRequest, opts ...grpc.CallOption) (*StatFileResponse, error)
+	// Returns time-limited URL which allows to download or upload the file directly, without this RPC
+	CreatePresignedURL(ctx context.Context, in *CreatePresignedURLRequest, opts ...grpc.CallOption) (*PresignedURL, error)
 	// Commands
 	Mkdir(ctx context.Context, in *MkdirRequest, opts ...grpc.CallOption) (*StatusResponse, error)
 	UploadFile(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[FileContentRequest, StatusResponse], error)
 	UpdateFileContent(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[FileContentRequest, StatusResponse], error)
-	DeleteFiles(ctx context.Context, in *DeleteFilesRequest, opts ...grpc.CallOption) (*StatusResponse, error)
+	DeleteFiles(ctx context.Context, in *DeleteFilesRequest, opts ...grpc.CallOption) (*DeleteFilesResponse, error)
+	CopyFiles(ctx context.Context, in *RelocateFilesRequest, opts ...grpc.CallOption) (*RelocateFilesResponse, error)
+	// Also used for renaming
+	MoveFiles(ctx context.Context, in *RelocateFilesRequest, opts ...grpc.CallOption) (*RelocateFilesResponse, error)
+	// Buckets
+	CreateBucket(ctx context.Context, in *CreateBucketRequest, opts ...grpc.CallOption) (*StatusResponse, error)
+	DeleteBucket(ctx context.Context, in *DeleteBucketRequest, opts ...grpc.CallOption) (*StatusResponse, error)
+	ListBuckets(ctx context.Context, in *ListBucketsRequest, opts ...grpc.CallOption) (*ListBucketsResponse, error)
+	GetBucketInfo(ctx context.Context, in *GetBucketInfoRequest, opts ...grpc.CallOption) (*BucketInfo, error)
+	SetBucketVersioning(ctx context.Context, in *SetBucketVersioningRequest, opts ...grpc.CallOption) (*StatusResponse, error)
+	// Usage of the bucket or of all buckets of the owner against its quota, fails if quotas aren't enabled
+	GetQuotaUsage(ctx context.Context, in *GetQuotaUsageRequest, opts ...grpc.CallOption) (*QuotaUsage, error)
+	// Versions (download specific version via GetFileByPath)
+	ListFileVersions(ctx context.Context, in *ListFileVersionsRequest, opts ...grpc.CallOption) (*ListFileVersionsResponse, error)
+	RestoreFileVersion(ctx context.Context, in *FileVersionRequest, opts ...grpc.CallOption) (*RestoreFileVersionResponse, error)
+	DeleteFileVersion(ctx context.Context, in *FileVersionRequest, opts ...grpc.CallOption) (*StatusResponse, error)
+	// Resumable uploads
+	InitiateUpload(ctx context.Context, in *InitiateUploadRequest, opts ...grpc.CallOption) (*InitiateUploadResponse, error)
+	UploadPart(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[UploadPartRequest, UploadPartResponse], error)
+	CompleteUpload(ctx context.Context, in *UploadSessionRequest, opts ...grpc.CallOption) (*StatusResponse, error)
+	AbortUpload(ctx context.Context, in *UploadSessionRequest, opts ...grpc.CallOption) (*StatusResponse, error)
 }
 
 type fileRepositoryServiceClient struct {
@@ -78,6 +124,46 @@ func (c *fileRepositoryServiceClient) GetFileByPath(ctx context.Context, in *Get
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type FileRepositoryService_GetFileByPathClient = grpc.ServerStreamingClient[FileChunk]
 
+func (c *fileRepositoryServiceClient) ListDirectory(ctx context.Context, in *ListDirectoryRequest, opts ...grpc.CallOption) (*ListDirectoryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListDirectoryResponse)
+	err := c.cc.Invoke(ctx, FileRepositoryService_ListDirectory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileRepositoryServiceClient) ListUploadedParts(ctx context.Context, in *UploadSessionRequest, opts ...grpc.CallOption) (*ListUploadedPartsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListUploadedPartsResponse)
+	err := c.cc.Invoke(ctx, FileRepositoryService_ListUploadedParts_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileRepositoryServiceClient) StatFile(ctx context.Context, in *StatFileRequest, opts ...grpc.CallOption) (*StatFileResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StatFileResponse)
+	err := c.cc.Invoke(ctx, FileRepositoryService_StatFile_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileRepositoryServiceClient) CreatePresignedURL(ctx context.Context, in *CreatePresignedURLRequest, opts ...grpc.CallOption) (*PresignedURL, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PresignedURL)
+	err := c.cc.Invoke(ctx, FileRepositoryService_CreatePresignedURL_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *fileRepositoryServiceClient) Mkdir(ctx context.Context, in *MkdirRequest, opts ...grpc.CallOption) (*StatusResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(StatusResponse)
@@ -114,9 +200,9 @@ func (c *fileRepositoryServiceClient) UpdateFileContent(ctx context.Context, opt
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type FileRepositoryService_UpdateFileContentClient = grpc.BidiStreamingClient[FileContentRequest, StatusResponse]
 
-func (c *fileRepositoryServiceClient) DeleteFiles(ctx context.Context, in *DeleteFilesRequest, opts ...grpc.CallOption) (*StatusResponse, error) {
+func (c *fileRepositoryServiceClient) DeleteFiles(ctx context.Context, in *DeleteFilesRequest, opts ...grpc.CallOption) (*DeleteFilesResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	out := new(StatusResponse)
+	out := new(DeleteFilesResponse)
 	err := c.cc.Invoke(ctx, FileRepositoryService_DeleteFiles_FullMethodName, in, out, cOpts...)
 	if err != nil {
 		return nil, err
@@ -124,6 +210,159 @@ func (c *fileRepositoryServiceClient) DeleteFiles(ctx context.Context, in *Delet
 	return out, nil
 }
 
+func (c *fileRepositoryServiceClient) CopyFiles(ctx context.Context, in *RelocateFilesRequest, opts ...grpc.CallOption) (*RelocateFilesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RelocateFilesResponse)
+	err := c.cc.Invoke(ctx, FileRepositoryService_CopyFiles_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileRepositoryServiceClient) MoveFiles(ctx context.Context, in *RelocateFilesRequest, opts ...grpc.CallOption) (*RelocateFilesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RelocateFilesResponse)
+	err := c.cc.Invoke(ctx, FileRepositoryService_MoveFiles_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileRepositoryServiceClient) CreateBucket(ctx context.Context, in *CreateBucketRequest, opts ...grpc.CallOption) (*StatusResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StatusResponse)
+	err := c.cc.Invoke(ctx, FileRepositoryService_CreateBucket_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileRepositoryServiceClient) DeleteBucket(ctx context.Context, in *DeleteBucketRequest, opts ...grpc.CallOption) (*StatusResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StatusResponse)
+	err := c.cc.Invoke(ctx, FileRepositoryService_DeleteBucket_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileRepositoryServiceClient) ListBuckets(ctx context.Context, in *ListBucketsRequest, opts ...grpc.CallOption) (*ListBucketsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListBucketsResponse)
+	err := c.cc.Invoke(ctx, FileRepositoryService_ListBuckets_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileRepositoryServiceClient) GetBucketInfo(ctx context.Context, in *GetBucketInfoRequest, opts ...grpc.CallOption) (*BucketInfo, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BucketInfo)
+	err := c.cc.Invoke(ctx, FileRepositoryService_GetBucketInfo_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileRepositoryServiceClient) SetBucketVersioning(ctx context.Context, in *SetBucketVersioningRequest, opts ...grpc.CallOption) (*StatusResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StatusResponse)
+	err := c.cc.Invoke(ctx, FileRepositoryService_SetBucketVersioning_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileRepositoryServiceClient) GetQuotaUsage(ctx context.Context, in *GetQuotaUsageRequest, opts ...grpc.CallOption) (*QuotaUsage, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(QuotaUsage)
+	err := c.cc.Invoke(ctx, FileRepositoryService_GetQuotaUsage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileRepositoryServiceClient) ListFileVersions(ctx context.Context, in *ListFileVersionsRequest, opts ...grpc.CallOption) (*ListFileVersionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListFileVersionsResponse)
+	err := c.cc.Invoke(ctx, FileRepositoryService_ListFileVersions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileRepositoryServiceClient) RestoreFileVersion(ctx context.Context, in *FileVersionRequest, opts ...grpc.CallOption) (*RestoreFileVersionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RestoreFileVersionResponse)
+	err := c.cc.Invoke(ctx, FileRepositoryService_RestoreFileVersion_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileRepositoryServiceClient) DeleteFileVersion(ctx context.Context, in *FileVersionRequest, opts ...grpc.CallOption) (*StatusResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StatusResponse)
+	err := c.cc.Invoke(ctx, FileRepositoryService_DeleteFileVersion_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileRepositoryServiceClient) InitiateUpload(ctx context.Context, in *InitiateUploadRequest, opts ...grpc.CallOption) (*InitiateUploadResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(InitiateUploadResponse)
+	err := c.cc.Invoke(ctx, FileRepositoryService_InitiateUpload_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileRepositoryServiceClient) UploadPart(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[UploadPartRequest, UploadPartResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &FileRepositoryService_ServiceDesc.Streams[3], FileRepositoryService_UploadPart_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[UploadPartRequest, UploadPartResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type FileRepositoryService_UploadPartClient = grpc.ClientStreamingClient[UploadPartRequest, UploadPartResponse]
+
+func (c *fileRepositoryServiceClient) CompleteUpload(ctx context.Context, in *UploadSessionRequest, opts ...grpc.CallOption) (*StatusResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StatusResponse)
+	err := c.cc.Invoke(ctx, FileRepositoryService_CompleteUpload_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileRepositoryServiceClient) AbortUpload(ctx context.Context, in *UploadSessionRequest, opts ...grpc.CallOption) (*StatusResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StatusResponse)
+	err := c.cc.Invoke(ctx, FileRepositoryService_AbortUpload_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // FileRepositoryServiceServer is the server API for FileRepositoryService service.
 // All implementations must embed UnimplementedFileRepositoryServiceServer
 // for forward compatibility.
@@ -131,11 +370,38 @@ type FileRepositoryServiceServer interface {
 	HealthCheck(context.Context, *HealthCheckRequest) (*HealthCheckResponse, error)
 	// Queries
 	GetFileByPath(*GetFileByPathRequest, grpc.ServerStreamingServer[FileChunk]) error
+	// Missing directory is listed as empty one, since in object storages directories are just prefixes of the keys
+	ListDirectory(context.Context, *ListDirectoryRequest) (*ListDirectoryResponse, error)
+	ListUploadedParts(context.Context, *UploadSessionRequest) (*ListUploadedPartsResponse, error)
+	// Returns info about files without their content, missing files aren't treated as errors
+	StatFile(context.Context, *StatFileRequest) (*StatFileResponse, error)
+	// Returns time-limited URL which allows to download or upload the file directly, without this RPC
+	CreatePresignedURL(context.Context, *CreatePresignedURLRequest) (*PresignedURL, error)
 	// Commands
 	Mkdir(context.Context, *MkdirRequest) (*StatusResponse, error)
 	UploadFile(grpc.BidiStreamingServer[FileContentRequest, StatusResponse]) error
 	UpdateFileContent(grpc.BidiStreamingServer[FileContentRequest, StatusResponse]) error
-	DeleteFiles(context.Context, *DeleteFilesRequest) (*StatusResponse, error)
+	DeleteFiles(context.Context, *DeleteFilesRequest) (*DeleteFilesResponse, error)
+	CopyFiles(context.Context, *RelocateFilesRequest) (*RelocateFilesResponse, error)
+	// Also used for renaming
+	MoveFiles(context.Context, *RelocateFilesRequest) (*RelocateFilesResponse, error)
+	// Buckets
+	CreateBucket(context.Context, *CreateBucketRequest) (*StatusResponse, error)
+	DeleteBucket(context.Context, *DeleteBucketRequest) (*StatusResponse, error)
+	ListBuckets(context.Context, *ListBucketsRequest) (*ListBucketsResponse, error)
+	GetBucketInfo(context.Context, *GetBucketInfoRequest) (*BucketInfo, error)
+	SetBucketVersioning(context.Context, *SetBucketVersioningRequest) (*StatusResponse, error)
+	// Usage of the bucket or of all buckets of the owner against its quota, fails if quotas aren't enabled
+	GetQuotaUsage(context.Context, *GetQuotaUsageRequest) (*QuotaUsage, error)
+	// Versions (download specific version via GetFileByPath)
+	ListFileVersions(context.Context, *ListFileVersionsRequest) (*ListFileVersionsResponse, error)
+	RestoreFileVersion(context.Context, *FileVersionRequest) (*RestoreFileVersionResponse, error)
+	DeleteFileVersion(context.Context, *FileVersionRequest) (*StatusResponse, error)
+	// Resumable uploads
+	InitiateUpload(context.Context, *InitiateUploadRequest) (*InitiateUploadResponse, error)
+	UploadPart(grpc.ClientStreamingServer[UploadPartRequest, UploadPartResponse]) error
+	CompleteUpload(context.Context, *UploadSessionRequest) (*StatusResponse, error)
+	AbortUpload(context.Context, *UploadSessionRequest) (*StatusResponse, error)
 	mustEmbedUnimplementedFileRepositoryServiceServer()
 }
 
@@ -152,6 +418,18 @@ func (UnimplementedFileRepositoryServiceServer) HealthCheck(context.Context, *He
 func (UnimplementedFileRepositoryServiceServer) GetFileByPath(*GetFileByPathRequest, grpc.ServerStreamingServer[FileChunk]) error {
 	return status.Errorf(codes.Unimplemented, "method GetFileByPath not implemented")
 }
+func (UnimplementedFileRepositoryServiceServer) ListDirectory(context.Context, *ListDirectoryRequest) (*ListDirectoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListDirectory not implemented")
+}
+func (UnimplementedFileRepositoryServiceServer) ListUploadedParts(context.Context, *UploadSessionRequest) (*ListUploadedPartsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListUploadedParts not implemented")
+}
+func (UnimplementedFileRepositoryServiceServer) StatFile(context.Context, *StatFileRequest) (*StatFileResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StatFile not implemented")
+}
+func (UnimplementedFileRepositoryServiceServer) CreatePresignedURL(context.Context, *CreatePresignedURLRequest) (*PresignedURL, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreatePresignedURL not implemented")
+}
 func (UnimplementedFileRepositoryServiceServer) Mkdir(context.Context, *MkdirRequest) (*StatusResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Mkdir not implemented")
 }
@@ -161,9 +439,54 @@ func (UnimplementedFileRepositoryServiceServer) UploadFile(grpc.BidiStreamingSer
 func (UnimplementedFileRepositoryServiceServer) UpdateFileContent(grpc.BidiStreamingServer[FileContentRequest, StatusResponse]) error {
 	return status.Errorf(codes.Unimplemented, "method UpdateFileContent not implemented")
 }
-func (UnimplementedFileRepositoryServiceServer) DeleteFiles(context.Context, *DeleteFilesRequest) (*StatusResponse, error) {
+func (UnimplementedFileRepositoryServiceServer) DeleteFiles(context.Context, *DeleteFilesRequest) (*DeleteFilesResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeleteFiles not implemented")
 }
+func (UnimplementedFileRepositoryServiceServer) CopyFiles(context.Context, *RelocateFilesRequest) (*RelocateFilesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CopyFiles not implemented")
+}
+func (UnimplementedFileRepositoryServiceServer) MoveFiles(context.Context, *RelocateFilesRequest) (*RelocateFilesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MoveFiles not implemented")
+}
+func (UnimplementedFileRepositoryServiceServer) CreateBucket(context.Context, *CreateBucketRequest) (*StatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateBucket not implemented")
+}
+func (UnimplementedFileRepositoryServiceServer) DeleteBucket(context.Context, *DeleteBucketRequest) (*StatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteBucket not implemented")
+}
+func (UnimplementedFileRepositoryServiceServer) ListBuckets(context.Context, *ListBucketsRequest) (*ListBucketsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListBuckets not implemented")
+}
+func (UnimplementedFileRepositoryServiceServer) GetBucketInfo(context.Context, *GetBucketInfoRequest) (*BucketInfo, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBucketInfo not implemented")
+}
+func (UnimplementedFileRepositoryServiceServer) SetBucketVersioning(context.Context, *SetBucketVersioningRequest) (*StatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetBucketVersioning not implemented")
+}
+func (UnimplementedFileRepositoryServiceServer) GetQuotaUsage(context.Context, *GetQuotaUsageRequest) (*QuotaUsage, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetQuotaUsage not implemented")
+}
+func (UnimplementedFileRepositoryServiceServer) ListFileVersions(context.Context, *ListFileVersionsRequest) (*ListFileVersionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListFileVersions not implemented")
+}
+func (UnimplementedFileRepositoryServiceServer) RestoreFileVersion(context.Context, *FileVersionRequest) (*RestoreFileVersionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreFileVersion not implemented")
+}
+func (UnimplementedFileRepositoryServiceServer) DeleteFileVersion(context.Context, *FileVersionRequest) (*StatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteFileVersion not implemented")
+}
+func (UnimplementedFileRepositoryServiceServer) InitiateUpload(context.Context, *InitiateUploadRequest) (*InitiateUploadResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method InitiateUpload not implemented")
+}
+func (UnimplementedFileRepositoryServiceServer) UploadPart(grpc.ClientStreamingServer[UploadPartRequest, UploadPartResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method UploadPart not implemented")
+}
+func (UnimplementedFileRepositoryServiceServer) CompleteUpload(context.Context, *UploadSessionRequest) (*StatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CompleteUpload not implemented")
+}
+func (UnimplementedFileRepositoryServiceServer) AbortUpload(context.Context, *UploadSessionRequest) (*StatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AbortUpload not implemented")
+}
 func (UnimplementedFileRepositoryServiceServer) mustEmbedUnimplementedFileRepositoryServiceServer() {}
 func (UnimplementedFileRepositoryServiceServer) testEmbeddedByValue()                               {}
 
@@ -214,6 +537,78 @@ func _FileRepositoryService_GetFileByPath_Handler(srv interface{}, stream grpc.S
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type FileRepositoryService_GetFileByPathServer = grpc.ServerStreamingServer[FileChunk]
 
+func _FileRepositoryService_ListDirectory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListDirectoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileRepositoryServiceServer).ListDirectory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileRepositoryService_ListDirectory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileRepositoryServiceServer).ListDirectory(ctx, req.(*ListDirectoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileRepositoryService_ListUploadedParts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UploadSessionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileRepositoryServiceServer).ListUploadedParts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileRepositoryService_ListUploadedParts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileRepositoryServiceServer).ListUploadedParts(ctx, req.(*UploadSessionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileRepositoryService_StatFile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StatFileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileRepositoryServiceServer).StatFile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileRepositoryService_StatFile_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileRepositoryServiceServer).StatFile(ctx, req.(*StatFileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileRepositoryService_CreatePresignedURL_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreatePresignedURLRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileRepositoryServiceServer).CreatePresignedURL(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileRepositoryService_CreatePresignedURL_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileRepositoryServiceServer).CreatePresignedURL(ctx, req.(*CreatePresignedURLRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _FileRepositoryService_Mkdir_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(MkdirRequest)
 	if err := dec(in); err != nil {
@@ -264,6 +659,265 @@ func _FileRepositoryService_DeleteFiles_Handler(srv interface{}, ctx context.Con
 	return interceptor(ctx, in, info, handler)
 }
 
+func _FileRepositoryService_CopyFiles_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RelocateFilesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileRepositoryServiceServer).CopyFiles(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileRepositoryService_CopyFiles_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileRepositoryServiceServer).CopyFiles(ctx, req.(*RelocateFilesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileRepositoryService_MoveFiles_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RelocateFilesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileRepositoryServiceServer).MoveFiles(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileRepositoryService_MoveFiles_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileRepositoryServiceServer).MoveFiles(ctx, req.(*RelocateFilesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileRepositoryService_CreateBucket_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateBucketRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileRepositoryServiceServer).CreateBucket(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileRepositoryService_CreateBucket_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileRepositoryServiceServer).CreateBucket(ctx, req.(*CreateBucketRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileRepositoryService_DeleteBucket_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteBucketRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileRepositoryServiceServer).DeleteBucket(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileRepositoryService_DeleteBucket_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileRepositoryServiceServer).DeleteBucket(ctx, req.(*DeleteBucketRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileRepositoryService_ListBuckets_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListBucketsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileRepositoryServiceServer).ListBuckets(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileRepositoryService_ListBuckets_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileRepositoryServiceServer).ListBuckets(ctx, req.(*ListBucketsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileRepositoryService_GetBucketInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBucketInfoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileRepositoryServiceServer).GetBucketInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileRepositoryService_GetBucketInfo_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileRepositoryServiceServer).GetBucketInfo(ctx, req.(*GetBucketInfoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileRepositoryService_SetBucketVersioning_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetBucketVersioningRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileRepositoryServiceServer).SetBucketVersioning(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileRepositoryService_SetBucketVersioning_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileRepositoryServiceServer).SetBucketVersioning(ctx, req.(*SetBucketVersioningRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileRepositoryService_GetQuotaUsage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetQuotaUsageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileRepositoryServiceServer).GetQuotaUsage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileRepositoryService_GetQuotaUsage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileRepositoryServiceServer).GetQuotaUsage(ctx, req.(*GetQuotaUsageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileRepositoryService_ListFileVersions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListFileVersionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileRepositoryServiceServer).ListFileVersions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileRepositoryService_ListFileVersions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileRepositoryServiceServer).ListFileVersions(ctx, req.(*ListFileVersionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileRepositoryService_RestoreFileVersion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FileVersionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileRepositoryServiceServer).RestoreFileVersion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileRepositoryService_RestoreFileVersion_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileRepositoryServiceServer).RestoreFileVersion(ctx, req.(*FileVersionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileRepositoryService_DeleteFileVersion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FileVersionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileRepositoryServiceServer).DeleteFileVersion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileRepositoryService_DeleteFileVersion_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileRepositoryServiceServer).DeleteFileVersion(ctx, req.(*FileVersionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileRepositoryService_InitiateUpload_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InitiateUploadRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileRepositoryServiceServer).InitiateUpload(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileRepositoryService_InitiateUpload_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileRepositoryServiceServer).InitiateUpload(ctx, req.(*InitiateUploadRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileRepositoryService_UploadPart_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(FileRepositoryServiceServer).UploadPart(&grpc.GenericServerStream[UploadPartRequest, UploadPartResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type FileRepositoryService_UploadPartServer = grpc.ClientStreamingServer[UploadPartRequest, UploadPartResponse]
+
+func _FileRepositoryService_CompleteUpload_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UploadSessionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileRepositoryServiceServer).CompleteUpload(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileRepositoryService_CompleteUpload_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileRepositoryServiceServer).CompleteUpload(ctx, req.(*UploadSessionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileRepositoryService_AbortUpload_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UploadSessionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileRepositoryServiceServer).AbortUpload(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileRepositoryService_AbortUpload_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileRepositoryServiceServer).AbortUpload(ctx, req.(*UploadSessionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // FileRepositoryService_ServiceDesc is the grpc.ServiceDesc for FileRepositoryService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -275,6 +929,22 @@ var FileRepositoryService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "HealthCheck",
 			Handler:    _FileRepositoryService_HealthCheck_Handler,
 		},
+		{
+			MethodName: "ListDirectory",
+			Handler:    _FileRepositoryService_ListDirectory_Handler,
+		},
+		{
+			MethodName: "ListUploadedParts",
+			Handler:    _FileRepositoryService_ListUploadedParts_Handler,
+		},
+		{
+			MethodName: "StatFile",
+			Handler:    _FileRepositoryService_StatFile_Handler,
+		},
+		{
+			MethodName: "CreatePresignedURL",
+			Handler:    _FileRepositoryService_CreatePresignedURL_Handler,
+		},
 		{
 			MethodName: "Mkdir",
 			Handler:    _FileRepositoryService_Mkdir_Handler,
@@ -283,6 +953,62 @@ var FileRepositoryService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteFiles",
 			Handler:    _FileRepositoryService_DeleteFiles_Handler,
 		},
+		{
+			MethodName: "CopyFiles",
+			Handler:    _FileRepositoryService_CopyFiles_Handler,
+		},
+		{
+			MethodName: "MoveFiles",
+			Handler:    _FileRepositoryService_MoveFiles_Handler,
+		},
+		{
+			MethodName: "CreateBucket",
+			Handler:    _FileRepositoryService_CreateBucket_Handler,
+		},
+		{
+			MethodName: "DeleteBucket",
+			Handler:    _FileRepositoryService_DeleteBucket_Handler,
+		},
+		{
+			MethodName: "ListBuckets",
+			Handler:    _FileRepositoryService_ListBuckets_Handler,
+		},
+		{
+			MethodName: "GetBucketInfo",
+			Handler:    _FileRepositoryService_GetBucketInfo_Handler,
+		},
+		{
+			MethodName: "SetBucketVersioning",
+			Handler:    _FileRepositoryService_SetBucketVersioning_Handler,
+		},
+		{
+			MethodName: "GetQuotaUsage",
+			Handler:    _FileRepositoryService_GetQuotaUsage_Handler,
+		},
+		{
+			MethodName: "ListFileVersions",
+			Handler:    _FileRepositoryService_ListFileVersions_Handler,
+		},
+		{
+			MethodName: "RestoreFileVersion",
+			Handler:    _FileRepositoryService_RestoreFileVersion_Handler,
+		},
+		{
+			MethodName: "DeleteFileVersion",
+			Handler:    _FileRepositoryService_DeleteFileVersion_Handler,
+		},
+		{
+			MethodName: "InitiateUpload",
+			Handler:    _FileRepositoryService_InitiateUpload_Handler,
+		},
+		{
+			MethodName: "CompleteUpload",
+			Handler:    _FileRepositoryService_CompleteUpload_Handler,
+		},
+		{
+			MethodName: "AbortUpload",
+			Handler:    _FileRepositoryService_AbortUpload_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -302,6 +1028,11 @@ var FileRepositoryService_ServiceDesc = grpc.ServiceDesc{
 			ServerStreams: true,
 			ClientStreams: true,
 		},
+		{
+			StreamName:    "UploadPart",
+			Handler:       _FileRepositoryService_UploadPart_Handler,
+			ClientStreams: true,
+		},
 	},
 	Metadata: "services/file-repository/file-repository.proto",
 }
@@ -9,6 +9,7 @@ package file_repository
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -21,6 +22,222 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type ArchiveFormat int32
+
+const (
+	ArchiveFormat_ARCHIVE_FORMAT_NONE     ArchiveFormat = 0
+	ArchiveFormat_ARCHIVE_FORMAT_TAR      ArchiveFormat = 1
+	ArchiveFormat_ARCHIVE_FORMAT_TAR_GZIP ArchiveFormat = 2
+	ArchiveFormat_ARCHIVE_FORMAT_ZIP      ArchiveFormat = 3
+)
+
+// Enum value maps for ArchiveFormat.
+var (
+	ArchiveFormat_name = map[int32]string{
+		0: "ARCHIVE_FORMAT_NONE",
+		1: "ARCHIVE_FORMAT_TAR",
+		2: "ARCHIVE_FORMAT_TAR_GZIP",
+		3: "ARCHIVE_FORMAT_ZIP",
+	}
+	ArchiveFormat_value = map[string]int32{
+		"ARCHIVE_FORMAT_NONE":     0,
+		"ARCHIVE_FORMAT_TAR":      1,
+		"ARCHIVE_FORMAT_TAR_GZIP": 2,
+		"ARCHIVE_FORMAT_ZIP":      3,
+	}
+)
+
+func (x ArchiveFormat) Enum() *ArchiveFormat {
+	p := new(ArchiveFormat)
+	*p = x
+	return p
+}
+
+func (x ArchiveFormat) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ArchiveFormat) Descriptor() protoreflect.EnumDescriptor {
+	return file_services_file_repository_types_proto_enumTypes[0].Descriptor()
+}
+
+func (ArchiveFormat) Type() protoreflect.EnumType {
+	return &file_services_file_repository_types_proto_enumTypes[0]
+}
+
+func (x ArchiveFormat) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ArchiveFormat.Descriptor instead.
+func (ArchiveFormat) EnumDescriptor() ([]byte, []int) {
+	return file_services_file_repository_types_proto_rawDescGZIP(), []int{0}
+}
+
+type OverwriteMode int32
+
+const (
+	// Nothing is relocated if at least one destination file already exists
+	OverwriteMode_OVERWRITE_MODE_FAIL OverwriteMode = 0
+	// Existing destination files are left untouched
+	OverwriteMode_OVERWRITE_MODE_SKIP    OverwriteMode = 1
+	OverwriteMode_OVERWRITE_MODE_REPLACE OverwriteMode = 2
+)
+
+// Enum value maps for OverwriteMode.
+var (
+	OverwriteMode_name = map[int32]string{
+		0: "OVERWRITE_MODE_FAIL",
+		1: "OVERWRITE_MODE_SKIP",
+		2: "OVERWRITE_MODE_REPLACE",
+	}
+	OverwriteMode_value = map[string]int32{
+		"OVERWRITE_MODE_FAIL":    0,
+		"OVERWRITE_MODE_SKIP":    1,
+		"OVERWRITE_MODE_REPLACE": 2,
+	}
+)
+
+func (x OverwriteMode) Enum() *OverwriteMode {
+	p := new(OverwriteMode)
+	*p = x
+	return p
+}
+
+func (x OverwriteMode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (OverwriteMode) Descriptor() protoreflect.EnumDescriptor {
+	return file_services_file_repository_types_proto_enumTypes[1].Descriptor()
+}
+
+func (OverwriteMode) Type() protoreflect.EnumType {
+	return &file_services_file_repository_types_proto_enumTypes[1]
+}
+
+func (x OverwriteMode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use OverwriteMode.Descriptor instead.
+func (OverwriteMode) EnumDescriptor() ([]byte, []int) {
+	return file_services_file_repository_types_proto_rawDescGZIP(), []int{1}
+}
+
+// Failed RPCs return gRPC status with google.rpc.ErrorInfo detail, its reason is the name of one of these values
+// and metadata contains "bucket" and "path" of the failed request (if they are known).
+// Depending on the failure, status may also contain google.rpc.BadRequest,
+// google.rpc.PreconditionFailure or google.rpc.QuotaFailure details.
+type ErrorReason int32
+
+const (
+	ErrorReason_ERROR_REASON_UNSPECIFIED           ErrorReason = 0
+	ErrorReason_ERROR_REASON_FILE_NOT_FOUND        ErrorReason = 1
+	ErrorReason_ERROR_REASON_BUCKET_NOT_FOUND      ErrorReason = 2
+	ErrorReason_ERROR_REASON_UPLOAD_NOT_FOUND      ErrorReason = 3
+	ErrorReason_ERROR_REASON_INVALID_PATH          ErrorReason = 4
+	ErrorReason_ERROR_REASON_INVALID_ARGUMENT      ErrorReason = 5
+	ErrorReason_ERROR_REASON_PRECONDITION_FAILED   ErrorReason = 6
+	ErrorReason_ERROR_REASON_DESTINATION_EXISTS    ErrorReason = 7
+	ErrorReason_ERROR_REASON_QUOTA_EXCEEDED        ErrorReason = 8
+	ErrorReason_ERROR_REASON_RANGE_NOT_SATISFIABLE ErrorReason = 9
+	ErrorReason_ERROR_REASON_CHECKSUM_MISMATCH     ErrorReason = 10
+	ErrorReason_ERROR_REASON_NOT_SUPPORTED         ErrorReason = 11
+	ErrorReason_ERROR_REASON_TIMEOUT               ErrorReason = 12
+	ErrorReason_ERROR_REASON_CANCELED              ErrorReason = 13
+	ErrorReason_ERROR_REASON_QUOTA_NOT_DEFINED     ErrorReason = 14
+	ErrorReason_ERROR_REASON_UNAUTHENTICATED       ErrorReason = 15
+	ErrorReason_ERROR_REASON_PERMISSION_DENIED     ErrorReason = 16
+	// Also carries google.rpc.RetryInfo detail with delay after which request can be retried
+	ErrorReason_ERROR_REASON_RATE_LIMITED          ErrorReason = 17
+	ErrorReason_ERROR_REASON_BUCKET_ALREADY_EXISTS ErrorReason = 18
+	ErrorReason_ERROR_REASON_BUCKET_NOT_EMPTY      ErrorReason = 19
+	// Bucket name is invalid or reserved by the storage
+	ErrorReason_ERROR_REASON_INVALID_BUCKET_NAME ErrorReason = 20
+	// Some storages can't have file and directory with the same path (e.g. "/a" and "/a/b")
+	ErrorReason_ERROR_REASON_PATH_CONFLICT ErrorReason = 21
+)
+
+// Enum value maps for ErrorReason.
+var (
+	ErrorReason_name = map[int32]string{
+		0:  "ERROR_REASON_UNSPECIFIED",
+		1:  "ERROR_REASON_FILE_NOT_FOUND",
+		2:  "ERROR_REASON_BUCKET_NOT_FOUND",
+		3:  "ERROR_REASON_UPLOAD_NOT_FOUND",
+		4:  "ERROR_REASON_INVALID_PATH",
+		5:  "ERROR_REASON_INVALID_ARGUMENT",
+		6:  "ERROR_REASON_PRECONDITION_FAILED",
+		7:  "ERROR_REASON_DESTINATION_EXISTS",
+		8:  "ERROR_REASON_QUOTA_EXCEEDED",
+		9:  "ERROR_REASON_RANGE_NOT_SATISFIABLE",
+		10: "ERROR_REASON_CHECKSUM_MISMATCH",
+		11: "ERROR_REASON_NOT_SUPPORTED",
+		12: "ERROR_REASON_TIMEOUT",
+		13: "ERROR_REASON_CANCELED",
+		14: "ERROR_REASON_QUOTA_NOT_DEFINED",
+		15: "ERROR_REASON_UNAUTHENTICATED",
+		16: "ERROR_REASON_PERMISSION_DENIED",
+		17: "ERROR_REASON_RATE_LIMITED",
+		18: "ERROR_REASON_BUCKET_ALREADY_EXISTS",
+		19: "ERROR_REASON_BUCKET_NOT_EMPTY",
+		20: "ERROR_REASON_INVALID_BUCKET_NAME",
+		21: "ERROR_REASON_PATH_CONFLICT",
+	}
+	ErrorReason_value = map[string]int32{
+		"ERROR_REASON_UNSPECIFIED":           0,
+		"ERROR_REASON_FILE_NOT_FOUND":        1,
+		"ERROR_REASON_BUCKET_NOT_FOUND":      2,
+		"ERROR_REASON_UPLOAD_NOT_FOUND":      3,
+		"ERROR_REASON_INVALID_PATH":          4,
+		"ERROR_REASON_INVALID_ARGUMENT":      5,
+		"ERROR_REASON_PRECONDITION_FAILED":   6,
+		"ERROR_REASON_DESTINATION_EXISTS":    7,
+		"ERROR_REASON_QUOTA_EXCEEDED":        8,
+		"ERROR_REASON_RANGE_NOT_SATISFIABLE": 9,
+		"ERROR_REASON_CHECKSUM_MISMATCH":     10,
+		"ERROR_REASON_NOT_SUPPORTED":         11,
+		"ERROR_REASON_TIMEOUT":               12,
+		"ERROR_REASON_CANCELED":              13,
+		"ERROR_REASON_QUOTA_NOT_DEFINED":     14,
+		"ERROR_REASON_UNAUTHENTICATED":       15,
+		"ERROR_REASON_PERMISSION_DENIED":     16,
+		"ERROR_REASON_RATE_LIMITED":          17,
+		"ERROR_REASON_BUCKET_ALREADY_EXISTS": 18,
+		"ERROR_REASON_BUCKET_NOT_EMPTY":      19,
+		"ERROR_REASON_INVALID_BUCKET_NAME":   20,
+		"ERROR_REASON_PATH_CONFLICT":         21,
+	}
+)
+
+func (x ErrorReason) Enum() *ErrorReason {
+	p := new(ErrorReason)
+	*p = x
+	return p
+}
+
+func (x ErrorReason) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ErrorReason) Descriptor() protoreflect.EnumDescriptor {
+	return file_services_file_repository_types_proto_enumTypes[2].Descriptor()
+}
+
+func (ErrorReason) Type() protoreflect.EnumType {
+	return &file_services_file_repository_types_proto_enumTypes[2]
+}
+
+func (x ErrorReason) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ErrorReason.Descriptor instead.
+func (ErrorReason) EnumDescriptor() ([]byte, []int) {
+	return file_services_file_repository_types_proto_rawDescGZIP(), []int{2}
+}
+
 type HealthCheckRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Service       string                 `protobuf:"bytes,1,opt,name=service,proto3" json:"service,omitempty"`
@@ -53,41 +270,2684 @@ func (x *HealthCheckRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use HealthCheckRequest.ProtoReflect.Descriptor instead.
-func (*HealthCheckRequest) Descriptor() ([]byte, []int) {
-	return file_services_file_repository_types_proto_rawDescGZIP(), []int{0}
+// Deprecated: Use HealthCheckRequest.ProtoReflect.Descriptor instead.
+func (*HealthCheckRequest) Descriptor() ([]byte, []int) {
+	return file_services_file_repository_types_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *HealthCheckRequest) GetService() string {
+	if x != nil {
+		return x.Service
+	}
+	return ""
+}
+
+type HealthCheckResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	Timestamp     string                 `protobuf:"bytes,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HealthCheckResponse) Reset() {
+	*x = HealthCheckResponse{}
+	mi := &file_services_file_repository_types_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HealthCheckResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HealthCheckResponse) ProtoMessage() {}
+
+func (x *HealthCheckResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_services_file_repository_types_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HealthCheckResponse.ProtoReflect.Descriptor instead.
+func (*HealthCheckResponse) Descriptor() ([]byte, []int) {
+	return file_services_file_repository_types_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *HealthCheckResponse) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *HealthCheckResponse) GetTimestamp() string {
+	if x != nil {
+		return x.Timestamp
+	}
+	return ""
+}
+
+type GetFileByPathRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Path      string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Bucket    string                 `protobuf:"bytes,2,opt,name=bucket,proto3" json:"bucket,omitempty"`
+	ChunkSize int32                  `protobuf:"varint,3,opt,name=chunk_size,json=chunkSize,proto3" json:"chunk_size,omitempty"`
+	// Required if path is a directory, in that case the whole directory
+	// will be sent as archive of this format.
+	ArchiveFormat ArchiveFormat `protobuf:"varint,4,opt,name=archive_format,json=archiveFormat,proto3,enum=file_repository.ArchiveFormat" json:"archive_format,omitempty"`
+	// Index of the first byte to read
+	Offset int64 `protobuf:"varint,5,opt,name=offset,proto3" json:"offset,omitempty"`
+	// Max amount of bytes to read, if 0 then file will be read till the end
+	Length int64 `protobuf:"varint,6,opt,name=length,proto3" json:"length,omitempty"`
+	// If specified, then this version of the file will be sent instead of the current one
+	VersionId     string `protobuf:"bytes,7,opt,name=version_id,json=versionId,proto3" json:"version_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetFileByPathRequest) Reset() {
+	*x = GetFileByPathRequest{}
+	mi := &file_services_file_repository_types_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetFileByPathRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetFileByPathRequest) ProtoMessage() {}
+
+func (x *GetFileByPathRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_services_file_repository_types_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetFileByPathRequest.ProtoReflect.Descriptor instead.
+func (*GetFileByPathRequest) Descriptor() ([]byte, []int) {
+	return file_services_file_repository_types_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetFileByPathRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *GetFileByPathRequest) GetBucket() string {
+	if x != nil {
+		return x.Bucket
+	}
+	return ""
+}
+
+func (x *GetFileByPathRequest) GetChunkSize() int32 {
+	if x != nil {
+		return x.ChunkSize
+	}
+	return 0
+}
+
+func (x *GetFileByPathRequest) GetArchiveFormat() ArchiveFormat {
+	if x != nil {
+		return x.ArchiveFormat
+	}
+	return ArchiveFormat_ARCHIVE_FORMAT_NONE
+}
+
+func (x *GetFileByPathRequest) GetOffset() int64 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *GetFileByPathRequest) GetLength() int64 {
+	if x != nil {
+		return x.Length
+	}
+	return 0
+}
+
+func (x *GetFileByPathRequest) GetVersionId() string {
+	if x != nil {
+		return x.VersionId
+	}
+	return ""
+}
+
+type ListDirectoryRequest struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Path              string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Bucket            string                 `protobuf:"bytes,2,opt,name=bucket,proto3" json:"bucket,omitempty"`
+	Recursive         bool                   `protobuf:"varint,3,opt,name=recursive,proto3" json:"recursive,omitempty"`
+	Limit             int32                  `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
+	ContinuationToken string                 `protobuf:"bytes,5,opt,name=continuation_token,json=continuationToken,proto3" json:"continuation_token,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *ListDirectoryRequest) Reset() {
+	*x = ListDirectoryRequest{}
+	mi := &file_services_file_repository_types_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDirectoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDirectoryRequest) ProtoMessage() {}
+
+func (x *ListDirectoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_services_file_repository_types_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDirectoryRequest.ProtoReflect.Descriptor instead.
+func (*ListDirectoryRequest) Descriptor() ([]byte, []int) {
+	return file_services_file_repository_types_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ListDirectoryRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *ListDirectoryRequest) GetBucket() string {
+	if x != nil {
+		return x.Bucket
+	}
+	return ""
+}
+
+func (x *ListDirectoryRequest) GetRecursive() bool {
+	if x != nil {
+		return x.Recursive
+	}
+	return false
+}
+
+func (x *ListDirectoryRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListDirectoryRequest) GetContinuationToken() string {
+	if x != nil {
+		return x.ContinuationToken
+	}
+	return ""
+}
+
+type DirectoryEntry struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	Path         string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Size         int64                  `protobuf:"varint,2,opt,name=size,proto3" json:"size,omitempty"`
+	LastModified *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=last_modified,json=lastModified,proto3" json:"last_modified,omitempty"`
+	Etag         string                 `protobuf:"bytes,4,opt,name=etag,proto3" json:"etag,omitempty"`
+	IsDirectory  bool                   `protobuf:"varint,5,opt,name=is_directory,json=isDirectory,proto3" json:"is_directory,omitempty"`
+	// Size which file actually occupies in the storage (e.g. after compression)
+	StoredSize    int64 `protobuf:"varint,6,opt,name=stored_size,json=storedSize,proto3" json:"stored_size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DirectoryEntry) Reset() {
+	*x = DirectoryEntry{}
+	mi := &file_services_file_repository_types_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DirectoryEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DirectoryEntry) ProtoMessage() {}
+
+func (x *DirectoryEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_services_file_repository_types_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DirectoryEntry.ProtoReflect.Descriptor instead.
+func (*DirectoryEntry) Descriptor() ([]byte, []int) {
+	return file_services_file_repository_types_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *DirectoryEntry) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *DirectoryEntry) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *DirectoryEntry) GetLastModified() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastModified
+	}
+	return nil
+}
+
+func (x *DirectoryEntry) GetEtag() string {
+	if x != nil {
+		return x.Etag
+	}
+	return ""
+}
+
+func (x *DirectoryEntry) GetIsDirectory() bool {
+	if x != nil {
+		return x.IsDirectory
+	}
+	return false
+}
+
+func (x *DirectoryEntry) GetStoredSize() int64 {
+	if x != nil {
+		return x.StoredSize
+	}
+	return 0
+}
+
+type ListDirectoryResponse struct {
+	state                 protoimpl.MessageState `protogen:"open.v1"`
+	Entries               []*DirectoryEntry      `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+	NextContinuationToken string                 `protobuf:"bytes,2,opt,name=next_continuation_token,json=nextContinuationToken,proto3" json:"next_continuation_token,omitempty"`
+	IsTruncated           bool                   `protobuf:"varint,3,opt,name=is_truncated,json=isTruncated,proto3" json:"is_truncated,omitempty"`
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
+}
+
+func (x *ListDirectoryResponse) Reset() {
+	*x = ListDirectoryResponse{}
+	mi := &file_services_file_repository_types_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDirectoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDirectoryResponse) ProtoMessage() {}
+
+func (x *ListDirectoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_services_file_repository_types_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDirectoryResponse.ProtoReflect.Descriptor instead.
+func (*ListDirectoryResponse) Descriptor() ([]byte, []int) {
+	return file_services_file_repository_types_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListDirectoryResponse) GetEntries() []*DirectoryEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+func (x *ListDirectoryResponse) GetNextContinuationToken() string {
+	if x != nil {
+		return x.NextContinuationToken
+	}
+	return ""
+}
+
+func (x *ListDirectoryResponse) GetIsTruncated() bool {
+	if x != nil {
+		return x.IsTruncated
+	}
+	return false
+}
+
+type StatFileRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Bucket string                 `protobuf:"bytes,1,opt,name=bucket,proto3" json:"bucket,omitempty"`
+	// Directories must end with '/'. Up to 1000 paths can be requested at once.
+	Paths []string `protobuf:"bytes,2,rep,name=paths,proto3" json:"paths,omitempty"`
+	// If specified, then this version of the file will be statted instead of the current one.
+	// Can be used only with single path.
+	VersionId     string `protobuf:"bytes,3,opt,name=version_id,json=versionId,proto3" json:"version_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StatFileRequest) Reset() {
+	*x = StatFileRequest{}
+	mi := &file_services_file_repository_types_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StatFileRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatFileRequest) ProtoMessage() {}
+
+func (x *StatFileRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_services_file_repository_types_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatFileRequest.ProtoReflect.Descriptor instead.
+func (*StatFileRequest) Descriptor() ([]byte, []int) {
+	return file_services_file_repository_types_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *StatFileRequest) GetBucket() string {
+	if x != nil {
+		return x.Bucket
+	}
+	return ""
+}
+
+func (x *StatFileRequest) GetPaths() []string {
+	if x != nil {
+		return x.Paths
+	}
+	return nil
+}
+
+func (x *StatFileRequest) GetVersionId() string {
+	if x != nil {
+		return x.VersionId
+	}
+	return ""
+}
+
+type FileStat struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Path  string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	// If false, then all other fields are unset
+	Exists bool  `protobuf:"varint,2,opt,name=exists,proto3" json:"exists,omitempty"`
+	Size   int64 `protobuf:"varint,3,opt,name=size,proto3" json:"size,omitempty"`
+	// Size which file actually occupies in the storage (e.g. after compression)
+	StoredSize   int64                  `protobuf:"varint,4,opt,name=stored_size,json=storedSize,proto3" json:"stored_size,omitempty"`
+	LastModified *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=last_modified,json=lastModified,proto3" json:"last_modified,omitempty"`
+	Etag         string                 `protobuf:"bytes,6,opt,name=etag,proto3" json:"etag,omitempty"`
+	// Empty if it's unknown
+	ContentType string `protobuf:"bytes,7,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
+	// Metadata set by the client
+	Metadata map[string]string `protobuf:"bytes,8,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// Empty if versioning was never enabled for the bucket
+	VersionId string `protobuf:"bytes,9,opt,name=version_id,json=versionId,proto3" json:"version_id,omitempty"`
+	// Digest of the content which was verified on upload, empty if it's unknown
+	Checksum      string `protobuf:"bytes,10,opt,name=checksum,proto3" json:"checksum,omitempty"`
+	ChecksumType  string `protobuf:"bytes,11,opt,name=checksum_type,json=checksumType,proto3" json:"checksum_type,omitempty"`
+	IsDirectory   bool   `protobuf:"varint,12,opt,name=is_directory,json=isDirectory,proto3" json:"is_directory,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FileStat) Reset() {
+	*x = FileStat{}
+	mi := &file_services_file_repository_types_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FileStat) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FileStat) ProtoMessage() {}
+
+func (x *FileStat) ProtoReflect() protoreflect.Message {
+	mi := &file_services_file_repository_types_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FileStat.ProtoReflect.Descriptor instead.
+func (*FileStat) Descriptor() ([]byte, []int) {
+	return file_services_file_repository_types_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *FileStat) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *FileStat) GetExists() bool {
+	if x != nil {
+		return x.Exists
+	}
+	return false
+}
+
+func (x *FileStat) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *FileStat) GetStoredSize() int64 {
+	if x != nil {
+		return x.StoredSize
+	}
+	return 0
+}
+
+func (x *FileStat) GetLastModified() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastModified
+	}
+	return nil
+}
+
+func (x *FileStat) GetEtag() string {
+	if x != nil {
+		return x.Etag
+	}
+	return ""
+}
+
+func (x *FileStat) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+func (x *FileStat) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *FileStat) GetVersionId() string {
+	if x != nil {
+		return x.VersionId
+	}
+	return ""
+}
+
+func (x *FileStat) GetChecksum() string {
+	if x != nil {
+		return x.Checksum
+	}
+	return ""
+}
+
+func (x *FileStat) GetChecksumType() string {
+	if x != nil {
+		return x.ChecksumType
+	}
+	return ""
+}
+
+func (x *FileStat) GetIsDirectory() bool {
+	if x != nil {
+		return x.IsDirectory
+	}
+	return false
+}
+
+type StatFileResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Ordered the same way as requested paths
+	Files         []*FileStat `protobuf:"bytes,1,rep,name=files,proto3" json:"files,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StatFileResponse) Reset() {
+	*x = StatFileResponse{}
+	mi := &file_services_file_repository_types_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StatFileResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatFileResponse) ProtoMessage() {}
+
+func (x *StatFileResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_services_file_repository_types_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatFileResponse.ProtoReflect.Descriptor instead.
+func (*StatFileResponse) Descriptor() ([]byte, []int) {
+	return file_services_file_repository_types_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *StatFileResponse) GetFiles() []*FileStat {
+	if x != nil {
+		return x.Files
+	}
+	return nil
+}
+
+type CreatePresignedURLRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Bucket string                 `protobuf:"bytes,1,opt,name=bucket,proto3" json:"bucket,omitempty"`
+	Path   string                 `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	// "GET" to download the file or "PUT" to upload it
+	Method string `protobuf:"bytes,3,opt,name=method,proto3" json:"method,omitempty"`
+	// Lifetime of the URL in seconds, 15 minutes by default and 7 days at most
+	ExpiresIn int64 `protobuf:"varint,4,opt,name=expires_in,json=expiresIn,proto3" json:"expires_in,omitempty"`
+	// Restrictions of the upload, supported only for "PUT": if specified, then upload
+	// must be sent with exactly such Content-Type and Content-Length headers.
+	ContentType   string `protobuf:"bytes,5,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
+	Size          int64  `protobuf:"varint,6,opt,name=size,proto3" json:"size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreatePresignedURLRequest) Reset() {
+	*x = CreatePresignedURLRequest{}
+	mi := &file_services_file_repository_types_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreatePresignedURLRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreatePresignedURLRequest) ProtoMessage() {}
+
+func (x *CreatePresignedURLRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_services_file_repository_types_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreatePresignedURLRequest.ProtoReflect.Descriptor instead.
+func (*CreatePresignedURLRequest) Descriptor() ([]byte, []int) {
+	return file_services_file_repository_types_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *CreatePresignedURLRequest) GetBucket() string {
+	if x != nil {
+		return x.Bucket
+	}
+	return ""
+}
+
+func (x *CreatePresignedURLRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *CreatePresignedURLRequest) GetMethod() string {
+	if x != nil {
+		return x.Method
+	}
+	return ""
+}
+
+func (x *CreatePresignedURLRequest) GetExpiresIn() int64 {
+	if x != nil {
+		return x.ExpiresIn
+	}
+	return 0
+}
+
+func (x *CreatePresignedURLRequest) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+func (x *CreatePresignedURLRequest) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+type PresignedURL struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Url       string                 `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	Method    string                 `protobuf:"bytes,2,opt,name=method,proto3" json:"method,omitempty"`
+	ExpiresAt *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	// Headers which must be sent with the request as is
+	Headers       map[string]string `protobuf:"bytes,4,rep,name=headers,proto3" json:"headers,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PresignedURL) Reset() {
+	*x = PresignedURL{}
+	mi := &file_services_file_repository_types_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PresignedURL) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PresignedURL) ProtoMessage() {}
+
+func (x *PresignedURL) ProtoReflect() protoreflect.Message {
+	mi := &file_services_file_repository_types_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PresignedURL.ProtoReflect.Descriptor instead.
+func (*PresignedURL) Descriptor() ([]byte, []int) {
+	return file_services_file_repository_types_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *PresignedURL) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *PresignedURL) GetMethod() string {
+	if x != nil {
+		return x.Method
+	}
+	return ""
+}
+
+func (x *PresignedURL) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+func (x *PresignedURL) GetHeaders() map[string]string {
+	if x != nil {
+		return x.Headers
+	}
+	return nil
+}
+
+type MkdirRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Bucket        string                 `protobuf:"bytes,2,opt,name=bucket,proto3" json:"bucket,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MkdirRequest) Reset() {
+	*x = MkdirRequest{}
+	mi := &file_services_file_repository_types_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MkdirRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MkdirRequest) ProtoMessage() {}
+
+func (x *MkdirRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_services_file_repository_types_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MkdirRequest.ProtoReflect.Descriptor instead.
+func (*MkdirRequest) Descriptor() ([]byte, []int) {
+	return file_services_file_repository_types_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *MkdirRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *MkdirRequest) GetBucket() string {
+	if x != nil {
+		return x.Bucket
+	}
+	return ""
+}
+
+type FileContentHeader struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Path   string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Bucket string                 `protobuf:"bytes,2,opt,name=bucket,proto3" json:"bucket,omitempty"`
+	Size   int64                  `protobuf:"varint,3,opt,name=size,proto3" json:"size,omitempty"`
+	// If specified, then content will be extracted into path (which must be a directory).
+	// Supported only by UploadFile.
+	ArchiveFormat ArchiveFormat `protobuf:"varint,4,opt,name=archive_format,json=archiveFormat,proto3,enum=file_repository.ArchiveFormat" json:"archive_format,omitempty"`
+	// Expected hex encoded digest of the content. If specified, then file won't be stored
+	// unless digest of the received content matches it. Can't be used with archive_format.
+	Checksum string `protobuf:"bytes,5,opt,name=checksum,proto3" json:"checksum,omitempty"`
+	// Hash algorithm of the checksum (the same as in common.FileMetadata), only "sha256" is supported.
+	// Required if checksum is specified.
+	ChecksumType string `protobuf:"bytes,6,opt,name=checksum_type,json=checksumType,proto3" json:"checksum_type,omitempty"`
+	// Preconditions of the write, the same as corresponding HTTP headers. If any of them isn't satisfied,
+	// then content isn't written and FailedPrecondition error is returned. Can't be used with archive_format.
+	// Content is written only if ETag of the current file matches it, "*" matches any existing file.
+	IfMatch string `protobuf:"bytes,7,opt,name=if_match,json=ifMatch,proto3" json:"if_match,omitempty"`
+	// Only "*" is supported: content is written only if file doesn't exist yet.
+	// Can't be combined with other preconditions.
+	IfNoneMatch string `protobuf:"bytes,8,opt,name=if_none_match,json=ifNoneMatch,proto3" json:"if_none_match,omitempty"`
+	// Content is written only if file exists and wasn't modified after this time.
+	IfUnmodifiedSince *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=if_unmodified_since,json=ifUnmodifiedSince,proto3" json:"if_unmodified_since,omitempty"`
+	// If any of these is specified, then server sends progress messages (see UploadProgress) while content is written:
+	// each time when another progress_interval_bytes are committed and (or) every progress_interval_ms.
+	// Time based messages are sent even if nothing was committed since the previous one, so stalled uploads can be detected.
+	ProgressIntervalBytes int64 `protobuf:"varint,10,opt,name=progress_interval_bytes,json=progressIntervalBytes,proto3" json:"progress_interval_bytes,omitempty"`
+	ProgressIntervalMs    int64 `protobuf:"varint,11,opt,name=progress_interval_ms,json=progressIntervalMs,proto3" json:"progress_interval_ms,omitempty"`
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
+}
+
+func (x *FileContentHeader) Reset() {
+	*x = FileContentHeader{}
+	mi := &file_services_file_repository_types_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FileContentHeader) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FileContentHeader) ProtoMessage() {}
+
+func (x *FileContentHeader) ProtoReflect() protoreflect.Message {
+	mi := &file_services_file_repository_types_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FileContentHeader.ProtoReflect.Descriptor instead.
+func (*FileContentHeader) Descriptor() ([]byte, []int) {
+	return file_services_file_repository_types_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *FileContentHeader) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *FileContentHeader) GetBucket() string {
+	if x != nil {
+		return x.Bucket
+	}
+	return ""
+}
+
+func (x *FileContentHeader) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *FileContentHeader) GetArchiveFormat() ArchiveFormat {
+	if x != nil {
+		return x.ArchiveFormat
+	}
+	return ArchiveFormat_ARCHIVE_FORMAT_NONE
+}
+
+func (x *FileContentHeader) GetChecksum() string {
+	if x != nil {
+		return x.Checksum
+	}
+	return ""
+}
+
+func (x *FileContentHeader) GetChecksumType() string {
+	if x != nil {
+		return x.ChecksumType
+	}
+	return ""
+}
+
+func (x *FileContentHeader) GetIfMatch() string {
+	if x != nil {
+		return x.IfMatch
+	}
+	return ""
+}
+
+func (x *FileContentHeader) GetIfNoneMatch() string {
+	if x != nil {
+		return x.IfNoneMatch
+	}
+	return ""
+}
+
+func (x *FileContentHeader) GetIfUnmodifiedSince() *timestamppb.Timestamp {
+	if x != nil {
+		return x.IfUnmodifiedSince
+	}
+	return nil
+}
+
+func (x *FileContentHeader) GetProgressIntervalBytes() int64 {
+	if x != nil {
+		return x.ProgressIntervalBytes
+	}
+	return 0
+}
+
+func (x *FileContentHeader) GetProgressIntervalMs() int64 {
+	if x != nil {
+		return x.ProgressIntervalMs
+	}
+	return 0
+}
+
+type FileContentRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Data:
+	//
+	//	*FileContentRequest_Header
+	//	*FileContentRequest_Chunk
+	Data          isFileContentRequest_Data `protobuf_oneof:"data"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FileContentRequest) Reset() {
+	*x = FileContentRequest{}
+	mi := &file_services_file_repository_types_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FileContentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FileContentRequest) ProtoMessage() {}
+
+func (x *FileContentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_services_file_repository_types_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FileContentRequest.ProtoReflect.Descriptor instead.
+func (*FileContentRequest) Descriptor() ([]byte, []int) {
+	return file_services_file_repository_types_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *FileContentRequest) GetData() isFileContentRequest_Data {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *FileContentRequest) GetHeader() *FileContentHeader {
+	if x != nil {
+		if x, ok := x.Data.(*FileContentRequest_Header); ok {
+			return x.Header
+		}
+	}
+	return nil
+}
+
+func (x *FileContentRequest) GetChunk() []byte {
+	if x != nil {
+		if x, ok := x.Data.(*FileContentRequest_Chunk); ok {
+			return x.Chunk
+		}
+	}
+	return nil
+}
+
+type isFileContentRequest_Data interface {
+	isFileContentRequest_Data()
+}
+
+type FileContentRequest_Header struct {
+	Header *FileContentHeader `protobuf:"bytes,1,opt,name=header,proto3,oneof"`
+}
+
+type FileContentRequest_Chunk struct {
+	Chunk []byte `protobuf:"bytes,2,opt,name=chunk,proto3,oneof"`
+}
+
+func (*FileContentRequest_Header) isFileContentRequest_Data() {}
+
+func (*FileContentRequest_Chunk) isFileContentRequest_Data() {}
+
+type DeleteFilesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Paths         []string               `protobuf:"bytes,1,rep,name=paths,proto3" json:"paths,omitempty"`
+	Bucket        string                 `protobuf:"bytes,2,opt,name=bucket,proto3" json:"bucket,omitempty"`
+	Recursive     bool                   `protobuf:"varint,3,opt,name=recursive,proto3" json:"recursive,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteFilesRequest) Reset() {
+	*x = DeleteFilesRequest{}
+	mi := &file_services_file_repository_types_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteFilesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteFilesRequest) ProtoMessage() {}
+
+func (x *DeleteFilesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_services_file_repository_types_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteFilesRequest.ProtoReflect.Descriptor instead.
+func (*DeleteFilesRequest) Descriptor() ([]byte, []int) {
+	return file_services_file_repository_types_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *DeleteFilesRequest) GetPaths() []string {
+	if x != nil {
+		return x.Paths
+	}
+	return nil
+}
+
+func (x *DeleteFilesRequest) GetBucket() string {
+	if x != nil {
+		return x.Bucket
+	}
+	return ""
+}
+
+func (x *DeleteFilesRequest) GetRecursive() bool {
+	if x != nil {
+		return x.Recursive
+	}
+	return false
+}
+
+type DeletionFailure struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeletionFailure) Reset() {
+	*x = DeletionFailure{}
+	mi := &file_services_file_repository_types_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeletionFailure) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeletionFailure) ProtoMessage() {}
+
+func (x *DeletionFailure) ProtoReflect() protoreflect.Message {
+	mi := &file_services_file_repository_types_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeletionFailure.ProtoReflect.Descriptor instead.
+func (*DeletionFailure) Descriptor() ([]byte, []int) {
+	return file_services_file_repository_types_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *DeletionFailure) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *DeletionFailure) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type DeletionResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	DeletedCount  int64                  `protobuf:"varint,2,opt,name=deleted_count,json=deletedCount,proto3" json:"deleted_count,omitempty"`
+	Failures      []*DeletionFailure     `protobuf:"bytes,3,rep,name=failures,proto3" json:"failures,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeletionResult) Reset() {
+	*x = DeletionResult{}
+	mi := &file_services_file_repository_types_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeletionResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeletionResult) ProtoMessage() {}
+
+func (x *DeletionResult) ProtoReflect() protoreflect.Message {
+	mi := &file_services_file_repository_types_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeletionResult.ProtoReflect.Descriptor instead.
+func (*DeletionResult) Descriptor() ([]byte, []int) {
+	return file_services_file_repository_types_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *DeletionResult) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *DeletionResult) GetDeletedCount() int64 {
+	if x != nil {
+		return x.DeletedCount
+	}
+	return 0
+}
+
+func (x *DeletionResult) GetFailures() []*DeletionFailure {
+	if x != nil {
+		return x.Failures
+	}
+	return nil
+}
+
+type DeleteFilesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        int32                  `protobuf:"varint,1,opt,name=status,proto3" json:"status,omitempty"`
+	Results       []*DeletionResult      `protobuf:"bytes,2,rep,name=results,proto3" json:"results,omitempty"`
+	DeletedCount  int64                  `protobuf:"varint,3,opt,name=deleted_count,json=deletedCount,proto3" json:"deleted_count,omitempty"`
+	Failures      []*DeletionFailure     `protobuf:"bytes,4,rep,name=failures,proto3" json:"failures,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteFilesResponse) Reset() {
+	*x = DeleteFilesResponse{}
+	mi := &file_services_file_repository_types_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteFilesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteFilesResponse) ProtoMessage() {}
+
+func (x *DeleteFilesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_services_file_repository_types_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteFilesResponse.ProtoReflect.Descriptor instead.
+func (*DeleteFilesResponse) Descriptor() ([]byte, []int) {
+	return file_services_file_repository_types_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *DeleteFilesResponse) GetStatus() int32 {
+	if x != nil {
+		return x.Status
+	}
+	return 0
+}
+
+func (x *DeleteFilesResponse) GetResults() []*DeletionResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+func (x *DeleteFilesResponse) GetDeletedCount() int64 {
+	if x != nil {
+		return x.DeletedCount
+	}
+	return 0
+}
+
+func (x *DeleteFilesResponse) GetFailures() []*DeletionFailure {
+	if x != nil {
+		return x.Failures
+	}
+	return nil
+}
+
+// If source_path is a directory, then destination_path must be a directory as well,
+// in that case all files under source_path will be relocated preserving their relative paths.
+type RelocateFilesRequest struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	SourceBucket string                 `protobuf:"bytes,1,opt,name=source_bucket,json=sourceBucket,proto3" json:"source_bucket,omitempty"`
+	SourcePath   string                 `protobuf:"bytes,2,opt,name=source_path,json=sourcePath,proto3" json:"source_path,omitempty"`
+	// If empty, then source_bucket is used
+	DestinationBucket string        `protobuf:"bytes,3,opt,name=destination_bucket,json=destinationBucket,proto3" json:"destination_bucket,omitempty"`
+	DestinationPath   string        `protobuf:"bytes,4,opt,name=destination_path,json=destinationPath,proto3" json:"destination_path,omitempty"`
+	Overwrite         OverwriteMode `protobuf:"varint,5,opt,name=overwrite,proto3,enum=file_repository.OverwriteMode" json:"overwrite,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *RelocateFilesRequest) Reset() {
+	*x = RelocateFilesRequest{}
+	mi := &file_services_file_repository_types_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RelocateFilesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RelocateFilesRequest) ProtoMessage() {}
+
+func (x *RelocateFilesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_services_file_repository_types_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RelocateFilesRequest.ProtoReflect.Descriptor instead.
+func (*RelocateFilesRequest) Descriptor() ([]byte, []int) {
+	return file_services_file_repository_types_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *RelocateFilesRequest) GetSourceBucket() string {
+	if x != nil {
+		return x.SourceBucket
+	}
+	return ""
+}
+
+func (x *RelocateFilesRequest) GetSourcePath() string {
+	if x != nil {
+		return x.SourcePath
+	}
+	return ""
+}
+
+func (x *RelocateFilesRequest) GetDestinationBucket() string {
+	if x != nil {
+		return x.DestinationBucket
+	}
+	return ""
+}
+
+func (x *RelocateFilesRequest) GetDestinationPath() string {
+	if x != nil {
+		return x.DestinationPath
+	}
+	return ""
+}
+
+func (x *RelocateFilesRequest) GetOverwrite() OverwriteMode {
+	if x != nil {
+		return x.Overwrite
+	}
+	return OverwriteMode_OVERWRITE_MODE_FAIL
+}
+
+type RelocatedFile struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	SourcePath      string                 `protobuf:"bytes,1,opt,name=source_path,json=sourcePath,proto3" json:"source_path,omitempty"`
+	DestinationPath string                 `protobuf:"bytes,2,opt,name=destination_path,json=destinationPath,proto3" json:"destination_path,omitempty"`
+	Skipped         bool                   `protobuf:"varint,3,opt,name=skipped,proto3" json:"skipped,omitempty"`
+	// Empty if file was relocated (or skipped) successfully
+	Error         string `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RelocatedFile) Reset() {
+	*x = RelocatedFile{}
+	mi := &file_services_file_repository_types_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RelocatedFile) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RelocatedFile) ProtoMessage() {}
+
+func (x *RelocatedFile) ProtoReflect() protoreflect.Message {
+	mi := &file_services_file_repository_types_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RelocatedFile.ProtoReflect.Descriptor instead.
+func (*RelocatedFile) Descriptor() ([]byte, []int) {
+	return file_services_file_repository_types_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *RelocatedFile) GetSourcePath() string {
+	if x != nil {
+		return x.SourcePath
+	}
+	return ""
+}
+
+func (x *RelocatedFile) GetDestinationPath() string {
+	if x != nil {
+		return x.DestinationPath
+	}
+	return ""
+}
+
+func (x *RelocatedFile) GetSkipped() bool {
+	if x != nil {
+		return x.Skipped
+	}
+	return false
+}
+
+func (x *RelocatedFile) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type RelocateFilesResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Status         int32                  `protobuf:"varint,1,opt,name=status,proto3" json:"status,omitempty"`
+	Files          []*RelocatedFile       `protobuf:"bytes,2,rep,name=files,proto3" json:"files,omitempty"`
+	RelocatedCount int64                  `protobuf:"varint,3,opt,name=relocated_count,json=relocatedCount,proto3" json:"relocated_count,omitempty"`
+	SkippedCount   int64                  `protobuf:"varint,4,opt,name=skipped_count,json=skippedCount,proto3" json:"skipped_count,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *RelocateFilesResponse) Reset() {
+	*x = RelocateFilesResponse{}
+	mi := &file_services_file_repository_types_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RelocateFilesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RelocateFilesResponse) ProtoMessage() {}
+
+func (x *RelocateFilesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_services_file_repository_types_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RelocateFilesResponse.ProtoReflect.Descriptor instead.
+func (*RelocateFilesResponse) Descriptor() ([]byte, []int) {
+	return file_services_file_repository_types_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *RelocateFilesResponse) GetStatus() int32 {
+	if x != nil {
+		return x.Status
+	}
+	return 0
+}
+
+func (x *RelocateFilesResponse) GetFiles() []*RelocatedFile {
+	if x != nil {
+		return x.Files
+	}
+	return nil
+}
+
+func (x *RelocateFilesResponse) GetRelocatedCount() int64 {
+	if x != nil {
+		return x.RelocatedCount
+	}
+	return 0
+}
+
+func (x *RelocateFilesResponse) GetSkippedCount() int64 {
+	if x != nil {
+		return x.SkippedCount
+	}
+	return 0
+}
+
+type FileChunk struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Content    []byte                 `protobuf:"bytes,1,opt,name=content,proto3" json:"content,omitempty"`
+	ChunkIndex int64                  `protobuf:"varint,2,opt,name=chunk_index,json=chunkIndex,proto3" json:"chunk_index,omitempty"`
+	// -1 if size is unknown beforehand (e.g. directory archive)
+	TotalSize int64 `protobuf:"varint,3,opt,name=total_size,json=totalSize,proto3" json:"total_size,omitempty"`
+	// Inclusive range of bytes which is actually served (the same as in HTTP Content-Range).
+	// For empty files range_end is -1, for directory archives both are 0.
+	RangeStart int64 `protobuf:"varint,4,opt,name=range_start,json=rangeStart,proto3" json:"range_start,omitempty"`
+	RangeEnd   int64 `protobuf:"varint,5,opt,name=range_end,json=rangeEnd,proto3" json:"range_end,omitempty"`
+	// Set only in the last chunk: digest of the whole file (not only of the served range)
+	// which was verified on upload. Empty if it's unknown (e.g. for directory archives).
+	Checksum      string `protobuf:"bytes,6,opt,name=checksum,proto3" json:"checksum,omitempty"`
+	ChecksumType  string `protobuf:"bytes,7,opt,name=checksum_type,json=checksumType,proto3" json:"checksum_type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FileChunk) Reset() {
+	*x = FileChunk{}
+	mi := &file_services_file_repository_types_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FileChunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FileChunk) ProtoMessage() {}
+
+func (x *FileChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_services_file_repository_types_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FileChunk.ProtoReflect.Descriptor instead.
+func (*FileChunk) Descriptor() ([]byte, []int) {
+	return file_services_file_repository_types_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *FileChunk) GetContent() []byte {
+	if x != nil {
+		return x.Content
+	}
+	return nil
+}
+
+func (x *FileChunk) GetChunkIndex() int64 {
+	if x != nil {
+		return x.ChunkIndex
+	}
+	return 0
+}
+
+func (x *FileChunk) GetTotalSize() int64 {
+	if x != nil {
+		return x.TotalSize
+	}
+	return 0
+}
+
+func (x *FileChunk) GetRangeStart() int64 {
+	if x != nil {
+		return x.RangeStart
+	}
+	return 0
+}
+
+func (x *FileChunk) GetRangeEnd() int64 {
+	if x != nil {
+		return x.RangeEnd
+	}
+	return 0
+}
+
+func (x *FileChunk) GetChecksum() string {
+	if x != nil {
+		return x.Checksum
+	}
+	return ""
+}
+
+func (x *FileChunk) GetChecksumType() string {
+	if x != nil {
+		return x.ChecksumType
+	}
+	return ""
+}
+
+type ExtractedEntry struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Path        string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Size        int64                  `protobuf:"varint,2,opt,name=size,proto3" json:"size,omitempty"`
+	IsDirectory bool                   `protobuf:"varint,3,opt,name=is_directory,json=isDirectory,proto3" json:"is_directory,omitempty"`
+	// Empty if entry was extracted successfully
+	Error         string `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExtractedEntry) Reset() {
+	*x = ExtractedEntry{}
+	mi := &file_services_file_repository_types_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExtractedEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExtractedEntry) ProtoMessage() {}
+
+func (x *ExtractedEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_services_file_repository_types_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExtractedEntry.ProtoReflect.Descriptor instead.
+func (*ExtractedEntry) Descriptor() ([]byte, []int) {
+	return file_services_file_repository_types_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *ExtractedEntry) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *ExtractedEntry) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *ExtractedEntry) GetIsDirectory() bool {
+	if x != nil {
+		return x.IsDirectory
+	}
+	return false
+}
+
+func (x *ExtractedEntry) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+// Final response is sent only on success, so its status is 2xx HTTP status code (207 if operation succeeded only partially).
+// Failures are reported as gRPC statuses (see ErrorReason).
+type StatusResponse struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Status  int32                  `protobuf:"varint,1,opt,name=status,proto3" json:"status,omitempty"`
+	Message string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	// Filled only if archive was uploaded
+	Entries []*ExtractedEntry `protobuf:"bytes,3,rep,name=entries,proto3" json:"entries,omitempty"`
+	// Digest of the received content computed by the server (empty for archives)
+	Checksum     string `protobuf:"bytes,4,opt,name=checksum,proto3" json:"checksum,omitempty"`
+	ChecksumType string `protobuf:"bytes,5,opt,name=checksum_type,json=checksumType,proto3" json:"checksum_type,omitempty"`
+	// ETag of the written file (empty for archives)
+	Etag string `protobuf:"bytes,6,opt,name=etag,proto3" json:"etag,omitempty"`
+	// Set in progress messages of uploads (their status is 102) and in the final message of the upload
+	Progress      *UploadProgress `protobuf:"bytes,7,opt,name=progress,proto3" json:"progress,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StatusResponse) Reset() {
+	*x = StatusResponse{}
+	mi := &file_services_file_repository_types_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatusResponse) ProtoMessage() {}
+
+func (x *StatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_services_file_repository_types_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatusResponse.ProtoReflect.Descriptor instead.
+func (*StatusResponse) Descriptor() ([]byte, []int) {
+	return file_services_file_repository_types_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *StatusResponse) GetStatus() int32 {
+	if x != nil {
+		return x.Status
+	}
+	return 0
+}
+
+func (x *StatusResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *StatusResponse) GetEntries() []*ExtractedEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+func (x *StatusResponse) GetChecksum() string {
+	if x != nil {
+		return x.Checksum
+	}
+	return ""
+}
+
+func (x *StatusResponse) GetChecksumType() string {
+	if x != nil {
+		return x.ChecksumType
+	}
+	return ""
+}
+
+func (x *StatusResponse) GetEtag() string {
+	if x != nil {
+		return x.Etag
+	}
+	return ""
+}
+
+func (x *StatusResponse) GetProgress() *UploadProgress {
+	if x != nil {
+		return x.Progress
+	}
+	return nil
+}
+
+type UploadProgress struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Bytes of the content received and passed to the storage so far
+	CommittedBytes int64 `protobuf:"varint,1,opt,name=committed_bytes,json=committedBytes,proto3" json:"committed_bytes,omitempty"`
+	// Declared size of the content
+	TotalBytes    int64 `protobuf:"varint,2,opt,name=total_bytes,json=totalBytes,proto3" json:"total_bytes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UploadProgress) Reset() {
+	*x = UploadProgress{}
+	mi := &file_services_file_repository_types_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UploadProgress) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UploadProgress) ProtoMessage() {}
+
+func (x *UploadProgress) ProtoReflect() protoreflect.Message {
+	mi := &file_services_file_repository_types_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UploadProgress.ProtoReflect.Descriptor instead.
+func (*UploadProgress) Descriptor() ([]byte, []int) {
+	return file_services_file_repository_types_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *UploadProgress) GetCommittedBytes() int64 {
+	if x != nil {
+		return x.CommittedBytes
+	}
+	return 0
+}
+
+func (x *UploadProgress) GetTotalBytes() int64 {
+	if x != nil {
+		return x.TotalBytes
+	}
+	return 0
+}
+
+type InitiateUploadRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Bucket        string                 `protobuf:"bytes,2,opt,name=bucket,proto3" json:"bucket,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InitiateUploadRequest) Reset() {
+	*x = InitiateUploadRequest{}
+	mi := &file_services_file_repository_types_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InitiateUploadRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InitiateUploadRequest) ProtoMessage() {}
+
+func (x *InitiateUploadRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_services_file_repository_types_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InitiateUploadRequest.ProtoReflect.Descriptor instead.
+func (*InitiateUploadRequest) Descriptor() ([]byte, []int) {
+	return file_services_file_repository_types_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *InitiateUploadRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *InitiateUploadRequest) GetBucket() string {
+	if x != nil {
+		return x.Bucket
+	}
+	return ""
+}
+
+type InitiateUploadResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UploadId      string                 `protobuf:"bytes,1,opt,name=upload_id,json=uploadId,proto3" json:"upload_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InitiateUploadResponse) Reset() {
+	*x = InitiateUploadResponse{}
+	mi := &file_services_file_repository_types_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InitiateUploadResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InitiateUploadResponse) ProtoMessage() {}
+
+func (x *InitiateUploadResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_services_file_repository_types_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InitiateUploadResponse.ProtoReflect.Descriptor instead.
+func (*InitiateUploadResponse) Descriptor() ([]byte, []int) {
+	return file_services_file_repository_types_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *InitiateUploadResponse) GetUploadId() string {
+	if x != nil {
+		return x.UploadId
+	}
+	return ""
+}
+
+// Identifies upload session created by InitiateUpload
+type UploadSessionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Bucket        string                 `protobuf:"bytes,2,opt,name=bucket,proto3" json:"bucket,omitempty"`
+	UploadId      string                 `protobuf:"bytes,3,opt,name=upload_id,json=uploadId,proto3" json:"upload_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UploadSessionRequest) Reset() {
+	*x = UploadSessionRequest{}
+	mi := &file_services_file_repository_types_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UploadSessionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UploadSessionRequest) ProtoMessage() {}
+
+func (x *UploadSessionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_services_file_repository_types_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UploadSessionRequest.ProtoReflect.Descriptor instead.
+func (*UploadSessionRequest) Descriptor() ([]byte, []int) {
+	return file_services_file_repository_types_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *UploadSessionRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *UploadSessionRequest) GetBucket() string {
+	if x != nil {
+		return x.Bucket
+	}
+	return ""
+}
+
+func (x *UploadSessionRequest) GetUploadId() string {
+	if x != nil {
+		return x.UploadId
+	}
+	return ""
+}
+
+type UploadPartHeader struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Path     string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Bucket   string                 `protobuf:"bytes,2,opt,name=bucket,proto3" json:"bucket,omitempty"`
+	UploadId string                 `protobuf:"bytes,3,opt,name=upload_id,json=uploadId,proto3" json:"upload_id,omitempty"`
+	// From 1 to 10000. Uploading part with the same number again will replace previous one.
+	PartNumber    int32 `protobuf:"varint,4,opt,name=part_number,json=partNumber,proto3" json:"part_number,omitempty"`
+	Size          int64 `protobuf:"varint,5,opt,name=size,proto3" json:"size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UploadPartHeader) Reset() {
+	*x = UploadPartHeader{}
+	mi := &file_services_file_repository_types_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UploadPartHeader) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UploadPartHeader) ProtoMessage() {}
+
+func (x *UploadPartHeader) ProtoReflect() protoreflect.Message {
+	mi := &file_services_file_repository_types_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UploadPartHeader.ProtoReflect.Descriptor instead.
+func (*UploadPartHeader) Descriptor() ([]byte, []int) {
+	return file_services_file_repository_types_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *UploadPartHeader) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *UploadPartHeader) GetBucket() string {
+	if x != nil {
+		return x.Bucket
+	}
+	return ""
+}
+
+func (x *UploadPartHeader) GetUploadId() string {
+	if x != nil {
+		return x.UploadId
+	}
+	return ""
+}
+
+func (x *UploadPartHeader) GetPartNumber() int32 {
+	if x != nil {
+		return x.PartNumber
+	}
+	return 0
+}
+
+func (x *UploadPartHeader) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+type UploadPartRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Data:
+	//
+	//	*UploadPartRequest_Header
+	//	*UploadPartRequest_Chunk
+	Data          isUploadPartRequest_Data `protobuf_oneof:"data"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UploadPartRequest) Reset() {
+	*x = UploadPartRequest{}
+	mi := &file_services_file_repository_types_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UploadPartRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UploadPartRequest) ProtoMessage() {}
+
+func (x *UploadPartRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_services_file_repository_types_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UploadPartRequest.ProtoReflect.Descriptor instead.
+func (*UploadPartRequest) Descriptor() ([]byte, []int) {
+	return file_services_file_repository_types_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *UploadPartRequest) GetData() isUploadPartRequest_Data {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *UploadPartRequest) GetHeader() *UploadPartHeader {
+	if x != nil {
+		if x, ok := x.Data.(*UploadPartRequest_Header); ok {
+			return x.Header
+		}
+	}
+	return nil
+}
+
+func (x *UploadPartRequest) GetChunk() []byte {
+	if x != nil {
+		if x, ok := x.Data.(*UploadPartRequest_Chunk); ok {
+			return x.Chunk
+		}
+	}
+	return nil
+}
+
+type isUploadPartRequest_Data interface {
+	isUploadPartRequest_Data()
+}
+
+type UploadPartRequest_Header struct {
+	Header *UploadPartHeader `protobuf:"bytes,1,opt,name=header,proto3,oneof"`
+}
+
+type UploadPartRequest_Chunk struct {
+	Chunk []byte `protobuf:"bytes,2,opt,name=chunk,proto3,oneof"`
+}
+
+func (*UploadPartRequest_Header) isUploadPartRequest_Data() {}
+
+func (*UploadPartRequest_Chunk) isUploadPartRequest_Data() {}
+
+type UploadPart struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PartNumber    int32                  `protobuf:"varint,1,opt,name=part_number,json=partNumber,proto3" json:"part_number,omitempty"`
+	Etag          string                 `protobuf:"bytes,2,opt,name=etag,proto3" json:"etag,omitempty"`
+	Size          int64                  `protobuf:"varint,3,opt,name=size,proto3" json:"size,omitempty"`
+	LastModified  *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=last_modified,json=lastModified,proto3" json:"last_modified,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UploadPart) Reset() {
+	*x = UploadPart{}
+	mi := &file_services_file_repository_types_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UploadPart) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UploadPart) ProtoMessage() {}
+
+func (x *UploadPart) ProtoReflect() protoreflect.Message {
+	mi := &file_services_file_repository_types_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UploadPart.ProtoReflect.Descriptor instead.
+func (*UploadPart) Descriptor() ([]byte, []int) {
+	return file_services_file_repository_types_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *UploadPart) GetPartNumber() int32 {
+	if x != nil {
+		return x.PartNumber
+	}
+	return 0
+}
+
+func (x *UploadPart) GetEtag() string {
+	if x != nil {
+		return x.Etag
+	}
+	return ""
+}
+
+func (x *UploadPart) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *UploadPart) GetLastModified() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastModified
+	}
+	return nil
+}
+
+type UploadPartResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Part          *UploadPart            `protobuf:"bytes,1,opt,name=part,proto3" json:"part,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UploadPartResponse) Reset() {
+	*x = UploadPartResponse{}
+	mi := &file_services_file_repository_types_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UploadPartResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UploadPartResponse) ProtoMessage() {}
+
+func (x *UploadPartResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_services_file_repository_types_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UploadPartResponse.ProtoReflect.Descriptor instead.
+func (*UploadPartResponse) Descriptor() ([]byte, []int) {
+	return file_services_file_repository_types_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *UploadPartResponse) GetPart() *UploadPart {
+	if x != nil {
+		return x.Part
+	}
+	return nil
+}
+
+type ListUploadedPartsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Parts         []*UploadPart          `protobuf:"bytes,1,rep,name=parts,proto3" json:"parts,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListUploadedPartsResponse) Reset() {
+	*x = ListUploadedPartsResponse{}
+	mi := &file_services_file_repository_types_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListUploadedPartsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListUploadedPartsResponse) ProtoMessage() {}
+
+func (x *ListUploadedPartsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_services_file_repository_types_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListUploadedPartsResponse.ProtoReflect.Descriptor instead.
+func (*ListUploadedPartsResponse) Descriptor() ([]byte, []int) {
+	return file_services_file_repository_types_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *ListUploadedPartsResponse) GetParts() []*UploadPart {
+	if x != nil {
+		return x.Parts
+	}
+	return nil
+}
+
+type CreateBucketRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateBucketRequest) Reset() {
+	*x = CreateBucketRequest{}
+	mi := &file_services_file_repository_types_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateBucketRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateBucketRequest) ProtoMessage() {}
+
+func (x *CreateBucketRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_services_file_repository_types_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateBucketRequest.ProtoReflect.Descriptor instead.
+func (*CreateBucketRequest) Descriptor() ([]byte, []int) {
+	return file_services_file_repository_types_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *CreateBucketRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type DeleteBucketRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Name  string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// If true, then bucket will be deleted even if it isn't empty
+	Force         bool `protobuf:"varint,2,opt,name=force,proto3" json:"force,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteBucketRequest) Reset() {
+	*x = DeleteBucketRequest{}
+	mi := &file_services_file_repository_types_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteBucketRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteBucketRequest) ProtoMessage() {}
+
+func (x *DeleteBucketRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_services_file_repository_types_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteBucketRequest.ProtoReflect.Descriptor instead.
+func (*DeleteBucketRequest) Descriptor() ([]byte, []int) {
+	return file_services_file_repository_types_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *DeleteBucketRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *DeleteBucketRequest) GetForce() bool {
+	if x != nil {
+		return x.Force
+	}
+	return false
+}
+
+type ListBucketsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListBucketsRequest) Reset() {
+	*x = ListBucketsRequest{}
+	mi := &file_services_file_repository_types_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListBucketsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListBucketsRequest) ProtoMessage() {}
+
+func (x *ListBucketsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_services_file_repository_types_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListBucketsRequest.ProtoReflect.Descriptor instead.
+func (*ListBucketsRequest) Descriptor() ([]byte, []int) {
+	return file_services_file_repository_types_proto_rawDescGZIP(), []int{35}
+}
+
+type Bucket struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	CreationDate  *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=creation_date,json=creationDate,proto3" json:"creation_date,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Bucket) Reset() {
+	*x = Bucket{}
+	mi := &file_services_file_repository_types_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Bucket) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Bucket) ProtoMessage() {}
+
+func (x *Bucket) ProtoReflect() protoreflect.Message {
+	mi := &file_services_file_repository_types_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Bucket.ProtoReflect.Descriptor instead.
+func (*Bucket) Descriptor() ([]byte, []int) {
+	return file_services_file_repository_types_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *Bucket) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Bucket) GetCreationDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreationDate
+	}
+	return nil
+}
+
+type ListBucketsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Buckets       []*Bucket              `protobuf:"bytes,1,rep,name=buckets,proto3" json:"buckets,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListBucketsResponse) Reset() {
+	*x = ListBucketsResponse{}
+	mi := &file_services_file_repository_types_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListBucketsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListBucketsResponse) ProtoMessage() {}
+
+func (x *ListBucketsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_services_file_repository_types_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListBucketsResponse.ProtoReflect.Descriptor instead.
+func (*ListBucketsResponse) Descriptor() ([]byte, []int) {
+	return file_services_file_repository_types_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *ListBucketsResponse) GetBuckets() []*Bucket {
+	if x != nil {
+		return x.Buckets
+	}
+	return nil
+}
+
+type GetBucketInfoRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetBucketInfoRequest) Reset() {
+	*x = GetBucketInfoRequest{}
+	mi := &file_services_file_repository_types_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetBucketInfoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBucketInfoRequest) ProtoMessage() {}
+
+func (x *GetBucketInfoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_services_file_repository_types_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBucketInfoRequest.ProtoReflect.Descriptor instead.
+func (*GetBucketInfoRequest) Descriptor() ([]byte, []int) {
+	return file_services_file_repository_types_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *GetBucketInfoRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type BucketInfo struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	Name         string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	CreationDate *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=creation_date,json=creationDate,proto3" json:"creation_date,omitempty"`
+	// Directory markers are counted as well
+	ObjectCount       int64 `protobuf:"varint,3,opt,name=object_count,json=objectCount,proto3" json:"object_count,omitempty"`
+	TotalSize         int64 `protobuf:"varint,4,opt,name=total_size,json=totalSize,proto3" json:"total_size,omitempty"`
+	VersioningEnabled bool  `protobuf:"varint,5,opt,name=versioning_enabled,json=versioningEnabled,proto3" json:"versioning_enabled,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *BucketInfo) Reset() {
+	*x = BucketInfo{}
+	mi := &file_services_file_repository_types_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BucketInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BucketInfo) ProtoMessage() {}
+
+func (x *BucketInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_services_file_repository_types_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BucketInfo.ProtoReflect.Descriptor instead.
+func (*BucketInfo) Descriptor() ([]byte, []int) {
+	return file_services_file_repository_types_proto_rawDescGZIP(), []int{39}
 }
 
-func (x *HealthCheckRequest) GetService() string {
+func (x *BucketInfo) GetName() string {
 	if x != nil {
-		return x.Service
+		return x.Name
 	}
 	return ""
 }
 
-type HealthCheckResponse struct {
+func (x *BucketInfo) GetCreationDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreationDate
+	}
+	return nil
+}
+
+func (x *BucketInfo) GetObjectCount() int64 {
+	if x != nil {
+		return x.ObjectCount
+	}
+	return 0
+}
+
+func (x *BucketInfo) GetTotalSize() int64 {
+	if x != nil {
+		return x.TotalSize
+	}
+	return 0
+}
+
+func (x *BucketInfo) GetVersioningEnabled() bool {
+	if x != nil {
+		return x.VersioningEnabled
+	}
+	return false
+}
+
+// Exactly one of the bucket and owner must be specified
+type GetQuotaUsageRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Status        string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
-	Timestamp     string                 `protobuf:"bytes,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Bucket        string                 `protobuf:"bytes,1,opt,name=bucket,proto3" json:"bucket,omitempty"`
+	Owner         string                 `protobuf:"bytes,2,opt,name=owner,proto3" json:"owner,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *HealthCheckResponse) Reset() {
-	*x = HealthCheckResponse{}
-	mi := &file_services_file_repository_types_proto_msgTypes[1]
+func (x *GetQuotaUsageRequest) Reset() {
+	*x = GetQuotaUsageRequest{}
+	mi := &file_services_file_repository_types_proto_msgTypes[40]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *HealthCheckResponse) String() string {
+func (x *GetQuotaUsageRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*HealthCheckResponse) ProtoMessage() {}
+func (*GetQuotaUsageRequest) ProtoMessage() {}
 
-func (x *HealthCheckResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_services_file_repository_types_proto_msgTypes[1]
+func (x *GetQuotaUsageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_services_file_repository_types_proto_msgTypes[40]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -98,49 +2958,52 @@ func (x *HealthCheckResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use HealthCheckResponse.ProtoReflect.Descriptor instead.
-func (*HealthCheckResponse) Descriptor() ([]byte, []int) {
-	return file_services_file_repository_types_proto_rawDescGZIP(), []int{1}
+// Deprecated: Use GetQuotaUsageRequest.ProtoReflect.Descriptor instead.
+func (*GetQuotaUsageRequest) Descriptor() ([]byte, []int) {
+	return file_services_file_repository_types_proto_rawDescGZIP(), []int{40}
 }
 
-func (x *HealthCheckResponse) GetStatus() string {
+func (x *GetQuotaUsageRequest) GetBucket() string {
 	if x != nil {
-		return x.Status
+		return x.Bucket
 	}
 	return ""
 }
 
-func (x *HealthCheckResponse) GetTimestamp() string {
+func (x *GetQuotaUsageRequest) GetOwner() string {
 	if x != nil {
-		return x.Timestamp
+		return x.Owner
 	}
 	return ""
 }
 
-type GetFileByPathRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
-	Bucket        string                 `protobuf:"bytes,2,opt,name=bucket,proto3" json:"bucket,omitempty"`
-	ChunkSize     int32                  `protobuf:"varint,3,opt,name=chunk_size,json=chunkSize,proto3" json:"chunk_size,omitempty"`
+// Previous versions of the files and directory markers aren't counted
+type QuotaUsage struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	UsedBytes   int64                  `protobuf:"varint,1,opt,name=used_bytes,json=usedBytes,proto3" json:"used_bytes,omitempty"`
+	UsedObjects int64                  `protobuf:"varint,2,opt,name=used_objects,json=usedObjects,proto3" json:"used_objects,omitempty"`
+	// 0 means that there is no limit
+	MaxBytes      int64 `protobuf:"varint,3,opt,name=max_bytes,json=maxBytes,proto3" json:"max_bytes,omitempty"`
+	MaxObjects    int64 `protobuf:"varint,4,opt,name=max_objects,json=maxObjects,proto3" json:"max_objects,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetFileByPathRequest) Reset() {
-	*x = GetFileByPathRequest{}
-	mi := &file_services_file_repository_types_proto_msgTypes[2]
+func (x *QuotaUsage) Reset() {
+	*x = QuotaUsage{}
+	mi := &file_services_file_repository_types_proto_msgTypes[41]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetFileByPathRequest) String() string {
+func (x *QuotaUsage) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetFileByPathRequest) ProtoMessage() {}
+func (*QuotaUsage) ProtoMessage() {}
 
-func (x *GetFileByPathRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_services_file_repository_types_proto_msgTypes[2]
+func (x *QuotaUsage) ProtoReflect() protoreflect.Message {
+	mi := &file_services_file_repository_types_proto_msgTypes[41]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -151,55 +3014,63 @@ func (x *GetFileByPathRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetFileByPathRequest.ProtoReflect.Descriptor instead.
-func (*GetFileByPathRequest) Descriptor() ([]byte, []int) {
-	return file_services_file_repository_types_proto_rawDescGZIP(), []int{2}
+// Deprecated: Use QuotaUsage.ProtoReflect.Descriptor instead.
+func (*QuotaUsage) Descriptor() ([]byte, []int) {
+	return file_services_file_repository_types_proto_rawDescGZIP(), []int{41}
 }
 
-func (x *GetFileByPathRequest) GetPath() string {
+func (x *QuotaUsage) GetUsedBytes() int64 {
 	if x != nil {
-		return x.Path
+		return x.UsedBytes
 	}
-	return ""
+	return 0
 }
 
-func (x *GetFileByPathRequest) GetBucket() string {
+func (x *QuotaUsage) GetUsedObjects() int64 {
 	if x != nil {
-		return x.Bucket
+		return x.UsedObjects
 	}
-	return ""
+	return 0
 }
 
-func (x *GetFileByPathRequest) GetChunkSize() int32 {
+func (x *QuotaUsage) GetMaxBytes() int64 {
 	if x != nil {
-		return x.ChunkSize
+		return x.MaxBytes
 	}
 	return 0
 }
 
-type MkdirRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
-	Bucket        string                 `protobuf:"bytes,2,opt,name=bucket,proto3" json:"bucket,omitempty"`
+func (x *QuotaUsage) GetMaxObjects() int64 {
+	if x != nil {
+		return x.MaxObjects
+	}
+	return 0
+}
+
+type SetBucketVersioningRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Name  string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// Disabling versioning doesn't delete existing versions
+	Enabled       bool `protobuf:"varint,2,opt,name=enabled,proto3" json:"enabled,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *MkdirRequest) Reset() {
-	*x = MkdirRequest{}
-	mi := &file_services_file_repository_types_proto_msgTypes[3]
+func (x *SetBucketVersioningRequest) Reset() {
+	*x = SetBucketVersioningRequest{}
+	mi := &file_services_file_repository_types_proto_msgTypes[42]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *MkdirRequest) String() string {
+func (x *SetBucketVersioningRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*MkdirRequest) ProtoMessage() {}
+func (*SetBucketVersioningRequest) ProtoMessage() {}
 
-func (x *MkdirRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_services_file_repository_types_proto_msgTypes[3]
+func (x *SetBucketVersioningRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_services_file_repository_types_proto_msgTypes[42]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -210,49 +3081,48 @@ func (x *MkdirRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use MkdirRequest.ProtoReflect.Descriptor instead.
-func (*MkdirRequest) Descriptor() ([]byte, []int) {
-	return file_services_file_repository_types_proto_rawDescGZIP(), []int{3}
+// Deprecated: Use SetBucketVersioningRequest.ProtoReflect.Descriptor instead.
+func (*SetBucketVersioningRequest) Descriptor() ([]byte, []int) {
+	return file_services_file_repository_types_proto_rawDescGZIP(), []int{42}
 }
 
-func (x *MkdirRequest) GetPath() string {
+func (x *SetBucketVersioningRequest) GetName() string {
 	if x != nil {
-		return x.Path
+		return x.Name
 	}
 	return ""
 }
 
-func (x *MkdirRequest) GetBucket() string {
+func (x *SetBucketVersioningRequest) GetEnabled() bool {
 	if x != nil {
-		return x.Bucket
+		return x.Enabled
 	}
-	return ""
+	return false
 }
 
-type FileContentHeader struct {
+type ListFileVersionsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
 	Bucket        string                 `protobuf:"bytes,2,opt,name=bucket,proto3" json:"bucket,omitempty"`
-	Size          int64                  `protobuf:"varint,3,opt,name=size,proto3" json:"size,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *FileContentHeader) Reset() {
-	*x = FileContentHeader{}
-	mi := &file_services_file_repository_types_proto_msgTypes[4]
+func (x *ListFileVersionsRequest) Reset() {
+	*x = ListFileVersionsRequest{}
+	mi := &file_services_file_repository_types_proto_msgTypes[43]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *FileContentHeader) String() string {
+func (x *ListFileVersionsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*FileContentHeader) ProtoMessage() {}
+func (*ListFileVersionsRequest) ProtoMessage() {}
 
-func (x *FileContentHeader) ProtoReflect() protoreflect.Message {
-	mi := &file_services_file_repository_types_proto_msgTypes[4]
+func (x *ListFileVersionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_services_file_repository_types_proto_msgTypes[43]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -263,58 +3133,52 @@ func (x *FileContentHeader) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use FileContentHeader.ProtoReflect.Descriptor instead.
-func (*FileContentHeader) Descriptor() ([]byte, []int) {
-	return file_services_file_repository_types_proto_rawDescGZIP(), []int{4}
+// Deprecated: Use ListFileVersionsRequest.ProtoReflect.Descriptor instead.
+func (*ListFileVersionsRequest) Descriptor() ([]byte, []int) {
+	return file_services_file_repository_types_proto_rawDescGZIP(), []int{43}
 }
 
-func (x *FileContentHeader) GetPath() string {
+func (x *ListFileVersionsRequest) GetPath() string {
 	if x != nil {
 		return x.Path
 	}
 	return ""
 }
 
-func (x *FileContentHeader) GetBucket() string {
+func (x *ListFileVersionsRequest) GetBucket() string {
 	if x != nil {
 		return x.Bucket
 	}
 	return ""
 }
 
-func (x *FileContentHeader) GetSize() int64 {
-	if x != nil {
-		return x.Size
-	}
-	return 0
-}
-
-type FileContentRequest struct {
-	state protoimpl.MessageState `protogen:"open.v1"`
-	// Types that are valid to be assigned to Data:
-	//
-	//	*FileContentRequest_Header
-	//	*FileContentRequest_Chunk
-	Data          isFileContentRequest_Data `protobuf_oneof:"data"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+type FileVersion struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	VersionId      string                 `protobuf:"bytes,1,opt,name=version_id,json=versionId,proto3" json:"version_id,omitempty"`
+	Size           int64                  `protobuf:"varint,2,opt,name=size,proto3" json:"size,omitempty"`
+	LastModified   *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=last_modified,json=lastModified,proto3" json:"last_modified,omitempty"`
+	Etag           string                 `protobuf:"bytes,4,opt,name=etag,proto3" json:"etag,omitempty"`
+	IsLatest       bool                   `protobuf:"varint,5,opt,name=is_latest,json=isLatest,proto3" json:"is_latest,omitempty"`
+	IsDeleteMarker bool                   `protobuf:"varint,6,opt,name=is_delete_marker,json=isDeleteMarker,proto3" json:"is_delete_marker,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
-func (x *FileContentRequest) Reset() {
-	*x = FileContentRequest{}
-	mi := &file_services_file_repository_types_proto_msgTypes[5]
+func (x *FileVersion) Reset() {
+	*x = FileVersion{}
+	mi := &file_services_file_repository_types_proto_msgTypes[44]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *FileContentRequest) String() string {
+func (x *FileVersion) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*FileContentRequest) ProtoMessage() {}
+func (*FileVersion) ProtoMessage() {}
 
-func (x *FileContentRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_services_file_repository_types_proto_msgTypes[5]
+func (x *FileVersion) ProtoReflect() protoreflect.Message {
+	mi := &file_services_file_repository_types_proto_msgTypes[44]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -325,75 +3189,76 @@ func (x *FileContentRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use FileContentRequest.ProtoReflect.Descriptor instead.
-func (*FileContentRequest) Descriptor() ([]byte, []int) {
-	return file_services_file_repository_types_proto_rawDescGZIP(), []int{5}
+// Deprecated: Use FileVersion.ProtoReflect.Descriptor instead.
+func (*FileVersion) Descriptor() ([]byte, []int) {
+	return file_services_file_repository_types_proto_rawDescGZIP(), []int{44}
 }
 
-func (x *FileContentRequest) GetData() isFileContentRequest_Data {
+func (x *FileVersion) GetVersionId() string {
 	if x != nil {
-		return x.Data
+		return x.VersionId
 	}
-	return nil
+	return ""
 }
 
-func (x *FileContentRequest) GetHeader() *FileContentHeader {
+func (x *FileVersion) GetSize() int64 {
 	if x != nil {
-		if x, ok := x.Data.(*FileContentRequest_Header); ok {
-			return x.Header
-		}
+		return x.Size
 	}
-	return nil
+	return 0
 }
 
-func (x *FileContentRequest) GetChunk() []byte {
+func (x *FileVersion) GetLastModified() *timestamppb.Timestamp {
 	if x != nil {
-		if x, ok := x.Data.(*FileContentRequest_Chunk); ok {
-			return x.Chunk
-		}
+		return x.LastModified
 	}
 	return nil
 }
 
-type isFileContentRequest_Data interface {
-	isFileContentRequest_Data()
+func (x *FileVersion) GetEtag() string {
+	if x != nil {
+		return x.Etag
+	}
+	return ""
 }
 
-type FileContentRequest_Header struct {
-	Header *FileContentHeader `protobuf:"bytes,1,opt,name=header,proto3,oneof"`
+func (x *FileVersion) GetIsLatest() bool {
+	if x != nil {
+		return x.IsLatest
+	}
+	return false
 }
 
-type FileContentRequest_Chunk struct {
-	Chunk []byte `protobuf:"bytes,2,opt,name=chunk,proto3,oneof"`
+func (x *FileVersion) GetIsDeleteMarker() bool {
+	if x != nil {
+		return x.IsDeleteMarker
+	}
+	return false
 }
 
-func (*FileContentRequest_Header) isFileContentRequest_Data() {}
-
-func (*FileContentRequest_Chunk) isFileContentRequest_Data() {}
-
-type DeleteFilesRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Paths         []string               `protobuf:"bytes,1,rep,name=paths,proto3" json:"paths,omitempty"`
-	Bucket        string                 `protobuf:"bytes,2,opt,name=bucket,proto3" json:"bucket,omitempty"`
+type ListFileVersionsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Ordered from the newest to the oldest one
+	Versions      []*FileVersion `protobuf:"bytes,1,rep,name=versions,proto3" json:"versions,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DeleteFilesRequest) Reset() {
-	*x = DeleteFilesRequest{}
-	mi := &file_services_file_repository_types_proto_msgTypes[6]
+func (x *ListFileVersionsResponse) Reset() {
+	*x = ListFileVersionsResponse{}
+	mi := &file_services_file_repository_types_proto_msgTypes[45]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeleteFilesRequest) String() string {
+func (x *ListFileVersionsResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteFilesRequest) ProtoMessage() {}
+func (*ListFileVersionsResponse) ProtoMessage() {}
 
-func (x *DeleteFilesRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_services_file_repository_types_proto_msgTypes[6]
+func (x *ListFileVersionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_services_file_repository_types_proto_msgTypes[45]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -404,49 +3269,43 @@ func (x *DeleteFilesRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteFilesRequest.ProtoReflect.Descriptor instead.
-func (*DeleteFilesRequest) Descriptor() ([]byte, []int) {
-	return file_services_file_repository_types_proto_rawDescGZIP(), []int{6}
+// Deprecated: Use ListFileVersionsResponse.ProtoReflect.Descriptor instead.
+func (*ListFileVersionsResponse) Descriptor() ([]byte, []int) {
+	return file_services_file_repository_types_proto_rawDescGZIP(), []int{45}
 }
 
-func (x *DeleteFilesRequest) GetPaths() []string {
+func (x *ListFileVersionsResponse) GetVersions() []*FileVersion {
 	if x != nil {
-		return x.Paths
+		return x.Versions
 	}
 	return nil
 }
 
-func (x *DeleteFilesRequest) GetBucket() string {
-	if x != nil {
-		return x.Bucket
-	}
-	return ""
-}
-
-type FileChunk struct {
+// Identifies version of the file returned by ListFileVersions
+type FileVersionRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Content       []byte                 `protobuf:"bytes,1,opt,name=content,proto3" json:"content,omitempty"`
-	ChunkIndex    int64                  `protobuf:"varint,2,opt,name=chunk_index,json=chunkIndex,proto3" json:"chunk_index,omitempty"`
-	TotalSize     int64                  `protobuf:"varint,3,opt,name=total_size,json=totalSize,proto3" json:"total_size,omitempty"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Bucket        string                 `protobuf:"bytes,2,opt,name=bucket,proto3" json:"bucket,omitempty"`
+	VersionId     string                 `protobuf:"bytes,3,opt,name=version_id,json=versionId,proto3" json:"version_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *FileChunk) Reset() {
-	*x = FileChunk{}
-	mi := &file_services_file_repository_types_proto_msgTypes[7]
+func (x *FileVersionRequest) Reset() {
+	*x = FileVersionRequest{}
+	mi := &file_services_file_repository_types_proto_msgTypes[46]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *FileChunk) String() string {
+func (x *FileVersionRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*FileChunk) ProtoMessage() {}
+func (*FileVersionRequest) ProtoMessage() {}
 
-func (x *FileChunk) ProtoReflect() protoreflect.Message {
-	mi := &file_services_file_repository_types_proto_msgTypes[7]
+func (x *FileVersionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_services_file_repository_types_proto_msgTypes[46]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -457,55 +3316,56 @@ func (x *FileChunk) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use FileChunk.ProtoReflect.Descriptor instead.
-func (*FileChunk) Descriptor() ([]byte, []int) {
-	return file_services_file_repository_types_proto_rawDescGZIP(), []int{7}
+// Deprecated: Use FileVersionRequest.ProtoReflect.Descriptor instead.
+func (*FileVersionRequest) Descriptor() ([]byte, []int) {
+	return file_services_file_repository_types_proto_rawDescGZIP(), []int{46}
 }
 
-func (x *FileChunk) GetContent() []byte {
+func (x *FileVersionRequest) GetPath() string {
 	if x != nil {
-		return x.Content
+		return x.Path
 	}
-	return nil
+	return ""
 }
 
-func (x *FileChunk) GetChunkIndex() int64 {
+func (x *FileVersionRequest) GetBucket() string {
 	if x != nil {
-		return x.ChunkIndex
+		return x.Bucket
 	}
-	return 0
+	return ""
 }
 
-func (x *FileChunk) GetTotalSize() int64 {
+func (x *FileVersionRequest) GetVersionId() string {
 	if x != nil {
-		return x.TotalSize
+		return x.VersionId
 	}
-	return 0
+	return ""
 }
 
-type StatusResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Status        int32                  `protobuf:"varint,1,opt,name=status,proto3" json:"status,omitempty"`
-	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+type RestoreFileVersionResponse struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Status int32                  `protobuf:"varint,1,opt,name=status,proto3" json:"status,omitempty"`
+	// ID of the new current version
+	VersionId     string `protobuf:"bytes,2,opt,name=version_id,json=versionId,proto3" json:"version_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *StatusResponse) Reset() {
-	*x = StatusResponse{}
-	mi := &file_services_file_repository_types_proto_msgTypes[8]
+func (x *RestoreFileVersionResponse) Reset() {
+	*x = RestoreFileVersionResponse{}
+	mi := &file_services_file_repository_types_proto_msgTypes[47]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *StatusResponse) String() string {
+func (x *RestoreFileVersionResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*StatusResponse) ProtoMessage() {}
+func (*RestoreFileVersionResponse) ProtoMessage() {}
 
-func (x *StatusResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_services_file_repository_types_proto_msgTypes[8]
+func (x *RestoreFileVersionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_services_file_repository_types_proto_msgTypes[47]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -516,21 +3376,21 @@ func (x *StatusResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use StatusResponse.ProtoReflect.Descriptor instead.
-func (*StatusResponse) Descriptor() ([]byte, []int) {
-	return file_services_file_repository_types_proto_rawDescGZIP(), []int{8}
+// Deprecated: Use RestoreFileVersionResponse.ProtoReflect.Descriptor instead.
+func (*RestoreFileVersionResponse) Descriptor() ([]byte, []int) {
+	return file_services_file_repository_types_proto_rawDescGZIP(), []int{47}
 }
 
-func (x *StatusResponse) GetStatus() int32 {
+func (x *RestoreFileVersionResponse) GetStatus() int32 {
 	if x != nil {
 		return x.Status
 	}
 	return 0
 }
 
-func (x *StatusResponse) GetMessage() string {
+func (x *RestoreFileVersionResponse) GetVersionId() string {
 	if x != nil {
-		return x.Message
+		return x.VersionId
 	}
 	return ""
 }
@@ -539,40 +3399,286 @@ var File_services_file_repository_types_proto protoreflect.FileDescriptor
 
 const file_services_file_repository_types_proto_rawDesc = "" +
 	"\n" +
-	"$services/file-repository/types.proto\x12\x0ffile_repository\".\n" +
+	"$services/file-repository/types.proto\x12\x0ffile_repository\x1a\x1fgoogle/protobuf/timestamp.proto\".\n" +
 	"\x12HealthCheckRequest\x12\x18\n" +
 	"\aservice\x18\x01 \x01(\tR\aservice\"K\n" +
 	"\x13HealthCheckResponse\x12\x16\n" +
 	"\x06status\x18\x01 \x01(\tR\x06status\x12\x1c\n" +
-	"\ttimestamp\x18\x02 \x01(\tR\ttimestamp\"a\n" +
+	"\ttimestamp\x18\x02 \x01(\tR\ttimestamp\"\xf7\x01\n" +
 	"\x14GetFileByPathRequest\x12\x12\n" +
 	"\x04path\x18\x01 \x01(\tR\x04path\x12\x16\n" +
 	"\x06bucket\x18\x02 \x01(\tR\x06bucket\x12\x1d\n" +
 	"\n" +
-	"chunk_size\x18\x03 \x01(\x05R\tchunkSize\":\n" +
+	"chunk_size\x18\x03 \x01(\x05R\tchunkSize\x12E\n" +
+	"\x0earchive_format\x18\x04 \x01(\x0e2\x1e.file_repository.ArchiveFormatR\rarchiveFormat\x12\x16\n" +
+	"\x06offset\x18\x05 \x01(\x03R\x06offset\x12\x16\n" +
+	"\x06length\x18\x06 \x01(\x03R\x06length\x12\x1d\n" +
+	"\n" +
+	"version_id\x18\a \x01(\tR\tversionId\"\xa5\x01\n" +
+	"\x14ListDirectoryRequest\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12\x16\n" +
+	"\x06bucket\x18\x02 \x01(\tR\x06bucket\x12\x1c\n" +
+	"\trecursive\x18\x03 \x01(\bR\trecursive\x12\x14\n" +
+	"\x05limit\x18\x04 \x01(\x05R\x05limit\x12-\n" +
+	"\x12continuation_token\x18\x05 \x01(\tR\x11continuationToken\"\xd1\x01\n" +
+	"\x0eDirectoryEntry\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12\x12\n" +
+	"\x04size\x18\x02 \x01(\x03R\x04size\x12?\n" +
+	"\rlast_modified\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\flastModified\x12\x12\n" +
+	"\x04etag\x18\x04 \x01(\tR\x04etag\x12!\n" +
+	"\fis_directory\x18\x05 \x01(\bR\visDirectory\x12\x1f\n" +
+	"\vstored_size\x18\x06 \x01(\x03R\n" +
+	"storedSize\"\xad\x01\n" +
+	"\x15ListDirectoryResponse\x129\n" +
+	"\aentries\x18\x01 \x03(\v2\x1f.file_repository.DirectoryEntryR\aentries\x126\n" +
+	"\x17next_continuation_token\x18\x02 \x01(\tR\x15nextContinuationToken\x12!\n" +
+	"\fis_truncated\x18\x03 \x01(\bR\visTruncated\"^\n" +
+	"\x0fStatFileRequest\x12\x16\n" +
+	"\x06bucket\x18\x01 \x01(\tR\x06bucket\x12\x14\n" +
+	"\x05paths\x18\x02 \x03(\tR\x05paths\x12\x1d\n" +
+	"\n" +
+	"version_id\x18\x03 \x01(\tR\tversionId\"\xe8\x03\n" +
+	"\bFileStat\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12\x16\n" +
+	"\x06exists\x18\x02 \x01(\bR\x06exists\x12\x12\n" +
+	"\x04size\x18\x03 \x01(\x03R\x04size\x12\x1f\n" +
+	"\vstored_size\x18\x04 \x01(\x03R\n" +
+	"storedSize\x12?\n" +
+	"\rlast_modified\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\flastModified\x12\x12\n" +
+	"\x04etag\x18\x06 \x01(\tR\x04etag\x12!\n" +
+	"\fcontent_type\x18\a \x01(\tR\vcontentType\x12C\n" +
+	"\bmetadata\x18\b \x03(\v2'.file_repository.FileStat.MetadataEntryR\bmetadata\x12\x1d\n" +
+	"\n" +
+	"version_id\x18\t \x01(\tR\tversionId\x12\x1a\n" +
+	"\bchecksum\x18\n" +
+	" \x01(\tR\bchecksum\x12#\n" +
+	"\rchecksum_type\x18\v \x01(\tR\fchecksumType\x12!\n" +
+	"\fis_directory\x18\f \x01(\bR\visDirectory\x1a;\n" +
+	"\rMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"C\n" +
+	"\x10StatFileResponse\x12/\n" +
+	"\x05files\x18\x01 \x03(\v2\x19.file_repository.FileStatR\x05files\"\xb5\x01\n" +
+	"\x19CreatePresignedURLRequest\x12\x16\n" +
+	"\x06bucket\x18\x01 \x01(\tR\x06bucket\x12\x12\n" +
+	"\x04path\x18\x02 \x01(\tR\x04path\x12\x16\n" +
+	"\x06method\x18\x03 \x01(\tR\x06method\x12\x1d\n" +
+	"\n" +
+	"expires_in\x18\x04 \x01(\x03R\texpiresIn\x12!\n" +
+	"\fcontent_type\x18\x05 \x01(\tR\vcontentType\x12\x12\n" +
+	"\x04size\x18\x06 \x01(\x03R\x04size\"\xf5\x01\n" +
+	"\fPresignedURL\x12\x10\n" +
+	"\x03url\x18\x01 \x01(\tR\x03url\x12\x16\n" +
+	"\x06method\x18\x02 \x01(\tR\x06method\x129\n" +
+	"\n" +
+	"expires_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\x12D\n" +
+	"\aheaders\x18\x04 \x03(\v2*.file_repository.PresignedURL.HeadersEntryR\aheaders\x1a:\n" +
+	"\fHeadersEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\":\n" +
 	"\fMkdirRequest\x12\x12\n" +
 	"\x04path\x18\x01 \x01(\tR\x04path\x12\x16\n" +
-	"\x06bucket\x18\x02 \x01(\tR\x06bucket\"S\n" +
+	"\x06bucket\x18\x02 \x01(\tR\x06bucket\"\xd0\x03\n" +
 	"\x11FileContentHeader\x12\x12\n" +
 	"\x04path\x18\x01 \x01(\tR\x04path\x12\x16\n" +
 	"\x06bucket\x18\x02 \x01(\tR\x06bucket\x12\x12\n" +
-	"\x04size\x18\x03 \x01(\x03R\x04size\"r\n" +
+	"\x04size\x18\x03 \x01(\x03R\x04size\x12E\n" +
+	"\x0earchive_format\x18\x04 \x01(\x0e2\x1e.file_repository.ArchiveFormatR\rarchiveFormat\x12\x1a\n" +
+	"\bchecksum\x18\x05 \x01(\tR\bchecksum\x12#\n" +
+	"\rchecksum_type\x18\x06 \x01(\tR\fchecksumType\x12\x19\n" +
+	"\bif_match\x18\a \x01(\tR\aifMatch\x12\"\n" +
+	"\rif_none_match\x18\b \x01(\tR\vifNoneMatch\x12J\n" +
+	"\x13if_unmodified_since\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\x11ifUnmodifiedSince\x126\n" +
+	"\x17progress_interval_bytes\x18\n" +
+	" \x01(\x03R\x15progressIntervalBytes\x120\n" +
+	"\x14progress_interval_ms\x18\v \x01(\x03R\x12progressIntervalMs\"r\n" +
 	"\x12FileContentRequest\x12<\n" +
 	"\x06header\x18\x01 \x01(\v2\".file_repository.FileContentHeaderH\x00R\x06header\x12\x16\n" +
 	"\x05chunk\x18\x02 \x01(\fH\x00R\x05chunkB\x06\n" +
-	"\x04data\"B\n" +
+	"\x04data\"`\n" +
 	"\x12DeleteFilesRequest\x12\x14\n" +
 	"\x05paths\x18\x01 \x03(\tR\x05paths\x12\x16\n" +
-	"\x06bucket\x18\x02 \x01(\tR\x06bucket\"e\n" +
+	"\x06bucket\x18\x02 \x01(\tR\x06bucket\x12\x1c\n" +
+	"\trecursive\x18\x03 \x01(\bR\trecursive\"=\n" +
+	"\x0fDeletionFailure\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\"\x87\x01\n" +
+	"\x0eDeletionResult\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12#\n" +
+	"\rdeleted_count\x18\x02 \x01(\x03R\fdeletedCount\x12<\n" +
+	"\bfailures\x18\x03 \x03(\v2 .file_repository.DeletionFailureR\bfailures\"\xcb\x01\n" +
+	"\x13DeleteFilesResponse\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\x05R\x06status\x129\n" +
+	"\aresults\x18\x02 \x03(\v2\x1f.file_repository.DeletionResultR\aresults\x12#\n" +
+	"\rdeleted_count\x18\x03 \x01(\x03R\fdeletedCount\x12<\n" +
+	"\bfailures\x18\x04 \x03(\v2 .file_repository.DeletionFailureR\bfailures\"\xf4\x01\n" +
+	"\x14RelocateFilesRequest\x12#\n" +
+	"\rsource_bucket\x18\x01 \x01(\tR\fsourceBucket\x12\x1f\n" +
+	"\vsource_path\x18\x02 \x01(\tR\n" +
+	"sourcePath\x12-\n" +
+	"\x12destination_bucket\x18\x03 \x01(\tR\x11destinationBucket\x12)\n" +
+	"\x10destination_path\x18\x04 \x01(\tR\x0fdestinationPath\x12<\n" +
+	"\toverwrite\x18\x05 \x01(\x0e2\x1e.file_repository.OverwriteModeR\toverwrite\"\x8b\x01\n" +
+	"\rRelocatedFile\x12\x1f\n" +
+	"\vsource_path\x18\x01 \x01(\tR\n" +
+	"sourcePath\x12)\n" +
+	"\x10destination_path\x18\x02 \x01(\tR\x0fdestinationPath\x12\x18\n" +
+	"\askipped\x18\x03 \x01(\bR\askipped\x12\x14\n" +
+	"\x05error\x18\x04 \x01(\tR\x05error\"\xb3\x01\n" +
+	"\x15RelocateFilesResponse\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\x05R\x06status\x124\n" +
+	"\x05files\x18\x02 \x03(\v2\x1e.file_repository.RelocatedFileR\x05files\x12'\n" +
+	"\x0frelocated_count\x18\x03 \x01(\x03R\x0erelocatedCount\x12#\n" +
+	"\rskipped_count\x18\x04 \x01(\x03R\fskippedCount\"\xe4\x01\n" +
 	"\tFileChunk\x12\x18\n" +
 	"\acontent\x18\x01 \x01(\fR\acontent\x12\x1f\n" +
 	"\vchunk_index\x18\x02 \x01(\x03R\n" +
 	"chunkIndex\x12\x1d\n" +
 	"\n" +
-	"total_size\x18\x03 \x01(\x03R\ttotalSize\"B\n" +
+	"total_size\x18\x03 \x01(\x03R\ttotalSize\x12\x1f\n" +
+	"\vrange_start\x18\x04 \x01(\x03R\n" +
+	"rangeStart\x12\x1b\n" +
+	"\trange_end\x18\x05 \x01(\x03R\brangeEnd\x12\x1a\n" +
+	"\bchecksum\x18\x06 \x01(\tR\bchecksum\x12#\n" +
+	"\rchecksum_type\x18\a \x01(\tR\fchecksumType\"q\n" +
+	"\x0eExtractedEntry\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12\x12\n" +
+	"\x04size\x18\x02 \x01(\x03R\x04size\x12!\n" +
+	"\fis_directory\x18\x03 \x01(\bR\visDirectory\x12\x14\n" +
+	"\x05error\x18\x04 \x01(\tR\x05error\"\x8f\x02\n" +
 	"\x0eStatusResponse\x12\x16\n" +
 	"\x06status\x18\x01 \x01(\x05R\x06status\x12\x18\n" +
-	"\amessage\x18\x02 \x01(\tR\amessageBPZNgithub.com/abaxoth0/Vega/common/protobuf/generated/go/services/file-repositoryb\x06proto3"
+	"\amessage\x18\x02 \x01(\tR\amessage\x129\n" +
+	"\aentries\x18\x03 \x03(\v2\x1f.file_repository.ExtractedEntryR\aentries\x12\x1a\n" +
+	"\bchecksum\x18\x04 \x01(\tR\bchecksum\x12#\n" +
+	"\rchecksum_type\x18\x05 \x01(\tR\fchecksumType\x12\x12\n" +
+	"\x04etag\x18\x06 \x01(\tR\x04etag\x12;\n" +
+	"\bprogress\x18\a \x01(\v2\x1f.file_repository.UploadProgressR\bprogress\"Z\n" +
+	"\x0eUploadProgress\x12'\n" +
+	"\x0fcommitted_bytes\x18\x01 \x01(\x03R\x0ecommittedBytes\x12\x1f\n" +
+	"\vtotal_bytes\x18\x02 \x01(\x03R\n" +
+	"totalBytes\"C\n" +
+	"\x15InitiateUploadRequest\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12\x16\n" +
+	"\x06bucket\x18\x02 \x01(\tR\x06bucket\"5\n" +
+	"\x16InitiateUploadResponse\x12\x1b\n" +
+	"\tupload_id\x18\x01 \x01(\tR\buploadId\"_\n" +
+	"\x14UploadSessionRequest\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12\x16\n" +
+	"\x06bucket\x18\x02 \x01(\tR\x06bucket\x12\x1b\n" +
+	"\tupload_id\x18\x03 \x01(\tR\buploadId\"\x90\x01\n" +
+	"\x10UploadPartHeader\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12\x16\n" +
+	"\x06bucket\x18\x02 \x01(\tR\x06bucket\x12\x1b\n" +
+	"\tupload_id\x18\x03 \x01(\tR\buploadId\x12\x1f\n" +
+	"\vpart_number\x18\x04 \x01(\x05R\n" +
+	"partNumber\x12\x12\n" +
+	"\x04size\x18\x05 \x01(\x03R\x04size\"p\n" +
+	"\x11UploadPartRequest\x12;\n" +
+	"\x06header\x18\x01 \x01(\v2!.file_repository.UploadPartHeaderH\x00R\x06header\x12\x16\n" +
+	"\x05chunk\x18\x02 \x01(\fH\x00R\x05chunkB\x06\n" +
+	"\x04data\"\x96\x01\n" +
+	"\n" +
+	"UploadPart\x12\x1f\n" +
+	"\vpart_number\x18\x01 \x01(\x05R\n" +
+	"partNumber\x12\x12\n" +
+	"\x04etag\x18\x02 \x01(\tR\x04etag\x12\x12\n" +
+	"\x04size\x18\x03 \x01(\x03R\x04size\x12?\n" +
+	"\rlast_modified\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\flastModified\"E\n" +
+	"\x12UploadPartResponse\x12/\n" +
+	"\x04part\x18\x01 \x01(\v2\x1b.file_repository.UploadPartR\x04part\"N\n" +
+	"\x19ListUploadedPartsResponse\x121\n" +
+	"\x05parts\x18\x01 \x03(\v2\x1b.file_repository.UploadPartR\x05parts\")\n" +
+	"\x13CreateBucketRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\"?\n" +
+	"\x13DeleteBucketRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
+	"\x05force\x18\x02 \x01(\bR\x05force\"\x14\n" +
+	"\x12ListBucketsRequest\"]\n" +
+	"\x06Bucket\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12?\n" +
+	"\rcreation_date\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\fcreationDate\"H\n" +
+	"\x13ListBucketsResponse\x121\n" +
+	"\abuckets\x18\x01 \x03(\v2\x17.file_repository.BucketR\abuckets\"*\n" +
+	"\x14GetBucketInfoRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\"\xd2\x01\n" +
+	"\n" +
+	"BucketInfo\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12?\n" +
+	"\rcreation_date\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\fcreationDate\x12!\n" +
+	"\fobject_count\x18\x03 \x01(\x03R\vobjectCount\x12\x1d\n" +
+	"\n" +
+	"total_size\x18\x04 \x01(\x03R\ttotalSize\x12-\n" +
+	"\x12versioning_enabled\x18\x05 \x01(\bR\x11versioningEnabled\"D\n" +
+	"\x14GetQuotaUsageRequest\x12\x16\n" +
+	"\x06bucket\x18\x01 \x01(\tR\x06bucket\x12\x14\n" +
+	"\x05owner\x18\x02 \x01(\tR\x05owner\"\x8c\x01\n" +
+	"\n" +
+	"QuotaUsage\x12\x1d\n" +
+	"\n" +
+	"used_bytes\x18\x01 \x01(\x03R\tusedBytes\x12!\n" +
+	"\fused_objects\x18\x02 \x01(\x03R\vusedObjects\x12\x1b\n" +
+	"\tmax_bytes\x18\x03 \x01(\x03R\bmaxBytes\x12\x1f\n" +
+	"\vmax_objects\x18\x04 \x01(\x03R\n" +
+	"maxObjects\"J\n" +
+	"\x1aSetBucketVersioningRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x18\n" +
+	"\aenabled\x18\x02 \x01(\bR\aenabled\"E\n" +
+	"\x17ListFileVersionsRequest\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12\x16\n" +
+	"\x06bucket\x18\x02 \x01(\tR\x06bucket\"\xdc\x01\n" +
+	"\vFileVersion\x12\x1d\n" +
+	"\n" +
+	"version_id\x18\x01 \x01(\tR\tversionId\x12\x12\n" +
+	"\x04size\x18\x02 \x01(\x03R\x04size\x12?\n" +
+	"\rlast_modified\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\flastModified\x12\x12\n" +
+	"\x04etag\x18\x04 \x01(\tR\x04etag\x12\x1b\n" +
+	"\tis_latest\x18\x05 \x01(\bR\bisLatest\x12(\n" +
+	"\x10is_delete_marker\x18\x06 \x01(\bR\x0eisDeleteMarker\"T\n" +
+	"\x18ListFileVersionsResponse\x128\n" +
+	"\bversions\x18\x01 \x03(\v2\x1c.file_repository.FileVersionR\bversions\"_\n" +
+	"\x12FileVersionRequest\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12\x16\n" +
+	"\x06bucket\x18\x02 \x01(\tR\x06bucket\x12\x1d\n" +
+	"\n" +
+	"version_id\x18\x03 \x01(\tR\tversionId\"S\n" +
+	"\x1aRestoreFileVersionResponse\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\x05R\x06status\x12\x1d\n" +
+	"\n" +
+	"version_id\x18\x02 \x01(\tR\tversionId*u\n" +
+	"\rArchiveFormat\x12\x17\n" +
+	"\x13ARCHIVE_FORMAT_NONE\x10\x00\x12\x16\n" +
+	"\x12ARCHIVE_FORMAT_TAR\x10\x01\x12\x1b\n" +
+	"\x17ARCHIVE_FORMAT_TAR_GZIP\x10\x02\x12\x16\n" +
+	"\x12ARCHIVE_FORMAT_ZIP\x10\x03*]\n" +
+	"\rOverwriteMode\x12\x17\n" +
+	"\x13OVERWRITE_MODE_FAIL\x10\x00\x12\x17\n" +
+	"\x13OVERWRITE_MODE_SKIP\x10\x01\x12\x1a\n" +
+	"\x16OVERWRITE_MODE_REPLACE\x10\x02*\xfb\x05\n" +
+	"\vErrorReason\x12\x1c\n" +
+	"\x18ERROR_REASON_UNSPECIFIED\x10\x00\x12\x1f\n" +
+	"\x1bERROR_REASON_FILE_NOT_FOUND\x10\x01\x12!\n" +
+	"\x1dERROR_REASON_BUCKET_NOT_FOUND\x10\x02\x12!\n" +
+	"\x1dERROR_REASON_UPLOAD_NOT_FOUND\x10\x03\x12\x1d\n" +
+	"\x19ERROR_REASON_INVALID_PATH\x10\x04\x12!\n" +
+	"\x1dERROR_REASON_INVALID_ARGUMENT\x10\x05\x12$\n" +
+	" ERROR_REASON_PRECONDITION_FAILED\x10\x06\x12#\n" +
+	"\x1fERROR_REASON_DESTINATION_EXISTS\x10\a\x12\x1f\n" +
+	"\x1bERROR_REASON_QUOTA_EXCEEDED\x10\b\x12&\n" +
+	"\"ERROR_REASON_RANGE_NOT_SATISFIABLE\x10\t\x12\"\n" +
+	"\x1eERROR_REASON_CHECKSUM_MISMATCH\x10\n" +
+	"\x12\x1e\n" +
+	"\x1aERROR_REASON_NOT_SUPPORTED\x10\v\x12\x18\n" +
+	"\x14ERROR_REASON_TIMEOUT\x10\f\x12\x19\n" +
+	"\x15ERROR_REASON_CANCELED\x10\r\x12\"\n" +
+	"\x1eERROR_REASON_QUOTA_NOT_DEFINED\x10\x0e\x12 \n" +
+	"\x1cERROR_REASON_UNAUTHENTICATED\x10\x0f\x12\"\n" +
+	"\x1eERROR_REASON_PERMISSION_DENIED\x10\x10\x12\x1d\n" +
+	"\x19ERROR_REASON_RATE_LIMITED\x10\x11\x12&\n" +
+	"\"ERROR_REASON_BUCKET_ALREADY_EXISTS\x10\x12\x12!\n" +
+	"\x1dERROR_REASON_BUCKET_NOT_EMPTY\x10\x13\x12$\n" +
+	" ERROR_REASON_INVALID_BUCKET_NAME\x10\x14\x12\x1e\n" +
+	"\x1aERROR_REASON_PATH_CONFLICT\x10\x15BPZNgithub.com/abaxoth0/Vega/common/protobuf/generated/go/services/file-repositoryb\x06proto3"
 
 var (
 	file_services_file_repository_types_proto_rawDescOnce sync.Once
@@ -586,25 +3692,97 @@ func file_services_file_repository_types_proto_rawDescGZIP() []byte {
 	return file_services_file_repository_types_proto_rawDescData
 }
 
-var file_services_file_repository_types_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_services_file_repository_types_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
+var file_services_file_repository_types_proto_msgTypes = make([]protoimpl.MessageInfo, 50)
 var file_services_file_repository_types_proto_goTypes = []any{
-	(*HealthCheckRequest)(nil),   // 0: file_repository.HealthCheckRequest
-	(*HealthCheckResponse)(nil),  // 1: file_repository.HealthCheckResponse
-	(*GetFileByPathRequest)(nil), // 2: file_repository.GetFileByPathRequest
-	(*MkdirRequest)(nil),         // 3: file_repository.MkdirRequest
-	(*FileContentHeader)(nil),    // 4: file_repository.FileContentHeader
-	(*FileContentRequest)(nil),   // 5: file_repository.FileContentRequest
-	(*DeleteFilesRequest)(nil),   // 6: file_repository.DeleteFilesRequest
-	(*FileChunk)(nil),            // 7: file_repository.FileChunk
-	(*StatusResponse)(nil),       // 8: file_repository.StatusResponse
+	(ArchiveFormat)(0),                 // 0: file_repository.ArchiveFormat
+	(OverwriteMode)(0),                 // 1: file_repository.OverwriteMode
+	(ErrorReason)(0),                   // 2: file_repository.ErrorReason
+	(*HealthCheckRequest)(nil),         // 3: file_repository.HealthCheckRequest
+	(*HealthCheckResponse)(nil),        // 4: file_repository.HealthCheckResponse
+	(*GetFileByPathRequest)(nil),       // 5: file_repository.GetFileByPathRequest
+	(*ListDirectoryRequest)(nil),       // 6: file_repository.ListDirectoryRequest
+	(*DirectoryEntry)(nil),             // 7: file_repository.DirectoryEntry
+	(*ListDirectoryResponse)(nil),      // 8: file_repository.ListDirectoryResponse
+	(*StatFileRequest)(nil),            // 9: file_repository.StatFileRequest
+	(*FileStat)(nil),                   // 10: file_repository.FileStat
+	(*StatFileResponse)(nil),           // 11: file_repository.StatFileResponse
+	(*CreatePresignedURLRequest)(nil),  // 12: file_repository.CreatePresignedURLRequest
+	(*PresignedURL)(nil),               // 13: file_repository.PresignedURL
+	(*MkdirRequest)(nil),               // 14: file_repository.MkdirRequest
+	(*FileContentHeader)(nil),          // 15: file_repository.FileContentHeader
+	(*FileContentRequest)(nil),         // 16: file_repository.FileContentRequest
+	(*DeleteFilesRequest)(nil),         // 17: file_repository.DeleteFilesRequest
+	(*DeletionFailure)(nil),            // 18: file_repository.DeletionFailure
+	(*DeletionResult)(nil),             // 19: file_repository.DeletionResult
+	(*DeleteFilesResponse)(nil),        // 20: file_repository.DeleteFilesResponse
+	(*RelocateFilesRequest)(nil),       // 21: file_repository.RelocateFilesRequest
+	(*RelocatedFile)(nil),              // 22: file_repository.RelocatedFile
+	(*RelocateFilesResponse)(nil),      // 23: file_repository.RelocateFilesResponse
+	(*FileChunk)(nil),                  // 24: file_repository.FileChunk
+	(*ExtractedEntry)(nil),             // 25: file_repository.ExtractedEntry
+	(*StatusResponse)(nil),             // 26: file_repository.StatusResponse
+	(*UploadProgress)(nil),             // 27: file_repository.UploadProgress
+	(*InitiateUploadRequest)(nil),      // 28: file_repository.InitiateUploadRequest
+	(*InitiateUploadResponse)(nil),     // 29: file_repository.InitiateUploadResponse
+	(*UploadSessionRequest)(nil),       // 30: file_repository.UploadSessionRequest
+	(*UploadPartHeader)(nil),           // 31: file_repository.UploadPartHeader
+	(*UploadPartRequest)(nil),          // 32: file_repository.UploadPartRequest
+	(*UploadPart)(nil),                 // 33: file_repository.UploadPart
+	(*UploadPartResponse)(nil),         // 34: file_repository.UploadPartResponse
+	(*ListUploadedPartsResponse)(nil),  // 35: file_repository.ListUploadedPartsResponse
+	(*CreateBucketRequest)(nil),        // 36: file_repository.CreateBucketRequest
+	(*DeleteBucketRequest)(nil),        // 37: file_repository.DeleteBucketRequest
+	(*ListBucketsRequest)(nil),         // 38: file_repository.ListBucketsRequest
+	(*Bucket)(nil),                     // 39: file_repository.Bucket
+	(*ListBucketsResponse)(nil),        // 40: file_repository.ListBucketsResponse
+	(*GetBucketInfoRequest)(nil),       // 41: file_repository.GetBucketInfoRequest
+	(*BucketInfo)(nil),                 // 42: file_repository.BucketInfo
+	(*GetQuotaUsageRequest)(nil),       // 43: file_repository.GetQuotaUsageRequest
+	(*QuotaUsage)(nil),                 // 44: file_repository.QuotaUsage
+	(*SetBucketVersioningRequest)(nil), // 45: file_repository.SetBucketVersioningRequest
+	(*ListFileVersionsRequest)(nil),    // 46: file_repository.ListFileVersionsRequest
+	(*FileVersion)(nil),                // 47: file_repository.FileVersion
+	(*ListFileVersionsResponse)(nil),   // 48: file_repository.ListFileVersionsResponse
+	(*FileVersionRequest)(nil),         // 49: file_repository.FileVersionRequest
+	(*RestoreFileVersionResponse)(nil), // 50: file_repository.RestoreFileVersionResponse
+	nil,                                // 51: file_repository.FileStat.MetadataEntry
+	nil,                                // 52: file_repository.PresignedURL.HeadersEntry
+	(*timestamppb.Timestamp)(nil),      // 53: google.protobuf.Timestamp
 }
 var file_services_file_repository_types_proto_depIdxs = []int32{
-	4, // 0: file_repository.FileContentRequest.header:type_name -> file_repository.FileContentHeader
-	1, // [1:1] is the sub-list for method output_type
-	1, // [1:1] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	0,  // 0: file_repository.GetFileByPathRequest.archive_format:type_name -> file_repository.ArchiveFormat
+	53, // 1: file_repository.DirectoryEntry.last_modified:type_name -> google.protobuf.Timestamp
+	7,  // 2: file_repository.ListDirectoryResponse.entries:type_name -> file_repository.DirectoryEntry
+	53, // 3: file_repository.FileStat.last_modified:type_name -> google.protobuf.Timestamp
+	51, // 4: file_repository.FileStat.metadata:type_name -> file_repository.FileStat.MetadataEntry
+	10, // 5: file_repository.StatFileResponse.files:type_name -> file_repository.FileStat
+	53, // 6: file_repository.PresignedURL.expires_at:type_name -> google.protobuf.Timestamp
+	52, // 7: file_repository.PresignedURL.headers:type_name -> file_repository.PresignedURL.HeadersEntry
+	0,  // 8: file_repository.FileContentHeader.archive_format:type_name -> file_repository.ArchiveFormat
+	53, // 9: file_repository.FileContentHeader.if_unmodified_since:type_name -> google.protobuf.Timestamp
+	15, // 10: file_repository.FileContentRequest.header:type_name -> file_repository.FileContentHeader
+	18, // 11: file_repository.DeletionResult.failures:type_name -> file_repository.DeletionFailure
+	19, // 12: file_repository.DeleteFilesResponse.results:type_name -> file_repository.DeletionResult
+	18, // 13: file_repository.DeleteFilesResponse.failures:type_name -> file_repository.DeletionFailure
+	1,  // 14: file_repository.RelocateFilesRequest.overwrite:type_name -> file_repository.OverwriteMode
+	22, // 15: file_repository.RelocateFilesResponse.files:type_name -> file_repository.RelocatedFile
+	25, // 16: file_repository.StatusResponse.entries:type_name -> file_repository.ExtractedEntry
+	27, // 17: file_repository.StatusResponse.progress:type_name -> file_repository.UploadProgress
+	31, // 18: file_repository.UploadPartRequest.header:type_name -> file_repository.UploadPartHeader
+	53, // 19: file_repository.UploadPart.last_modified:type_name -> google.protobuf.Timestamp
+	33, // 20: file_repository.UploadPartResponse.part:type_name -> file_repository.UploadPart
+	33, // 21: file_repository.ListUploadedPartsResponse.parts:type_name -> file_repository.UploadPart
+	53, // 22: file_repository.Bucket.creation_date:type_name -> google.protobuf.Timestamp
+	39, // 23: file_repository.ListBucketsResponse.buckets:type_name -> file_repository.Bucket
+	53, // 24: file_repository.BucketInfo.creation_date:type_name -> google.protobuf.Timestamp
+	53, // 25: file_repository.FileVersion.last_modified:type_name -> google.protobuf.Timestamp
+	47, // 26: file_repository.ListFileVersionsResponse.versions:type_name -> file_repository.FileVersion
+	27, // [27:27] is the sub-list for method output_type
+	27, // [27:27] is the sub-list for method input_type
+	27, // [27:27] is the sub-list for extension type_name
+	27, // [27:27] is the sub-list for extension extendee
+	0,  // [0:27] is the sub-list for field type_name
 }
 
 func init() { file_services_file_repository_types_proto_init() }
@@ -612,22 +3790,27 @@ func file_services_file_repository_types_proto_init() {
 	if File_services_file_repository_types_proto != nil {
 		return
 	}
-	file_services_file_repository_types_proto_msgTypes[5].OneofWrappers = []any{
+	file_services_file_repository_types_proto_msgTypes[13].OneofWrappers = []any{
 		(*FileContentRequest_Header)(nil),
 		(*FileContentRequest_Chunk)(nil),
 	}
+	file_services_file_repository_types_proto_msgTypes[29].OneofWrappers = []any{
+		(*UploadPartRequest_Header)(nil),
+		(*UploadPartRequest_Chunk)(nil),
+	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_services_file_repository_types_proto_rawDesc), len(file_services_file_repository_types_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   9,
+			NumEnums:      3,
+			NumMessages:   50,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
 		GoTypes:           file_services_file_repository_types_proto_goTypes,
 		DependencyIndexes: file_services_file_repository_types_proto_depIdxs,
+		EnumInfos:         file_services_file_repository_types_proto_enumTypes,
 		MessageInfos:      file_services_file_repository_types_proto_msgTypes,
 	}.Build()
 	File_services_file_repository_types_proto = out.File
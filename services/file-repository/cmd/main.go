@@ -3,12 +3,27 @@ package main
 import (
 	"bytes"
 	"context"
+	"errors"
+	"fmt"
 	"log"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
 	"time"
 	fileapplication "vega_file_repository/packages/application/file"
+	"vega_file_repository/packages/infrastructure/auth"
 	ObjectStorage "vega_file_repository/packages/infrastructure/object-storage"
+	"vega_file_repository/packages/infrastructure/object-storage/compression"
 	StorageConnection "vega_file_repository/packages/infrastructure/object-storage/connection"
+	"vega_file_repository/packages/infrastructure/object-storage/encryption"
+	"vega_file_repository/packages/infrastructure/object-storage/presign"
+	"vega_file_repository/packages/infrastructure/object-storage/quota"
+	"vega_file_repository/packages/infrastructure/ratelimit"
+	"vega_file_repository/packages/infrastructure/tlsconfig"
 	"vega_file_repository/packages/presentation/grpc"
+	HTTPGateway "vega_file_repository/packages/presentation/http"
 
 	"github.com/minio/minio-go/v7"
 	"github.com/minio/minio-go/v7/pkg/credentials"
@@ -19,15 +34,345 @@ func main() {
 	// testgRPC()
 }
 
-func testgRPC() {
-	err := ObjectStorage.Driver.Connect(&StorageConnection.Config{
-		URL:      "localhost:9000",
-		Login:    "minioadmin",
-		Password: "minioadmin",
-		Token:    "",
-		Secure:   false,
-	})
+// Object storage driver is selected via VEGA_STORAGE_DRIVER env variable ("minio" by default, "minio-dedup" or "local").
+// Local driver stores buckets in VEGA_LOCAL_STORAGE_ROOT directory, so MinIO container isn't required.
+// MinIO is connected via TLS if VEGA_MINIO_SECURE is "true", its certificate can be signed by CAs from VEGA_MINIO_CA_FILE.
+func connectStorage() error {
+	driver := ObjectStorage.DriverName(os.Getenv("VEGA_STORAGE_DRIVER"))
+	if err := ObjectStorage.SelectDriver(driver); err != nil {
+		return err
+	}
+
+	encrypted, err := setupEncryption()
+	if err != nil {
+		return err
+	}
+	// Encrypted content can't be compressed, so compression must be applied before encryption
+	if err := setupCompression(); err != nil {
+		return err
+	}
+	// Quotas are enforced by the original sizes of the files, so they must wrap all other layers
+	if err := setupQuotas(); err != nil {
+		return err
+	}
+	// URLs signed by the service are served through all other layers
+	if err := setupPresign(); err != nil {
+		return err
+	}
+
+	if driver == ObjectStorage.LocalDriver {
+		root := os.Getenv("VEGA_LOCAL_STORAGE_ROOT")
+		if root == "" {
+			root = "./local_data"
+		}
+		err = ObjectStorage.Driver.Connect(&StorageConnection.Config{URL: root})
+	} else {
+		err = ObjectStorage.Driver.Connect(&StorageConnection.Config{
+			URL:      "localhost:9000",
+			Login:    "minioadmin",
+			Password: "minioadmin",
+			Token:    "",
+			Secure:   os.Getenv("VEGA_MINIO_SECURE") == "true",
+			CAFile:   os.Getenv("VEGA_MINIO_CA_FILE"),
+		})
+	}
 	if err != nil {
+		return err
+	}
+
+	if encrypted != nil && os.Getenv("VEGA_ENCRYPTION_ROTATE_KEYS") == "true" {
+		go rotateEncryptionKeys(encrypted)
+	}
+
+	return nil
+}
+
+// Encryption at rest is enabled if VEGA_ENCRYPTION_KEYS is set (see encryption.ParseKeyring for its format).
+// Only buckets listed in VEGA_ENCRYPTED_BUCKETS (comma separated) are encrypted, if it's empty then all of them.
+// If VEGA_ENCRYPTION_ROTATE_KEYS is "true", then data keys of all encrypted files
+// are rewrapped by the active master key in background once storage is connected.
+// Files which were stored before encryption was enabled can be read only while
+// VEGA_ENCRYPTION_ALLOW_UNENCRYPTED is "true" (see encryption.Driver.AllowUnencryptedFiles).
+// Returns nil if encryption isn't enabled.
+func setupEncryption() (*encryption.Driver, error) {
+	keys := os.Getenv("VEGA_ENCRYPTION_KEYS")
+	if keys == "" {
+		return nil, nil
+	}
+
+	keyring, err := encryption.ParseKeyring(keys)
+	if err != nil {
+		return nil, err
+	}
+
+	var buckets []string
+	for _, bucket := range strings.Split(os.Getenv("VEGA_ENCRYPTED_BUCKETS"), ",") {
+		if bucket = strings.TrimSpace(bucket); bucket != "" {
+			buckets = append(buckets, bucket)
+		}
+	}
+
+	driver := encryption.NewDriver(ObjectStorage.Driver, keyring, buckets)
+	if os.Getenv("VEGA_ENCRYPTION_ALLOW_UNENCRYPTED") == "true" {
+		driver.AllowUnencryptedFiles()
+	}
+	ObjectStorage.Driver = driver
+
+	return driver, nil
+}
+
+// Compression is enabled if VEGA_COMPRESSION_POLICY is set (see compression.ParsePolicy for its format)
+func setupCompression() error {
+	policy := os.Getenv("VEGA_COMPRESSION_POLICY")
+	if policy == "" {
+		return nil
+	}
+
+	parsed, err := compression.ParsePolicy(policy)
+	if err != nil {
+		return err
+	}
+
+	ObjectStorage.Driver = compression.NewDriver(ObjectStorage.Driver, parsed)
+
+	return nil
+}
+
+// Quotas are enforced if VEGA_QUOTAS_FILE is set (see quota.ParseConfig for its format)
+func setupQuotas() error {
+	path := os.Getenv("VEGA_QUOTAS_FILE")
+	if path == "" {
+		return nil
+	}
+
+	config, err := quota.LoadConfig(path)
+	if err != nil {
+		return err
+	}
+
+	ObjectStorage.Driver = quota.NewDriver(ObjectStorage.Driver, config)
+
+	return nil
+}
+
+// URLs which storage can't presign by itself are signed by the service if VEGA_PRESIGN_SECRET is set.
+// VEGA_PRESIGN_BASE_URL is the public address of the HTTP server which serves them (see serveHTTP).
+func setupPresign() error {
+	secret := os.Getenv("VEGA_PRESIGN_SECRET")
+	if secret == "" {
+		return nil
+	}
+
+	signer, err := presign.NewSigner(secret, os.Getenv("VEGA_PRESIGN_BASE_URL"))
+	if err != nil {
+		return err
+	}
+
+	ObjectStorage.Driver = presign.NewDriver(ObjectStorage.Driver, signer)
+
+	return nil
+}
+
+// HTTP gateway listens on VEGA_HTTP_PORT (8080 by default), it also serves URLs presigned by the service
+// Gateway isn't required for gRPC server, so it's just not started (and nil is returned) if it can't be.
+func serveHTTP(verifier *auth.Verifier, policy *auth.Policy) *HTTPGateway.Server {
+	port := uint16(8080)
+	if env := os.Getenv("VEGA_HTTP_PORT"); env != "" {
+		parsed, err := strconv.ParseUint(env, 10, 16)
+		if err != nil {
+			log.Printf("Invalid VEGA_HTTP_PORT: %v\n", err)
+			return nil
+		}
+		port = uint16(parsed)
+	}
+
+	gateway, err := HTTPGateway.NewServer(ObjectStorage.Driver)
+	if err != nil {
+		log.Printf("Failed to create HTTP gateway: %v\n", err)
+		return nil
+	}
+	if verifier != nil {
+		if err := gateway.EnableAuth(verifier, policy); err != nil {
+			log.Printf("Failed to enable authentication of HTTP gateway: %v\n", err)
+			return nil
+		}
+	}
+	if driver, ok := ObjectStorage.Driver.(*presign.Driver); ok {
+		gateway.Handle(presign.PathPrefix, driver.Handler())
+	}
+
+	println("HTTP gateway started on port", port)
+
+	go func() {
+		if err := gateway.Start(port); err != nil {
+			log.Printf("HTTP gateway failed: %v\n", err)
+		}
+	}()
+
+	return gateway
+}
+
+// Returns value of env variable in time.ParseDuration format (e.g. "90m") or def if it isn't set
+func durationEnv(name string, def time.Duration) (time.Duration, error) {
+	env := os.Getenv(name)
+	if env == "" {
+		return def, nil
+	}
+	parsed, err := time.ParseDuration(env)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %v", name, err)
+	}
+	if parsed <= 0 {
+		return 0, fmt.Errorf("%s must be positive, but got %s", name, env)
+	}
+	return parsed, nil
+}
+
+// Upload sessions older than VEGA_UPLOADS_MAX_AGE (24h by default) are aborted
+// each VEGA_UPLOADS_COLLECTION_INTERVAL (1h by default).
+func setupUploadsCollector() (*fileapplication.StaleUploadsCollector, error) {
+	interval, err := durationEnv("VEGA_UPLOADS_COLLECTION_INTERVAL", time.Hour)
+	if err != nil {
+		return nil, err
+	}
+	maxAge, err := durationEnv("VEGA_UPLOADS_MAX_AGE", time.Hour*24)
+	if err != nil {
+		return nil, err
+	}
+	return fileapplication.NewStaleUploadsCollector(ObjectStorage.Driver, interval, maxAge), nil
+}
+
+// How long in-flight requests are waited for on shutdown, can be set via VEGA_SHUTDOWN_TIMEOUT (e.g. "45s")
+func shutdownTimeout() time.Duration {
+	timeout := time.Second * 30
+	if env := os.Getenv("VEGA_SHUTDOWN_TIMEOUT"); env != "" {
+		parsed, err := time.ParseDuration(env)
+		if err != nil || parsed <= 0 {
+			log.Printf("Invalid VEGA_SHUTDOWN_TIMEOUT \"%s\", using default one: %s\n", env, timeout)
+			return timeout
+		}
+		timeout = parsed
+	}
+	return timeout
+}
+
+// Servers are drained concurrently, so total shutdown time doesn't exceed the timeout
+func shutdown(server *grpc.Server, gateway *HTTPGateway.Server) {
+	timeout := shutdownTimeout()
+	log.Printf("Shutting down, waiting up to %s for in-flight requests\n", timeout)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if gateway == nil {
+			return
+		}
+		if err := gateway.Shutdown(timeout); err != nil {
+			log.Printf("HTTP gateway shutdown: %v\n", err)
+		}
+	}()
+
+	if err := server.Shutdown(timeout); err != nil {
+		log.Printf("gRPC server shutdown: %v\n", err)
+	}
+	<-done
+}
+
+// Requests are authenticated if VEGA_AUTH_JWT_KEY is set, in that case VEGA_AUTH_POLICY_FILE
+// with grants of the callers is required as well (see auth.ParsePolicy for its format).
+// Returns nil verifier if authentication is disabled.
+func setupAuth() (*auth.Verifier, *auth.Policy, error) {
+	key := os.Getenv("VEGA_AUTH_JWT_KEY")
+	if key == "" {
+		log.Println("VEGA_AUTH_JWT_KEY isn't set, requests won't be authenticated")
+		return nil, nil, nil
+	}
+
+	verifier, err := auth.NewVerifier(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	path := os.Getenv("VEGA_AUTH_POLICY_FILE")
+	if path == "" {
+		return nil, nil, errors.New("VEGA_AUTH_POLICY_FILE must be set if authentication is enabled")
+	}
+	policy, err := auth.LoadPolicy(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return verifier, policy, nil
+}
+
+// Callers of gRPC server are rate limited if VEGA_RATE_LIMITS_FILE is set (see ratelimit.ParseConfig for its format)
+func setupRateLimits(server *grpc.Server) error {
+	path := os.Getenv("VEGA_RATE_LIMITS_FILE")
+	if path == "" {
+		return nil
+	}
+
+	config, err := ratelimit.LoadConfig(path)
+	if err != nil {
+		return err
+	}
+
+	return server.EnableRateLimits(ratelimit.NewLimiter(config))
+}
+
+// gRPC server is served over TLS if VEGA_TLS_CERT_FILE and VEGA_TLS_KEY_FILE are set. Clients must present
+// certificates signed by CAs from VEGA_TLS_CLIENT_CA_FILE if it's set as well (mutual TLS).
+// All files are reloaded once they are modified, so certificates can be rotated without restart.
+func setupTLS(server *grpc.Server) error {
+	certFile := os.Getenv("VEGA_TLS_CERT_FILE")
+	keyFile := os.Getenv("VEGA_TLS_KEY_FILE")
+	clientCAFile := os.Getenv("VEGA_TLS_CLIENT_CA_FILE")
+	if certFile == "" && keyFile == "" {
+		if clientCAFile != "" {
+			return errors.New("VEGA_TLS_CLIENT_CA_FILE requires VEGA_TLS_CERT_FILE and VEGA_TLS_KEY_FILE to be set")
+		}
+		log.Println("VEGA_TLS_CERT_FILE isn't set, gRPC server won't use TLS")
+		return nil
+	}
+	if certFile == "" || keyFile == "" {
+		return errors.New("both VEGA_TLS_CERT_FILE and VEGA_TLS_KEY_FILE must be set")
+	}
+
+	cert, err := tlsconfig.LoadCertificate(certFile, keyFile)
+	if err != nil {
+		return err
+	}
+	var clientCAs *tlsconfig.CAPool
+	if clientCAFile != "" {
+		if clientCAs, err = tlsconfig.LoadCAPool(clientCAFile); err != nil {
+			return err
+		}
+	}
+
+	return server.EnableTLS(tlsconfig.ServerConfig(cert, clientCAs))
+}
+
+func rotateEncryptionKeys(driver *encryption.Driver) {
+	buckets, err := driver.ListBuckets(&fileapplication.ListBucketsQuery{})
+	if err != nil {
+		log.Printf("Failed to rotate encryption keys: %v\n", err)
+		return
+	}
+
+	for _, bucket := range buckets {
+		if !driver.IsEncrypted(bucket.Name) {
+			continue
+		}
+		rotated, err := driver.RotateKeys(context.Background(), bucket.Name)
+		if err != nil {
+			log.Printf("Failed to rotate encryption keys of bucket \"%s\": %v\n", bucket.Name, err)
+			continue
+		}
+		log.Printf("Encryption keys of bucket \"%s\" are rotated: %d files rewrapped\n", bucket.Name, rotated)
+	}
+}
+
+func testgRPC() {
+	if err := connectStorage(); err != nil {
 		panic(err)
 	}
 	defer ObjectStorage.Driver.Disconnect()
@@ -36,23 +381,59 @@ func testgRPC() {
 	if err != nil {
 		panic(err)
 	}
+	verifier, policy, err := setupAuth()
+	if err != nil {
+		panic(err)
+	}
+	if verifier != nil {
+		if err := server.EnableAuth(verifier, policy); err != nil {
+			panic(err)
+		}
+	}
+	if err := setupRateLimits(server); err != nil {
+		panic(err)
+	}
+	if err := setupTLS(server); err != nil {
+		panic(err)
+	}
 
-	println("gRPC server started on port 50001")
+	// Lets grpcurl and similar tools discover RPCs, shouldn't be enabled for public servers
+	if os.Getenv("VEGA_GRPC_REFLECTION") == "true" {
+		server.EnableReflection()
+	}
 
-	if err := server.Start(50001); err != nil {
+	gateway := serveHTTP(verifier, policy)
+
+	uploadsCollector, err := setupUploadsCollector()
+	if err != nil {
 		panic(err)
 	}
+	uploadsCollector.Start()
+	defer uploadsCollector.Stop()
+
+	// Kubernetes sends SIGTERM before killing the pod, so in-flight uploads must be finished by then
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, os.Interrupt)
+	defer stop()
+
+	println("gRPC server started on port 50001")
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- server.Start(50001)
+	}()
+
+	select {
+	case err := <-serveErr:
+		if err != nil {
+			panic(err)
+		}
+	case <-ctx.Done():
+		shutdown(server, gateway)
+	}
 }
 
 func testStorage() {
-	err := ObjectStorage.Driver.Connect(&StorageConnection.Config{
-		URL:      "localhost:9000",
-		Login:    "minioadmin",
-		Password: "minioadmin",
-		Token:    "",
-		Secure:   false,
-	})
-	if err != nil {
+	if err := connectStorage(); err != nil {
 		panic(err)
 	}
 
@@ -60,7 +441,7 @@ func testStorage() {
 		panic("failed to ping object storage")
 	}
 
-	_, err = ObjectStorage.Driver.GetFileByPath(&fileapplication.GetFileByPathQuery{
+	_, err := ObjectStorage.Driver.GetFileByPath(&fileapplication.GetFileByPathQuery{
 		Path:   "/newfile.txt",
 		Bucket: "test-bucket",
 	})
@@ -84,7 +465,7 @@ func testStorage() {
 	// 	Paths: []string{"/test1.txt", "/test2.txt", "/test4.txt"},
 	// 	Bucket: "test-bucket",
 	// })
-	e := ObjectStorage.Driver.UpdateFileContent(&fileapplication.UpdateFileContentCommand{
+	_, e := ObjectStorage.Driver.UpdateFileContent(&fileapplication.UpdateFileContentCommand{
 		Path:       "/my-new-file.txt",
 		Bucket:     "test-bucket",
 		NewContent: bytes.NewReader([]byte("full replace upd test")),
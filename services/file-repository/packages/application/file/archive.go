@@ -0,0 +1,21 @@
+package fileapplication
+
+type ArchiveFormat uint8
+
+const (
+	ArchiveFormatNone ArchiveFormat = iota
+	ArchiveFormatTar
+	ArchiveFormatTarGzip
+	ArchiveFormatZip
+)
+
+var archiveFormatMap = map[ArchiveFormat]string{
+	ArchiveFormatNone:    "none",
+	ArchiveFormatTar:     "tar",
+	ArchiveFormatTarGzip: "tar.gz",
+	ArchiveFormatZip:     "zip",
+}
+
+func (f ArchiveFormat) String() string {
+	return archiveFormatMap[f]
+}
@@ -0,0 +1,129 @@
+package fileapplication
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"hash"
+	"io"
+	"strings"
+	"vega_file_repository/packages/domain/entity"
+)
+
+const ChecksumSHA256 string = "sha256"
+
+// Algorithm used if client hasn't specified expected checksum
+const DefaultChecksumAlgorithm = ChecksumSHA256
+
+var checksumHashers = map[string]func() hash.Hash{
+	ChecksumSHA256: sha256.New,
+}
+
+var (
+	ErrUnsupportedChecksumAlgorithm = errors.New("unsupported checksum algorithm")
+	ErrInvalidChecksum              = errors.New("invalid checksum: it must be hex encoded digest")
+	ErrChecksumMismatch             = errors.New("checksum of the received content doesn't match expected one")
+	ErrChecksumOfArchive            = errors.New("checksum verification isn't supported for archive uploads")
+)
+
+// Also normalizes checksum (algorithm and value are lowercased)
+func ValidateChecksum(checksum *entity.Checksum) error {
+	checksum.Algorithm = strings.ToLower(checksum.Algorithm)
+	checksum.Value = strings.ToLower(checksum.Value)
+
+	newHash, ok := checksumHashers[checksum.Algorithm]
+	if !ok {
+		return ErrUnsupportedChecksumAlgorithm
+	}
+	digest, err := hex.DecodeString(checksum.Value)
+	if err != nil || len(digest) != newHash().Size() {
+		return ErrInvalidChecksum
+	}
+	return nil
+}
+
+// Hashes content while it's read. If expected checksum is specified, then it's verified
+// right before the last byte of the content is returned: in case of mismatch ErrChecksumMismatch
+// is returned instead of it, so storage will never receive the whole content and won't store it.
+//
+// Only first size bytes are hashed, all reads after that are passed directly to the content
+// (so storage still can detect that content is bigger than declared).
+type ChecksumReader struct {
+	content   io.Reader
+	remaining int64
+	algorithm string
+	hash      hash.Hash
+	expected  *entity.Checksum
+	mismatch  bool
+}
+
+// expected may be nil, in that case content is hashed using DefaultChecksumAlgorithm.
+func NewChecksumReader(content io.Reader, size int64, expected *entity.Checksum) (*ChecksumReader, error) {
+	if content == nil {
+		content = strings.NewReader("")
+	}
+
+	algorithm := DefaultChecksumAlgorithm
+	if expected != nil {
+		if err := ValidateChecksum(expected); err != nil {
+			return nil, err
+		}
+		algorithm = expected.Algorithm
+	}
+
+	r := &ChecksumReader{
+		content:   content,
+		remaining: size,
+		algorithm: algorithm,
+		hash:      checksumHashers[algorithm](),
+		expected:  expected,
+	}
+	// Empty content won't be read at all
+	if size == 0 && !r.isExpected() {
+		return nil, ErrChecksumMismatch
+	}
+
+	return r, nil
+}
+
+func (r *ChecksumReader) Read(p []byte) (int, error) {
+	if r.remaining <= 0 {
+		return r.content.Read(p)
+	}
+	if int64(len(p)) > r.remaining {
+		p = p[:r.remaining]
+	}
+
+	n, err := r.content.Read(p)
+	r.hash.Write(p[:n])
+	r.remaining -= int64(n)
+
+	if r.remaining == 0 && !r.isExpected() {
+		r.mismatch = true
+		return 0, ErrChecksumMismatch
+	}
+
+	return n, err
+}
+
+func (r *ChecksumReader) isExpected() bool {
+	return r.expected == nil || r.expected.Value == hex.EncodeToString(r.hash.Sum(nil))
+}
+
+// Storages may wrap errors returned by reader, so this function must be used to
+// check if storage has failed due to checksum mismatch: in that case ErrChecksumMismatch
+// is returned, otherwise err itself.
+func (r *ChecksumReader) ResolveError(err error) error {
+	if r.mismatch {
+		return ErrChecksumMismatch
+	}
+	return err
+}
+
+// Returns checksum of the content read so far
+func (r *ChecksumReader) Checksum() *entity.Checksum {
+	return &entity.Checksum{
+		Algorithm: r.algorithm,
+		Value:     hex.EncodeToString(r.hash.Sum(nil)),
+	}
+}
@@ -1,9 +1,15 @@
 package fileapplication
 
 import (
+	"errors"
 	"io"
+	"strconv"
+	"strings"
+	"time"
+	"vega_file_repository/packages/domain/entity"
 
 	"github.com/abaxoth0/Vega/libs/go/packages/CQRS"
+	"github.com/abaxoth0/Vega/libs/go/packages/file"
 )
 
 type MkdirCommand struct {
@@ -13,11 +19,32 @@ type MkdirCommand struct {
 	cqrs.CommandQuery
 }
 
+var ErrPartialExtraction = errors.New("some of the archive entries weren't extracted")
+
+var (
+	ErrUploadOfDirectory  = errors.New("can't upload file as directory")
+	ErrUpdateOfDirectory  = errors.New("can't update content of directory")
+	ErrInvalidContentSize = errors.New("content size must be greater than 0")
+	// Storage may restrict paths further than the file package does (e.g. local one can't store "." segments)
+	ErrInvalidPath = errors.New("invalid path")
+	// Some storages (e.g. local one) can't have file and directory with the same path,
+	// so for example "/a" and "/a/b" can't exist at the same time.
+	ErrPathConflict = errors.New("path conflicts with existing file or directory")
+)
+
 type UploadFileCommand struct {
 	Content     io.Reader
 	ContentSize int64
 	Path        string
 	Bucket      string
+	// If specified, then Content will be treated as archive of this format
+	// and extracted into Path, which must be a directory in that case.
+	ArchiveFormat ArchiveFormat
+	// If specified, then file won't be stored unless checksum of the content matches this one.
+	// Can't be used with ArchiveFormat.
+	Checksum *entity.Checksum
+	// Can't be used with ArchiveFormat
+	Preconditions WritePreconditions
 
 	cqrs.CommandQuery
 }
@@ -27,17 +54,34 @@ type UpdateFileContentCommand struct {
 	Bucket     string
 	NewContent io.Reader
 	Size	   int64
+	// Same as UploadFileCommand.Checksum
+	Checksum *entity.Checksum
+	// Same as UploadFileCommand.Preconditions
+	Preconditions WritePreconditions
 
 	cqrs.CommandQuery
 }
 
+var ErrPartialDeletion = errors.New("some of the files weren't deleted")
+
 type DeleteFilesCommand struct {
 	Paths  []string
 	Bucket string
+	// If true, then all objects under directory paths will be deleted as well,
+	// otherwise only the directory marker will be.
+	Recursive bool
 
 	cqrs.CommandQuery
 }
 
+var (
+	ErrBucketAlreadyExists = errors.New("bucket already exists")
+	ErrBucketIsNotEmpty    = errors.New("bucket isn't empty")
+	ErrInvalidBucketName   = errors.New("invalid bucket name")
+	// Buckets which are used by the storage internally can't be created or deleted
+	ErrReservedBucket = errors.New("bucket is reserved by the storage")
+)
+
 type MakeBucketCommand struct {
 	Name string
 
@@ -50,3 +94,195 @@ type DeleteBucketCommand struct {
 
 	cqrs.CommandQuery
 }
+
+// Once enabled, each overwrite or deletion of the file keeps its previous content as a separate version.
+// Disabling versioning doesn't delete existing versions, but new ones won't be created.
+type SetBucketVersioningCommand struct {
+	Name    string
+	Enabled bool
+
+	cqrs.CommandQuery
+}
+
+const (
+	MinUploadPartNumber int = 1
+	MaxUploadPartNumber int = 10000
+)
+
+var (
+	ErrUploadDoesNotExist      = errors.New("upload session doesn't exist (it may be already completed or aborted)")
+	ErrNoUploadParts           = errors.New("upload has no parts")
+	ErrInvalidUploadPartNumber = errors.New(
+		"invalid part number: must be between " + strconv.Itoa(MinUploadPartNumber) +
+			" and " + strconv.Itoa(MaxUploadPartNumber),
+	)
+)
+
+// Starts resumable upload session. Content is sent by numbered parts, each one of them
+// can be (re)uploaded independently. File appears in storage only after session is completed.
+type InitiateUploadCommand struct {
+	Bucket string
+	Path   string
+
+	cqrs.CommandQuery
+}
+
+// Uploading part with the same number again will replace previous one.
+type UploadPartCommand struct {
+	Bucket     string
+	Path       string
+	UploadID   string
+	PartNumber int
+	Content    io.Reader
+	Size       int64
+
+	cqrs.CommandQuery
+}
+
+// Assembles all uploaded parts (ordered by their numbers) into file.
+type CompleteUploadCommand struct {
+	Bucket   string
+	Path     string
+	UploadID string
+
+	cqrs.CommandQuery
+}
+
+type AbortUploadCommand struct {
+	Bucket   string
+	Path     string
+	UploadID string
+
+	cqrs.CommandQuery
+}
+
+// Aborts all upload sessions (in all buckets) which were initiated earlier than MaxAge ago.
+type AbortStaleUploadsCommand struct {
+	MaxAge time.Duration
+
+	cqrs.CommandQuery
+}
+
+// Defines what to do if destination file of the copy or move already exists.
+type OverwriteMode uint8
+
+const (
+	// Nothing is copied (or moved) if at least one destination file already exists
+	OverwriteFail OverwriteMode = iota
+	// Existing destination files are left untouched, other files are copied (or moved) as usual
+	OverwriteSkip
+	OverwriteReplace
+)
+
+var overwriteModeMap = map[OverwriteMode]string{
+	OverwriteFail:    "fail",
+	OverwriteSkip:    "skip",
+	OverwriteReplace: "replace",
+}
+
+func (m OverwriteMode) String() string {
+	return overwriteModeMap[m]
+}
+
+var (
+	ErrDestinationExists      = errors.New("destination file already exists")
+	ErrPartialRelocation      = errors.New("some of the files weren't copied (or moved)")
+	ErrRelocationTypeMismatch = errors.New("source and destination must be both files or both directories")
+	ErrRelocationIntoItself   = errors.New("destination can't be the same as source or be inside of it")
+	ErrUnknownOverwriteMode   = errors.New("unknown overwrite mode")
+)
+
+// Source and destination of CopyFilesCommand and MoveFilesCommand.
+// If SourcePath is a directory, then all files under it will be relocated
+// into DestinationPath (which must be a directory as well) preserving their relative paths.
+type FilesRelocation struct {
+	SourceBucket string
+	SourcePath   string
+	// If empty, then SourceBucket is used
+	DestinationBucket string
+	DestinationPath   string
+	Overwrite         OverwriteMode
+}
+
+// Also sets DestinationBucket to SourceBucket if it's empty
+func (r *FilesRelocation) Validate() error {
+	if err := file.ValidatePathFormat(r.SourcePath); err != nil {
+		return err
+	}
+	if err := file.ValidatePathFormat(r.DestinationPath); err != nil {
+		return err
+	}
+	if _, ok := overwriteModeMap[r.Overwrite]; !ok {
+		return ErrUnknownOverwriteMode
+	}
+	if file.IsDirectory(r.SourcePath) != file.IsDirectory(r.DestinationPath) {
+		return ErrRelocationTypeMismatch
+	}
+	if r.DestinationBucket == "" {
+		r.DestinationBucket = r.SourceBucket
+	}
+	if r.DestinationBucket == r.SourceBucket {
+		if r.DestinationPath == r.SourcePath ||
+			(file.IsDirectory(r.SourcePath) && strings.HasPrefix(r.DestinationPath, r.SourcePath)) {
+			return ErrRelocationIntoItself
+		}
+	}
+	return nil
+}
+
+// Returns path where file with specified source path must be relocated.
+func (r *FilesRelocation) DestinationOf(sourcePath string) string {
+	return r.DestinationPath + strings.TrimPrefix(sourcePath, r.SourcePath)
+}
+
+// Copying is performed on the storage side, so content doesn't go through the service.
+type CopyFilesCommand struct {
+	FilesRelocation
+
+	cqrs.CommandQuery
+}
+
+// Moves (or renames) files. Source file is deleted only after it was successfully copied.
+type MoveFilesCommand struct {
+	FilesRelocation
+
+	cqrs.CommandQuery
+}
+
+var ErrVersionIDNotSpecified = errors.New("version ID isn't specified")
+
+// Version of the file targeted by RestoreFileVersionCommand and DeleteFileVersionCommand.
+type FileVersionTarget struct {
+	Bucket    string
+	Path      string
+	VersionID string
+}
+
+func (t *FileVersionTarget) Validate() error {
+	if err := file.ValidatePathFormat(t.Path); err != nil {
+		return err
+	}
+	if file.IsDirectory(t.Path) {
+		return ErrVersionOfDirectory
+	}
+	if t.VersionID == "" {
+		return ErrVersionIDNotSpecified
+	}
+	return nil
+}
+
+// Makes content of the specified version a current content of the file.
+// Restored content is saved as a new version, so history of the file is never rewritten.
+type RestoreFileVersionCommand struct {
+	FileVersionTarget
+
+	cqrs.CommandQuery
+}
+
+// Permanently deletes specified version of the file.
+// If it's the current version, then previous one (if any) becomes current.
+type DeleteFileVersionCommand struct {
+	FileVersionTarget
+
+	cqrs.CommandQuery
+}
@@ -0,0 +1,55 @@
+package fileapplication
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	ErrPreconditionFailed        = errors.New("precondition of the write isn't satisfied (file was changed or already exists)")
+	ErrConflictingPreconditions  = errors.New("create-only precondition can't be combined with other preconditions")
+	ErrPreconditionsOfArchive    = errors.New("preconditions aren't supported for archive uploads")
+	ErrPreconditionsNotSupported = errors.New("preconditions aren't supported by the storage")
+)
+
+// Preconditions of the write, the same as in HTTP conditional requests. Drivers check them atomically
+// with the write itself, so concurrent writers can't overwrite changes of each other unnoticed.
+// If any of them isn't satisfied, then file isn't written and ErrPreconditionFailed is returned.
+type WritePreconditions struct {
+	// If specified, then file is written only if its current ETag is the same.
+	// "*" means that file must exist, regardless of its ETag.
+	IfMatch string
+	// If true, then file is written only if it doesn't exist yet (the same as "If-None-Match: *")
+	IfNoneMatch bool
+	// If specified, then file is written only if it exists and wasn't modified after this time.
+	// Compared with precision of seconds, same as HTTP dates.
+	IfUnmodifiedSince time.Time
+}
+
+func (p *WritePreconditions) IsSet() bool {
+	return p.IfMatch != "" || p.IfNoneMatch || !p.IfUnmodifiedSince.IsZero()
+}
+
+// Also normalizes preconditions (quotes around ETag are trimmed)
+func (p *WritePreconditions) Validate() error {
+	p.IfMatch = strings.Trim(p.IfMatch, "\"")
+	if p.IfNoneMatch && (p.IfMatch != "" || !p.IfUnmodifiedSince.IsZero()) {
+		return ErrConflictingPreconditions
+	}
+	return nil
+}
+
+// Checks preconditions against current state of the file, exists is false if there is no such file.
+func (p *WritePreconditions) Check(exists bool, etag string, lastModified time.Time) error {
+	if p.IfNoneMatch && exists {
+		return ErrPreconditionFailed
+	}
+	if p.IfMatch != "" && (!exists || (p.IfMatch != "*" && p.IfMatch != strings.Trim(etag, "\""))) {
+		return ErrPreconditionFailed
+	}
+	if !p.IfUnmodifiedSince.IsZero() && (!exists || lastModified.Truncate(time.Second).After(p.IfUnmodifiedSince)) {
+		return ErrPreconditionFailed
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package fileapplication
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/abaxoth0/Vega/libs/go/packages/CQRS"
+	"github.com/abaxoth0/Vega/libs/go/packages/file"
+)
+
+const (
+	DefaultPresignedURLExpiry = time.Minute * 15
+	// The same as max expiry of S3 presigned URLs
+	MaxPresignedURLExpiry = time.Hour * 24 * 7
+)
+
+var (
+	// Returned by drivers which can't presign URL for the file (e.g. if its content is transformed by the service),
+	// in that case URL may be signed by the service itself (see presign package).
+	ErrPresignNotSupported    = errors.New("storage can't presign URL for this file")
+	ErrInvalidPresignMethod   = errors.New("only GET and PUT requests can be presigned")
+	ErrPresignedURLExpiry     = errors.New("presigned URL can't expire later than in 7 days")
+	ErrPresignOfDirectory     = errors.New("URL can't be presigned for directory")
+	ErrRestrictionsOfDownload = errors.New("content type and size can be restricted only for uploads")
+)
+
+// Presigned URL allows to download (GET) or upload (PUT) the file without any other authorization till it expires.
+type CreatePresignedURLQuery struct {
+	Bucket string
+	Path   string
+	// http.MethodGet or http.MethodPut
+	Method string
+	// If <= 0, then DefaultPresignedURLExpiry is used
+	Expiry time.Duration
+	// Restrictions of the upload: if specified, then upload must be sent with exactly such Content-Type header
+	ContentType string
+	// and with exactly such Content-Length.
+	Size int64
+
+	cqrs.CommandQuery
+}
+
+// Also sets default expiry if it isn't specified
+func (q *CreatePresignedURLQuery) Validate() error {
+	if err := file.ValidatePathFormat(q.Path); err != nil {
+		return err
+	}
+	if file.IsDirectory(q.Path) {
+		return ErrPresignOfDirectory
+	}
+	if q.Method != http.MethodGet && q.Method != http.MethodPut {
+		return ErrInvalidPresignMethod
+	}
+	if q.Method == http.MethodGet && (q.ContentType != "" || q.Size != 0) {
+		return ErrRestrictionsOfDownload
+	}
+	if q.Size < 0 {
+		return errors.New("size can't be negative")
+	}
+	if q.Expiry <= 0 {
+		q.Expiry = DefaultPresignedURLExpiry
+	}
+	if q.Expiry > MaxPresignedURLExpiry {
+		return ErrPresignedURLExpiry
+	}
+	return nil
+}
@@ -2,8 +2,10 @@ package fileapplication
 
 import (
 	"errors"
+	"vega_file_repository/packages/domain/entity"
 
 	cqrs "github.com/abaxoth0/Vega/libs/go/packages/CQRS"
+	"github.com/abaxoth0/Vega/libs/go/packages/file"
 )
 
 var (
@@ -11,9 +13,146 @@ var (
 	ErrBucketDoesNotExist = errors.New("requested bucket doesn't exist")
 )
 
+var ErrArchiveFormatNotSpecified = errors.New("requested file is directory, but archive format isn't specified")
+
+var ErrVersionOfDirectory = errors.New("directories have no versions")
+
+var (
+	ErrInvalidRange        = errors.New("invalid range: offset and length can't be negative")
+	ErrRangeNotSatisfiable = errors.New("requested range not satisfiable")
+	ErrRangeOfDirectory    = errors.New("range can't be requested for directory")
+)
+
 type GetFileByPathQuery struct {
 	Bucket string
 	Path   string
+	// Used only if Path is a directory, in that case
+	// the whole subtree will be sent as archive of this format.
+	ArchiveFormat ArchiveFormat
+	// Index of the first byte to read
+	Offset int64
+	// Max amount of bytes to read, if 0 then file will be read till the end
+	Length int64
+	// If specified, then this version of the file will be read instead of the current one.
+	// Can't be used for directories.
+	VersionID string
+
+	cqrs.CommandQuery
+}
+
+func (q *GetFileByPathQuery) IsRanged() bool {
+	return q.Offset != 0 || q.Length != 0
+}
+
+// Converts Offset and Length into range of bytes of the file with specified size.
+// Length is truncated if it exceeds the end of the file.
+func (q *GetFileByPathQuery) ResolveRange(size int64) (entity.ByteRange, error) {
+	if q.Offset < 0 || q.Length < 0 {
+		return entity.ByteRange{}, ErrInvalidRange
+	}
+	if size == 0 && q.Offset == 0 {
+		return entity.ByteRange{Start: 0, End: -1}, nil
+	}
+	if q.Offset >= size {
+		return entity.ByteRange{}, ErrRangeNotSatisfiable
+	}
+
+	r := entity.ByteRange{Start: q.Offset, End: size - 1}
+	if q.Length > 0 && q.Offset+q.Length < size {
+		r.End = q.Offset + q.Length - 1
+	}
+
+	return r, nil
+}
+
+const (
+	DefaultListDirectoryLimit int = 1000
+	// S3-compatible storages won't return more than 1000 keys per request anyway
+	MaxListDirectoryLimit int = 1000
+)
+
+type ListDirectoryQuery struct {
+	Bucket string
+	// Must be a directory, use "/" to list the root of the bucket.
+	// Directory which doesn't exist is listed as empty one, since in object storages directories are just prefixes of the keys.
+	Path string
+	// If true, then all entries in subtree will be listed,
+	// otherwise only direct children of the directory.
+	Recursive bool
+	// Max amount of entries per page. If <= 0, then DefaultListDirectoryLimit will be used.
+	Limit int
+	// Token returned in previous page (path of its last entry), leave empty to get the first one.
+	ContinuationToken string
+
+	cqrs.CommandQuery
+}
+
+type ListUploadedPartsQuery struct {
+	Bucket   string
+	Path     string
+	UploadID string
+
+	cqrs.CommandQuery
+}
+
+type ListBucketsQuery struct {
+	cqrs.CommandQuery
+}
+
+// Objects are counted one by one, so for big buckets it may take a while.
+type GetBucketInfoQuery struct {
+	Name string
+
+	cqrs.CommandQuery
+}
+
+type ListFileVersionsQuery struct {
+	Bucket string
+	Path   string
 
 	cqrs.CommandQuery
 }
+
+// Statting is cheap, but amount of paths must be limited anyway, since all of them are handled at once
+const MaxStatFilePaths int = 1000
+
+var (
+	ErrNoPathsToStat          = errors.New("at least one path must be specified")
+	ErrTooManyPathsToStat     = errors.New("too many paths to stat")
+	ErrVersionOfMultipleFiles = errors.New("version ID can be specified only if single path is requested")
+)
+
+// Paths which don't exist aren't treated as errors, instead they are reported with FileStat.Exists set to false.
+// Directory exists if it has marker (see Mkdir) or any entries.
+type StatFileQuery struct {
+	Bucket string
+	Paths  []string
+	// If specified, then this version of the file will be statted instead of the current one.
+	// Can be used only with single path, which isn't a directory.
+	VersionID string
+
+	cqrs.CommandQuery
+}
+
+func (q *StatFileQuery) Validate() error {
+	if len(q.Paths) == 0 {
+		return ErrNoPathsToStat
+	}
+	if len(q.Paths) > MaxStatFilePaths {
+		return ErrTooManyPathsToStat
+	}
+	for _, path := range q.Paths {
+		if err := file.ValidatePathFormat(path); err != nil {
+			return err
+		}
+	}
+	if q.VersionID != "" {
+		if len(q.Paths) > 1 {
+			return ErrVersionOfMultipleFiles
+		}
+		if file.IsDirectory(q.Paths[0]) {
+			return ErrVersionOfDirectory
+		}
+	}
+	return nil
+}
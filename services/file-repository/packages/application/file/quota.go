@@ -0,0 +1,35 @@
+package fileapplication
+
+import (
+	"errors"
+	"vega_file_repository/packages/domain/entity"
+
+	"github.com/abaxoth0/Vega/libs/go/packages/CQRS"
+)
+
+var (
+	ErrQuotaExceeded           = errors.New("storage quota exceeded")
+	ErrNoQuota                 = errors.New("quota isn't defined")
+	ErrInvalidQuotaUsageTarget = errors.New("exactly one of the bucket and owner must be specified")
+)
+
+// Implemented only by drivers which enforce quotas. Writes which would exceed the quota
+// fail with ErrQuotaExceeded before any content is read.
+type QuotaUsageReader interface {
+	GetQuotaUsage(query *GetQuotaUsageQuery) (*entity.QuotaUsage, error)
+}
+
+// Usage of the owner is the total usage of all of its buckets
+type GetQuotaUsageQuery struct {
+	Bucket string
+	Owner  string
+
+	cqrs.CommandQuery
+}
+
+func (q *GetQuotaUsageQuery) Validate() error {
+	if (q.Bucket == "") == (q.Owner == "") {
+		return ErrInvalidQuotaUsageTarget
+	}
+	return nil
+}
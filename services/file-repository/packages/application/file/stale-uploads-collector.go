@@ -0,0 +1,98 @@
+package fileapplication
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	cqrs "github.com/abaxoth0/Vega/libs/go/packages/CQRS"
+)
+
+// Periodically aborts upload sessions which weren't completed (or aborted) in time.
+// Without it parts of abandoned uploads will occupy storage forever.
+type StaleUploadsCollector struct {
+	handler  CommandHandler
+	interval time.Duration
+	maxAge   time.Duration
+
+	mu      sync.Mutex
+	stop    chan struct{}
+	stopped chan struct{}
+}
+
+// Interval is a delay between collections,
+// maxAge is a min age of the upload session to be considered stale.
+func NewStaleUploadsCollector(handler CommandHandler, interval time.Duration, maxAge time.Duration) *StaleUploadsCollector {
+	return &StaleUploadsCollector{
+		handler:  handler,
+		interval: interval,
+		maxAge:   maxAge,
+	}
+}
+
+// Starts collection in a new goroutine. Does nothing if collector is already started.
+func (c *StaleUploadsCollector) Start() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.stop != nil {
+		return
+	}
+
+	c.stop = make(chan struct{})
+	c.stopped = make(chan struct{})
+
+	go c.run(c.stop, c.stopped)
+}
+
+// Stops collection and waits until current one (if there is any) finishes.
+// Does nothing if collector isn't started.
+func (c *StaleUploadsCollector) Stop() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.stop == nil {
+		return
+	}
+
+	close(c.stop)
+	<-c.stopped
+
+	c.stop = nil
+	c.stopped = nil
+}
+
+func (c *StaleUploadsCollector) run(stop <-chan struct{}, stopped chan<- struct{}) {
+	defer close(stopped)
+
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			c.Collect()
+		}
+	}
+}
+
+// Aborts all stale upload sessions right away
+func (c *StaleUploadsCollector) Collect() {
+	aborted, err := c.handler.AbortStaleUploads(&AbortStaleUploadsCommand{
+		MaxAge: c.maxAge,
+		// Collection must not take more time than interval between them
+		CommandQuery: cqrs.CommandQuery{
+			Context:        context.Background(),
+			ContextTimeout: c.interval,
+		},
+	})
+	if err != nil {
+		log.Printf("Failed to abort stale uploads: %v\n", err)
+	}
+	if aborted > 0 {
+		log.Printf("Aborted %d stale uploads\n", aborted)
+	}
+}
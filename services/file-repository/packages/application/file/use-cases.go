@@ -11,13 +11,45 @@ type UseCases interface {
 
 type QueryHandler interface {
 	GetFileByPath(query *GetFileByPathQuery) (*entity.FileStream, error)
+	ListDirectory(query *ListDirectoryQuery) (*entity.DirectoryListing, error)
+	// Returns parts ordered by their numbers
+	ListUploadedParts(query *ListUploadedPartsQuery) ([]entity.UploadPart, error)
+	// Returns buckets ordered by their names
+	ListBuckets(query *ListBucketsQuery) ([]entity.Bucket, error)
+	GetBucketInfo(query *GetBucketInfoQuery) (*entity.BucketInfo, error)
+	// Returns versions ordered from the newest to the oldest one
+	ListFileVersions(query *ListFileVersionsQuery) ([]entity.FileVersion, error)
+	// Returns stats in the same order as paths in the query
+	StatFile(query *StatFileQuery) ([]entity.FileStat, error)
+	// May return ErrPresignNotSupported if storage itself can't presign URL for the file
+	CreatePresignedURL(query *CreatePresignedURLQuery) (*entity.PresignedURL, error)
 }
 
 type CommandHandler interface {
 	Mkdir(cmd *MkdirCommand) error
-	UploadFile(cmd *UploadFileCommand) error
-	UpdateFileContent(cmd *UpdateFileContentCommand) error
-	DeleteFiles(cmd *DeleteFilesCommand) error
+	// Besides regular errors may return ErrPartialExtraction alongside with
+	// result if some of the archive entries weren't extracted.
+	UploadFile(cmd *UploadFileCommand) (*entity.UploadResult, error)
+	UpdateFileContent(cmd *UpdateFileContentCommand) (*entity.UploadResult, error)
+	// Besides regular errors may return ErrPartialDeletion alongside with
+	// report if some of the objects weren't deleted.
+	DeleteFiles(cmd *DeleteFilesCommand) (*entity.DeletionReport, error)
+	// Besides regular errors may return ErrPartialRelocation alongside with
+	// report if some of the files weren't copied.
+	CopyFiles(cmd *CopyFilesCommand) (*entity.RelocationReport, error)
+	// The same as CopyFiles, but successfully copied files are deleted from source.
+	MoveFiles(cmd *MoveFilesCommand) (*entity.RelocationReport, error)
+	// Returns ID of the created upload session
+	InitiateUpload(cmd *InitiateUploadCommand) (string, error)
+	UploadPart(cmd *UploadPartCommand) (*entity.UploadPart, error)
+	CompleteUpload(cmd *CompleteUploadCommand) error
+	AbortUpload(cmd *AbortUploadCommand) error
+	// Returns amount of aborted sessions
+	AbortStaleUploads(cmd *AbortStaleUploadsCommand) (int, error)
 	MakeBucket(cmd *MakeBucketCommand) error
 	DeleteBucket(cmd *DeleteBucketCommand) error
+	SetBucketVersioning(cmd *SetBucketVersioningCommand) error
+	// Returns ID of the new current version
+	RestoreFileVersion(cmd *RestoreFileVersionCommand) (string, error)
+	DeleteFileVersion(cmd *DeleteFileVersionCommand) error
 }
@@ -0,0 +1,18 @@
+package entity
+
+import "time"
+
+type Bucket struct {
+	Name         string
+	CreationDate time.Time
+}
+
+type BucketInfo struct {
+	Bucket
+	// Directory markers are counted as well
+	ObjectCount int64
+	// Total size of all objects in bytes (previous versions of files aren't counted)
+	TotalSize int64
+	// If true, then previous content of files is kept on overwrite and deletion
+	VersioningEnabled bool
+}
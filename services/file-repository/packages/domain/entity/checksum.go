@@ -0,0 +1,9 @@
+package entity
+
+// Digest of the file content
+type Checksum struct {
+	// Name of the hash algorithm, e.g. "sha256"
+	Algorithm string
+	// Hex encoded digest
+	Value string
+}
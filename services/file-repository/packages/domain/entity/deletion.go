@@ -0,0 +1,35 @@
+package entity
+
+type DeletionFailure struct {
+	// Path of the object which wasn't deleted
+	Path   string
+	Reason string
+}
+
+// Result of deletion of a single requested path.
+// If path is a directory and deletion is recursive, then it covers all objects in its subtree.
+type DeletionResult struct {
+	Path         string
+	DeletedCount int64
+	Failures     []DeletionFailure
+}
+
+type DeletionReport struct {
+	Results []DeletionResult
+}
+
+func (r *DeletionReport) DeletedCount() int64 {
+	var count int64
+	for _, result := range r.Results {
+		count += result.DeletedCount
+	}
+	return count
+}
+
+func (r *DeletionReport) Failures() []DeletionFailure {
+	failures := []DeletionFailure{}
+	for _, result := range r.Results {
+		failures = append(failures, result.Failures...)
+	}
+	return failures
+}
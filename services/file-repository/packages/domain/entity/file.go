@@ -3,11 +3,71 @@ package entity
 import (
 	"context"
 	"io"
+	"time"
 )
 
+// Inclusive range of bytes (the same as in HTTP Range header)
+type ByteRange struct {
+	Start int64
+	End   int64
+}
+
+func (r ByteRange) Length() int64 {
+	return r.End - r.Start + 1
+}
+
 type FileStream struct {
 	Content io.Reader
-	Size    int64
-	Context context.Context
-	Cancel  context.CancelFunc
+	// Size of the whole file, -1 if it's unknown beforehand (e.g. for directory archives)
+	Size int64
+	// Bytes of the file which are actually streamed in Content.
+	// For empty files End is -1, for directory archives it's unset.
+	Range ByteRange
+	// Checksum which was verified when file was uploaded, nil if it's unknown
+	Checksum *Checksum
+	Context  context.Context
+	Cancel   context.CancelFunc
+}
+
+type FileInfo struct {
+	Path string
+	// Size of the content
+	Size int64
+	// Size which file actually occupies in the storage, differs from Size if content
+	// is transformed before it's stored (e.g. compressed)
+	StoredSize   int64
+	LastModified time.Time
+	ETag         string
+	// true if path ends with '/' (see file.IsDirectory)
+	IsDirectory bool
+}
+
+// Information about the file which is available without reading its content
+type FileStat struct {
+	Path string
+	// If false, then all other fields are unset
+	Exists bool
+	// Same as FileInfo.Size
+	Size int64
+	// Same as FileInfo.StoredSize
+	StoredSize   int64
+	LastModified time.Time
+	ETag         string
+	// Empty if it's unknown
+	ContentType string
+	// Metadata which was set by the client, internal metadata of the storage isn't included
+	Metadata map[string]string
+	// Empty if versioning was never enabled for the bucket
+	VersionID string
+	// Checksum which was verified when file was uploaded, nil if it's unknown
+	Checksum *Checksum
+	// true if path ends with '/' (see file.IsDirectory)
+	IsDirectory bool
+}
+
+type DirectoryListing struct {
+	Entries []FileInfo
+	// Empty if there are no more entries left
+	NextContinuationToken string
+	IsTruncated           bool
 }
@@ -0,0 +1,12 @@
+package entity
+
+import "time"
+
+type PresignedURL struct {
+	URL string
+	// HTTP method which must be used with URL
+	Method    string
+	ExpiresAt time.Time
+	// Headers which must be sent with the request as is (e.g. restrictions of the upload)
+	Headers map[string]string
+}
@@ -0,0 +1,12 @@
+package entity
+
+// Current usage of the bucket (or of all buckets of the owner) against its quota.
+// Only current versions of the files are counted, directory markers aren't counted at all.
+type QuotaUsage struct {
+	// Total size of the files in bytes
+	UsedBytes   int64
+	UsedObjects int64
+	// 0 means that there is no limit
+	MaxBytes   int64
+	MaxObjects int64
+}
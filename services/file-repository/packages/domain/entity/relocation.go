@@ -0,0 +1,46 @@
+package entity
+
+type RelocatedFile struct {
+	SourcePath      string
+	DestinationPath string
+	// True if destination file already existed and overwrite mode is "skip"
+	Skipped bool
+	// Empty if file was relocated (or skipped) successfully
+	Error string
+}
+
+// Result of copying or moving of files. If source is a directory,
+// then it contains all files (including directory markers) in its subtree.
+type RelocationReport struct {
+	Files []RelocatedFile
+}
+
+func (r *RelocationReport) RelocatedCount() int64 {
+	var count int64
+	for _, f := range r.Files {
+		if !f.Skipped && f.Error == "" {
+			count++
+		}
+	}
+	return count
+}
+
+func (r *RelocationReport) SkippedCount() int64 {
+	var count int64
+	for _, f := range r.Files {
+		if f.Skipped {
+			count++
+		}
+	}
+	return count
+}
+
+func (r *RelocationReport) Failures() []RelocatedFile {
+	failures := []RelocatedFile{}
+	for _, f := range r.Files {
+		if f.Error != "" {
+			failures = append(failures, f)
+		}
+	}
+	return failures
+}
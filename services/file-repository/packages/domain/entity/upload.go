@@ -0,0 +1,38 @@
+package entity
+
+import "time"
+
+type ExtractedEntry struct {
+	// Path of the created object, or name of the entry inside archive if it was rejected
+	Path        string
+	Size        int64
+	IsDirectory bool
+	// Empty if entry was extracted successfully
+	Error string
+}
+
+type UploadResult struct {
+	// Filled only if content was uploaded as archive
+	Entries []ExtractedEntry
+	// Checksum computed while content was uploaded, nil for archives
+	Checksum *Checksum
+	// ETag of the written file, empty for archives
+	ETag string
+}
+
+func (r *UploadResult) FailedEntries() []ExtractedEntry {
+	failed := []ExtractedEntry{}
+	for _, entry := range r.Entries {
+		if entry.Error != "" {
+			failed = append(failed, entry)
+		}
+	}
+	return failed
+}
+
+type UploadPart struct {
+	PartNumber   int
+	ETag         string
+	Size         int64
+	LastModified time.Time
+}
@@ -0,0 +1,14 @@
+package entity
+
+import "time"
+
+type FileVersion struct {
+	VersionID    string
+	Size         int64
+	LastModified time.Time
+	ETag         string
+	// true for the current version of the file
+	IsLatest bool
+	// Deletion of the file in versioned bucket is recorded as version without content
+	IsDeleteMarker bool
+}
@@ -0,0 +1,94 @@
+package archive
+
+import (
+	"io"
+	FileApplication "vega_file_repository/packages/application/file"
+	"vega_file_repository/packages/domain/entity"
+
+	"github.com/abaxoth0/Vega/libs/go/packages/file"
+)
+
+// Stores single extracted entry at path. For directories content is nil.
+type StoreFunc func(path string, entry *Entry, content io.Reader) error
+
+// Extracts archive entries one by one into dirPath using store.
+//
+// Unsafe (e.g. "../../etc/passwd") and unsupported (e.g. symlinks) entries are rejected,
+// but this doesn't stop extraction of other entries. If any entry was rejected or failed
+// to be stored, then FileApplication.ErrPartialExtraction is returned alongside with result.
+func Extract(
+	format FileApplication.ArchiveFormat,
+	src io.Reader,
+	size int64,
+	dirPath string,
+	store StoreFunc,
+) (*entity.UploadResult, error) {
+	reader, err := NewReader(format, src, size)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	result := &entity.UploadResult{Entries: []entity.ExtractedEntry{}}
+	failed := false
+
+	for {
+		entry, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return result, err
+		}
+		if entry.Path == "" {
+			continue
+		}
+
+		extracted := extractEntry(dirPath, entry, reader, store)
+		if extracted.Error != "" {
+			failed = true
+		}
+		result.Entries = append(result.Entries, extracted)
+	}
+
+	if failed {
+		return result, FileApplication.ErrPartialExtraction
+	}
+
+	return result, nil
+}
+
+func extractEntry(dirPath string, entry *Entry, content io.Reader, store StoreFunc) entity.ExtractedEntry {
+	extracted := entity.ExtractedEntry{
+		Path:        entry.Path,
+		Size:        entry.Size,
+		IsDirectory: entry.Kind == EntryDirectory,
+	}
+
+	if entry.Kind == EntryUnsupported {
+		extracted.Error = "unsupported entry type"
+		return extracted
+	}
+
+	name, err := ValidateEntryPath(entry.Path)
+	if err != nil {
+		extracted.Error = err.Error()
+		return extracted
+	}
+
+	path := dirPath + name
+	if err := file.ValidatePathFormat(path); err != nil {
+		extracted.Error = err.Error()
+		return extracted
+	}
+	extracted.Path = path
+
+	if entry.Kind == EntryDirectory {
+		content = nil
+	}
+	if err := store(path, entry, content); err != nil {
+		extracted.Error = err.Error()
+	}
+
+	return extracted
+}
@@ -0,0 +1,211 @@
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"time"
+	FileApplication "vega_file_repository/packages/application/file"
+)
+
+var ErrUnsafeEntryPath = errors.New("unsafe archive entry path")
+
+type EntryKind uint8
+
+const (
+	EntryFile EntryKind = iota
+	EntryDirectory
+	// Symlinks, hard links, devices and so on. Such entries can't be stored in object storage.
+	EntryUnsupported
+)
+
+type Entry struct {
+	// Relative to the root of archive, without leading "./"
+	Path    string
+	Kind    EntryKind
+	Size    int64
+	ModTime time.Time
+}
+
+// Iterates over archive entries. Content of current entry can be read via Read().
+type Reader interface {
+	// Returns io.EOF if there are no more entries
+	Next() (*Entry, error)
+	Read(p []byte) (int, error)
+	// Releases all resources allocated by reader, but doesn't close the source
+	Close() error
+}
+
+// Size is a max size of the archive. Tar archives are read as a stream,
+// but zip requires random access, so it's buffered in temporary file first.
+func NewReader(format FileApplication.ArchiveFormat, src io.Reader, size int64) (Reader, error) {
+	switch format {
+	case FileApplication.ArchiveFormatTar:
+		return &tarReader{tar: tar.NewReader(src)}, nil
+	case FileApplication.ArchiveFormatTarGzip:
+		gz, err := gzip.NewReader(src)
+		if err != nil {
+			return nil, err
+		}
+		return &tarReader{tar: tar.NewReader(gz), gzip: gz}, nil
+	case FileApplication.ArchiveFormatZip:
+		return newZipReader(src, size)
+	}
+	return nil, ErrUnsupportedFormat
+}
+
+// Verifies that entry path won't escape the directory where archive is extracted.
+// Returns cleaned path.
+func ValidateEntryPath(path string) (string, error) {
+	path = strings.TrimPrefix(path, "./")
+	if path == "" || strings.HasPrefix(path, "/") || strings.Contains(path, "\\") {
+		return "", ErrUnsafeEntryPath
+	}
+	for _, segment := range strings.Split(strings.TrimSuffix(path, "/"), "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return "", ErrUnsafeEntryPath
+		}
+	}
+	return path, nil
+}
+
+type tarReader struct {
+	tar *tar.Reader
+	// nil if archive isn't compressed
+	gzip *gzip.Reader
+}
+
+func (r *tarReader) Next() (*Entry, error) {
+	header, err := r.tar.Next()
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &Entry{
+		Path:    strings.TrimPrefix(header.Name, "./"),
+		Size:    header.Size,
+		ModTime: header.ModTime,
+	}
+	switch header.Typeflag {
+	case tar.TypeReg:
+		entry.Kind = EntryFile
+	case tar.TypeDir:
+		entry.Kind = EntryDirectory
+		entry.Size = 0
+		// Path is empty if entry is the root of archive (e.g. "./")
+		if entry.Path != "" && !strings.HasSuffix(entry.Path, "/") {
+			entry.Path += "/"
+		}
+	default:
+		entry.Kind = EntryUnsupported
+	}
+
+	return entry, nil
+}
+
+func (r *tarReader) Read(p []byte) (int, error) {
+	return r.tar.Read(p)
+}
+
+func (r *tarReader) Close() error {
+	if r.gzip != nil {
+		return r.gzip.Close()
+	}
+	return nil
+}
+
+type zipReader struct {
+	tmp     *os.File
+	zip     *zip.Reader
+	next    int
+	current io.ReadCloser
+}
+
+func newZipReader(src io.Reader, size int64) (*zipReader, error) {
+	tmp, err := os.CreateTemp("", "vega-archive-*.zip")
+	if err != nil {
+		return nil, err
+	}
+	r := &zipReader{tmp: tmp}
+
+	// +1 to detect if archive is bigger than declared
+	n, err := io.Copy(tmp, io.LimitReader(src, size+1))
+	if err != nil {
+		r.Close()
+		return nil, err
+	}
+	if n > size {
+		r.Close()
+		return nil, errors.New("archive is bigger than declared size")
+	}
+
+	r.zip, err = zip.NewReader(tmp, n)
+	if err != nil {
+		r.Close()
+		return nil, err
+	}
+
+	return r, nil
+}
+
+func (r *zipReader) Next() (*Entry, error) {
+	if r.current != nil {
+		r.current.Close()
+		r.current = nil
+	}
+	if r.next >= len(r.zip.File) {
+		return nil, io.EOF
+	}
+
+	f := r.zip.File[r.next]
+	r.next++
+
+	entry := &Entry{
+		Path:    strings.TrimPrefix(f.Name, "./"),
+		Size:    int64(f.UncompressedSize64),
+		ModTime: f.Modified,
+	}
+	mode := f.Mode()
+	switch {
+	case mode.IsDir():
+		entry.Kind = EntryDirectory
+		entry.Size = 0
+		// Path is empty if entry is the root of archive (e.g. "./")
+		if entry.Path != "" && !strings.HasSuffix(entry.Path, "/") {
+			entry.Path += "/"
+		}
+		return entry, nil
+	case mode.IsRegular():
+		entry.Kind = EntryFile
+	default:
+		entry.Kind = EntryUnsupported
+		return entry, nil
+	}
+
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	r.current = rc
+
+	return entry, nil
+}
+
+func (r *zipReader) Read(p []byte) (int, error) {
+	if r.current == nil {
+		return 0, io.EOF
+	}
+	return r.current.Read(p)
+}
+
+func (r *zipReader) Close() error {
+	if r.current != nil {
+		r.current.Close()
+	}
+	r.tmp.Close()
+	return os.Remove(r.tmp.Name())
+}
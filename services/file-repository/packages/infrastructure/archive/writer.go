@@ -0,0 +1,113 @@
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"errors"
+	"io"
+	"strings"
+	"time"
+	FileApplication "vega_file_repository/packages/application/file"
+)
+
+var ErrUnsupportedFormat = errors.New("unsupported archive format")
+
+// Writes entries into archive on the fly, so the whole archive is never kept in memory.
+//
+// All paths are relative to the root of the archive. Directories paths must end with '/'.
+type Writer interface {
+	WriteDirectory(path string, modTime time.Time) error
+	// Size must exactly match amount of bytes in content
+	WriteFile(path string, size int64, modTime time.Time, content io.Reader) error
+	// Flushes all remaining data, but doesn't close the underlying writer
+	Close() error
+}
+
+func NewWriter(format FileApplication.ArchiveFormat, dst io.Writer) (Writer, error) {
+	switch format {
+	case FileApplication.ArchiveFormatTar:
+		return &tarWriter{tar: tar.NewWriter(dst)}, nil
+	case FileApplication.ArchiveFormatTarGzip:
+		gz := gzip.NewWriter(dst)
+		return &tarWriter{tar: tar.NewWriter(gz), gzip: gz}, nil
+	case FileApplication.ArchiveFormatZip:
+		return &zipWriter{zip: zip.NewWriter(dst)}, nil
+	}
+	return nil, ErrUnsupportedFormat
+}
+
+type tarWriter struct {
+	tar *tar.Writer
+	// nil if archive isn't compressed
+	gzip *gzip.Writer
+}
+
+func (w *tarWriter) WriteDirectory(path string, modTime time.Time) error {
+	return w.tar.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeDir,
+		Name:     path,
+		Mode:     0755,
+		ModTime:  modTime,
+	})
+}
+
+func (w *tarWriter) WriteFile(path string, size int64, modTime time.Time, content io.Reader) error {
+	err := w.tar.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     path,
+		Size:     size,
+		Mode:     0644,
+		ModTime:  modTime,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(w.tar, content)
+	return err
+}
+
+func (w *tarWriter) Close() error {
+	if err := w.tar.Close(); err != nil {
+		return err
+	}
+	if w.gzip != nil {
+		return w.gzip.Close()
+	}
+	return nil
+}
+
+type zipWriter struct {
+	zip *zip.Writer
+}
+
+func (w *zipWriter) WriteDirectory(path string, modTime time.Time) error {
+	if !strings.HasSuffix(path, "/") {
+		path += "/"
+	}
+	_, err := w.zip.CreateHeader(&zip.FileHeader{
+		Name:     path,
+		Method:   zip.Store,
+		Modified: modTime,
+	})
+	return err
+}
+
+func (w *zipWriter) WriteFile(path string, size int64, modTime time.Time, content io.Reader) error {
+	header := &zip.FileHeader{
+		Name:     path,
+		Method:   zip.Deflate,
+		Modified: modTime,
+	}
+	// Zip writer computes sizes by itself (they are written in data descriptor after content)
+	entry, err := w.zip.CreateHeader(header)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(entry, content)
+	return err
+}
+
+func (w *zipWriter) Close() error {
+	return w.zip.Close()
+}
@@ -0,0 +1,123 @@
+package auth
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+	"vega_file_repository/packages/infrastructure/auth/authtest"
+)
+
+const testKey = "0123456789abcdef0123456789abcdef"
+
+func TestVerifier(t *testing.T) {
+	verifier, err := NewVerifier(testKey)
+	if err != nil {
+		t.Fatalf("Failed to create verifier: %v", err)
+	}
+
+	t.Run("Valid token", func(t *testing.T) {
+		identity, err := verifier.Verify(authtest.Issue(testKey, "alice", time.Minute))
+		if err != nil {
+			t.Fatalf("Valid token must be accepted: %v", err)
+		}
+		if identity.Subject != "alice" {
+			t.Errorf("Expected subject \"alice\", but got \"%s\"", identity.Subject)
+		}
+
+		ctx := WithIdentity(context.Background(), identity)
+		if fromCtx, ok := IdentityFromContext(ctx); !ok || fromCtx != identity {
+			t.Errorf("Identity must be stored in context")
+		}
+		if _, ok := IdentityFromContext(context.Background()); ok {
+			t.Errorf("Context without identity must not be authenticated")
+		}
+	})
+
+	t.Run("Invalid tokens", func(t *testing.T) {
+		token := authtest.Issue(testKey, "alice", time.Minute)
+		segments := strings.Split(token, ".")
+
+		encode := func(s string) string {
+			return base64.RawURLEncoding.EncodeToString([]byte(s))
+		}
+		otherKey := strings.Repeat("x", minKeyLength)
+
+		cases := []struct {
+			name     string
+			token    string
+			expected error
+		}{
+			{"malformed", "not-a-token", ErrMalformedToken},
+			{"signed with another key", authtest.Issue(otherKey, "alice", time.Minute), ErrInvalidSignature},
+			{"tampered claims", segments[0] + "." + encode(`{"sub":"admin","exp":9999999999}`) + "." + segments[2], ErrInvalidSignature},
+			{"unsigned", encode(`{"alg":"none"}`) + "." + segments[1] + ".", ErrUnsupportedAlgorithm},
+			{"expired", authtest.Issue(testKey, "alice", -time.Hour), ErrTokenExpired},
+			{"without subject", authtest.Issue(testKey, "", time.Minute), ErrMissingSubject},
+		}
+		for _, c := range cases {
+			_, err := verifier.Verify(c.token)
+			if !errors.Is(err, c.expected) {
+				t.Errorf("Token %s: expected %v, but got %v", c.name, c.expected, err)
+			}
+			if !errors.Is(err, ErrUnauthenticated) {
+				t.Errorf("Token %s: all token errors must be ErrUnauthenticated, but got %v", c.name, err)
+			}
+		}
+	})
+
+	if _, err := NewVerifier("short"); !errors.Is(err, ErrWeakKey) {
+		t.Errorf("Short key must be rejected, but got: %v", err)
+	}
+}
+
+func TestPolicy(t *testing.T) {
+	policy, err := ParsePolicy([]byte(`{
+		"subjects": {
+			"backup-service": {"backups": "write", "photos": "read"},
+			"operator": {"*": "admin"},
+			"*": {"public": "read"}
+		}
+	}`))
+	if err != nil {
+		t.Fatalf("Failed to parse policy: %v", err)
+	}
+
+	cases := []struct {
+		subject  string
+		bucket   string
+		expected Access
+	}{
+		{"backup-service", "backups", AccessWrite},
+		{"backup-service", "photos", AccessRead},
+		{"backup-service", "public", AccessRead},
+		{"backup-service", "private", AccessNone},
+		{"operator", "private", AccessAdmin},
+		{"operator", Wildcard, AccessAdmin},
+		{"backup-service", Wildcard, AccessNone},
+		{"stranger", "public", AccessRead},
+		{"stranger", "backups", AccessNone},
+	}
+	for _, c := range cases {
+		if access := policy.Access(c.subject, c.bucket); access != c.expected {
+			t.Errorf("Access of \"%s\" to \"%s\": expected %s, but got %s", c.subject, c.bucket, c.expected, access)
+		}
+	}
+	if !policy.Allows("backup-service", "backups", AccessRead) || policy.Allows("backup-service", "backups", AccessAdmin) {
+		t.Errorf("Higher access must include lower ones only")
+	}
+
+	invalid := []string{
+		`{}`,
+		`{"subjects": {"alice": {}}}`,
+		`{"subjects": {"alice": {"photos": "owner"}}}`,
+		`{"subjects": {"alice": {"photos": "read"}}, "unknown": true}`,
+	}
+	for _, data := range invalid {
+		if _, err := ParsePolicy([]byte(data)); err == nil {
+			t.Errorf("Policy must be rejected: %s", data)
+		}
+	}
+}
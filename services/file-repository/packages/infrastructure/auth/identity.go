@@ -0,0 +1,24 @@
+package auth
+
+import (
+	"context"
+	"time"
+)
+
+// Authenticated caller
+type Identity struct {
+	Subject   string
+	ExpiresAt time.Time
+}
+
+type identityKey struct{}
+
+func WithIdentity(ctx context.Context, identity *Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, identity)
+}
+
+// Returns false if request wasn't authenticated (e.g. if authentication is disabled)
+func IdentityFromContext(ctx context.Context) (*Identity, bool) {
+	identity, ok := ctx.Value(identityKey{}).(*Identity)
+	return identity, ok
+}
@@ -0,0 +1,115 @@
+package auth
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// Matches any bucket in grants of the subject, or any authenticated subject in the policy
+const Wildcard = "*"
+
+// Each access level includes all lower ones
+type Access int
+
+const (
+	AccessNone Access = iota
+	// Downloading, listing and stating of the files, reading of bucket info
+	AccessRead
+	// Everything which modifies files of the bucket
+	AccessWrite
+	// Creation and deletion of the bucket, changing of its settings
+	AccessAdmin
+)
+
+var accessNames = map[string]Access{
+	"read":  AccessRead,
+	"write": AccessWrite,
+	"admin": AccessAdmin,
+}
+
+func (a Access) String() string {
+	for name, access := range accessNames {
+		if access == a {
+			return name
+		}
+	}
+	return "none"
+}
+
+func (a *Access) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		return err
+	}
+	access, ok := accessNames[name]
+	if !ok {
+		return fmt.Errorf("unknown access \"%s\", it must be one of: read, write, admin", name)
+	}
+	*a = access
+	return nil
+}
+
+var ErrEmptyPolicy = errors.New("auth policy must define grants of at least one subject")
+
+// Grants of the subjects (JWT "sub" claims) to the buckets
+type Policy struct {
+	Subjects map[string]map[string]Access `json:"subjects"`
+}
+
+// Parses policy in JSON format:
+//
+//	{
+//	  "subjects": {
+//	    "backup-service": {"backups": "write", "photos": "read"},
+//	    "operator": {"*": "admin"},
+//	    "*": {"public": "read"}
+//	  }
+//	}
+//
+// "*" subject grants access to all authenticated callers, "*" bucket grants access to all buckets.
+func ParsePolicy(data []byte) (*Policy, error) {
+	policy := &Policy{}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(policy); err != nil {
+		return nil, fmt.Errorf("invalid auth policy: %v", err)
+	}
+
+	if len(policy.Subjects) == 0 {
+		return nil, ErrEmptyPolicy
+	}
+	for subject, grants := range policy.Subjects {
+		if len(grants) == 0 {
+			return nil, fmt.Errorf("subject \"%s\" has no grants", subject)
+		}
+	}
+
+	return policy, nil
+}
+
+func LoadPolicy(path string) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePolicy(data)
+}
+
+// Returns the highest access to the bucket granted to the subject.
+// Wildcard bucket matches only grants to all buckets.
+func (p *Policy) Access(subject string, bucket string) Access {
+	access := AccessNone
+	for _, s := range []string{subject, Wildcard} {
+		grants := p.Subjects[s]
+		access = max(access, grants[bucket], grants[Wildcard])
+	}
+	return access
+}
+
+func (p *Policy) Allows(subject string, bucket string, access Access) bool {
+	return p.Access(subject, bucket) >= access
+}
@@ -0,0 +1,132 @@
+// Authentication of the callers via JWT and their authorization via policy of bucket grants.
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"strings"
+	"time"
+)
+
+// Key is used as HMAC key, so it must be at least as long as the shortest supported hash
+const minKeyLength = sha256.Size
+
+// Tolerated difference between clocks of the token issuer and of the service
+const clockSkew = time.Second * 30
+
+var (
+	ErrUnauthenticated  = errors.New("request isn't authenticated")
+	ErrPermissionDenied = errors.New("permission denied")
+
+	ErrWeakKey = errors.New("JWT key must be at least 32 bytes long")
+
+	ErrMissingToken         = fmt.Errorf("%w: bearer token is missing", ErrUnauthenticated)
+	ErrMalformedToken       = fmt.Errorf("%w: malformed token", ErrUnauthenticated)
+	ErrUnsupportedAlgorithm = fmt.Errorf("%w: unsupported signing algorithm of the token, only HS256, HS384 and HS512 are supported", ErrUnauthenticated)
+	ErrInvalidSignature     = fmt.Errorf("%w: invalid signature of the token", ErrUnauthenticated)
+	ErrTokenExpired         = fmt.Errorf("%w: token is expired", ErrUnauthenticated)
+	ErrTokenNotValidYet     = fmt.Errorf("%w: token isn't valid yet", ErrUnauthenticated)
+	ErrMissingSubject       = fmt.Errorf("%w: token has no subject", ErrUnauthenticated)
+	ErrMissingExpiration    = fmt.Errorf("%w: token has no expiration time", ErrUnauthenticated)
+)
+
+var algorithms = map[string]func() hash.Hash{
+	"HS256": sha256.New,
+	"HS384": sha512.New384,
+	"HS512": sha512.New,
+}
+
+type header struct {
+	Algorithm string `json:"alg"`
+}
+
+// Registered claims which are used by the service, all other claims are ignored
+type claims struct {
+	Subject   string `json:"sub"`
+	ExpiresAt int64  `json:"exp"`
+	NotBefore int64  `json:"nbf"`
+}
+
+// Verifies HMAC signed JWTs issued by the trusted party which shares the key with the service.
+// Tokens must have subject ("sub") and expiration time ("exp").
+type Verifier struct {
+	key []byte
+}
+
+func NewVerifier(key string) (*Verifier, error) {
+	if len(key) < minKeyLength {
+		return nil, ErrWeakKey
+	}
+	return &Verifier{key: []byte(key)}, nil
+}
+
+func (v *Verifier) sign(algorithm string, signingInput string) []byte {
+	mac := hmac.New(algorithms[algorithm], v.key)
+	mac.Write([]byte(signingInput))
+	return mac.Sum(nil)
+}
+
+func decodeSegment(segment string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return ErrMalformedToken
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return ErrMalformedToken
+	}
+	return nil
+}
+
+func (v *Verifier) Verify(token string) (*Identity, error) {
+	segments := strings.Split(token, ".")
+	if len(segments) != 3 {
+		return nil, ErrMalformedToken
+	}
+
+	var h header
+	if err := decodeSegment(segments[0], &h); err != nil {
+		return nil, err
+	}
+	// Algorithm is checked before anything else, so "none" and asymmetric algorithms can't be used to forge the token
+	if _, ok := algorithms[h.Algorithm]; !ok {
+		return nil, ErrUnsupportedAlgorithm
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(segments[2])
+	if err != nil {
+		return nil, ErrMalformedToken
+	}
+	if !hmac.Equal(signature, v.sign(h.Algorithm, segments[0]+"."+segments[1])) {
+		return nil, ErrInvalidSignature
+	}
+
+	var c claims
+	if err := decodeSegment(segments[1], &c); err != nil {
+		return nil, err
+	}
+	if c.Subject == "" {
+		return nil, ErrMissingSubject
+	}
+	if c.ExpiresAt == 0 {
+		return nil, ErrMissingExpiration
+	}
+	now := time.Now()
+	expiresAt := time.Unix(c.ExpiresAt, 0)
+	if now.After(expiresAt.Add(clockSkew)) {
+		return nil, ErrTokenExpired
+	}
+	if c.NotBefore != 0 && now.Add(clockSkew).Before(time.Unix(c.NotBefore, 0)) {
+		return nil, ErrTokenNotValidYet
+	}
+
+	return &Identity{
+		Subject:   c.Subject,
+		ExpiresAt: expiresAt,
+	}, nil
+}
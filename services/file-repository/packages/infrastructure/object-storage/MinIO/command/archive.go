@@ -0,0 +1,35 @@
+package miniocommand
+
+import (
+	"context"
+	"errors"
+	"io"
+	FileApplication "vega_file_repository/packages/application/file"
+	"vega_file_repository/packages/domain/entity"
+	"vega_file_repository/packages/infrastructure/archive"
+	MinIOCommon "vega_file_repository/packages/infrastructure/object-storage/MinIO/common"
+
+	"github.com/minio/minio-go/v7"
+)
+
+// Extracts archive entries one by one into objects under cmd.Path (see archive.Extract).
+func (h *defaultCommandHandler) uploadArchive(cmd *FileApplication.UploadFileCommand) (*entity.UploadResult, error) {
+	if cmd.Content == nil {
+		return nil, errors.New("archive content is missing")
+	}
+
+	ctx, cancel := context.WithTimeout(cmd.Context, cmd.ContextTimeout)
+	defer cancel()
+
+	if err := MinIOCommon.IsBucketExist(ctx, cmd.Bucket); err != nil {
+		return nil, err
+	}
+
+	return archive.Extract(
+		cmd.ArchiveFormat, cmd.Content, cmd.ContentSize, cmd.Path,
+		func(path string, entry *archive.Entry, content io.Reader) error {
+			_, err := storage.Client.PutObject(ctx, cmd.Bucket, path, content, entry.Size, minio.PutObjectOptions{})
+			return err
+		},
+	)
+}
@@ -3,12 +3,13 @@ package miniocommand
 import (
 	"bytes"
 	"context"
-	"errors"
 	"fmt"
 	"io"
-	"strconv"
 	"strings"
+	"sync"
+	"time"
 	FileApplication "vega_file_repository/packages/application/file"
+	"vega_file_repository/packages/domain/entity"
 	MinIOCommon "vega_file_repository/packages/infrastructure/object-storage/MinIO/common"
 	MinIOConnection "vega_file_repository/packages/infrastructure/object-storage/MinIO/connection"
 
@@ -67,16 +68,27 @@ func (h *defaultCommandHandler) Mkdir(cmd *FileApplication.MkdirCommand) error {
 	return nil
 }
 
-func (h *defaultCommandHandler) UploadFile(cmd *FileApplication.UploadFileCommand) error {
+func (h *defaultCommandHandler) UploadFile(cmd *FileApplication.UploadFileCommand) (*entity.UploadResult, error) {
 	if err := h.preprocessTargetedCommandQuery(&cmd.CommandQuery, cmd.Path); err != nil {
-		return err
+		return nil, err
 	}
 	if cmd.ContentSize <= 0 {
-		return errors.New("Content size cannot be equal or less than 0, but got " + strconv.FormatInt(cmd.ContentSize, 10))
+		return nil, fmt.Errorf("%w, but got %d", FileApplication.ErrInvalidContentSize, cmd.ContentSize)
+	}
+	if cmd.ArchiveFormat != FileApplication.ArchiveFormatNone {
+		if !file.IsDirectory(cmd.Path) {
+			return nil, file.ErrFileIsNotDirectory
+		}
+		if cmd.Checksum != nil {
+			return nil, FileApplication.ErrChecksumOfArchive
+		}
+		if cmd.Preconditions.IsSet() {
+			return nil, FileApplication.ErrPreconditionsOfArchive
+		}
+		return h.uploadArchive(cmd)
 	}
 	if file.IsDirectory(cmd.Path) {
-		// TODO (FEAT): Allow upload archives as directories (using flag in cmd?)
-		return errors.New("Can't upload file as directory")
+		return nil, FileApplication.ErrUploadOfDirectory
 	}
 	if cmd.Content == nil {
 		cmd.Content = bytes.NewReader([]byte{})
@@ -86,15 +98,92 @@ func (h *defaultCommandHandler) UploadFile(cmd *FileApplication.UploadFileComman
 	defer cancel()
 
 	if err := MinIOCommon.IsBucketExist(ctx, cmd.Bucket); err != nil {
+		return nil, err
+	}
+
+	return h.putObject(ctx, cmd.Bucket, cmd.Path, cmd.Content, cmd.ContentSize, cmd.Checksum, &cmd.Preconditions)
+}
+
+// Content is hashed while it's uploaded, so returned checksum is always computed by the service itself.
+// Object metadata can't be changed after upload, so checksum is stored only if it was known beforehand
+// (expected checksum is verified before the last byte is sent, so stored one always matches the content).
+// Preconditions may be nil.
+func (h *defaultCommandHandler) putObject(
+	ctx context.Context,
+	bucket string,
+	path string,
+	content io.Reader,
+	size int64,
+	expected *entity.Checksum,
+	preconditions *FileApplication.WritePreconditions,
+) (*entity.UploadResult, error) {
+	if MinIOCommon.DeduplicationEnabled {
+		// References are empty objects, so their ETags are all the same and can't identify the content
+		if preconditions != nil && preconditions.IsSet() {
+			return nil, FileApplication.ErrPreconditionsNotSupported
+		}
+		return h.putDeduplicatedObject(ctx, bucket, path, content, size, expected)
+	}
+
+	opts := minio.PutObjectOptions{
+		UserMetadata: MinIOCommon.ChecksumMetadata(expected),
+	}
+	if err := h.setPreconditions(ctx, bucket, path, preconditions, &opts); err != nil {
+		return nil, err
+	}
+
+	reader, err := FileApplication.NewChecksumReader(content, size, expected)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := storage.Client.PutObject(ctx, bucket, path, reader, size, opts)
+	if err != nil {
+		if MinIOCommon.IsErrorCode(err, minio.PreconditionFailed) {
+			return nil, FileApplication.ErrPreconditionFailed
+		}
+		return nil, reader.ResolveError(err)
+	}
+
+	return &entity.UploadResult{
+		Checksum: reader.Checksum(),
+		ETag:     strings.Trim(info.ETag, "\""),
+	}, nil
+}
+
+// Converts preconditions into conditional headers of the put, so storage checks them atomically.
+// S3 can't check modification time on put, so it's checked against current state of the object,
+// which then must stay unchanged till the put (the same way as If-Match).
+func (h *defaultCommandHandler) setPreconditions(
+	ctx context.Context,
+	bucket string,
+	path string,
+	preconditions *FileApplication.WritePreconditions,
+	opts *minio.PutObjectOptions,
+) error {
+	if preconditions == nil || !preconditions.IsSet() {
+		return nil
+	}
+	if err := preconditions.Validate(); err != nil {
 		return err
 	}
+	if preconditions.IfNoneMatch {
+		opts.SetMatchETagExcept("*")
+		return nil
+	}
 
-	_, err := storage.Client.PutObject(
-		ctx, cmd.Bucket, cmd.Path, cmd.Content, cmd.ContentSize, minio.PutObjectOptions{},
-	)
+	// Checked beforehand as well, so content isn't uploaded in vain
+	stat, err := storage.Client.StatObject(ctx, bucket, path, minio.StatObjectOptions{})
 	if err != nil {
+		if !MinIOCommon.IsErrorCode(err, minio.NoSuchKey) {
+			return err
+		}
+		return preconditions.Check(false, "", time.Time{})
+	}
+	if err := preconditions.Check(true, stat.ETag, stat.LastModified); err != nil {
 		return err
 	}
+	opts.SetMatchETag(strings.Trim(stat.ETag, "\""))
 
 	return nil
 }
@@ -105,17 +194,15 @@ func (h *defaultCommandHandler) fullReplace(
 	path string,
 	content io.Reader,
 	size int64,
-) error {
-	_, err := storage.Client.PutObject(ctx, bucket, path, content, size, minio.PutObjectOptions{})
-	if err != nil {
-		return err
-	}
-	return nil
+	expected *entity.Checksum,
+	preconditions *FileApplication.WritePreconditions,
+) (*entity.UploadResult, error) {
+	return h.putObject(ctx, bucket, path, content, size, expected, preconditions)
 }
 
-func (h *defaultCommandHandler) UpdateFileContent(cmd *FileApplication.UpdateFileContentCommand) error {
+func (h *defaultCommandHandler) UpdateFileContent(cmd *FileApplication.UpdateFileContentCommand) (*entity.UploadResult, error) {
 	if err := h.preprocessTargetedCommandQuery(&cmd.CommandQuery, cmd.Path); err != nil {
-		return err
+		return nil, err
 	}
 
 	ctx, cancel := context.WithTimeout(cmd.Context, cmd.ContextTimeout)
@@ -130,11 +217,7 @@ func (h *defaultCommandHandler) UpdateFileContent(cmd *FileApplication.UpdateFil
 	// remote document editing. But this documents aren't really big in most cases, few MB maybe.
 	// And fully updating them won't be problematic, althogh it will create more pressure on network
 	// traffic and disk I/O, but for consistency - it's reasonable tradeoff.
-	if err := h.fullReplace(ctx, cmd.Bucket, cmd.Path, cmd.NewContent, cmd.Size); err != nil {
-		return err
-	}
-
-	return nil
+	return h.fullReplace(ctx, cmd.Bucket, cmd.Path, cmd.NewContent, cmd.Size, cmd.Checksum, &cmd.Preconditions)
 }
 
 // TODO (FEAT?): By default MinIO doesn't consider situation when you trying to delete non-existing file as error.
@@ -143,16 +226,14 @@ func (h *defaultCommandHandler) UpdateFileContent(cmd *FileApplication.UpdateFil
 // possibility for users to decide - should file existance be checked before deletion or not? But this can be used
 // for possible attacks, like DoS... so - does it even worth this? I don't know, i can't really imagine situations
 // when this functional will be really needed... So maybe leave it as it is works now? Again - i don't know...
-//
-// TODO (FEAT): Implement recursive deletion for directories
-func (h *defaultCommandHandler) DeleteFiles(cmd *FileApplication.DeleteFilesCommand) error {
+func (h *defaultCommandHandler) DeleteFiles(cmd *FileApplication.DeleteFilesCommand) (*entity.DeletionReport, error) {
 	if !cmd.CommandQuery.IsInit() {
 		cqrs.InitDefaultCommandQuery(&cmd.CommandQuery)
 	}
 
 	for _, path := range cmd.Paths {
 		if err := file.ValidatePathFormat(path); err != nil {
-			return err
+			return nil, err
 		}
 	}
 
@@ -160,60 +241,182 @@ func (h *defaultCommandHandler) DeleteFiles(cmd *FileApplication.DeleteFilesComm
 	defer cancel()
 
 	if err := MinIOCommon.IsBucketExist(ctx, cmd.Bucket); err != nil {
-		return err
+		return nil, err
 	}
 
-	if len(cmd.Paths) == 1 {
-		err := storage.Client.RemoveObject(ctx, cmd.Bucket, cmd.Paths[0], minio.RemoveObjectOptions{})
-		if err != nil {
-			return err
-		}
-		return nil
+	deduplicated, err := MinIOCommon.IsDeduplicationUsed(ctx)
+	if err != nil {
+		return nil, err
 	}
 
-	objectsCh := make(chan minio.ObjectInfo, len(cmd.Paths))
-
-	for _, path := range cmd.Paths {
-		objectsCh <- minio.ObjectInfo{Key: path}
+	report := &entity.DeletionReport{
+		Results: make([]entity.DeletionResult, len(cmd.Paths)),
 	}
-	close(objectsCh)
 
-	errorCh := storage.Client.RemoveObjects(ctx, cmd.Bucket, objectsCh, minio.RemoveObjectsOptions{})
+	// Object name -> index of the requested path (in cmd.Paths) which produced this object.
+	// Objects are sent to RemoveObjects() concurrently with reading deletion errors,
+	// so access to it must be synchronized.
+	owners := make(map[string]int, len(cmd.Paths))
+	// Object name -> blob which it refers to, such blobs are released once objects are deleted.
+	blobs := make(map[string]string)
+	ownersMu := new(sync.Mutex)
+
+	objectsCh := make(chan minio.ObjectInfo, min(len(cmd.Paths), 1000))
+
+	go func() {
+		defer close(objectsCh)
+
+		send := func(i int, objectName string, ref *MinIOCommon.BlobReference) bool {
+			ownersMu.Lock()
+			// Same object may be covered by several requested paths
+			if _, ok := owners[objectName]; ok {
+				ownersMu.Unlock()
+				return true
+			}
+			owners[objectName] = i
+			if ref != nil {
+				blobs[objectName] = ref.Key
+			}
+			report.Results[i].DeletedCount++
+			ownersMu.Unlock()
+
+			select {
+			case objectsCh <- minio.ObjectInfo{Key: objectName}:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
 
-	var errors []string
-	for err := range errorCh {
-		if err.Err != nil {
-			errors = append(errors, fmt.Sprintf("Failed to delete %s: %v", err.ObjectName, err.Err))
+		for i, path := range cmd.Paths {
+			report.Results[i].Path = path
+
+			if !cmd.Recursive || !file.IsDirectory(path) {
+				key := MinIOCommon.ObjectKey(path)
+				var ref *MinIOCommon.BlobReference
+				if deduplicated {
+					// If stat failed, then object will most likely fail to be deleted as well,
+					// so error is reported by RemoveObjects()
+					ref, _ = h.blobReferenceOf(ctx, cmd.Bucket, key)
+				}
+				if !send(i, key, ref) {
+					return
+				}
+				continue
+			}
+
+			// RemoveObjects() splits objects into batches by itself (up to 1000 objects per request),
+			// so there are no need to collect all of them in memory.
+			for object := range storage.Client.ListObjects(ctx, cmd.Bucket, minio.ListObjectsOptions{
+				Prefix:       MinIOCommon.ObjectKey(path),
+				Recursive:    true,
+				WithMetadata: deduplicated,
+			}) {
+				if object.Err != nil {
+					ownersMu.Lock()
+					report.Results[i].Failures = append(report.Results[i].Failures, entity.DeletionFailure{
+						Path:   path,
+						Reason: object.Err.Error(),
+					})
+					ownersMu.Unlock()
+					break
+				}
+				if !send(i, object.Key, MinIOCommon.BlobReferenceFromMetadata(object.UserMetadata)) {
+					return
+				}
+			}
+		}
+	}()
+
+	// Successful deletions are reported as well, so blobs are released only for objects which are surely deleted
+	resultCh := storage.Client.RemoveObjectsWithResult(ctx, cmd.Bucket, objectsCh, minio.RemoveObjectsOptions{})
+
+	// Object name -> blob which it referred to, for deleted objects only
+	released := make(map[string]string)
+	var errs []string
+	for result := range resultCh {
+		ownersMu.Lock()
+		if result.Err == nil {
+			if blob, ok := blobs[result.ObjectName]; ok {
+				released[result.ObjectName] = blob
+			}
+			ownersMu.Unlock()
+			continue
+		}
+		i, ok := owners[result.ObjectName]
+		if ok {
+			report.Results[i].DeletedCount--
+			report.Results[i].Failures = append(report.Results[i].Failures, entity.DeletionFailure{
+				Path:   MinIOCommon.ObjectPath(result.ObjectName),
+				Reason: result.Err.Error(),
+			})
+		}
+		ownersMu.Unlock()
+		// Error isn't related to any specific object (e.g. invalid bucket name)
+		if !ok {
+			errs = append(errs, fmt.Sprintf("Failed to delete %s: %v", result.ObjectName, result.Err))
 		}
 	}
 
-	if len(errors) > 0 {
-		return fmt.Errorf("deletion errors: %s", strings.Join(errors, ";"))
+	// Objects are already deleted, so failed release can only leave unused blob.
+	// Some objects may be deleted even if deletion failed in general, so their blobs are released anyway.
+	for objectName, blob := range released {
+		h.releaseBlob(ctx, blob, cmd.Bucket, objectName)
+	}
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("deletion errors: %s", strings.Join(errs, ";"))
+	}
+	if err := ctx.Err(); err != nil {
+		return report, err
+	}
+	if len(report.Failures()) > 0 {
+		return report, FileApplication.ErrPartialDeletion
 	}
 
-	return nil
+	return report, nil
 }
 
 func (h *defaultCommandHandler) MakeBucket(cmd *FileApplication.MakeBucketCommand) error {
+	if cmd.Name == MinIOCommon.BlobsBucket {
+		return MinIOCommon.ErrReservedBucket
+	}
+
 	ctx, cancel := h.preprocessCommandQuery(&cmd.CommandQuery)
 	defer cancel()
 
 	if err := storage.Client.MakeBucket(ctx, cmd.Name, minio.MakeBucketOptions{}); err != nil {
-		return err
+		return MinIOCommon.BucketError(err)
 	}
 
 	return nil
 }
 
 func (h *defaultCommandHandler) DeleteBucket(cmd *FileApplication.DeleteBucketCommand) error {
+	if cmd.Name == MinIOCommon.BlobsBucket {
+		return MinIOCommon.ErrReservedBucket
+	}
+
 	ctx, cancel := h.preprocessCommandQuery(&cmd.CommandQuery)
 	defer cancel()
 
+	// Blobs referred by objects of the bucket must be released after it's deleted
+	var blobs map[string]string
+	if cmd.Force {
+		var err error
+		if blobs, err = h.listBlobReferences(ctx, cmd.Name); err != nil {
+			return MinIOCommon.BucketError(err)
+		}
+	}
+
 	err := storage.Client.RemoveBucketWithOptions(ctx, cmd.Name, minio.RemoveBucketOptions{
 		ForceDelete: cmd.Force,
 	})
 	if err != nil {
-		return err
+		return MinIOCommon.BucketError(err)
+	}
+
+	for key, blob := range blobs {
+		h.releaseBlob(ctx, blob, cmd.Name, key)
 	}
 
 	return nil
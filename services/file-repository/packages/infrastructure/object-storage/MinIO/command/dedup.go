@@ -0,0 +1,296 @@
+package miniocommand
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"hash/fnv"
+	"io"
+	"strings"
+	"sync"
+	FileApplication "vega_file_repository/packages/application/file"
+	"vega_file_repository/packages/domain/entity"
+	MinIOCommon "vega_file_repository/packages/infrastructure/object-storage/MinIO/common"
+
+	"github.com/minio/minio-go/v7"
+)
+
+// Each reference to the blob is tracked by the empty marker object in blobs bucket:
+// "refs/<blob key>/<bucket>/<object key>", so blob can be deleted once there are no markers left.
+// Markers are added and removed only while blob is locked, but these locks are local to the process,
+// hence only one instance of the service may work with deduplicated files at once.
+const (
+	blobReferencesPrefix = "refs/"
+	blobUploadsPrefix    = "tmp/"
+)
+
+var ErrBlobNotFound = errors.New("Blob which file refers to doesn't exist")
+
+// Striped locks: blobs are hashed into fixed amount of mutexes, so there are no need to
+// track lifetime of the lock of each blob. To avoid deadlocks only one blob may be locked at once.
+var blobLocks [256]sync.Mutex
+
+// Same as blobLocks, but for objects which refer to blobs. Reference of the object is read and replaced
+// only while the object is locked, so concurrent writes of the same file can't release blob which it ends up referring to.
+// Blob may be locked while object is locked, but not vice versa.
+var objectLocks [256]sync.Mutex
+
+func lockStripe(locks *[256]sync.Mutex, name string) func() {
+	h := fnv.New32a()
+	h.Write([]byte(name))
+	mu := &locks[h.Sum32()%uint32(len(locks))]
+	mu.Lock()
+	return mu.Unlock
+}
+
+func lockBlob(blob string) func() {
+	return lockStripe(&blobLocks, blob)
+}
+
+func lockObject(bucket string, key string) func() {
+	return lockStripe(&objectLocks, bucket+"/"+key)
+}
+
+func blobReferencesOf(blob string) string {
+	return blobReferencesPrefix + blob + "/"
+}
+
+func blobReferenceMarker(blob string, bucket string, key string) string {
+	return blobReferencesOf(blob) + bucket + "/" + MinIOCommon.ObjectKey(key)
+}
+
+// Returns blob which object refers to, nil if object doesn't exist or isn't reference
+func (h *defaultCommandHandler) blobReferenceOf(ctx context.Context, bucket string, key string) (*MinIOCommon.BlobReference, error) {
+	stat, err := storage.Client.StatObject(ctx, bucket, key, minio.StatObjectOptions{})
+	if err != nil {
+		if MinIOCommon.IsErrorCode(err, minio.NoSuchKey) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return MinIOCommon.BlobReferenceFromMetadata(stat.UserMetadata), nil
+}
+
+// Deduplicated counterpart of putObject(). Content is uploaded into the blobs bucket first,
+// since its checksum (and hence the blob) is unknown until the whole content is read.
+// If such blob already exists, then uploaded content is just dropped.
+// Unlike regular objects, computed checksum is always stored.
+func (h *defaultCommandHandler) putDeduplicatedObject(
+	ctx context.Context,
+	bucket string,
+	path string,
+	content io.Reader,
+	size int64,
+	expected *entity.Checksum,
+) (*entity.UploadResult, error) {
+	key := MinIOCommon.ObjectKey(path)
+
+	uploadKey, ref, checksum, err := h.uploadBlobContent(ctx, content, size, expected)
+	if err != nil {
+		return nil, err
+	}
+	defer storage.Client.RemoveObject(ctx, MinIOCommon.BlobsBucket, uploadKey, minio.RemoveObjectOptions{})
+
+	// Upload of the content is the longest part, so object is locked only after it
+	unlock := lockObject(bucket, key)
+	defer unlock()
+
+	previous, err := h.blobReferenceOf(ctx, bucket, key)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.storeBlob(ctx, uploadKey, ref, bucket, key); err != nil {
+		return nil, err
+	}
+
+	info, err := storage.Client.PutObject(ctx, bucket, key, bytes.NewReader([]byte{}), 0, minio.PutObjectOptions{
+		UserMetadata: MinIOCommon.BlobReferenceMetadata(ref, checksum),
+	})
+	if err != nil {
+		// If object already referred to this blob, then its marker must be kept
+		if previous == nil || previous.Key != ref.Key {
+			h.releaseBlob(ctx, ref.Key, bucket, key)
+		}
+		return nil, err
+	}
+
+	if previous != nil && previous.Key != ref.Key {
+		// File is already updated, so failure here can only leave unused blob
+		h.releaseBlob(ctx, previous.Key, bucket, key)
+	}
+
+	return &entity.UploadResult{
+		Checksum: checksum,
+		ETag:     strings.Trim(info.ETag, "\""),
+	}, nil
+}
+
+// Uploads content into the temporary object of the blobs bucket, which must be removed by the caller.
+// Returns key of this object and the blob which content belongs to.
+func (h *defaultCommandHandler) uploadBlobContent(
+	ctx context.Context,
+	content io.Reader,
+	size int64,
+	expected *entity.Checksum,
+) (string, *MinIOCommon.BlobReference, *entity.Checksum, error) {
+	if err := MinIOCommon.EnsureBlobsBucket(ctx); err != nil {
+		return "", nil, nil, err
+	}
+
+	reader, err := FileApplication.NewChecksumReader(content, size, expected)
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return "", nil, nil, err
+	}
+	uploadKey := blobUploadsPrefix + hex.EncodeToString(id)
+
+	_, err = storage.Client.PutObject(ctx, MinIOCommon.BlobsBucket, uploadKey, reader, size, minio.PutObjectOptions{})
+	if err != nil {
+		return "", nil, nil, reader.ResolveError(err)
+	}
+
+	checksum := reader.Checksum()
+	ref := &MinIOCommon.BlobReference{
+		Key:  MinIOCommon.BlobKey(checksum),
+		Size: size,
+	}
+	return uploadKey, ref, checksum, nil
+}
+
+// Creates blob from the uploaded content (unless it already exists) and marks it as referred by the specified object.
+func (h *defaultCommandHandler) storeBlob(
+	ctx context.Context,
+	uploadKey string,
+	ref *MinIOCommon.BlobReference,
+	bucket string,
+	key string,
+) error {
+	unlock := lockBlob(ref.Key)
+	defer unlock()
+
+	exists, err := h.isObjectExist(ctx, MinIOCommon.BlobsBucket, ref.Key)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		src := minio.CopySrcOptions{
+			Bucket: MinIOCommon.BlobsBucket,
+			Object: uploadKey,
+		}
+		dst := minio.CopyDestOptions{
+			Bucket: MinIOCommon.BlobsBucket,
+			Object: ref.Key,
+		}
+		if ref.Size > maxCopyObjectSize {
+			_, err = storage.Client.ComposeObject(ctx, dst, src)
+		} else {
+			_, err = storage.Client.CopyObject(ctx, dst, src)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return h.putBlobReferenceMarker(ctx, ref.Key, bucket, key)
+}
+
+func (h *defaultCommandHandler) putBlobReferenceMarker(ctx context.Context, blob string, bucket string, key string) error {
+	_, err := storage.Client.PutObject(
+		ctx, MinIOCommon.BlobsBucket, blobReferenceMarker(blob, bucket, key),
+		bytes.NewReader([]byte{}), 0, minio.PutObjectOptions{},
+	)
+	return err
+}
+
+// Marks existing blob as referred by the specified object (e.g. when reference is copied).
+func (h *defaultCommandHandler) addBlobReference(ctx context.Context, blob string, bucket string, key string) error {
+	unlock := lockBlob(blob)
+	defer unlock()
+
+	// Blob may be released concurrently
+	exists, err := h.isObjectExist(ctx, MinIOCommon.BlobsBucket, blob)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrBlobNotFound
+	}
+
+	return h.putBlobReferenceMarker(ctx, blob, bucket, key)
+}
+
+// Removes reference of the object to the blob and deletes the blob if nothing else refers to it.
+//
+// Versions of the objects aren't tracked, so in buckets with versioning (even suspended one)
+// blobs are never released: they may be still referred by previous versions.
+// Errors are only reported, since failed release can't cause data loss, just leave unused blob.
+func (h *defaultCommandHandler) releaseBlob(ctx context.Context, blob string, bucket string, key string) error {
+	versioning, err := storage.Client.GetBucketVersioning(ctx, bucket)
+	// Bucket may be already deleted (e.g. by forced DeleteBucket)
+	if err != nil && !MinIOCommon.IsErrorCode(err, minio.NoSuchBucket) {
+		return err
+	}
+	if versioning.Enabled() || versioning.Suspended() {
+		return nil
+	}
+
+	unlock := lockBlob(blob)
+	defer unlock()
+
+	marker := blobReferenceMarker(blob, bucket, key)
+	if err := storage.Client.RemoveObject(ctx, MinIOCommon.BlobsBucket, marker, minio.RemoveObjectOptions{}); err != nil {
+		return err
+	}
+
+	// Listing is stopped after the first marker
+	listCtx, cancelListing := context.WithCancel(ctx)
+	defer cancelListing()
+
+	for object := range storage.Client.ListObjects(listCtx, MinIOCommon.BlobsBucket, minio.ListObjectsOptions{
+		Prefix:    blobReferencesOf(blob),
+		Recursive: true,
+		MaxKeys:   1,
+	}) {
+		if object.Err != nil {
+			return object.Err
+		}
+		// Blob is still referred by something else
+		return nil
+	}
+
+	return storage.Client.RemoveObject(ctx, MinIOCommon.BlobsBucket, blob, minio.RemoveObjectOptions{})
+}
+
+// Returns all references in the bucket: object key -> blob key.
+func (h *defaultCommandHandler) listBlobReferences(ctx context.Context, bucket string) (map[string]string, error) {
+	deduplicated, err := MinIOCommon.IsDeduplicationUsed(ctx)
+	if err != nil || !deduplicated {
+		return nil, err
+	}
+
+	refs := make(map[string]string)
+	for object := range storage.Client.ListObjects(ctx, bucket, minio.ListObjectsOptions{
+		Recursive:    true,
+		WithMetadata: true,
+	}) {
+		if object.Err != nil {
+			// Bucket doesn't exist, so it's up to the deletion to report it
+			if MinIOCommon.IsErrorCode(object.Err, minio.NoSuchBucket) {
+				return nil, nil
+			}
+			return nil, object.Err
+		}
+		if ref := MinIOCommon.BlobReferenceFromMetadata(object.UserMetadata); ref != nil {
+			refs[object.Key] = ref.Key
+		}
+	}
+
+	return refs, nil
+}
@@ -0,0 +1,157 @@
+package miniocommand
+
+import (
+	"errors"
+	"time"
+	FileApplication "vega_file_repository/packages/application/file"
+	"vega_file_repository/packages/domain/entity"
+	MinIOCommon "vega_file_repository/packages/infrastructure/object-storage/MinIO/common"
+
+	"github.com/abaxoth0/Vega/libs/go/packages/file"
+	"github.com/minio/minio-go/v7"
+)
+
+func uploadError(err error) error {
+	if MinIOCommon.IsErrorCode(err, minio.NoSuchUpload) {
+		return FileApplication.ErrUploadDoesNotExist
+	}
+	return err
+}
+
+func (h *defaultCommandHandler) InitiateUpload(cmd *FileApplication.InitiateUploadCommand) (string, error) {
+	if err := h.preprocessTargetedCommandQuery(&cmd.CommandQuery, cmd.Path); err != nil {
+		return "", err
+	}
+	if file.IsDirectory(cmd.Path) {
+		return "", FileApplication.ErrUploadOfDirectory
+	}
+
+	ctx, cancel := h.preprocessCommandQuery(&cmd.CommandQuery)
+	defer cancel()
+
+	if err := MinIOCommon.IsBucketExist(ctx, cmd.Bucket); err != nil {
+		return "", err
+	}
+
+	return MinIOCommon.Core().NewMultipartUpload(ctx, cmd.Bucket, cmd.Path, minio.PutObjectOptions{})
+}
+
+// Note that S3-compatible storages require all parts except the last one to be at least 5 MiB.
+// This isn't checked here, since it's unknown which part is the last one until upload is completed.
+func (h *defaultCommandHandler) UploadPart(cmd *FileApplication.UploadPartCommand) (*entity.UploadPart, error) {
+	if err := h.preprocessTargetedCommandQuery(&cmd.CommandQuery, cmd.Path); err != nil {
+		return nil, err
+	}
+	if cmd.PartNumber < FileApplication.MinUploadPartNumber || cmd.PartNumber > FileApplication.MaxUploadPartNumber {
+		return nil, FileApplication.ErrInvalidUploadPartNumber
+	}
+	if cmd.Content == nil || cmd.Size <= 0 {
+		return nil, errors.New("part content is missing")
+	}
+
+	ctx, cancel := h.preprocessCommandQuery(&cmd.CommandQuery)
+	defer cancel()
+
+	part, err := MinIOCommon.Core().PutObjectPart(
+		ctx, cmd.Bucket, cmd.Path, cmd.UploadID, cmd.PartNumber, cmd.Content, cmd.Size, minio.PutObjectPartOptions{},
+	)
+	if err != nil {
+		return nil, uploadError(err)
+	}
+
+	return &entity.UploadPart{
+		PartNumber:   part.PartNumber,
+		ETag:         part.ETag,
+		Size:         part.Size,
+		LastModified: part.LastModified,
+	}, nil
+}
+
+func (h *defaultCommandHandler) CompleteUpload(cmd *FileApplication.CompleteUploadCommand) error {
+	if err := h.preprocessTargetedCommandQuery(&cmd.CommandQuery, cmd.Path); err != nil {
+		return err
+	}
+
+	ctx, cancel := h.preprocessCommandQuery(&cmd.CommandQuery)
+	defer cancel()
+
+	parts := []minio.CompletePart{}
+	marker := 0
+	for {
+		result, err := MinIOCommon.Core().ListObjectParts(ctx, cmd.Bucket, cmd.Path, cmd.UploadID, marker, 0)
+		if err != nil {
+			return uploadError(err)
+		}
+		for _, part := range result.ObjectParts {
+			parts = append(parts, minio.CompletePart{
+				PartNumber: part.PartNumber,
+				ETag:       part.ETag,
+			})
+		}
+		if !result.IsTruncated {
+			break
+		}
+		marker = result.NextPartNumberMarker
+	}
+	if len(parts) == 0 {
+		return FileApplication.ErrNoUploadParts
+	}
+
+	_, err := MinIOCommon.Core().CompleteMultipartUpload(ctx, cmd.Bucket, cmd.Path, cmd.UploadID, parts, minio.PutObjectOptions{})
+	if err != nil {
+		return uploadError(err)
+	}
+
+	return nil
+}
+
+func (h *defaultCommandHandler) AbortUpload(cmd *FileApplication.AbortUploadCommand) error {
+	if err := h.preprocessTargetedCommandQuery(&cmd.CommandQuery, cmd.Path); err != nil {
+		return err
+	}
+
+	ctx, cancel := h.preprocessCommandQuery(&cmd.CommandQuery)
+	defer cancel()
+
+	if err := MinIOCommon.Core().AbortMultipartUpload(ctx, cmd.Bucket, cmd.Path, cmd.UploadID); err != nil {
+		return uploadError(err)
+	}
+
+	return nil
+}
+
+func (h *defaultCommandHandler) AbortStaleUploads(cmd *FileApplication.AbortStaleUploadsCommand) (int, error) {
+	if cmd.MaxAge <= 0 {
+		return 0, errors.New("max age must be greater than 0")
+	}
+
+	ctx, cancel := h.preprocessCommandQuery(&cmd.CommandQuery)
+	defer cancel()
+
+	buckets, err := storage.Client.ListBuckets(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	threshold := time.Now().Add(-cmd.MaxAge)
+	aborted := 0
+
+	for _, bucket := range buckets {
+		for upload := range storage.Client.ListIncompleteUploads(ctx, bucket.Name, "", true) {
+			if upload.Err != nil {
+				return aborted, upload.Err
+			}
+			if upload.Initiated.After(threshold) {
+				continue
+			}
+			err := MinIOCommon.Core().AbortMultipartUpload(ctx, bucket.Name, upload.Key, upload.UploadID)
+			// Upload may be completed or aborted concurrently
+			if err != nil && !MinIOCommon.IsErrorCode(err, minio.NoSuchUpload) {
+				return aborted, err
+			}
+			aborted++
+		}
+	}
+
+	return aborted, nil
+}
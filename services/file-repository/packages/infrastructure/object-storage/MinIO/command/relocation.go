@@ -0,0 +1,213 @@
+package miniocommand
+
+import (
+	"context"
+	FileApplication "vega_file_repository/packages/application/file"
+	"vega_file_repository/packages/domain/entity"
+	MinIOCommon "vega_file_repository/packages/infrastructure/object-storage/MinIO/common"
+
+	cqrs "github.com/abaxoth0/Vega/libs/go/packages/CQRS"
+	errs "github.com/abaxoth0/Vega/libs/go/packages/erorrs"
+	"github.com/abaxoth0/Vega/libs/go/packages/file"
+	"github.com/minio/minio-go/v7"
+)
+
+// CopyObject() can't copy objects bigger than 5 GiB in a single request, such objects
+// are copied via ComposeObject(), which copies them by parts (still on the storage side).
+const maxCopyObjectSize int64 = 5 * 1024 * 1024 * 1024
+
+type relocatedObject struct {
+	sourceKey      string
+	destinationKey string
+	size           int64
+	// Blob which object refers to, empty if object isn't reference
+	blob string
+}
+
+func (h *defaultCommandHandler) CopyFiles(cmd *FileApplication.CopyFilesCommand) (*entity.RelocationReport, error) {
+	return h.relocateFiles(&cmd.CommandQuery, &cmd.FilesRelocation, false)
+}
+
+func (h *defaultCommandHandler) MoveFiles(cmd *FileApplication.MoveFilesCommand) (*entity.RelocationReport, error) {
+	return h.relocateFiles(&cmd.CommandQuery, &cmd.FilesRelocation, true)
+}
+
+// Objects are copied one by one, if move is true then each object is deleted right after it was copied.
+func (h *defaultCommandHandler) relocateFiles(
+	commandQuery *cqrs.CommandQuery,
+	relocation *FileApplication.FilesRelocation,
+	move bool,
+) (*entity.RelocationReport, error) {
+	if err := relocation.Validate(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := h.preprocessCommandQuery(commandQuery)
+	defer cancel()
+
+	if err := MinIOCommon.IsBucketExist(ctx, relocation.SourceBucket); err != nil {
+		return nil, err
+	}
+	if err := MinIOCommon.IsBucketExist(ctx, relocation.DestinationBucket); err != nil {
+		return nil, err
+	}
+
+	// All objects are listed before copying, so listing won't be affected by copied ones
+	objects, err := h.listRelocatedObjects(ctx, relocation)
+	if err != nil {
+		return nil, err
+	}
+	if len(objects) == 0 {
+		return nil, errs.StatusNotFound
+	}
+
+	skip := make([]bool, len(objects))
+	if relocation.Overwrite != FileApplication.OverwriteReplace {
+		for i, object := range objects {
+			exists, err := h.isObjectExist(ctx, relocation.DestinationBucket, object.destinationKey)
+			if err != nil {
+				return nil, err
+			}
+			if exists && relocation.Overwrite == FileApplication.OverwriteFail {
+				return nil, FileApplication.ErrDestinationExists
+			}
+			skip[i] = exists
+		}
+	}
+
+	report := &entity.RelocationReport{
+		Files: make([]entity.RelocatedFile, len(objects)),
+	}
+
+	for i, object := range objects {
+		relocated := &report.Files[i]
+		relocated.SourcePath = MinIOCommon.ObjectPath(object.sourceKey)
+		relocated.DestinationPath = MinIOCommon.ObjectPath(object.destinationKey)
+
+		if skip[i] {
+			relocated.Skipped = true
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+
+		// Copy of the reference refers to the same blob, so it must be marked before copying,
+		// otherwise blob may be released in between. If copy fails, then marker is kept:
+		// it can only prevent unused blob from being deleted.
+		if object.blob != "" {
+			err := h.addBlobReference(ctx, object.blob, relocation.DestinationBucket, object.destinationKey)
+			if err != nil {
+				relocated.Error = err.Error()
+				continue
+			}
+		}
+		if err := h.copyObject(ctx, relocation, object); err != nil {
+			relocated.Error = err.Error()
+			continue
+		}
+		if move {
+			err := storage.Client.RemoveObject(ctx, relocation.SourceBucket, object.sourceKey, minio.RemoveObjectOptions{})
+			if err != nil {
+				relocated.Error = "file was copied, but source wasn't deleted: " + err.Error()
+			} else if object.blob != "" {
+				h.releaseBlob(ctx, object.blob, relocation.SourceBucket, object.sourceKey)
+			}
+		}
+	}
+
+	if len(report.Failures()) > 0 {
+		return report, FileApplication.ErrPartialRelocation
+	}
+
+	return report, nil
+}
+
+func (h *defaultCommandHandler) listRelocatedObjects(
+	ctx context.Context,
+	relocation *FileApplication.FilesRelocation,
+) ([]relocatedObject, error) {
+	if !file.IsDirectory(relocation.SourcePath) {
+		stat, err := storage.Client.StatObject(
+			ctx, relocation.SourceBucket, relocation.SourcePath, minio.StatObjectOptions{},
+		)
+		if err != nil {
+			if MinIOCommon.IsErrorCode(err, minio.NoSuchKey) {
+				return []relocatedObject{}, nil
+			}
+			return nil, err
+		}
+		return []relocatedObject{{
+			sourceKey:      MinIOCommon.ObjectKey(relocation.SourcePath),
+			destinationKey: MinIOCommon.ObjectKey(relocation.DestinationPath),
+			size:           stat.Size,
+			blob:           blobOf(stat.UserMetadata),
+		}}, nil
+	}
+
+	deduplicated, err := MinIOCommon.IsDeduplicationUsed(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	objects := []relocatedObject{}
+	for object := range storage.Client.ListObjects(ctx, relocation.SourceBucket, minio.ListObjectsOptions{
+		Prefix:       MinIOCommon.ObjectKey(relocation.SourcePath),
+		Recursive:    true,
+		WithMetadata: deduplicated,
+	}) {
+		if object.Err != nil {
+			return nil, object.Err
+		}
+		objects = append(objects, relocatedObject{
+			sourceKey:      object.Key,
+			destinationKey: MinIOCommon.ObjectKey(relocation.DestinationOf(MinIOCommon.ObjectPath(object.Key))),
+			size:           object.Size,
+			blob:           blobOf(object.UserMetadata),
+		})
+	}
+
+	return objects, nil
+}
+
+func blobOf(metadata map[string]string) string {
+	if ref := MinIOCommon.BlobReferenceFromMetadata(metadata); ref != nil {
+		return ref.Key
+	}
+	return ""
+}
+
+func (h *defaultCommandHandler) isObjectExist(ctx context.Context, bucket string, key string) (bool, error) {
+	_, err := storage.Client.StatObject(ctx, bucket, key, minio.StatObjectOptions{})
+	if err != nil {
+		if MinIOCommon.IsErrorCode(err, minio.NoSuchKey) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+func (h *defaultCommandHandler) copyObject(
+	ctx context.Context,
+	relocation *FileApplication.FilesRelocation,
+	object relocatedObject,
+) error {
+	src := minio.CopySrcOptions{
+		Bucket: relocation.SourceBucket,
+		Object: object.sourceKey,
+	}
+	dst := minio.CopyDestOptions{
+		Bucket: relocation.DestinationBucket,
+		Object: object.destinationKey,
+	}
+
+	var err error
+	if object.size > maxCopyObjectSize {
+		_, err = storage.Client.ComposeObject(ctx, dst, src)
+	} else {
+		_, err = storage.Client.CopyObject(ctx, dst, src)
+	}
+
+	return err
+}
@@ -0,0 +1,90 @@
+package miniocommand
+
+import (
+	FileApplication "vega_file_repository/packages/application/file"
+	MinIOCommon "vega_file_repository/packages/infrastructure/object-storage/MinIO/common"
+
+	errs "github.com/abaxoth0/Vega/libs/go/packages/erorrs"
+	"github.com/minio/minio-go/v7"
+)
+
+func (h *defaultCommandHandler) SetBucketVersioning(cmd *FileApplication.SetBucketVersioningCommand) error {
+	ctx, cancel := h.preprocessCommandQuery(&cmd.CommandQuery)
+	defer cancel()
+
+	if err := MinIOCommon.IsBucketExist(ctx, cmd.Name); err != nil {
+		return err
+	}
+
+	// Versioning of the bucket can't be disabled once it was enabled, only suspended
+	if cmd.Enabled {
+		return storage.Client.EnableVersioning(ctx, cmd.Name)
+	}
+	return storage.Client.SuspendVersioning(ctx, cmd.Name)
+}
+
+// Version is copied over the file on the storage side, so copy becomes a new current version.
+func (h *defaultCommandHandler) RestoreFileVersion(cmd *FileApplication.RestoreFileVersionCommand) (string, error) {
+	if err := cmd.Validate(); err != nil {
+		return "", err
+	}
+
+	ctx, cancel := h.preprocessCommandQuery(&cmd.CommandQuery)
+	defer cancel()
+
+	if err := MinIOCommon.IsBucketExist(ctx, cmd.Bucket); err != nil {
+		return "", err
+	}
+
+	key := MinIOCommon.ObjectKey(cmd.Path)
+
+	stat, err := storage.Client.StatObject(ctx, cmd.Bucket, key, minio.StatObjectOptions{
+		VersionID: cmd.VersionID,
+	})
+	if err != nil {
+		if MinIOCommon.IsErrorCode(err, minio.NoSuchKey) || MinIOCommon.IsErrorCode(err, minio.NoSuchVersion) {
+			return "", errs.StatusNotFound
+		}
+		return "", err
+	}
+
+	src := minio.CopySrcOptions{
+		Bucket:    cmd.Bucket,
+		Object:    key,
+		VersionID: cmd.VersionID,
+	}
+	dst := minio.CopyDestOptions{
+		Bucket: cmd.Bucket,
+		Object: key,
+	}
+
+	var info minio.UploadInfo
+	if stat.Size > maxCopyObjectSize {
+		info, err = storage.Client.ComposeObject(ctx, dst, src)
+	} else {
+		info, err = storage.Client.CopyObject(ctx, dst, src)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return info.VersionID, nil
+}
+
+// Same as for regular objects, deletion of non-existing version isn't considered as error.
+func (h *defaultCommandHandler) DeleteFileVersion(cmd *FileApplication.DeleteFileVersionCommand) error {
+	if err := cmd.Validate(); err != nil {
+		return err
+	}
+
+	ctx, cancel := h.preprocessCommandQuery(&cmd.CommandQuery)
+	defer cancel()
+
+	if err := MinIOCommon.IsBucketExist(ctx, cmd.Bucket); err != nil {
+		return err
+	}
+
+	return storage.Client.RemoveObject(ctx, cmd.Bucket, MinIOCommon.ObjectKey(cmd.Path), minio.RemoveObjectOptions{
+		VersionID: cmd.VersionID,
+	})
+}
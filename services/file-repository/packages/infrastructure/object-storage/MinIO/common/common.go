@@ -2,15 +2,23 @@ package miniocommon
 
 import (
 	"context"
-	"errors"
+	"fmt"
+	"strings"
+	FileApplication "vega_file_repository/packages/application/file"
+	"vega_file_repository/packages/domain/entity"
 	MinIOConnection "vega_file_repository/packages/infrastructure/object-storage/MinIO/connection"
+
+	"github.com/minio/minio-go/v7"
 )
 
 var storage = MinIOConnection.Manager
 
-var ErrBucketDoesntExist = errors.New("Bucket doesn't exist")
+var ErrBucketDoesntExist = FileApplication.ErrBucketDoesNotExist
 
 func IsBucketExist(ctx context.Context, bucket string) error {
+	if bucket == BlobsBucket {
+		return ErrBucketDoesntExist
+	}
 	ok, err := storage.Client.BucketExists(ctx, bucket)
 	if err != nil {
 		return err
@@ -20,3 +28,91 @@ func IsBucketExist(ctx context.Context, bucket string) error {
 	}
 	return nil
 }
+
+// Regular client hides multipart uploads behind PutObject(), so low-level API must be used.
+func Core() minio.Core {
+	return minio.Core{Client: storage.Client}
+}
+
+// MinIO trims leading '/' of object names, so all keys returned by listings are
+// relative to the root of the bucket. This function converts path into such key.
+func ObjectKey(path string) string {
+	return strings.TrimPrefix(path, "/")
+}
+
+// Reverse of ObjectKey: converts key returned by MinIO back into path.
+func ObjectPath(key string) string {
+	if strings.HasPrefix(key, "/") {
+		return key
+	}
+	return "/" + key
+}
+
+// Converts errors of bucket operations into errors of use cases, so they can be handled regardless of the storage
+func BucketError(err error) error {
+	switch minio.ToErrorResponse(err).Code {
+	case minio.NoSuchBucket:
+		return FileApplication.ErrBucketDoesNotExist
+	case minio.BucketAlreadyOwnedByYou, minio.BucketAlreadyExists:
+		return FileApplication.ErrBucketAlreadyExists
+	case minio.BucketNotEmpty:
+		return FileApplication.ErrBucketIsNotEmpty
+	case minio.InvalidBucketName:
+		return fmt.Errorf("%w: %s", FileApplication.ErrInvalidBucketName, minio.ToErrorResponse(err).Message)
+	}
+	return err
+}
+
+// Checks if err is MinIO error response with specified code (e.g. minio.NoSuchKey)
+func IsErrorCode(err error, code string) bool {
+	if err, ok := err.(minio.ErrorResponse); ok {
+		return err.Code == code
+	}
+	return false
+}
+
+// Keys of the user metadata where verified checksum of the object is stored
+// (MinIO returns them in canonical form without "X-Amz-Meta-" prefix).
+const (
+	checksumMetadataKey          = "Vega-Checksum"
+	checksumAlgorithmMetadataKey = "Vega-Checksum-Algorithm"
+)
+
+// Prefix of the user metadata keys which are used internally (e.g. to store checksum)
+const internalMetadataPrefix = "Vega-"
+
+// Returns user metadata of the object without internal keys, so it contains only metadata set by the client.
+// Keys are returned without listing prefix (see listingMetadataPrefix).
+func ClientMetadata(metadata map[string]string) map[string]string {
+	client := make(map[string]string, len(metadata))
+	for key, value := range metadata {
+		key = strings.TrimPrefix(key, listingMetadataPrefix)
+		if !strings.HasPrefix(key, internalMetadataPrefix) {
+			client[key] = value
+		}
+	}
+	return client
+}
+
+// Returns user metadata which must be set on upload to store checksum, nil if checksum is nil
+func ChecksumMetadata(checksum *entity.Checksum) map[string]string {
+	if checksum == nil {
+		return nil
+	}
+	return map[string]string{
+		checksumMetadataKey:          checksum.Value,
+		checksumAlgorithmMetadataKey: checksum.Algorithm,
+	}
+}
+
+// Reverse of ChecksumMetadata, returns nil if object has no stored checksum
+func ChecksumFromMetadata(metadata map[string]string) *entity.Checksum {
+	value, algorithm := metadata[checksumMetadataKey], metadata[checksumAlgorithmMetadataKey]
+	if value == "" || algorithm == "" {
+		return nil
+	}
+	return &entity.Checksum{
+		Algorithm: algorithm,
+		Value:     value,
+	}
+}
@@ -0,0 +1,119 @@
+package miniocommon
+
+import (
+	"context"
+	"strconv"
+	"sync/atomic"
+	FileApplication "vega_file_repository/packages/application/file"
+	"vega_file_repository/packages/domain/entity"
+
+	"github.com/minio/minio-go/v7"
+)
+
+// In deduplication mode content of each uploaded file is stored in this bucket only once
+// (as blob named after its checksum), while the file itself becomes an empty object
+// which refers to the blob via user metadata.
+// This bucket is hidden from users: it can't be listed, created, deleted or accessed directly.
+const BlobsBucket = "vega-blobs"
+
+var ErrReservedBucket = FileApplication.ErrReservedBucket
+
+// If true, then uploaded files are deduplicated. Files which were uploaded
+// before this mode was enabled (or after it was disabled) are still readable, since
+// references are always resolved, regardless of this flag.
+var DeduplicationEnabled = false
+
+// Set once blobs bucket was seen, since it's never deleted after that
+var blobsBucketExists atomic.Bool
+
+// Keys of the user metadata of the reference object
+const (
+	blobMetadataKey     = "Vega-Blob"
+	blobSizeMetadataKey = "Vega-Blob-Size"
+)
+
+// Prefix of the user metadata keys in listings (stat returns keys without it)
+const listingMetadataPrefix = "X-Amz-Meta-"
+
+type BlobReference struct {
+	// Name of the blob object in BlobsBucket
+	Key  string
+	Size int64
+}
+
+// Blobs are named after checksum of their content, so same content always has the same blob
+func BlobKey(checksum *entity.Checksum) string {
+	return checksum.Algorithm + "/" + checksum.Value
+}
+
+// Returns user metadata of the object which refers to the blob.
+// Checksum of the content is stored as well, so it's returned on download as for regular objects.
+func BlobReferenceMetadata(ref *BlobReference, checksum *entity.Checksum) map[string]string {
+	metadata := ChecksumMetadata(checksum)
+	if metadata == nil {
+		metadata = make(map[string]string, 2)
+	}
+	metadata[blobMetadataKey] = ref.Key
+	metadata[blobSizeMetadataKey] = strconv.FormatInt(ref.Size, 10)
+	return metadata
+}
+
+// Reverse of BlobReferenceMetadata, returns nil if object isn't reference.
+// Accepts both metadata returned by stat and by listing.
+func BlobReferenceFromMetadata(metadata map[string]string) *BlobReference {
+	key, sizeValue := metadataValue(metadata, blobMetadataKey), metadataValue(metadata, blobSizeMetadataKey)
+	if key == "" || sizeValue == "" {
+		return nil
+	}
+	size, err := strconv.ParseInt(sizeValue, 10, 64)
+	if err != nil {
+		return nil
+	}
+	return &BlobReference{
+		Key:  key,
+		Size: size,
+	}
+}
+
+func metadataValue(metadata map[string]string, key string) string {
+	if value, ok := metadata[key]; ok {
+		return value
+	}
+	return metadata[listingMetadataPrefix+key]
+}
+
+// Reports if there may be any references in the storage, so they must be resolved.
+// If deduplication was never used, then this check costs a single request.
+func IsDeduplicationUsed(ctx context.Context) (bool, error) {
+	if DeduplicationEnabled || blobsBucketExists.Load() {
+		return true, nil
+	}
+	ok, err := storage.Client.BucketExists(ctx, BlobsBucket)
+	if err != nil {
+		return false, err
+	}
+	if ok {
+		blobsBucketExists.Store(true)
+	}
+	return ok, nil
+}
+
+// Creates blobs bucket if it doesn't exist yet
+func EnsureBlobsBucket(ctx context.Context) error {
+	if blobsBucketExists.Load() {
+		return nil
+	}
+	ok, err := storage.Client.BucketExists(ctx, BlobsBucket)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		err := storage.Client.MakeBucket(ctx, BlobsBucket, minio.MakeBucketOptions{})
+		// Bucket may be created concurrently
+		if err != nil && !IsErrorCode(err, "BucketAlreadyOwnedByYou") {
+			return err
+		}
+	}
+	blobsBucketExists.Store(true)
+	return nil
+}
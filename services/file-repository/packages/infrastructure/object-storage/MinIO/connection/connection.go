@@ -2,8 +2,12 @@ package minioconnection
 
 import (
 	"context"
+	"errors"
+	"net"
+	"strings"
 	"time"
 	StorageConnection "vega_file_repository/packages/infrastructure/object-storage/connection"
+	"vega_file_repository/packages/infrastructure/tlsconfig"
 
 	"github.com/minio/minio-go/v7"
 	"github.com/minio/minio-go/v7/pkg/credentials"
@@ -24,11 +28,38 @@ func (m *defaultConnectionManager) Status() StorageConnection.Status {
 	return m.status
 }
 
+// Host of the endpoint (host[:port]), which certificate of the storage must be issued for
+func endpointHost(endpoint string) string {
+	host, _, err := net.SplitHostPort(endpoint)
+	if err != nil {
+		// Endpoint without port
+		host = endpoint
+	}
+	return strings.Trim(host, "[]")
+}
+
 func (m *defaultConnectionManager) Connect(cfg *StorageConnection.Config) error {
-	client, err := minio.New(cfg.URL, &minio.Options{
+	options := &minio.Options{
 		Creds:  credentials.NewStaticV4(cfg.Login, cfg.Password, cfg.Token),
 		Secure: cfg.Secure,
-	})
+	}
+	if cfg.CAFile != "" {
+		if !cfg.Secure {
+			return errors.New("CA bundle is specified, but connection isn't secure")
+		}
+		rootCAs, err := tlsconfig.LoadCAPool(cfg.CAFile)
+		if err != nil {
+			return err
+		}
+		transport, err := minio.DefaultTransport(true)
+		if err != nil {
+			return err
+		}
+		transport.TLSClientConfig = tlsconfig.ClientConfig(rootCAs, endpointHost(cfg.URL))
+		options.Transport = transport
+	}
+
+	client, err := minio.New(cfg.URL, options)
 	if err != nil {
 		return err
 	}
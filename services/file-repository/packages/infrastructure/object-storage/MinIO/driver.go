@@ -3,6 +3,7 @@ package minio
 import (
 	FileApplication "vega_file_repository/packages/application/file"
 	MinIOCommand "vega_file_repository/packages/infrastructure/object-storage/MinIO/command"
+	MinIOCommon "vega_file_repository/packages/infrastructure/object-storage/MinIO/common"
 	MinIOConnection "vega_file_repository/packages/infrastructure/object-storage/MinIO/connection"
 	MinIOQuery "vega_file_repository/packages/infrastructure/object-storage/MinIO/query"
 	StorageConnection "vega_file_repository/packages/infrastructure/object-storage/connection"
@@ -15,6 +16,18 @@ type Driver struct {
 }
 
 func InitDriver() *Driver {
+	MinIOCommon.DeduplicationEnabled = false
+	return newDriver()
+}
+
+// Same as regular driver, but content of uploaded files is stored only once per unique checksum.
+// Multipart and archive uploads aren't deduplicated.
+func InitDeduplicatingDriver() *Driver {
+	MinIOCommon.DeduplicationEnabled = true
+	return newDriver()
+}
+
+func newDriver() *Driver {
 	return &Driver{
 		Manager:        MinIOConnection.Manager,
 		QueryHandler:   MinIOQuery.Handler,
@@ -1,15 +1,26 @@
 package minio
 
 import (
+	"archive/tar"
 	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"io"
+	"net/http"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 	FileApplication "vega_file_repository/packages/application/file"
+	"vega_file_repository/packages/domain/entity"
+	MinIOCommon "vega_file_repository/packages/infrastructure/object-storage/MinIO/common"
 	StorageConnection "vega_file_repository/packages/infrastructure/object-storage/connection"
+
+	cqrs "github.com/abaxoth0/Vega/libs/go/packages/CQRS"
 )
 
 func connect(driver *Driver) error {
@@ -183,7 +194,7 @@ func TestUseCasesImplementation(t *testing.T) {
 			if !input.invalid {
 				filesPaths = append(filesPaths, input.path)
 			}
-			e := driver.UploadFile(&cmd)
+			_, e := driver.UploadFile(&cmd)
 			// Allow invalid inputs to be used, but ignore the result.
 			// Just to see will it cause panic or some unexpected behaviour or not.
 			if e != nil && !input.invalid {
@@ -214,11 +225,532 @@ func TestUseCasesImplementation(t *testing.T) {
 		})
 	})
 
+	t.Run("GetFileByPath() range", func(t *testing.T) {
+		rangeInputs := []struct {
+			offset   int64
+			length   int64
+			expected string
+			invalid  bool
+		}{
+			{offset: 6, length: 5, expected: fileContent[6:11]},
+			{offset: 0, length: 5, expected: fileContent[:5]},
+			{offset: int64(len(fileContent)) - 8, expected: fileContent[len(fileContent)-8:]},
+			{offset: int64(len(fileContent)) - 8, length: 100, expected: fileContent[len(fileContent)-8:]},
+			{offset: int64(len(fileContent)), invalid: true},
+			{offset: -1, invalid: true},
+			{length: -1, invalid: true},
+		}
+		for _, input := range rangeInputs {
+			stream, err := driver.GetFileByPath(&FileApplication.GetFileByPathQuery{
+				Bucket: bucketName,
+				Path:   "/test-file.txt",
+				Offset: input.offset,
+				Length: input.length,
+			})
+			if input.invalid {
+				if err == nil {
+					stream.Cancel()
+					t.Errorf("Range (offset %d, length %d) must be invalid", input.offset, input.length)
+				}
+				continue
+			}
+			if err != nil {
+				t.Errorf("Failed to get range (offset %d, length %d): %v", input.offset, input.length, err)
+				continue
+			}
+			content, err := io.ReadAll(stream.Content)
+			stream.Cancel()
+			if err != nil {
+				t.Errorf("Failed to read range (offset %d, length %d): %v", input.offset, input.length, err)
+				continue
+			}
+			if string(content) != input.expected {
+				t.Errorf("Range content doesn't match, expected \"%s\", but got \"%s\"", input.expected, content)
+			}
+			if stream.Range.Length() != int64(len(input.expected)) {
+				t.Errorf("Invalid served range: %+v", stream.Range)
+			}
+		}
+	})
+
+	t.Run("GetFileByPath() directory archive", func(t *testing.T) {
+		stream, err := driver.GetFileByPath(&FileApplication.GetFileByPathQuery{
+			Bucket:        bucketName,
+			Path:          "/dir/",
+			ArchiveFormat: FileApplication.ArchiveFormatTar,
+		})
+		if err != nil {
+			t.Fatalf("Failed to get directory archive: %v", err)
+		}
+		defer stream.Cancel()
+
+		expected := map[string]bool{
+			"dir/":           false,
+			"dir/file.txt":   false,
+			"dir/some.dir/f": false,
+		}
+		reader := tar.NewReader(stream.Content)
+		for {
+			header, err := reader.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatalf("Failed to read archive: %v", err)
+			}
+			if _, ok := expected[header.Name]; !ok {
+				t.Errorf("Unexpected archive entry \"%s\"", header.Name)
+				continue
+			}
+			expected[header.Name] = true
+			if header.Typeflag == tar.TypeReg {
+				content, err := io.ReadAll(reader)
+				if err != nil {
+					t.Fatalf("Failed to read archive entry \"%s\": %v", header.Name, err)
+				}
+				if string(content) != fileContent {
+					t.Errorf("Content of archive entry \"%s\" doesn't match", header.Name)
+				}
+			}
+		}
+		for name, found := range expected {
+			if !found {
+				t.Errorf("Archive entry \"%s\" is missing", name)
+			}
+		}
+
+		_, err = driver.GetFileByPath(&FileApplication.GetFileByPathQuery{
+			Bucket: bucketName,
+			Path:   "/dir/",
+		})
+		if err == nil {
+			t.Errorf("Directory must not be sent without archive format")
+		}
+	})
+
+	t.Run("ListDirectory()", func(t *testing.T) {
+		listing, err := driver.ListDirectory(&FileApplication.ListDirectoryQuery{
+			Bucket: bucketName,
+			Path:   "/dir/",
+		})
+		if err != nil {
+			t.Fatalf("Failed to list directory: %v", err)
+		}
+		expected := map[string]bool{
+			"/dir/file.txt":  false,
+			"/dir/some.dir/": true,
+		}
+		if len(listing.Entries) != len(expected) {
+			t.Fatalf("Expected %d entries, but got %d: %+v", len(expected), len(listing.Entries), listing.Entries)
+		}
+		for _, entry := range listing.Entries {
+			isDir, ok := expected[entry.Path]
+			if !ok {
+				t.Errorf("Unexpected entry \"%s\"", entry.Path)
+				continue
+			}
+			if entry.IsDirectory != isDir {
+				t.Errorf("Invalid IsDirectory for \"%s\", expected %t", entry.Path, isDir)
+			}
+			if !isDir && entry.Size != int64(len(fileContent)) {
+				t.Errorf("Invalid size of \"%s\": %d", entry.Path, entry.Size)
+			}
+		}
+
+		listing, err = driver.ListDirectory(&FileApplication.ListDirectoryQuery{
+			Bucket:    bucketName,
+			Path:      "/dir/",
+			Recursive: true,
+		})
+		if err != nil {
+			t.Fatalf("Failed to list directory recursively: %v", err)
+		}
+		if len(listing.Entries) != 2 {
+			t.Errorf("Expected 2 entries in recursive listing, but got %d: %+v", len(listing.Entries), listing.Entries)
+		}
+
+		t.Log("Testing pagination...")
+		total := 0
+		token := ""
+		for {
+			listing, err = driver.ListDirectory(&FileApplication.ListDirectoryQuery{
+				Bucket:            bucketName,
+				Path:              "/",
+				Recursive:         true,
+				Limit:             1,
+				ContinuationToken: token,
+			})
+			if err != nil {
+				t.Fatalf("Failed to list directory page: %v", err)
+			}
+			if len(listing.Entries) > 1 {
+				t.Fatalf("Page limit exceeded: %d entries", len(listing.Entries))
+			}
+			total += len(listing.Entries)
+			if !listing.IsTruncated {
+				break
+			}
+			token = listing.NextContinuationToken
+		}
+		// uploaded files + directory created by Mkdir()
+		if total != len(filesPaths)+1 {
+			t.Errorf("Expected %d entries in total, but got %d", len(filesPaths)+1, total)
+		}
+		t.Log("Testing pagination: OK")
+
+		_, err = driver.ListDirectory(&FileApplication.ListDirectoryQuery{
+			Bucket: bucketName,
+			Path:   "/test-file.txt",
+		})
+		if err == nil {
+			t.Errorf("Listing of non-directory path must fail")
+		}
+
+		// Storage lists files before subdirectories, but they must be neither skipped nor repeated across pages
+		seen := make(map[string]bool)
+		token = ""
+		for {
+			listing, err = driver.ListDirectory(&FileApplication.ListDirectoryQuery{
+				Bucket:            bucketName,
+				Path:              "/dir/",
+				Limit:             1,
+				ContinuationToken: token,
+			})
+			if err != nil {
+				t.Fatalf("Failed to list directory page: %v", err)
+			}
+			for _, entry := range listing.Entries {
+				if seen[entry.Path] {
+					t.Errorf("Entry \"%s\" is listed twice", entry.Path)
+				}
+				seen[entry.Path] = true
+			}
+			if !listing.IsTruncated {
+				break
+			}
+			token = listing.NextContinuationToken
+		}
+		for path := range expected {
+			if !seen[path] {
+				t.Errorf("Entry \"%s\" is missing in paginated listing", path)
+			}
+		}
+
+		listing, err = driver.ListDirectory(&FileApplication.ListDirectoryQuery{
+			Bucket: bucketName,
+			Path:   "/missing-dir/",
+		})
+		if err != nil || len(listing.Entries) != 0 {
+			t.Errorf("Missing directory must be listed as empty one, but got: %+v (%v)", listing, err)
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err = driver.ListDirectory(&FileApplication.ListDirectoryQuery{
+			Bucket: bucketName,
+			Path:   "/",
+			CommandQuery: cqrs.CommandQuery{
+				Context:        ctx,
+				ContextTimeout: time.Minute,
+			},
+		})
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Listing must be cancelled with its context, but got: %v", err)
+		}
+	})
+
+	t.Run("UploadFile() archive", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		writer := tar.NewWriter(buf)
+		entries := []struct {
+			name     string
+			typeflag byte
+			content  string
+		}{
+			{name: "./", typeflag: tar.TypeDir},
+			{name: "./docs/", typeflag: tar.TypeDir},
+			{name: "./docs/readme.txt", typeflag: tar.TypeReg, content: fileContent},
+			{name: "../escape.txt", typeflag: tar.TypeReg, content: fileContent},
+			{name: "link", typeflag: tar.TypeSymlink},
+		}
+		for _, entry := range entries {
+			err := writer.WriteHeader(&tar.Header{
+				Name:     entry.name,
+				Typeflag: entry.typeflag,
+				Size:     int64(len(entry.content)),
+				Linkname: "/etc/passwd",
+			})
+			if err != nil {
+				t.Fatalf("Failed to create test archive: %v", err)
+			}
+			if _, err := writer.Write([]byte(entry.content)); err != nil {
+				t.Fatalf("Failed to create test archive: %v", err)
+			}
+		}
+		if err := writer.Close(); err != nil {
+			t.Fatalf("Failed to create test archive: %v", err)
+		}
+
+		result, err := driver.UploadFile(&FileApplication.UploadFileCommand{
+			Bucket:        bucketName,
+			Path:          "/extracted/",
+			Content:       buf,
+			ContentSize:   int64(buf.Len()),
+			ArchiveFormat: FileApplication.ArchiveFormatTar,
+		})
+		if !errors.Is(err, FileApplication.ErrPartialExtraction) {
+			t.Fatalf("Expected partial extraction, but got: %v", err)
+		}
+		if len(result.Entries) != 4 {
+			t.Fatalf("Expected 4 entries in result, but got %d: %+v", len(result.Entries), result.Entries)
+		}
+		if failed := result.FailedEntries(); len(failed) != 2 {
+			t.Errorf("Expected 2 rejected entries, but got %d: %+v", len(failed), failed)
+		}
+
+		stream, err := driver.GetFileByPath(&FileApplication.GetFileByPathQuery{
+			Bucket: bucketName,
+			Path:   "/extracted/docs/readme.txt",
+		})
+		if err != nil {
+			t.Fatalf("Failed to get extracted file: %v", err)
+		}
+		defer stream.Cancel()
+		content, err := io.ReadAll(stream.Content)
+		if err != nil {
+			t.Fatalf("Failed to read extracted file: %v", err)
+		}
+		if string(content) != fileContent {
+			t.Errorf("Content of extracted file doesn't match")
+		}
+	})
+
+	t.Run("Resumable upload", func(t *testing.T) {
+		const path = "/multipart.bin"
+
+		uploadID, err := driver.InitiateUpload(&FileApplication.InitiateUploadCommand{
+			Bucket: bucketName,
+			Path:   path,
+		})
+		if err != nil {
+			t.Fatalf("Failed to initiate upload: %v", err)
+		}
+
+		// All parts except the last one must be at least 5 MiB
+		parts := [][]byte{
+			bytes.Repeat([]byte{'a'}, 5*1024*1024),
+			[]byte(fileContent),
+		}
+		uploadPart := func(number int, content []byte) error {
+			_, err := driver.UploadPart(&FileApplication.UploadPartCommand{
+				Bucket:     bucketName,
+				Path:       path,
+				UploadID:   uploadID,
+				PartNumber: number,
+				Content:    bytes.NewReader(content),
+				Size:       int64(len(content)),
+				CommandQuery: cqrs.CommandQuery{
+					Context:        context.Background(),
+					ContextTimeout: time.Second * 30,
+				},
+			})
+			return err
+		}
+		for i, part := range parts {
+			if err := uploadPart(i+1, part); err != nil {
+				t.Fatalf("Failed to upload part %d: %v", i+1, err)
+			}
+		}
+		t.Log("Retrying last part...")
+		if err := uploadPart(len(parts), parts[len(parts)-1]); err != nil {
+			t.Fatalf("Failed to reupload part: %v", err)
+		}
+		if err := uploadPart(0, parts[0]); err == nil {
+			t.Errorf("Part with invalid number was uploaded")
+		}
+
+		uploaded, err := driver.ListUploadedParts(&FileApplication.ListUploadedPartsQuery{
+			Bucket:   bucketName,
+			Path:     path,
+			UploadID: uploadID,
+		})
+		if err != nil {
+			t.Fatalf("Failed to list uploaded parts: %v", err)
+		}
+		if len(uploaded) != len(parts) {
+			t.Fatalf("Expected %d uploaded parts, but got %d", len(parts), len(uploaded))
+		}
+
+		err = driver.CompleteUpload(&FileApplication.CompleteUploadCommand{
+			Bucket:   bucketName,
+			Path:     path,
+			UploadID: uploadID,
+		})
+		if err != nil {
+			t.Fatalf("Failed to complete upload: %v", err)
+		}
+
+		stream, err := driver.GetFileByPath(&FileApplication.GetFileByPathQuery{
+			Bucket: bucketName,
+			Path:   path,
+		})
+		if err != nil {
+			t.Fatalf("Failed to get uploaded file: %v", err)
+		}
+		stream.Cancel()
+		if stream.Size != int64(len(parts[0])+len(parts[1])) {
+			t.Errorf("Invalid size of uploaded file: %d", stream.Size)
+		}
+
+		t.Log("Testing stale uploads collection...")
+		abortedID, err := driver.InitiateUpload(&FileApplication.InitiateUploadCommand{
+			Bucket: bucketName,
+			Path:   "/abandoned.bin",
+		})
+		if err != nil {
+			t.Fatalf("Failed to initiate upload: %v", err)
+		}
+		time.Sleep(time.Millisecond * 10)
+		if _, err := driver.AbortStaleUploads(&FileApplication.AbortStaleUploadsCommand{
+			MaxAge: time.Millisecond,
+		}); err != nil {
+			t.Fatalf("Failed to abort stale uploads: %v", err)
+		}
+		_, err = driver.ListUploadedParts(&FileApplication.ListUploadedPartsQuery{
+			Bucket:   bucketName,
+			Path:     "/abandoned.bin",
+			UploadID: abortedID,
+		})
+		if !errors.Is(err, FileApplication.ErrUploadDoesNotExist) {
+			t.Errorf("Stale upload wasn't aborted: %v", err)
+		}
+		t.Log("Testing stale uploads collection: OK")
+	})
+
+	readFile := func(t *testing.T, path string) string {
+		stream, err := driver.GetFileByPath(&FileApplication.GetFileByPathQuery{
+			Bucket: bucketName,
+			Path:   path,
+		})
+		if err != nil {
+			t.Fatalf("Failed to get file \"%s\": %v", path, err)
+		}
+		defer stream.Cancel()
+		content, err := io.ReadAll(stream.Content)
+		if err != nil {
+			t.Fatalf("Failed to read content of \"%s\": %v", path, err)
+		}
+		return string(content)
+	}
+
+	t.Run("CopyFiles()", func(t *testing.T) {
+		copyFiles := func(source string, destination string, overwrite FileApplication.OverwriteMode) (*entity.RelocationReport, error) {
+			return driver.CopyFiles(&FileApplication.CopyFilesCommand{
+				FilesRelocation: FileApplication.FilesRelocation{
+					SourceBucket:    bucketName,
+					SourcePath:      source,
+					DestinationPath: destination,
+					Overwrite:       overwrite,
+				},
+			})
+		}
+
+		report, err := copyFiles("/dir/", "/copied/", FileApplication.OverwriteFail)
+		if err != nil {
+			t.Fatalf("Failed to copy directory: %v", err)
+		}
+		if report.RelocatedCount() != 2 {
+			t.Errorf("Expected 2 copied files, but got %d: %+v", report.RelocatedCount(), report.Files)
+		}
+		for _, path := range []string{"/dir/file.txt", "/copied/file.txt", "/copied/some.dir/f"} {
+			if content := readFile(t, path); content != fileContent {
+				t.Errorf("Content of \"%s\" doesn't match", path)
+			}
+		}
+
+		t.Log("Testing overwrite modes...")
+		if _, err := copyFiles("/dir/", "/copied/", FileApplication.OverwriteFail); !errors.Is(err, FileApplication.ErrDestinationExists) {
+			t.Errorf("Expected ErrDestinationExists, but got: %v", err)
+		}
+		report, err = copyFiles("/dir/", "/copied/", FileApplication.OverwriteSkip)
+		if err != nil {
+			t.Fatalf("Failed to copy directory: %v", err)
+		}
+		if report.SkippedCount() != 2 || report.RelocatedCount() != 0 {
+			t.Errorf("Expected 2 skipped files, but got %d (%d copied)", report.SkippedCount(), report.RelocatedCount())
+		}
+		report, err = copyFiles("/test-file.txt", "/copied/file.txt", FileApplication.OverwriteReplace)
+		if err != nil {
+			t.Fatalf("Failed to replace file: %v", err)
+		}
+		if report.RelocatedCount() != 1 {
+			t.Errorf("Expected 1 copied file, but got %d", report.RelocatedCount())
+		}
+		t.Log("Testing overwrite modes: OK")
+
+		if _, err := copyFiles("/dir/", "/dir/nested/", FileApplication.OverwriteFail); !errors.Is(err, FileApplication.ErrRelocationIntoItself) {
+			t.Errorf("Directory must not be copied into itself, but got: %v", err)
+		}
+		if _, err := copyFiles("/dir/", "/file-destination", FileApplication.OverwriteFail); !errors.Is(err, FileApplication.ErrRelocationTypeMismatch) {
+			t.Errorf("Directory must not be copied into file, but got: %v", err)
+		}
+		if _, err := copyFiles("/missing.txt", "/copied/missing.txt", FileApplication.OverwriteFail); err == nil {
+			t.Errorf("Copying of non-existing file must fail")
+		}
+	})
+
+	t.Run("MoveFiles()", func(t *testing.T) {
+		moveFiles := func(source string, destination string) (*entity.RelocationReport, error) {
+			return driver.MoveFiles(&FileApplication.MoveFilesCommand{
+				FilesRelocation: FileApplication.FilesRelocation{
+					SourceBucket:    bucketName,
+					SourcePath:      source,
+					DestinationPath: destination,
+				},
+			})
+		}
+
+		report, err := moveFiles("/copied/", "/moved/")
+		if err != nil {
+			t.Fatalf("Failed to move directory: %v", err)
+		}
+		if report.RelocatedCount() != 2 {
+			t.Errorf("Expected 2 moved files, but got %d: %+v", report.RelocatedCount(), report.Files)
+		}
+		listing, err := driver.ListDirectory(&FileApplication.ListDirectoryQuery{
+			Bucket:    bucketName,
+			Path:      "/copied/",
+			Recursive: true,
+		})
+		if err != nil {
+			t.Fatalf("Failed to list directory: %v", err)
+		}
+		if len(listing.Entries) != 0 {
+			t.Errorf("Source directory wasn't fully moved, remaining entries: %+v", listing.Entries)
+		}
+
+		t.Log("Testing rename...")
+		if _, err := moveFiles("/moved/file.txt", "/moved/renamed.txt"); err != nil {
+			t.Fatalf("Failed to rename file: %v", err)
+		}
+		if content := readFile(t, "/moved/renamed.txt"); content != fileContent {
+			t.Errorf("Content of renamed file doesn't match")
+		}
+		_, err = driver.GetFileByPath(&FileApplication.GetFileByPathQuery{
+			Bucket: bucketName,
+			Path:   "/moved/file.txt",
+		})
+		if err == nil {
+			t.Errorf("Renamed file still exists under old path")
+		}
+		t.Log("Testing rename: OK")
+	})
+
 	newFileContent := []byte("some new file content")
 
 	t.Run("UpdateFileContent()", func(t *testing.T) {
 		asyncProcess(filesPaths, func(_ int, path string) {
-			err = driver.UpdateFileContent(&FileApplication.UpdateFileContentCommand{
+			_, err = driver.UpdateFileContent(&FileApplication.UpdateFileContentCommand{
 				Path:       path,
 				Bucket:     bucketName,
 				NewContent: bytes.NewReader(newFileContent),
@@ -247,9 +779,205 @@ func TestUseCasesImplementation(t *testing.T) {
 		})
 	})
 
+	t.Run("StatFile()", func(t *testing.T) {
+		paths := append([]string{"/", "/missing-file.txt", "/missing-dir/"}, filesPaths...)
+		stats, err := driver.StatFile(&FileApplication.StatFileQuery{
+			Bucket: bucketName,
+			Paths:  paths,
+		})
+		if err != nil {
+			t.Fatalf("Failed to stat files: %v", err)
+		}
+		if len(stats) != len(paths) {
+			t.Fatalf("Expected %d stats, but got %d", len(paths), len(stats))
+		}
+		if !stats[0].Exists || !stats[0].IsDirectory {
+			t.Errorf("Root of the bucket must exist: %+v", stats[0])
+		}
+		if stats[1].Exists || stats[2].Exists {
+			t.Errorf("Missing files must be reported as not existing: %+v, %+v", stats[1], stats[2])
+		}
+		for i, stat := range stats[3:] {
+			if stat.Path != filesPaths[i] {
+				t.Errorf("Stats must be ordered as requested paths, expected \"%s\", but got \"%s\"", filesPaths[i], stat.Path)
+			}
+			if !stat.Exists || stat.Size != int64(len(newFileContent)) || stat.ETag == "" || stat.LastModified.IsZero() {
+				t.Errorf("Invalid stat of \"%s\": %+v", stat.Path, stat)
+			}
+		}
+
+		invalid := []*FileApplication.StatFileQuery{
+			{Bucket: bucketName},
+			{Bucket: bucketName, Paths: []string{"no-slash"}},
+			{Bucket: bucketName, Paths: []string{"/a", "/b"}, VersionID: "1"},
+		}
+		for _, query := range invalid {
+			if _, err := driver.StatFile(query); err == nil {
+				t.Errorf("Query must be rejected: %+v", query)
+			}
+		}
+		if _, err := driver.StatFile(&FileApplication.StatFileQuery{Bucket: bucketName + "-missing", Paths: []string{"/"}}); err == nil {
+			t.Errorf("Stat in missing bucket must fail")
+		}
+	})
+
+	t.Run("Conditional writes", func(t *testing.T) {
+		path := "/conditional.txt"
+		write := func(content string, preconditions FileApplication.WritePreconditions) (*entity.UploadResult, error) {
+			return driver.UpdateFileContent(&FileApplication.UpdateFileContentCommand{
+				Bucket:        bucketName,
+				Path:          path,
+				NewContent:    strings.NewReader(content),
+				Size:          int64(len(content)),
+				Preconditions: preconditions,
+			})
+		}
+
+		created, err := driver.UploadFile(&FileApplication.UploadFileCommand{
+			Bucket:        bucketName,
+			Path:          path,
+			Content:       strings.NewReader("v1"),
+			ContentSize:   2,
+			Preconditions: FileApplication.WritePreconditions{IfNoneMatch: true},
+		})
+		if err != nil {
+			t.Fatalf("Failed to create file: %v", err)
+		}
+		if created.ETag == "" {
+			t.Fatalf("ETag of the written file must be returned")
+		}
+		if _, err := write("v1-again", FileApplication.WritePreconditions{IfNoneMatch: true}); !errors.Is(err, FileApplication.ErrPreconditionFailed) {
+			t.Errorf("Create-only write of existing file must fail with ErrPreconditionFailed, but got: %v", err)
+		}
+		if _, err := write("stale", FileApplication.WritePreconditions{IfMatch: "stale-etag"}); !errors.Is(err, FileApplication.ErrPreconditionFailed) {
+			t.Errorf("Write with stale ETag must fail with ErrPreconditionFailed, but got: %v", err)
+		}
+
+		updated, err := write("v2-content", FileApplication.WritePreconditions{IfMatch: "\"" + created.ETag + "\""})
+		if err != nil {
+			t.Fatalf("Write with current ETag must succeed: %v", err)
+		}
+		if updated.ETag == "" || updated.ETag == created.ETag {
+			t.Errorf("New ETag must be returned, got \"%s\" (previous one is \"%s\")", updated.ETag, created.ETag)
+		}
+		if _, err := write("v3", FileApplication.WritePreconditions{IfMatch: created.ETag}); !errors.Is(err, FileApplication.ErrPreconditionFailed) {
+			t.Errorf("Write with outdated ETag must fail with ErrPreconditionFailed, but got: %v", err)
+		}
+		if _, err := write("v3", FileApplication.WritePreconditions{IfUnmodifiedSince: time.Now().Add(-time.Hour)}); !errors.Is(err, FileApplication.ErrPreconditionFailed) {
+			t.Errorf("Write of file modified since must fail with ErrPreconditionFailed, but got: %v", err)
+		}
+		if _, err := write("v3-content", FileApplication.WritePreconditions{IfUnmodifiedSince: time.Now().Add(time.Hour)}); err != nil {
+			t.Errorf("Write of unmodified file must succeed: %v", err)
+		}
+
+		stats, err := driver.StatFile(&FileApplication.StatFileQuery{Bucket: bucketName, Paths: []string{path}})
+		if err != nil {
+			t.Fatalf("Failed to stat file: %v", err)
+		}
+		if stats[0].Size != int64(len("v3-content")) {
+			t.Errorf("Failed writes mustn't change the file, expected size %d, but got %d", len("v3-content"), stats[0].Size)
+		}
+
+		// Only one of the writers which saw the same version of the file may succeed
+		var succeeded atomic.Int32
+		asyncProcess(make([]string, 8), func(i int, _ string) {
+			_, err := write("concurrent-"+strconv.Itoa(i), FileApplication.WritePreconditions{IfMatch: stats[0].ETag})
+			if err == nil {
+				succeeded.Add(1)
+			} else if !errors.Is(err, FileApplication.ErrPreconditionFailed) {
+				t.Errorf("Concurrent write failed with unexpected error: %v", err)
+			}
+		})
+		if n := succeeded.Load(); n != 1 {
+			t.Errorf("Exactly one of the concurrent conditional writes must succeed, but %d did", n)
+		}
+
+		missing := "/conditional-missing.txt"
+		_, err = driver.UpdateFileContent(&FileApplication.UpdateFileContentCommand{
+			Bucket:        bucketName,
+			Path:          missing,
+			NewContent:    strings.NewReader("content"),
+			Size:          7,
+			Preconditions: FileApplication.WritePreconditions{IfMatch: "*"},
+		})
+		if !errors.Is(err, FileApplication.ErrPreconditionFailed) {
+			t.Errorf("Write of missing file with \"If-Match: *\" must fail with ErrPreconditionFailed, but got: %v", err)
+		}
+		if _, err := write("v4", FileApplication.WritePreconditions{IfNoneMatch: true, IfMatch: "*"}); !errors.Is(err, FileApplication.ErrConflictingPreconditions) {
+			t.Errorf("Conflicting preconditions must be rejected, but got: %v", err)
+		}
+
+		if _, err := driver.DeleteFiles(&FileApplication.DeleteFilesCommand{Bucket: bucketName, Paths: []string{path}}); err != nil {
+			t.Errorf("Failed to delete file: %v", err)
+		}
+	})
+
+	t.Run("CreatePresignedURL()", func(t *testing.T) {
+		send := func(presigned *entity.PresignedURL, body []byte) *http.Response {
+			req, err := http.NewRequest(presigned.Method, presigned.URL, bytes.NewReader(body))
+			if err != nil {
+				t.Fatalf("Failed to create request: %v", err)
+			}
+			for name, value := range presigned.Headers {
+				req.Header.Set(name, value)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("Request failed: %v", err)
+			}
+			t.Cleanup(func() { resp.Body.Close() })
+			return resp
+		}
+
+		content := []byte("presigned upload")
+		upload, err := driver.CreatePresignedURL(&FileApplication.CreatePresignedURLQuery{
+			Bucket:      bucketName,
+			Path:        "/presigned.txt",
+			Method:      http.MethodPut,
+			ContentType: "text/plain",
+			Size:        int64(len(content)),
+		})
+		if err != nil {
+			t.Fatalf("Failed to presign upload: %v", err)
+		}
+		if resp := send(upload, content); resp.StatusCode != http.StatusOK {
+			t.Fatalf("Presigned upload failed: %s", resp.Status)
+		}
+
+		download, err := driver.CreatePresignedURL(&FileApplication.CreatePresignedURLQuery{
+			Bucket: bucketName,
+			Path:   "/presigned.txt",
+			Method: http.MethodGet,
+		})
+		if err != nil {
+			t.Fatalf("Failed to presign download: %v", err)
+		}
+		resp := send(download, nil)
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("Presigned download failed: %s", resp.Status)
+		}
+		downloaded, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("Failed to read response: %v", err)
+		}
+		if !bytes.Equal(downloaded, content) {
+			t.Errorf("Downloaded content doesn't match uploaded one")
+		}
+
+		// Restrictions of the upload are signed, so storage must reject upload without them
+		upload.Headers["Content-Type"] = "text/html"
+		if resp := send(upload, content); resp.StatusCode != http.StatusForbidden {
+			t.Errorf("Upload with another content type must be forbidden, but got %s", resp.Status)
+		}
+
+		if _, err := driver.DeleteFiles(&FileApplication.DeleteFilesCommand{Bucket: bucketName, Paths: []string{"/presigned.txt"}}); err != nil {
+			t.Errorf("Failed to delete file: %v", err)
+		}
+	})
+
 	t.Run("DeleteFiles()", func(t *testing.T) {
 		asyncProcess(filesPaths, func(_ int, path string) {
-			err = driver.DeleteFiles(&FileApplication.DeleteFilesCommand{
+			_, err = driver.DeleteFiles(&FileApplication.DeleteFilesCommand{
 				Bucket: bucketName,
 				Paths:  []string{path},
 			})
@@ -259,6 +987,350 @@ func TestUseCasesImplementation(t *testing.T) {
 		})
 	})
 
+	t.Run("DeleteFiles() recursive", func(t *testing.T) {
+		err = driver.Mkdir(&FileApplication.MkdirCommand{
+			Bucket: bucketName,
+			Path:   "/recursive/",
+		})
+		if err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		for _, path := range []string{"/recursive/a.txt", "/recursive/sub/b.txt"} {
+			_, err = driver.UploadFile(&FileApplication.UploadFileCommand{
+				Bucket:      bucketName,
+				Path:        path,
+				Content:     strings.NewReader(fileContent),
+				ContentSize: int64(len(fileContent)),
+			})
+			if err != nil {
+				t.Fatalf("Failed to upload file \"%s\": %v", path, err)
+			}
+		}
+
+		report, err := driver.DeleteFiles(&FileApplication.DeleteFilesCommand{
+			Bucket:    bucketName,
+			Paths:     []string{"/recursive/"},
+			Recursive: true,
+		})
+		if err != nil {
+			t.Fatalf("Failed to delete directory recursively: %v", err)
+		}
+		// 2 files + directory marker
+		if report.DeletedCount() != 3 {
+			t.Errorf("Expected 3 deleted objects, but got %d", report.DeletedCount())
+		}
+
+		listing, err := driver.ListDirectory(&FileApplication.ListDirectoryQuery{
+			Bucket:    bucketName,
+			Path:      "/recursive/",
+			Recursive: true,
+		})
+		if err != nil {
+			t.Fatalf("Failed to list directory: %v", err)
+		}
+		if len(listing.Entries) != 0 {
+			t.Errorf("Directory wasn't fully deleted, remaining entries: %+v", listing.Entries)
+		}
+	})
+
+	t.Run("Versioning", func(t *testing.T) {
+		path := "/versioned-file.txt"
+
+		readVersion := func(versionID string) (string, error) {
+			stream, err := driver.GetFileByPath(&FileApplication.GetFileByPathQuery{
+				Bucket:    bucketName,
+				Path:      path,
+				VersionID: versionID,
+			})
+			if err != nil {
+				return "", err
+			}
+			defer stream.Cancel()
+			content, err := io.ReadAll(stream.Content)
+			return string(content), err
+		}
+		listVersions := func() []entity.FileVersion {
+			versions, err := driver.ListFileVersions(&FileApplication.ListFileVersionsQuery{
+				Bucket: bucketName,
+				Path:   path,
+			})
+			if err != nil {
+				t.Fatalf("Failed to list versions: %v", err)
+			}
+			return versions
+		}
+
+		err := driver.SetBucketVersioning(&FileApplication.SetBucketVersioningCommand{
+			Name:    bucketName,
+			Enabled: true,
+		})
+		if err != nil {
+			t.Fatalf("Failed to enable versioning: %v", err)
+		}
+		info, err := driver.GetBucketInfo(&FileApplication.GetBucketInfoQuery{Name: bucketName})
+		if err != nil {
+			t.Fatalf("Failed to get bucket info: %v", err)
+		}
+		if !info.VersioningEnabled {
+			t.Errorf("Versioning must be enabled")
+		}
+
+		_, err = driver.UploadFile(&FileApplication.UploadFileCommand{
+			Bucket:      bucketName,
+			Path:        path,
+			Content:     strings.NewReader("first"),
+			ContentSize: 5,
+		})
+		if err != nil {
+			t.Fatalf("Failed to upload file: %v", err)
+		}
+		_, err = driver.UpdateFileContent(&FileApplication.UpdateFileContentCommand{
+			Bucket:     bucketName,
+			Path:       path,
+			NewContent: strings.NewReader("second"),
+			Size:       6,
+		})
+		if err != nil {
+			t.Fatalf("Failed to update file: %v", err)
+		}
+
+		versions := listVersions()
+		if len(versions) != 2 {
+			t.Fatalf("Expected 2 versions, but got %d", len(versions))
+		}
+		if !versions[0].IsLatest || versions[1].IsLatest {
+			t.Errorf("Only the newest version must be latest")
+		}
+		if content, err := readVersion(versions[1].VersionID); err != nil || content != "first" {
+			t.Errorf("Invalid content of previous version: %q (%v)", content, err)
+		}
+		if content, err := readVersion(""); err != nil || content != "second" {
+			t.Errorf("Invalid content of current version: %q (%v)", content, err)
+		}
+		if _, err := readVersion(versions[1].VersionID + "0"); err == nil {
+			t.Errorf("Reading of non-existing version must fail")
+		}
+
+		restoredID, err := driver.RestoreFileVersion(&FileApplication.RestoreFileVersionCommand{
+			FileVersionTarget: FileApplication.FileVersionTarget{
+				Bucket:    bucketName,
+				Path:      path,
+				VersionID: versions[1].VersionID,
+			},
+		})
+		if err != nil {
+			t.Fatalf("Failed to restore version: %v", err)
+		}
+		if content, err := readVersion(""); err != nil || content != "first" {
+			t.Errorf("Invalid content of restored version: %q (%v)", content, err)
+		}
+		versions = listVersions()
+		if len(versions) != 3 || versions[0].VersionID != restoredID {
+			t.Errorf("Restored version must be a new current version, but got: %+v", versions)
+		}
+
+		// Previous version must become current
+		err = driver.DeleteFileVersion(&FileApplication.DeleteFileVersionCommand{
+			FileVersionTarget: FileApplication.FileVersionTarget{
+				Bucket:    bucketName,
+				Path:      path,
+				VersionID: restoredID,
+			},
+		})
+		if err != nil {
+			t.Fatalf("Failed to delete version: %v", err)
+		}
+		if content, err := readVersion(""); err != nil || content != "second" {
+			t.Errorf("Invalid content of current version after deletion of the latest one: %q (%v)", content, err)
+		}
+
+		_, err = driver.DeleteFiles(&FileApplication.DeleteFilesCommand{
+			Bucket: bucketName,
+			Paths:  []string{path},
+		})
+		if err != nil {
+			t.Fatalf("Failed to delete file: %v", err)
+		}
+		if _, err := readVersion(""); err == nil {
+			t.Errorf("Deleted file must not have current version")
+		}
+		versions = listVersions()
+		if len(versions) < 2 {
+			t.Errorf("Versions must be kept after deletion of the file, but got: %+v", versions)
+		}
+
+		for _, version := range versions {
+			err := driver.DeleteFileVersion(&FileApplication.DeleteFileVersionCommand{
+				FileVersionTarget: FileApplication.FileVersionTarget{
+					Bucket:    bucketName,
+					Path:      path,
+					VersionID: version.VersionID,
+				},
+			})
+			if err != nil {
+				t.Errorf("Failed to delete version: %v", err)
+			}
+		}
+		_, err = driver.ListFileVersions(&FileApplication.ListFileVersionsQuery{
+			Bucket: bucketName,
+			Path:   path,
+		})
+		if err == nil {
+			t.Errorf("File must have no versions left")
+		}
+
+		err = driver.SetBucketVersioning(&FileApplication.SetBucketVersioningCommand{
+			Name:    bucketName,
+			Enabled: false,
+		})
+		if err != nil {
+			t.Errorf("Failed to disable versioning: %v", err)
+		}
+	})
+
+	t.Run("Checksum verification", func(t *testing.T) {
+		path := "/checksum-test.txt"
+		missingPath := "/checksum-mismatch-test.txt"
+		content := "verified content"
+		digest := sha256.Sum256([]byte(content))
+		checksum := entity.Checksum{
+			Algorithm: FileApplication.ChecksumSHA256,
+			Value:     hex.EncodeToString(digest[:]),
+		}
+		wrongChecksum := entity.Checksum{
+			Algorithm: FileApplication.ChecksumSHA256,
+			Value:     strings.Repeat("0", len(checksum.Value)),
+		}
+
+		expected := checksum
+		result, err := driver.UploadFile(&FileApplication.UploadFileCommand{
+			Bucket:      bucketName,
+			Path:        path,
+			Content:     strings.NewReader(content),
+			ContentSize: int64(len(content)),
+			Checksum:    &expected,
+		})
+		if err != nil {
+			t.Fatalf("Failed to upload file with valid checksum: %v", err)
+		}
+		if result.Checksum == nil || *result.Checksum != checksum {
+			t.Errorf("Invalid computed checksum: %+v", result.Checksum)
+		}
+
+		stream, err := driver.GetFileByPath(&FileApplication.GetFileByPathQuery{
+			Bucket: bucketName,
+			Path:   path,
+		})
+		if err != nil {
+			t.Fatalf("Failed to get file: %v", err)
+		}
+		stream.Cancel()
+		if stream.Checksum == nil || *stream.Checksum != checksum {
+			t.Errorf("Invalid stored checksum: %+v", stream.Checksum)
+		}
+
+		mismatched := wrongChecksum
+		_, err = driver.UploadFile(&FileApplication.UploadFileCommand{
+			Bucket:      bucketName,
+			Path:        missingPath,
+			Content:     strings.NewReader(content),
+			ContentSize: int64(len(content)),
+			Checksum:    &mismatched,
+		})
+		if !errors.Is(err, FileApplication.ErrChecksumMismatch) {
+			t.Errorf("Expected checksum mismatch, but got: %v", err)
+		}
+		if _, err := driver.GetFileByPath(&FileApplication.GetFileByPathQuery{
+			Bucket: bucketName,
+			Path:   missingPath,
+		}); err == nil {
+			t.Errorf("File with mismatched checksum must not be stored")
+		}
+
+		mismatched = wrongChecksum
+		_, err = driver.UpdateFileContent(&FileApplication.UpdateFileContentCommand{
+			Bucket:     bucketName,
+			Path:       path,
+			NewContent: strings.NewReader("other content"),
+			Size:       int64(len("other content")),
+			Checksum:   &mismatched,
+		})
+		if !errors.Is(err, FileApplication.ErrChecksumMismatch) {
+			t.Errorf("Expected checksum mismatch, but got: %v", err)
+		}
+		stream, err = driver.GetFileByPath(&FileApplication.GetFileByPathQuery{
+			Bucket: bucketName,
+			Path:   path,
+		})
+		if err != nil {
+			t.Fatalf("Failed to get file: %v", err)
+		}
+		data, err := io.ReadAll(stream.Content)
+		stream.Cancel()
+		if err != nil || string(data) != content {
+			t.Errorf("File must be left untouched after failed update, but got: %q (%v)", data, err)
+		}
+
+		_, err = driver.DeleteFiles(&FileApplication.DeleteFilesCommand{
+			Bucket: bucketName,
+			Paths:  []string{path},
+		})
+		if err != nil {
+			t.Errorf("Failed to delete file: %v", err)
+		}
+	})
+
+	t.Run("ListBuckets() and GetBucketInfo()", func(t *testing.T) {
+		buckets, err := driver.ListBuckets(&FileApplication.ListBucketsQuery{})
+		if err != nil {
+			t.Fatalf("Failed to list buckets: %v", err)
+		}
+		found := false
+		for _, bucket := range buckets {
+			if bucket.Name == bucketName {
+				found = true
+				if bucket.CreationDate.IsZero() {
+					t.Errorf("Creation date of the bucket is missing")
+				}
+			}
+		}
+		if !found {
+			t.Fatalf("Test bucket is missing in the list of buckets")
+		}
+
+		info, err := driver.GetBucketInfo(&FileApplication.GetBucketInfoQuery{
+			Name: bucketName,
+		})
+		if err != nil {
+			t.Fatalf("Failed to get bucket info: %v", err)
+		}
+		listing, err := driver.ListDirectory(&FileApplication.ListDirectoryQuery{
+			Bucket:    bucketName,
+			Path:      "/",
+			Recursive: true,
+		})
+		if err != nil {
+			t.Fatalf("Failed to list bucket: %v", err)
+		}
+		var totalSize int64
+		for _, entry := range listing.Entries {
+			totalSize += entry.Size
+		}
+		if info.ObjectCount != int64(len(listing.Entries)) || info.TotalSize != totalSize {
+			t.Errorf(
+				"Invalid bucket info, expected %d objects of %d bytes, but got %d objects of %d bytes",
+				len(listing.Entries), totalSize, info.ObjectCount, info.TotalSize,
+			)
+		}
+
+		_, err = driver.GetBucketInfo(&FileApplication.GetBucketInfoQuery{
+			Name: bucketName + "-missing",
+		})
+		if err == nil {
+			t.Errorf("Getting info of non-existing bucket must fail")
+		}
+	})
+
 	t.Run("DeleteBucket()", func(t *testing.T) {
 		err = driver.Mkdir(&FileApplication.MkdirCommand{
 			Bucket: bucketName,
@@ -286,3 +1358,182 @@ func TestUseCasesImplementation(t *testing.T) {
 		t.Logf("Failed to disconnect: %v", err)
 	}
 }
+
+func TestDeduplication(t *testing.T) {
+	driver := InitDeduplicatingDriver()
+	// Deduplication mode is global, so it must not affect other tests
+	t.Cleanup(func() { InitDriver() })
+
+	if err := connect(driver); err != nil {
+		t.Fatalf("Connection failed")
+	}
+	bucketName := "vega--auto-test-dedup-" + strconv.FormatInt(time.Now().UnixMilli(), 10)
+	if err := driver.MakeBucket(&FileApplication.MakeBucketCommand{Name: bucketName}); err != nil {
+		t.Fatalf("Failed to create a new bucket: %v", err)
+	}
+	defer driver.DeleteBucket(&FileApplication.DeleteBucketCommand{Name: bucketName, Force: true})
+
+	upload := func(path string, content string) {
+		_, err := driver.UploadFile(&FileApplication.UploadFileCommand{
+			Bucket:      bucketName,
+			Path:        path,
+			Content:     strings.NewReader(content),
+			ContentSize: int64(len(content)),
+		})
+		if err != nil {
+			t.Fatalf("Failed to upload \"%s\": %v", path, err)
+		}
+	}
+	read := func(path string) (string, error) {
+		stream, err := driver.GetFileByPath(&FileApplication.GetFileByPathQuery{
+			Bucket: bucketName,
+			Path:   path,
+		})
+		if err != nil {
+			return "", err
+		}
+		defer stream.Cancel()
+		data, err := io.ReadAll(stream.Content)
+		return string(data), err
+	}
+	expectContent := func(path string, expected string) {
+		content, err := read(path)
+		if err != nil {
+			t.Errorf("Failed to read \"%s\": %v", path, err)
+		} else if content != expected {
+			t.Errorf("Invalid content of \"%s\", expected %q, but got %q", path, expected, content)
+		}
+	}
+
+	content := "deduplicated content"
+	upload("/a.txt", content)
+	upload("/dir/b.txt", content)
+	expectContent("/a.txt", content)
+	expectContent("/dir/b.txt", content)
+
+	listing, err := driver.ListDirectory(&FileApplication.ListDirectoryQuery{
+		Bucket:    bucketName,
+		Path:      "/",
+		Recursive: true,
+	})
+	if err != nil {
+		t.Fatalf("Failed to list bucket: %v", err)
+	}
+	for _, entry := range listing.Entries {
+		if entry.Size != int64(len(content)) {
+			t.Errorf("Invalid size of \"%s\": expected %d, but got %d", entry.Path, len(content), entry.Size)
+		}
+	}
+
+	stream, err := driver.GetFileByPath(&FileApplication.GetFileByPathQuery{
+		Bucket: bucketName,
+		Path:   "/a.txt",
+		Offset: 6,
+		Length: 7,
+	})
+	if err != nil {
+		t.Fatalf("Failed to get range of the file: %v", err)
+	}
+	data, err := io.ReadAll(stream.Content)
+	stream.Cancel()
+	if err != nil || string(data) != content[6:13] {
+		t.Errorf("Invalid range of the file: %q (%v)", data, err)
+	}
+
+	_, err = driver.DeleteFiles(&FileApplication.DeleteFilesCommand{
+		Bucket: bucketName,
+		Paths:  []string{"/a.txt"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to delete file: %v", err)
+	}
+	if _, err := read("/a.txt"); err == nil {
+		t.Errorf("Deleted file is still readable")
+	}
+	// Content is still referred by another file
+	expectContent("/dir/b.txt", content)
+
+	_, err = driver.CopyFiles(&FileApplication.CopyFilesCommand{
+		FilesRelocation: FileApplication.FilesRelocation{
+			SourceBucket:      bucketName,
+			SourcePath:        "/dir/b.txt",
+			DestinationBucket: bucketName,
+			DestinationPath:   "/copy.txt",
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to copy file: %v", err)
+	}
+
+	updated := "updated content"
+	_, err = driver.UpdateFileContent(&FileApplication.UpdateFileContentCommand{
+		Bucket:     bucketName,
+		Path:       "/dir/b.txt",
+		NewContent: strings.NewReader(updated),
+		Size:       int64(len(updated)),
+	})
+	if err != nil {
+		t.Fatalf("Failed to update file: %v", err)
+	}
+	expectContent("/dir/b.txt", updated)
+	expectContent("/copy.txt", content)
+
+	_, err = driver.DeleteFiles(&FileApplication.DeleteFilesCommand{
+		Bucket:    bucketName,
+		Paths:     []string{"/dir/", "/copy.txt"},
+		Recursive: true,
+	})
+	if err != nil {
+		t.Fatalf("Failed to delete files: %v", err)
+	}
+
+	// Content must be stored again after all references to it were deleted
+	upload("/again.txt", content)
+	expectContent("/again.txt", content)
+
+	// Concurrent writes of the same file must leave its blob marked as referred by it,
+	// otherwise blob is deleted once another reference to it is released.
+	contents := []string{"first concurrent content", "second concurrent content", "third concurrent content"}
+	for range 10 {
+		var wg sync.WaitGroup
+		for _, content := range contents {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := driver.UploadFile(&FileApplication.UploadFileCommand{
+					Bucket:      bucketName,
+					Path:        "/concurrent.txt",
+					Content:     strings.NewReader(content),
+					ContentSize: int64(len(content)),
+				})
+				if err != nil {
+					t.Errorf("Failed to upload concurrently: %v", err)
+				}
+			}()
+		}
+		wg.Wait()
+	}
+	current, err := read("/concurrent.txt")
+	if err != nil {
+		t.Fatalf("Failed to read concurrently written file: %v", err)
+	}
+	upload("/same.txt", current)
+	_, err = driver.DeleteFiles(&FileApplication.DeleteFilesCommand{
+		Bucket: bucketName,
+		Paths:  []string{"/same.txt"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to delete file: %v", err)
+	}
+	expectContent("/concurrent.txt", current)
+
+	buckets, err := driver.ListBuckets(&FileApplication.ListBucketsQuery{})
+	if err != nil {
+		t.Fatalf("Failed to list buckets: %v", err)
+	}
+	for _, bucket := range buckets {
+		if bucket.Name == MinIOCommon.BlobsBucket {
+			t.Errorf("Blobs bucket must be hidden")
+		}
+	}
+}
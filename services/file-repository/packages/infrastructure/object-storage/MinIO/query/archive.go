@@ -0,0 +1,134 @@
+package minioquery
+
+import (
+	"context"
+	"io"
+	"path"
+	"strings"
+	"time"
+	FileApplication "vega_file_repository/packages/application/file"
+	"vega_file_repository/packages/domain/entity"
+	"vega_file_repository/packages/infrastructure/archive"
+	MinIOCommon "vega_file_repository/packages/infrastructure/object-storage/MinIO/common"
+
+	"github.com/abaxoth0/Vega/libs/go/packages/file"
+	"github.com/minio/minio-go/v7"
+)
+
+// Archive is built on the fly: objects are read one by one and written into the pipe,
+// so neither the whole archive nor the whole object is kept in memory.
+// Size of the returned stream is unknown beforehand, so it's always -1.
+func (h *defaultQueryHandler) getDirectoryArchive(query *FileApplication.GetFileByPathQuery) (*entity.FileStream, error) {
+	ctx, cancel := context.WithTimeout(query.Context, query.ContextTimeout)
+
+	if err := MinIOCommon.IsBucketExist(ctx, query.Bucket); err != nil {
+		cancel()
+		return nil, err
+	}
+
+	pr, pw := io.Pipe()
+
+	writer, err := archive.NewWriter(query.ArchiveFormat, pw)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	go func() {
+		err := h.writeDirectoryArchive(ctx, query.Bucket, query.Path, writer)
+		if err == nil {
+			err = writer.Close()
+		}
+		pw.CloseWithError(err)
+	}()
+
+	return &entity.FileStream{
+		Content: pr,
+		Size:    -1,
+		Context: ctx,
+		Cancel: func() {
+			cancel()
+			// Unblocks archive writer if reader stopped reading before EOF
+			pr.Close()
+		},
+	}, nil
+}
+
+func (h *defaultQueryHandler) writeDirectoryArchive(
+	ctx context.Context,
+	bucket string,
+	dirPath string,
+	writer archive.Writer,
+) error {
+	prefix := MinIOCommon.ObjectKey(dirPath)
+	// Entries are placed inside of the directory with the same name as requested one,
+	// so on extraction they won't be scattered around. Root of the bucket has no name though.
+	root := path.Base(dirPath) + "/"
+	if dirPath == "/" {
+		root = ""
+	}
+
+	if root != "" {
+		if err := writer.WriteDirectory(root, time.Now()); err != nil {
+			return err
+		}
+	}
+
+	for object := range storage.Client.ListObjects(ctx, bucket, minio.ListObjectsOptions{
+		Prefix:    prefix,
+		Recursive: true,
+	}) {
+		if object.Err != nil {
+			return object.Err
+		}
+		name := root + strings.TrimPrefix(object.Key, prefix)
+
+		if object.Key == prefix {
+			continue
+		}
+		if file.IsDirectory(object.Key) {
+			if err := writer.WriteDirectory(name, object.LastModified); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := h.writeArchiveFile(ctx, bucket, object.Key, name, writer); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (h *defaultQueryHandler) writeArchiveFile(
+	ctx context.Context,
+	bucket string,
+	key string,
+	name string,
+	writer archive.Writer,
+) error {
+	object, err := storage.Client.GetObject(ctx, bucket, key, minio.GetObjectOptions{})
+	if err != nil {
+		return err
+	}
+	defer object.Close()
+
+	// Object may be changed after it was listed, so size from listing can't be trusted
+	stat, err := object.Stat()
+	if err != nil {
+		return err
+	}
+
+	if ref := MinIOCommon.BlobReferenceFromMetadata(stat.UserMetadata); ref != nil {
+		blob, err := storage.Client.GetObject(ctx, MinIOCommon.BlobsBucket, ref.Key, minio.GetObjectOptions{})
+		if err != nil {
+			return err
+		}
+		defer blob.Close()
+
+		return writer.WriteFile(name, ref.Size, stat.LastModified, blob)
+	}
+
+	return writer.WriteFile(name, stat.Size, stat.LastModified, object)
+}
@@ -0,0 +1,104 @@
+package minioquery
+
+import (
+	"context"
+	FileApplication "vega_file_repository/packages/application/file"
+	"vega_file_repository/packages/domain/entity"
+	MinIOCommon "vega_file_repository/packages/infrastructure/object-storage/MinIO/common"
+
+	cqrs "github.com/abaxoth0/Vega/libs/go/packages/CQRS"
+	"github.com/minio/minio-go/v7"
+)
+
+func (h *defaultQueryHandler) ListBuckets(query *FileApplication.ListBucketsQuery) ([]entity.Bucket, error) {
+	if !query.CommandQuery.IsInit() {
+		cqrs.InitDefaultCommandQuery(&query.CommandQuery)
+	}
+
+	ctx, cancel := context.WithTimeout(query.Context, query.ContextTimeout)
+	defer cancel()
+
+	return h.listBuckets(ctx)
+}
+
+func (h *defaultQueryHandler) listBuckets(ctx context.Context) ([]entity.Bucket, error) {
+	// MinIO returns buckets already sorted by names
+	buckets, err := storage.Client.ListBuckets(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]entity.Bucket, 0, len(buckets))
+	for _, bucket := range buckets {
+		if bucket.Name == MinIOCommon.BlobsBucket {
+			continue
+		}
+		result = append(result, entity.Bucket{
+			Name:         bucket.Name,
+			CreationDate: bucket.CreationDate,
+		})
+	}
+
+	return result, nil
+}
+
+// S3 API has no way to get bucket statistics, so all objects have to be listed.
+// Size of deduplicated files is the size of their content, even though it may be shared with other files.
+func (h *defaultQueryHandler) GetBucketInfo(query *FileApplication.GetBucketInfoQuery) (*entity.BucketInfo, error) {
+	if !query.CommandQuery.IsInit() {
+		cqrs.InitDefaultCommandQuery(&query.CommandQuery)
+	}
+
+	ctx, cancel := context.WithTimeout(query.Context, query.ContextTimeout)
+	defer cancel()
+
+	if err := MinIOCommon.IsBucketExist(ctx, query.Name); err != nil {
+		return nil, err
+	}
+
+	// Creation date is available only via listing of all buckets
+	buckets, err := h.listBuckets(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &entity.BucketInfo{}
+	for _, bucket := range buckets {
+		if bucket.Name == query.Name {
+			info.Bucket = bucket
+			break
+		}
+	}
+	// Bucket was deleted concurrently
+	if info.Name == "" {
+		return nil, MinIOCommon.ErrBucketDoesntExist
+	}
+
+	versioning, err := storage.Client.GetBucketVersioning(ctx, query.Name)
+	if err != nil {
+		return nil, err
+	}
+	info.VersioningEnabled = versioning.Enabled()
+
+	deduplicated, err := MinIOCommon.IsDeduplicationUsed(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for object := range storage.Client.ListObjects(ctx, query.Name, minio.ListObjectsOptions{
+		Recursive:    true,
+		WithMetadata: deduplicated,
+	}) {
+		if object.Err != nil {
+			return nil, object.Err
+		}
+		info.ObjectCount++
+		if ref := MinIOCommon.BlobReferenceFromMetadata(object.UserMetadata); ref != nil {
+			info.TotalSize += ref.Size
+		} else {
+			info.TotalSize += object.Size
+		}
+	}
+
+	return info, nil
+}
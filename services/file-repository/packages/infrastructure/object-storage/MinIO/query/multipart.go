@@ -0,0 +1,47 @@
+package minioquery
+
+import (
+	"context"
+	FileApplication "vega_file_repository/packages/application/file"
+	"vega_file_repository/packages/domain/entity"
+	MinIOCommon "vega_file_repository/packages/infrastructure/object-storage/MinIO/common"
+
+	"github.com/minio/minio-go/v7"
+)
+
+func (h *defaultQueryHandler) ListUploadedParts(query *FileApplication.ListUploadedPartsQuery) ([]entity.UploadPart, error) {
+	if err := h.preprocessQuery(&query.CommandQuery, query.Path); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(query.Context, query.ContextTimeout)
+	defer cancel()
+
+	core := MinIOCommon.Core()
+
+	parts := []entity.UploadPart{}
+	marker := 0
+	for {
+		result, err := core.ListObjectParts(ctx, query.Bucket, query.Path, query.UploadID, marker, 0)
+		if err != nil {
+			if MinIOCommon.IsErrorCode(err, minio.NoSuchUpload) {
+				return nil, FileApplication.ErrUploadDoesNotExist
+			}
+			return nil, err
+		}
+		for _, part := range result.ObjectParts {
+			parts = append(parts, entity.UploadPart{
+				PartNumber:   part.PartNumber,
+				ETag:         part.ETag,
+				Size:         part.Size,
+				LastModified: part.LastModified,
+			})
+		}
+		if !result.IsTruncated {
+			break
+		}
+		marker = result.NextPartNumberMarker
+	}
+
+	return parts, nil
+}
@@ -0,0 +1,87 @@
+package minioquery
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+	FileApplication "vega_file_repository/packages/application/file"
+	"vega_file_repository/packages/domain/entity"
+	MinIOCommon "vega_file_repository/packages/infrastructure/object-storage/MinIO/common"
+
+	cqrs "github.com/abaxoth0/Vega/libs/go/packages/CQRS"
+	errs "github.com/abaxoth0/Vega/libs/go/packages/erorrs"
+	"github.com/minio/minio-go/v7"
+)
+
+// Restrictions of the upload are signed as headers, so MinIO rejects uploads which are sent without them.
+// In deduplication mode content of the file is downloaded directly from its blob, but uploads
+// can't be presigned, since references must be created by the service.
+func (h *defaultQueryHandler) CreatePresignedURL(query *FileApplication.CreatePresignedURLQuery) (*entity.PresignedURL, error) {
+	if !query.CommandQuery.IsInit() {
+		cqrs.InitDefaultCommandQuery(&query.CommandQuery)
+	}
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+	if query.Method == http.MethodPut && MinIOCommon.DeduplicationEnabled {
+		return nil, FileApplication.ErrPresignNotSupported
+	}
+
+	ctx, cancel := context.WithTimeout(query.Context, query.ContextTimeout)
+	defer cancel()
+
+	if err := MinIOCommon.IsBucketExist(ctx, query.Bucket); err != nil {
+		return nil, err
+	}
+
+	bucket, key := query.Bucket, MinIOCommon.ObjectKey(query.Path)
+	expiresAt := time.Now().Add(query.Expiry)
+
+	var presigned *url.URL
+	headers := http.Header{}
+	if query.Method == http.MethodGet {
+		if MinIOCommon.DeduplicationEnabled {
+			stat, err := storage.Client.StatObject(ctx, bucket, key, minio.StatObjectOptions{})
+			if err != nil {
+				if MinIOCommon.IsErrorCode(err, minio.NoSuchKey) {
+					return nil, errs.StatusNotFound
+				}
+				return nil, err
+			}
+			if ref := MinIOCommon.BlobReferenceFromMetadata(stat.UserMetadata); ref != nil {
+				bucket, key = MinIOCommon.BlobsBucket, ref.Key
+			}
+		}
+		u, err := storage.Client.PresignedGetObject(ctx, bucket, key, query.Expiry, nil)
+		if err != nil {
+			return nil, err
+		}
+		presigned = u
+	} else {
+		if query.ContentType != "" {
+			headers.Set("Content-Type", query.ContentType)
+		}
+		if query.Size > 0 {
+			headers.Set("Content-Length", strconv.FormatInt(query.Size, 10))
+		}
+		u, err := storage.Client.PresignHeader(ctx, http.MethodPut, bucket, key, query.Expiry, nil, headers)
+		if err != nil {
+			return nil, err
+		}
+		presigned = u
+	}
+
+	result := &entity.PresignedURL{
+		URL:       presigned.String(),
+		Method:    query.Method,
+		ExpiresAt: expiresAt,
+		Headers:   make(map[string]string, len(headers)),
+	}
+	for name := range headers {
+		result.Headers[name] = headers.Get(name)
+	}
+
+	return result, nil
+}
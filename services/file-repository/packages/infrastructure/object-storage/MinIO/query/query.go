@@ -2,7 +2,8 @@ package minioquery
 
 import (
 	"context"
-	"errors"
+	"slices"
+	"strings"
 	FileApplication "vega_file_repository/packages/application/file"
 	"vega_file_repository/packages/domain/entity"
 	MinIOCommon "vega_file_repository/packages/infrastructure/object-storage/MinIO/common"
@@ -29,10 +30,6 @@ func (h *defaultQueryHandler) preprocessQuery(commandQuery *cqrs.CommandQuery, p
 	if err != nil {
 		return err
 	}
-	if file.IsDirectory(path) {
-		// TODO need to make archive with all files in directory and send it
-		return errors.New("Requested file is directory")
-	}
 
 	return nil
 }
@@ -41,24 +38,67 @@ func (h *defaultQueryHandler) GetFileByPath(query *FileApplication.GetFileByPath
 	if err := h.preprocessQuery(&query.CommandQuery, query.Path); err != nil {
 		return nil, err
 	}
+	if file.IsDirectory(query.Path) {
+		if query.ArchiveFormat == FileApplication.ArchiveFormatNone {
+			return nil, FileApplication.ErrArchiveFormatNotSpecified
+		}
+		if query.IsRanged() {
+			return nil, FileApplication.ErrRangeOfDirectory
+		}
+		if query.VersionID != "" {
+			return nil, FileApplication.ErrVersionOfDirectory
+		}
+		return h.getDirectoryArchive(query)
+	}
 
 	ctx, cancel := context.WithTimeout(query.Context, query.ContextTimeout)
 
 	if err := MinIOCommon.IsBucketExist(ctx, query.Bucket); err != nil {
+		cancel()
 		return nil, err
 	}
 
-	object, err := storage.Client.GetObject(ctx, query.Bucket, query.Path, minio.GetObjectOptions{})
+	// Size of the file is required to resolve the range, so it must be known before getting the object.
+	stat, err := storage.Client.StatObject(ctx, query.Bucket, query.Path, minio.StatObjectOptions{
+		VersionID: query.VersionID,
+	})
 	if err != nil {
-		if err, ok := err.(minio.ErrorResponse); ok {
-			if err.Code == minio.NoSuchKey {
-				return nil, errs.StatusNotFound
-			}
+		cancel()
+		if MinIOCommon.IsErrorCode(err, minio.NoSuchKey) || MinIOCommon.IsErrorCode(err, minio.NoSuchVersion) {
+			return nil, errs.StatusNotFound
 		}
 		return nil, err
 	}
-	stat, err := object.Stat()
+
+	bucket, key, size := query.Bucket, query.Path, stat.Size
+	opts := minio.GetObjectOptions{VersionID: query.VersionID}
+	// Content of the reference is stored in the blob. Blobs are immutable, so ETag check isn't needed.
+	if ref := MinIOCommon.BlobReferenceFromMetadata(stat.UserMetadata); ref != nil {
+		bucket, key, size = MinIOCommon.BlobsBucket, ref.Key, ref.Size
+		opts = minio.GetObjectOptions{}
+	} else if stat.ETag != "" {
+		// Object may be changed between stat and get, in that case range may become invalid
+		if err := opts.SetMatchETag(stat.ETag); err != nil {
+			cancel()
+			return nil, err
+		}
+	}
+
+	byteRange, err := query.ResolveRange(size)
 	if err != nil {
+		cancel()
+		return nil, err
+	}
+	if query.IsRanged() && byteRange.Length() > 0 {
+		if err := opts.SetRange(byteRange.Start, byteRange.End); err != nil {
+			cancel()
+			return nil, err
+		}
+	}
+
+	object, err := storage.Client.GetObject(ctx, bucket, key, opts)
+	if err != nil {
+		cancel()
 		if err, ok := err.(minio.ErrorResponse); ok {
 			if err.Code == minio.NoSuchKey {
 				return nil, errs.StatusNotFound
@@ -68,9 +108,118 @@ func (h *defaultQueryHandler) GetFileByPath(query *FileApplication.GetFileByPath
 	}
 
 	return &entity.FileStream{
-		Content: object,
-		Size:    stat.Size,
-		Context: ctx,
-		Cancel:  cancel,
+		Content:  object,
+		Size:     size,
+		Range:    byteRange,
+		Checksum: MinIOCommon.ChecksumFromMetadata(stat.UserMetadata),
+		Context:  ctx,
+		Cancel:   cancel,
 	}, nil
 }
+
+func (h *defaultQueryHandler) ListDirectory(query *FileApplication.ListDirectoryQuery) (*entity.DirectoryListing, error) {
+	if !query.CommandQuery.IsInit() {
+		cqrs.InitDefaultCommandQuery(&query.CommandQuery)
+	}
+	if err := file.ValidatePathFormat(query.Path); err != nil {
+		return nil, err
+	}
+	if !file.IsDirectory(query.Path) {
+		return nil, file.ErrFileIsNotDirectory
+	}
+
+	limit := query.Limit
+	if limit <= 0 {
+		limit = FileApplication.DefaultListDirectoryLimit
+	}
+	if limit > FileApplication.MaxListDirectoryLimit {
+		limit = FileApplication.MaxListDirectoryLimit
+	}
+
+	ctx, cancel := context.WithTimeout(query.Context, query.ContextTimeout)
+	defer cancel()
+
+	if err := MinIOCommon.IsBucketExist(ctx, query.Bucket); err != nil {
+		return nil, err
+	}
+
+	deduplicated, err := MinIOCommon.IsDeduplicationUsed(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Storage lists files of each page before its subdirectories, so entries are ordered only within a whole page.
+	// Hence exactly one page is read, the next object is received only to find out if there are more pages.
+	pageSize := min(limit+1, FileApplication.MaxListDirectoryLimit)
+
+	prefix := MinIOCommon.ObjectKey(query.Path)
+	// Directory which doesn't exist is listed as empty one (as in local storage),
+	// since in object storages directories are just prefixes of the keys.
+	opts := minio.ListObjectsOptions{
+		Prefix:       prefix,
+		Recursive:    query.Recursive,
+		WithMetadata: deduplicated,
+		MaxKeys:      pageSize,
+	}
+	// Continuation token is path of the last returned entry, the same as in local storage
+	if query.ContinuationToken != "" {
+		opts.StartAfter = MinIOCommon.ObjectKey(query.ContinuationToken)
+	}
+
+	// Listing is stopped once the page is received
+	listCtx, cancelListing := context.WithCancel(ctx)
+	defer cancelListing()
+
+	entries := make([]entity.FileInfo, 0, pageSize)
+	received, isTruncated := 0, false
+	for object := range storage.Client.ListObjects(listCtx, query.Bucket, opts) {
+		if object.Err != nil {
+			return nil, object.Err
+		}
+		if received == pageSize {
+			isTruncated = true
+			break
+		}
+		received++
+		// Marker of the listed directory itself (created by Mkdir)
+		if object.Key == prefix {
+			continue
+		}
+		path := MinIOCommon.ObjectPath(object.Key)
+		size := object.Size
+		if ref := MinIOCommon.BlobReferenceFromMetadata(object.UserMetadata); ref != nil {
+			size = ref.Size
+		}
+		// Subdirectories (common prefixes) have only the key
+		entries = append(entries, entity.FileInfo{
+			Path:         path,
+			Size:         size,
+			StoredSize:   object.Size,
+			LastModified: object.LastModified,
+			ETag:         object.ETag,
+			IsDirectory:  file.IsDirectory(path),
+		})
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(entries, func(a, b entity.FileInfo) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+
+	listing := &entity.DirectoryListing{
+		Entries:     entries,
+		IsTruncated: isTruncated,
+	}
+	// Page has one entry more than requested, unless it contains marker of the directory
+	if len(entries) > limit {
+		listing.Entries = entries[:limit]
+		listing.IsTruncated = true
+	}
+	if listing.IsTruncated {
+		listing.NextContinuationToken = listing.Entries[len(listing.Entries)-1].Path
+	}
+
+	return listing, nil
+}
@@ -0,0 +1,132 @@
+package minioquery
+
+import (
+	"context"
+	"strings"
+	"sync"
+	FileApplication "vega_file_repository/packages/application/file"
+	"vega_file_repository/packages/domain/entity"
+	MinIOCommon "vega_file_repository/packages/infrastructure/object-storage/MinIO/common"
+
+	cqrs "github.com/abaxoth0/Vega/libs/go/packages/CQRS"
+	"github.com/abaxoth0/Vega/libs/go/packages/file"
+	"github.com/minio/minio-go/v7"
+)
+
+// Each path requires separate request, so they are statted concurrently
+const statConcurrency = 16
+
+func (h *defaultQueryHandler) StatFile(query *FileApplication.StatFileQuery) ([]entity.FileStat, error) {
+	if !query.CommandQuery.IsInit() {
+		cqrs.InitDefaultCommandQuery(&query.CommandQuery)
+	}
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(query.Context, query.ContextTimeout)
+	defer cancel()
+
+	if err := MinIOCommon.IsBucketExist(ctx, query.Bucket); err != nil {
+		return nil, err
+	}
+
+	stats := make([]entity.FileStat, len(query.Paths))
+	failures := make([]error, len(query.Paths))
+
+	semaphore := make(chan struct{}, statConcurrency)
+	var wg sync.WaitGroup
+	for i, path := range query.Paths {
+		semaphore <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-semaphore
+				wg.Done()
+			}()
+			var stat *entity.FileStat
+			if file.IsDirectory(path) {
+				stat, failures[i] = statDirectory(ctx, query.Bucket, path)
+			} else {
+				stat, failures[i] = statFile(ctx, query.Bucket, path, query.VersionID)
+			}
+			if stat != nil {
+				stats[i] = *stat
+			}
+		}()
+	}
+	wg.Wait()
+
+	for _, err := range failures {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return stats, nil
+}
+
+func statFile(ctx context.Context, bucket string, path string, versionID string) (*entity.FileStat, error) {
+	stat := &entity.FileStat{Path: path}
+
+	object, err := storage.Client.StatObject(ctx, bucket, MinIOCommon.ObjectKey(path), minio.StatObjectOptions{
+		VersionID: versionID,
+	})
+	if err != nil {
+		if MinIOCommon.IsErrorCode(err, minio.NoSuchKey) || MinIOCommon.IsErrorCode(err, minio.NoSuchVersion) {
+			return stat, nil
+		}
+		return nil, err
+	}
+
+	stat.Exists = true
+	stat.Size = object.Size
+	stat.StoredSize = object.Size
+	stat.LastModified = object.LastModified
+	stat.ETag = strings.Trim(object.ETag, "\"")
+	stat.ContentType = object.ContentType
+	stat.Metadata = MinIOCommon.ClientMetadata(object.UserMetadata)
+	stat.VersionID = object.VersionID
+	stat.Checksum = MinIOCommon.ChecksumFromMetadata(object.UserMetadata)
+	// Content of the reference is stored in the blob
+	if ref := MinIOCommon.BlobReferenceFromMetadata(object.UserMetadata); ref != nil {
+		stat.Size = ref.Size
+	}
+
+	return stat, nil
+}
+
+// Directories may exist without markers, so if there is no marker, then directory exists only if it has any entries
+func statDirectory(ctx context.Context, bucket string, path string) (*entity.FileStat, error) {
+	stat := &entity.FileStat{Path: path, IsDirectory: true}
+
+	key := MinIOCommon.ObjectKey(path)
+	// Root of the bucket always exists
+	if key == "" {
+		stat.Exists = true
+		return stat, nil
+	}
+
+	marker, err := storage.Client.StatObject(ctx, bucket, key, minio.StatObjectOptions{})
+	if err == nil {
+		stat.Exists = true
+		stat.LastModified = marker.LastModified
+		return stat, nil
+	}
+	if !MinIOCommon.IsErrorCode(err, minio.NoSuchKey) {
+		return nil, err
+	}
+
+	for object := range storage.Client.ListObjects(ctx, bucket, minio.ListObjectsOptions{
+		Prefix:  key,
+		MaxKeys: 1,
+	}) {
+		if object.Err != nil {
+			return nil, object.Err
+		}
+		stat.Exists = true
+		break
+	}
+
+	return stat, nil
+}
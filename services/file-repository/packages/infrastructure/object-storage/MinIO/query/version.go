@@ -0,0 +1,71 @@
+package minioquery
+
+import (
+	"context"
+	"strings"
+	FileApplication "vega_file_repository/packages/application/file"
+	"vega_file_repository/packages/domain/entity"
+	MinIOCommon "vega_file_repository/packages/infrastructure/object-storage/MinIO/common"
+
+	errs "github.com/abaxoth0/Vega/libs/go/packages/erorrs"
+	"github.com/abaxoth0/Vega/libs/go/packages/file"
+	"github.com/minio/minio-go/v7"
+)
+
+func (h *defaultQueryHandler) ListFileVersions(query *FileApplication.ListFileVersionsQuery) ([]entity.FileVersion, error) {
+	if err := h.preprocessQuery(&query.CommandQuery, query.Path); err != nil {
+		return nil, err
+	}
+	if file.IsDirectory(query.Path) {
+		return nil, FileApplication.ErrVersionOfDirectory
+	}
+
+	ctx, cancel := context.WithTimeout(query.Context, query.ContextTimeout)
+	defer cancel()
+
+	if err := MinIOCommon.IsBucketExist(ctx, query.Bucket); err != nil {
+		return nil, err
+	}
+
+	deduplicated, err := MinIOCommon.IsDeduplicationUsed(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	key := MinIOCommon.ObjectKey(query.Path)
+	versions := []entity.FileVersion{}
+
+	// Versions can be listed only by prefix, so versions of other files with the same prefix must be skipped.
+	// Versions of each object are returned from the newest to the oldest one.
+	for object := range storage.Client.ListObjects(ctx, query.Bucket, minio.ListObjectsOptions{
+		Prefix:       key,
+		Recursive:    true,
+		WithVersions: true,
+		WithMetadata: deduplicated,
+	}) {
+		if object.Err != nil {
+			return nil, object.Err
+		}
+		if object.Key != key {
+			continue
+		}
+		size := object.Size
+		if ref := MinIOCommon.BlobReferenceFromMetadata(object.UserMetadata); ref != nil {
+			size = ref.Size
+		}
+		versions = append(versions, entity.FileVersion{
+			VersionID:      object.VersionID,
+			Size:           size,
+			LastModified:   object.LastModified,
+			ETag:           strings.Trim(object.ETag, "\""),
+			IsLatest:       object.IsLatest,
+			IsDeleteMarker: object.IsDeleteMarker,
+		})
+	}
+
+	if len(versions) == 0 {
+		return nil, errs.StatusNotFound
+	}
+
+	return versions, nil
+}
@@ -0,0 +1,439 @@
+// Transparent compression of files, which doesn't rely on the storage itself.
+package compression
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	FileApplication "vega_file_repository/packages/application/file"
+	"vega_file_repository/packages/domain/entity"
+	objectstorage "vega_file_repository/packages/infrastructure/object-storage"
+	"vega_file_repository/packages/infrastructure/object-storage/layer"
+
+	cqrs "github.com/abaxoth0/Vega/libs/go/packages/CQRS"
+	"github.com/abaxoth0/Vega/libs/go/packages/file"
+)
+
+// Smaller files are stored as is, since compression can't save enough space to be worth it
+const minCompressedSize int64 = 512
+
+// Max amount of headers which are read concurrently while listing is resolved
+const headerReadConcurrency = 8
+
+var ErrContentSizeMismatch = errors.New("size of the content doesn't match declared one")
+
+// Wraps another driver: content of the files uploaded into buckets with compression policy is compressed
+// before it's passed to the wrapped driver and decompressed when it's read. Content which is already
+// compressed (detected by the extension and the first bytes of the file) is stored as is,
+// same as content which doesn't become smaller after compression.
+//
+// Compressed files are self-describing, so they are readable in any bucket (e.g. after relocation,
+// or if policy of the bucket was changed), but each read of the file requires additional request
+// to read its header. Listings of the buckets with compression policy report original sizes of the files,
+// but it also requires to read header of each listed file. Size occupied in the storage is reported as
+// FileInfo.StoredSize, bucket info is provided by the wrapped driver as is.
+// Checksums of the compressed files aren't stored, so they are unknown on download.
+//
+// Content is compressed into temporary file before it's uploaded, since its compressed size
+// must be known beforehand. Parts of resumable uploads are uploaded independently, so they aren't compressed.
+type Driver struct {
+	objectstorage.ObjectStorageDriver
+	policy *Policy
+}
+
+func NewDriver(driver objectstorage.ObjectStorageDriver, policy *Policy) *Driver {
+	return &Driver{
+		ObjectStorageDriver: driver,
+		policy:              policy,
+	}
+}
+
+func (d *Driver) IsCompressed(bucket string) bool {
+	return d.policy.Algorithm(bucket) != AlgorithmNone
+}
+
+func (d *Driver) GetFileByPath(query *FileApplication.GetFileByPathQuery) (*entity.FileStream, error) {
+	if file.IsDirectory(query.Path) {
+		// Archive built by the wrapped driver would consist of compressed files
+		if err := layer.ValidateArchiveQuery(query); err != nil {
+			return nil, err
+		}
+		return layer.GetDirectoryArchive(d, query)
+	}
+
+	h, err := d.readHeader(query.Bucket, query.Path, query.VersionID, query.CommandQuery)
+	if err != nil {
+		return nil, err
+	}
+	// File was stored as is
+	if h == nil {
+		return d.ObjectStorageDriver.GetFileByPath(query)
+	}
+
+	byteRange, err := query.ResolveRange(h.size)
+	if err != nil {
+		return nil, err
+	}
+
+	// Compressed content can't be read from the middle,
+	// so it's always read from the start and bytes before the range are skipped
+	stream, err := d.ObjectStorageDriver.GetFileByPath(&FileApplication.GetFileByPathQuery{
+		Bucket:       query.Bucket,
+		Path:         query.Path,
+		Offset:       headerSize,
+		VersionID:    query.VersionID,
+		CommandQuery: query.CommandQuery,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	decompressor, err := newDecompressor(h.algorithm, stream.Content)
+	if err != nil {
+		stream.Cancel()
+		return nil, err
+	}
+	content := &decompressingReader{decompressor: decompressor, remaining: h.size}
+	cancel := func() {
+		stream.Cancel()
+		content.Close()
+	}
+
+	if _, err := io.CopyN(io.Discard, content, byteRange.Start); err != nil {
+		cancel()
+		return nil, err
+	}
+
+	var limited io.Reader = content
+	// Whole content is read as is, so it's verified that it ends exactly where header says it does
+	if byteRange.Length() < h.size {
+		limited = io.LimitReader(content, byteRange.Length())
+	}
+
+	return &entity.FileStream{
+		Content: limited,
+		Size:    h.size,
+		Range:   byteRange,
+		Context: stream.Context,
+		Cancel:  cancel,
+	}, nil
+}
+
+// Returns header of the file, nil if file isn't compressed
+func (d *Driver) readHeader(
+	bucket string,
+	path string,
+	versionID string,
+	commandQuery cqrs.CommandQuery,
+) (*header, error) {
+	stream, err := d.ObjectStorageDriver.GetFileByPath(&FileApplication.GetFileByPathQuery{
+		Bucket:       bucket,
+		Path:         path,
+		Length:       headerSize,
+		VersionID:    versionID,
+		CommandQuery: commandQuery,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer stream.Cancel()
+
+	data, err := io.ReadAll(stream.Content)
+	if err != nil {
+		return nil, err
+	}
+
+	h, ok := decodeHeader(data)
+	if !ok {
+		return nil, nil
+	}
+	return h, nil
+}
+
+// Calls resolve for each index in [0, n) concurrently and waits for all of them
+func resolveConcurrently(n int, resolve func(i int)) {
+	semaphore := make(chan struct{}, headerReadConcurrency)
+	var wg sync.WaitGroup
+	for i := range n {
+		semaphore <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-semaphore
+				wg.Done()
+			}()
+			resolve(i)
+		}()
+	}
+	wg.Wait()
+}
+
+func (d *Driver) ListDirectory(query *FileApplication.ListDirectoryQuery) (*entity.DirectoryListing, error) {
+	listing, err := d.ObjectStorageDriver.ListDirectory(query)
+	if err != nil || !d.IsCompressed(query.Bucket) {
+		return listing, err
+	}
+
+	resolveConcurrently(len(listing.Entries), func(i int) {
+		entry := &listing.Entries[i]
+		if entry.IsDirectory || entry.Size < headerSize {
+			return
+		}
+		// Entry keeps its size from the wrapped driver if header can't be read
+		h, err := d.readHeader(query.Bucket, entry.Path, "", query.CommandQuery)
+		if err == nil && h != nil {
+			entry.Size = h.size
+		}
+	})
+
+	return listing, nil
+}
+
+func (d *Driver) ListFileVersions(query *FileApplication.ListFileVersionsQuery) ([]entity.FileVersion, error) {
+	versions, err := d.ObjectStorageDriver.ListFileVersions(query)
+	if err != nil || !d.IsCompressed(query.Bucket) {
+		return versions, err
+	}
+
+	resolveConcurrently(len(versions), func(i int) {
+		version := &versions[i]
+		if version.IsDeleteMarker || version.Size < headerSize {
+			return
+		}
+		h, err := d.readHeader(query.Bucket, query.Path, version.VersionID, query.CommandQuery)
+		if err == nil && h != nil {
+			version.Size = h.size
+		}
+	})
+
+	return versions, nil
+}
+
+func (d *Driver) StatFile(query *FileApplication.StatFileQuery) ([]entity.FileStat, error) {
+	stats, err := d.ObjectStorageDriver.StatFile(query)
+	if err != nil || !d.IsCompressed(query.Bucket) {
+		return stats, err
+	}
+
+	resolveConcurrently(len(stats), func(i int) {
+		stat := &stats[i]
+		if !stat.Exists || stat.IsDirectory || stat.Size < headerSize {
+			return
+		}
+		h, err := d.readHeader(query.Bucket, stat.Path, query.VersionID, query.CommandQuery)
+		if err == nil && h != nil {
+			stat.Size = h.size
+		}
+	})
+
+	return stats, nil
+}
+
+// Files in compressed buckets may be compressed, so they are accessible only through this driver.
+// Files in other buckets can be downloaded directly only if they aren't compressed (e.g. after relocation).
+func (d *Driver) CreatePresignedURL(query *FileApplication.CreatePresignedURLQuery) (*entity.PresignedURL, error) {
+	if d.IsCompressed(query.Bucket) {
+		return nil, FileApplication.ErrPresignNotSupported
+	}
+	if query.Method == http.MethodGet {
+		// Errors are left for the wrapped driver to report
+		h, err := d.readHeader(query.Bucket, query.Path, "", query.CommandQuery)
+		if err == nil && h != nil {
+			return nil, FileApplication.ErrPresignNotSupported
+		}
+	}
+	return d.ObjectStorageDriver.CreatePresignedURL(query)
+}
+
+func (d *Driver) UploadFile(cmd *FileApplication.UploadFileCommand) (*entity.UploadResult, error) {
+	// Invalid commands are left for the wrapped driver to reject
+	if cmd.ContentSize <= 0 {
+		return d.ObjectStorageDriver.UploadFile(cmd)
+	}
+	if cmd.ArchiveFormat != FileApplication.ArchiveFormatNone {
+		if !file.IsDirectory(cmd.Path) {
+			return nil, file.ErrFileIsNotDirectory
+		}
+		if cmd.Checksum != nil {
+			return nil, FileApplication.ErrChecksumOfArchive
+		}
+		if cmd.Preconditions.IsSet() {
+			return nil, FileApplication.ErrPreconditionsOfArchive
+		}
+		// Entries are extracted here instead of the wrapped driver, so each of them is compressed separately
+		return layer.UploadArchive(d.ObjectStorageDriver, cmd, func(path string, content io.Reader, size int64) error {
+			_, err := d.uploadFile(cmd.Bucket, path, content, size, nil, FileApplication.WritePreconditions{}, cmd.CommandQuery)
+			return err
+		})
+	}
+	if file.IsDirectory(cmd.Path) {
+		return d.ObjectStorageDriver.UploadFile(cmd)
+	}
+
+	return d.uploadFile(cmd.Bucket, cmd.Path, cmd.Content, cmd.ContentSize, cmd.Checksum, cmd.Preconditions, cmd.CommandQuery)
+}
+
+func (d *Driver) uploadFile(
+	bucket string,
+	path string,
+	content io.Reader,
+	size int64,
+	expected *entity.Checksum,
+	preconditions FileApplication.WritePreconditions,
+	commandQuery cqrs.CommandQuery,
+) (*entity.UploadResult, error) {
+	return d.writeCompressed(bucket, path, content, size, expected,
+		func(content io.Reader, size int64, checksum *entity.Checksum) (*entity.UploadResult, error) {
+			return d.ObjectStorageDriver.UploadFile(&FileApplication.UploadFileCommand{
+				Bucket:        bucket,
+				Path:          path,
+				Content:       content,
+				ContentSize:   size,
+				Checksum:      checksum,
+				Preconditions: preconditions,
+				CommandQuery:  commandQuery,
+			})
+		},
+	)
+}
+
+func (d *Driver) UpdateFileContent(cmd *FileApplication.UpdateFileContentCommand) (*entity.UploadResult, error) {
+	if cmd.Size <= 0 || file.IsDirectory(cmd.Path) {
+		return d.ObjectStorageDriver.UpdateFileContent(cmd)
+	}
+
+	return d.writeCompressed(cmd.Bucket, cmd.Path, cmd.NewContent, cmd.Size, cmd.Checksum,
+		func(content io.Reader, size int64, checksum *entity.Checksum) (*entity.UploadResult, error) {
+			return d.ObjectStorageDriver.UpdateFileContent(&FileApplication.UpdateFileContentCommand{
+				Bucket:        cmd.Bucket,
+				Path:          cmd.Path,
+				NewContent:    content,
+				Size:          size,
+				Checksum:      checksum,
+				Preconditions: cmd.Preconditions,
+				CommandQuery:  cmd.CommandQuery,
+			})
+		},
+	)
+}
+
+// Passes content to write either as is or compressed. In the latter case checksum can be verified only
+// before compression, so wrapped driver receives no expected checksum and returned checksum is replaced
+// by the checksum of the original content.
+func (d *Driver) writeCompressed(
+	bucket string,
+	path string,
+	content io.Reader,
+	size int64,
+	expected *entity.Checksum,
+	write func(content io.Reader, size int64, checksum *entity.Checksum) (*entity.UploadResult, error),
+) (*entity.UploadResult, error) {
+	head := make([]byte, min(size, sniffSize))
+	n, err := io.ReadFull(content, head)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return nil, err
+	}
+	truncated := n < len(head)
+	head = head[:n]
+	content = io.MultiReader(bytes.NewReader(head), content)
+
+	algorithm := d.policy.Algorithm(bucket)
+	// Content which looks like compressed file must be compressed regardless of the policy,
+	// otherwise it would be decompressed when it's read
+	_, mustCompress := decodeHeader(head)
+	if mustCompress && algorithm == AlgorithmNone {
+		algorithm = AlgorithmGzip
+	}
+	// Content which is shorter than declared is left for the wrapped driver to reject
+	if !mustCompress && (algorithm == AlgorithmNone || size < minCompressedSize ||
+		truncated || isCompressedContent(path, head)) {
+		return write(content, size, expected)
+	}
+
+	checksumReader, err := FileApplication.NewChecksumReader(content, size, expected)
+	if err != nil {
+		return nil, err
+	}
+
+	spool, err := os.CreateTemp("", "vega-compression-*")
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		spool.Close()
+		os.Remove(spool.Name())
+	}()
+
+	storedSize, err := compressInto(spool, algorithm, checksumReader, size)
+	if err != nil {
+		return nil, checksumReader.ResolveError(err)
+	}
+	checksum := checksumReader.Checksum()
+
+	if storedSize >= size && !mustCompress {
+		// Compression didn't save any space, so original content is restored from the compressed one
+		// (checksum is already verified, but it's passed to the wrapped driver, so it's stored along with the file)
+		if _, err := spool.Seek(headerSize, io.SeekStart); err != nil {
+			return nil, err
+		}
+		decompressor, err := newDecompressor(algorithm, spool)
+		if err != nil {
+			return nil, err
+		}
+		defer decompressor.Close()
+		return write(&decompressingReader{decompressor: decompressor, remaining: size}, size, checksum)
+	}
+
+	if _, err := spool.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	result, err := write(spool, storedSize, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	result.Checksum = checksum
+	return result, nil
+}
+
+// Writes header and compressed content into w, returns amount of written bytes
+func compressInto(w io.Writer, algorithm Algorithm, content io.Reader, size int64) (int64, error) {
+	counter := &countingWriter{w: w}
+
+	h := header{algorithm: algorithm, size: size}
+	if _, err := counter.Write(h.encode()); err != nil {
+		return 0, err
+	}
+
+	compressor, err := newCompressor(algorithm, counter)
+	if err != nil {
+		return 0, err
+	}
+	// Storages reject content which is bigger than declared, so it's read till the end
+	n, err := io.Copy(compressor, content)
+	if err != nil {
+		return 0, err
+	}
+	if n != size {
+		return 0, ErrContentSizeMismatch
+	}
+	if err := compressor.Close(); err != nil {
+		return 0, err
+	}
+
+	return counter.n, nil
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	w.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,346 @@
+package compression
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/rand"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	FileApplication "vega_file_repository/packages/application/file"
+	"vega_file_repository/packages/domain/entity"
+	objectstorage "vega_file_repository/packages/infrastructure/object-storage"
+	StorageConnection "vega_file_repository/packages/infrastructure/object-storage/connection"
+	"vega_file_repository/packages/infrastructure/object-storage/local"
+)
+
+func read(driver objectstorage.ObjectStorageDriver, query *FileApplication.GetFileByPathQuery) ([]byte, *entity.FileStream, error) {
+	stream, err := driver.GetFileByPath(query)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer stream.Cancel()
+	data, err := io.ReadAll(stream.Content)
+	return data, stream, err
+}
+
+func upload(t *testing.T, driver objectstorage.ObjectStorageDriver, bucket string, path string, content []byte) *entity.UploadResult {
+	result, err := driver.UploadFile(&FileApplication.UploadFileCommand{
+		Bucket:      bucket,
+		Path:        path,
+		Content:     bytes.NewReader(content),
+		ContentSize: int64(len(content)),
+	})
+	if err != nil {
+		t.Fatalf("Failed to upload %s: %v", path, err)
+	}
+	return result
+}
+
+func TestCompressionDriver(t *testing.T) {
+	storage := local.InitDriver()
+	if err := storage.Connect(&StorageConnection.Config{URL: t.TempDir()}); err != nil {
+		t.Fatalf("Connection failed: %v", err)
+	}
+	defer storage.Disconnect()
+
+	policy, err := ParsePolicy("logs:gzip,exports:zstd")
+	if err != nil {
+		t.Fatalf("Failed to parse policy: %v", err)
+	}
+	driver := NewDriver(storage, policy)
+
+	for _, bucket := range []string{"logs", "exports", "plain"} {
+		if err := driver.MakeBucket(&FileApplication.MakeBucketCommand{Name: bucket}); err != nil {
+			t.Fatalf("Failed to create bucket: %v", err)
+		}
+	}
+
+	content := []byte(strings.Repeat("Well, Prince, so Genoa and Lucca are now just family estates of the Buonapartes.\n", 2000))
+
+	t.Run("Upload and download", func(t *testing.T) {
+		for _, bucket := range []string{"logs", "exports"} {
+			result := upload(t, driver, bucket, "/book.txt", content)
+			if result.Checksum == nil {
+				t.Errorf("Checksum of the content is missing")
+			}
+
+			stored, _, err := read(storage, &FileApplication.GetFileByPathQuery{Bucket: bucket, Path: "/book.txt"})
+			if err != nil {
+				t.Fatalf("Failed to read stored file: %v", err)
+			}
+			if len(stored) >= len(content)/10 {
+				t.Errorf("Stored file in bucket \"%s\" isn't compressed: %d bytes", bucket, len(stored))
+			}
+
+			data, stream, err := read(driver, &FileApplication.GetFileByPathQuery{Bucket: bucket, Path: "/book.txt"})
+			if err != nil {
+				t.Fatalf("Failed to read file: %v", err)
+			}
+			if !bytes.Equal(data, content) || stream.Size != int64(len(content)) {
+				t.Errorf("Decompressed content in bucket \"%s\" doesn't match original one", bucket)
+			}
+
+			listing, err := driver.ListDirectory(&FileApplication.ListDirectoryQuery{Bucket: bucket, Path: "/"})
+			if err != nil {
+				t.Fatalf("Failed to list bucket: %v", err)
+			}
+			if len(listing.Entries) != 1 ||
+				listing.Entries[0].Size != int64(len(content)) ||
+				listing.Entries[0].StoredSize != int64(len(stored)) {
+				t.Errorf("Listing must report both original and stored sizes, but got: %+v", listing.Entries)
+			}
+
+			stats, err := driver.StatFile(&FileApplication.StatFileQuery{Bucket: bucket, Paths: []string{"/book.txt"}})
+			if err != nil {
+				t.Fatalf("Failed to stat file: %v", err)
+			}
+			if stats[0].Size != int64(len(content)) || stats[0].StoredSize != int64(len(stored)) {
+				t.Errorf("Stat must report both original and stored sizes, but got: %+v", stats[0])
+			}
+		}
+	})
+
+	t.Run("Range", func(t *testing.T) {
+		ranges := []struct {
+			offset int64
+			length int64
+		}{
+			{offset: 0, length: 10},
+			{offset: 1000, length: 5000},
+			{offset: int64(len(content)) - 1, length: 0},
+			{offset: 100, length: int64(len(content)) * 2},
+		}
+		for _, r := range ranges {
+			data, stream, err := read(driver, &FileApplication.GetFileByPathQuery{
+				Bucket: "exports",
+				Path:   "/book.txt",
+				Offset: r.offset,
+				Length: r.length,
+			})
+			if err != nil {
+				t.Fatalf("Failed to read range %+v: %v", r, err)
+			}
+			end := int64(len(content))
+			if r.length > 0 {
+				end = min(end, r.offset+r.length)
+			}
+			if !bytes.Equal(data, content[r.offset:end]) || stream.Range.Start != r.offset || stream.Range.End != end-1 {
+				t.Errorf("Invalid content of range %+v: %d bytes, %+v", r, len(data), stream.Range)
+			}
+		}
+
+		_, err := driver.GetFileByPath(&FileApplication.GetFileByPathQuery{
+			Bucket: "exports",
+			Path:   "/book.txt",
+			Offset: int64(len(content)),
+		})
+		if !errors.Is(err, FileApplication.ErrRangeNotSatisfiable) {
+			t.Errorf("Expected unsatisfiable range, but got: %v", err)
+		}
+	})
+
+	t.Run("Already compressed content", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		writer := gzip.NewWriter(buf)
+		writer.Write(content)
+		writer.Close()
+
+		random := make([]byte, 4096)
+		if _, err := rand.Read(random); err != nil {
+			t.Fatalf("Failed to generate content: %v", err)
+		}
+
+		files := map[string][]byte{
+			// Detected by content
+			"/book.txt.gz": buf.Bytes(),
+			// Detected by extension
+			"/photo.jpg": content,
+			// Doesn't become smaller after compression
+			"/random.bin": random,
+			// Too small to be compressed
+			"/small.txt": content[:100],
+		}
+		for path, data := range files {
+			upload(t, driver, "logs", path, data)
+			stored, _, err := read(storage, &FileApplication.GetFileByPathQuery{Bucket: "logs", Path: path})
+			if err != nil {
+				t.Fatalf("Failed to read stored file: %v", err)
+			}
+			if !bytes.Equal(stored, data) {
+				t.Errorf("File %s must be stored as is", path)
+			}
+			if got, _, err := read(driver, &FileApplication.GetFileByPathQuery{Bucket: "logs", Path: path}); err != nil || !bytes.Equal(got, data) {
+				t.Errorf("Invalid content of %s (%v)", path, err)
+			}
+		}
+	})
+
+	t.Run("Checksum", func(t *testing.T) {
+		_, err := driver.UpdateFileContent(&FileApplication.UpdateFileContentCommand{
+			Bucket:     "logs",
+			Path:       "/book.txt",
+			NewContent: bytes.NewReader(content),
+			Size:       int64(len(content)),
+			Checksum: &entity.Checksum{
+				Algorithm: FileApplication.ChecksumSHA256,
+				Value:     "0000000000000000000000000000000000000000000000000000000000000000",
+			},
+		})
+		if !errors.Is(err, FileApplication.ErrChecksumMismatch) {
+			t.Errorf("Expected checksum mismatch, but got: %v", err)
+		}
+
+		_, err = driver.UpdateFileContent(&FileApplication.UpdateFileContentCommand{
+			Bucket:     "logs",
+			Path:       "/book.txt",
+			NewContent: bytes.NewReader(content),
+			Size:       int64(len(content)) - 1,
+		})
+		if !errors.Is(err, ErrContentSizeMismatch) {
+			t.Errorf("Expected content size mismatch, but got: %v", err)
+		}
+
+		data, _, err := read(driver, &FileApplication.GetFileByPathQuery{Bucket: "logs", Path: "/book.txt"})
+		if err != nil || !bytes.Equal(data, content) {
+			t.Errorf("Rejected update must not change the file (%v)", err)
+		}
+	})
+
+	t.Run("Uncompressed bucket", func(t *testing.T) {
+		upload(t, driver, "plain", "/book.txt", content)
+		stored, _, err := read(storage, &FileApplication.GetFileByPathQuery{Bucket: "plain", Path: "/book.txt"})
+		if err != nil || !bytes.Equal(stored, content) {
+			t.Errorf("File in uncompressed bucket must be stored as is (%v)", err)
+		}
+
+		// Content which looks like compressed file is compressed anyway, otherwise it would be decompressed on read
+		fake := append((&header{algorithm: AlgorithmGzip, size: 3}).encode(), "abc"...)
+		upload(t, driver, "plain", "/fake.bin", fake)
+		data, _, err := read(driver, &FileApplication.GetFileByPathQuery{Bucket: "plain", Path: "/fake.bin"})
+		if err != nil || !bytes.Equal(data, fake) {
+			t.Errorf("Invalid content of the file which looks like compressed one: %q (%v)", data, err)
+		}
+
+		// Compressed files are readable after relocation into uncompressed bucket
+		_, err = driver.CopyFiles(&FileApplication.CopyFilesCommand{
+			FilesRelocation: FileApplication.FilesRelocation{
+				SourceBucket:      "logs",
+				DestinationBucket: "plain",
+				SourcePath:        "/book.txt",
+				DestinationPath:   "/copy.txt",
+			},
+		})
+		if err != nil {
+			t.Fatalf("Failed to copy file: %v", err)
+		}
+		data, _, err = read(driver, &FileApplication.GetFileByPathQuery{Bucket: "plain", Path: "/copy.txt"})
+		if err != nil || !bytes.Equal(data, content) {
+			t.Errorf("Relocated compressed file must be readable (%v)", err)
+		}
+	})
+
+	t.Run("Files stored before compression was enabled", func(t *testing.T) {
+		upload(t, storage, "logs", "/legacy.txt", content)
+		data, _, err := read(driver, &FileApplication.GetFileByPathQuery{Bucket: "logs", Path: "/legacy.txt"})
+		if err != nil || !bytes.Equal(data, content) {
+			t.Errorf("Uncompressed file must be readable as is (%v)", err)
+		}
+	})
+
+	t.Run("Corrupted file", func(t *testing.T) {
+		stored, _, err := read(storage, &FileApplication.GetFileByPathQuery{Bucket: "exports", Path: "/book.txt"})
+		if err != nil {
+			t.Fatalf("Failed to read stored file: %v", err)
+		}
+		truncated := stored[:len(stored)-10]
+		upload(t, storage, "exports", "/truncated.txt", truncated)
+
+		_, _, err = read(driver, &FileApplication.GetFileByPathQuery{Bucket: "exports", Path: "/truncated.txt"})
+		if err == nil {
+			t.Errorf("Truncated file must not be read successfully")
+		}
+	})
+
+	t.Run("Archive", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		writer := tar.NewWriter(buf)
+		writer.WriteHeader(&tar.Header{Name: "docs/", Typeflag: tar.TypeDir})
+		writer.WriteHeader(&tar.Header{Name: "docs/book.txt", Typeflag: tar.TypeReg, Size: int64(len(content))})
+		writer.Write(content)
+		if err := writer.Close(); err != nil {
+			t.Fatalf("Failed to create test archive: %v", err)
+		}
+
+		_, err := driver.UploadFile(&FileApplication.UploadFileCommand{
+			Bucket:        "logs",
+			Path:          "/archive/",
+			Content:       buf,
+			ContentSize:   int64(buf.Len()),
+			ArchiveFormat: FileApplication.ArchiveFormatTar,
+		})
+		if err != nil {
+			t.Fatalf("Failed to upload archive: %v", err)
+		}
+
+		stored, _, err := read(storage, &FileApplication.GetFileByPathQuery{Bucket: "logs", Path: "/archive/docs/book.txt"})
+		if err != nil || len(stored) >= len(content) {
+			t.Errorf("Extracted file isn't compressed (%v)", err)
+		}
+
+		data, _, err := read(driver, &FileApplication.GetFileByPathQuery{
+			Bucket:        "logs",
+			Path:          "/archive/",
+			ArchiveFormat: FileApplication.ArchiveFormatTar,
+		})
+		if err != nil {
+			t.Fatalf("Failed to download archive: %v", err)
+		}
+		reader := tar.NewReader(bytes.NewReader(data))
+		found := false
+		for {
+			header, err := reader.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatalf("Failed to read archive: %v", err)
+			}
+			if header.Name == "archive/docs/book.txt" {
+				found = true
+				if archived, _ := io.ReadAll(reader); !bytes.Equal(archived, content) {
+					t.Errorf("Archived file isn't decompressed")
+				}
+			}
+		}
+		if !found {
+			t.Errorf("Archived file is missing")
+		}
+	})
+}
+
+func TestParsePolicy(t *testing.T) {
+	invalid := []string{"", "logs", "logs:lz4", " , "}
+	for _, policy := range invalid {
+		if _, err := ParsePolicy(policy); err == nil {
+			t.Errorf("Policy %q must be rejected", policy)
+		}
+	}
+
+	policy, err := ParsePolicy("*:gzip, logs:zstd, media:none")
+	if err != nil {
+		t.Fatalf("Failed to parse policy: %v", err)
+	}
+	expected := map[string]Algorithm{
+		"logs":  AlgorithmZstd,
+		"media": AlgorithmNone,
+		"other": AlgorithmGzip,
+	}
+	for bucket, algorithm := range expected {
+		if policy.Algorithm(bucket) != algorithm {
+			t.Errorf("Expected %s for bucket \"%s\", but got %s", algorithm, bucket, policy.Algorithm(bucket))
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package compression
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"io"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Compressed file consists of the fixed size header followed by the compressed content:
+//
+//	magic (4) | version (1) | algorithm (1) | size of the original content (8) | compressed content
+//
+// Header is stored uncompressed, so original size can be read without decompressing the file.
+const (
+	formatVersion byte  = 1
+	headerSize    int64 = int64(len(magic) + 1 + 1 + 8)
+)
+
+var magic = [4]byte{'V', 'C', 'M', 'P'}
+
+var ErrCorruptedFile = errors.New("compressed file is corrupted")
+
+type header struct {
+	algorithm Algorithm
+	size      int64
+}
+
+func (h *header) encode() []byte {
+	buf := make([]byte, 0, headerSize)
+	buf = append(buf, magic[:]...)
+	buf = append(buf, formatVersion, byte(h.algorithm))
+	buf = binary.BigEndian.AppendUint64(buf, uint64(h.size))
+	return buf
+}
+
+// Returns false if data isn't header of the compressed file (e.g. file was stored before compression was enabled)
+func decodeHeader(data []byte) (*header, bool) {
+	if int64(len(data)) < headerSize || !bytes.Equal(data[:len(magic)], magic[:]) {
+		return nil, false
+	}
+	data = data[len(magic):]
+
+	version, algorithm := data[0], Algorithm(data[1])
+	if version != formatVersion || (algorithm != AlgorithmGzip && algorithm != AlgorithmZstd) {
+		return nil, false
+	}
+	size := binary.BigEndian.Uint64(data[2:])
+	if int64(size) < 0 {
+		return nil, false
+	}
+
+	return &header{algorithm: algorithm, size: int64(size)}, true
+}
+
+func newCompressor(algorithm Algorithm, w io.Writer) (io.WriteCloser, error) {
+	switch algorithm {
+	case AlgorithmGzip:
+		return gzip.NewWriter(w), nil
+	case AlgorithmZstd:
+		return zstd.NewWriter(w)
+	}
+	return nil, errors.New("compression algorithm isn't specified")
+}
+
+func newDecompressor(algorithm Algorithm, r io.Reader) (io.ReadCloser, error) {
+	switch algorithm {
+	case AlgorithmGzip:
+		return gzip.NewReader(r)
+	case AlgorithmZstd:
+		decoder, err := zstd.NewReader(r, zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			return nil, err
+		}
+		return decoder.IOReadCloser(), nil
+	}
+	return nil, ErrCorruptedFile
+}
+
+// Returns exactly size bytes of the decompressed content, otherwise fails with ErrCorruptedFile.
+type decompressingReader struct {
+	decompressor io.ReadCloser
+	remaining    int64
+}
+
+func (r *decompressingReader) Read(p []byte) (int, error) {
+	if r.remaining <= 0 {
+		// Content must end exactly where header says it does
+		var extra [1]byte
+		if n, _ := io.ReadFull(r.decompressor, extra[:]); n > 0 {
+			return 0, ErrCorruptedFile
+		}
+		return 0, io.EOF
+	}
+	if int64(len(p)) > r.remaining {
+		p = p[:r.remaining]
+	}
+
+	n, err := r.decompressor.Read(p)
+	r.remaining -= int64(n)
+
+	if err == io.EOF {
+		if r.remaining > 0 {
+			return n, ErrCorruptedFile
+		}
+		err = nil
+	}
+	return n, err
+}
+
+func (r *decompressingReader) Close() error {
+	return r.decompressor.Close()
+}
@@ -0,0 +1,87 @@
+package compression
+
+import (
+	"bytes"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+)
+
+// Amount of bytes used to detect type of the content, same as http.DetectContentType considers
+const sniffSize = 512
+
+// Types of the content which is already compressed, so compressing it again only wastes CPU.
+// Types which end with "/" or "." match all types with such prefix.
+var compressedTypes = []string{
+	"video/",
+	"image/jpeg",
+	"image/png",
+	"image/gif",
+	"image/webp",
+	"image/avif",
+	"image/heic",
+	"audio/mpeg",
+	"audio/aac",
+	"audio/mp4",
+	"audio/ogg",
+	"audio/webm",
+	"audio/flac",
+	"font/woff",
+	"font/woff2",
+	"application/zip",
+	"application/gzip",
+	"application/x-gzip",
+	"application/zstd",
+	"application/x-bzip2",
+	"application/x-xz",
+	"application/x-7z-compressed",
+	"application/x-rar-compressed",
+	"application/vnd.rar",
+	"application/pdf",
+	"application/epub+zip",
+	"application/java-archive",
+	"application/vnd.openxmlformats-officedocument.",
+	"application/vnd.oasis.opendocument.",
+}
+
+// Signatures of compressed formats which http.DetectContentType doesn't recognize
+var compressedSignatures = [][]byte{
+	{0x28, 0xB5, 0x2F, 0xFD},           // zstd
+	{0xFD, '7', 'z', 'X', 'Z', 0x00},   // xz
+	{'7', 'z', 0xBC, 0xAF, 0x27, 0x1C}, // 7z
+	{'B', 'Z', 'h'},                    // bzip2
+}
+
+// Type of the file is detected both by its extension and by the first bytes of its content (see sniffSize).
+func isCompressedContent(filePath string, head []byte) bool {
+	for _, signature := range compressedSignatures {
+		if bytes.HasPrefix(head, signature) {
+			return true
+		}
+	}
+	return isCompressedType(mime.TypeByExtension(path.Ext(filePath))) ||
+		isCompressedType(http.DetectContentType(head))
+}
+
+func isCompressedType(contentType string) bool {
+	if contentType == "" {
+		return false
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	for _, compressed := range compressedTypes {
+		if strings.HasSuffix(compressed, "/") || strings.HasSuffix(compressed, ".") {
+			if strings.HasPrefix(mediaType, compressed) {
+				return true
+			}
+			continue
+		}
+		if mediaType == compressed {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,88 @@
+package compression
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+type Algorithm byte
+
+// Values are stored in headers of compressed files, so they must never be changed
+const (
+	AlgorithmNone Algorithm = 0
+	AlgorithmGzip Algorithm = 1
+	AlgorithmZstd Algorithm = 2
+)
+
+var ErrEmptyPolicy = errors.New("compression policy must contain at least one bucket")
+
+func ParseAlgorithm(name string) (Algorithm, error) {
+	switch strings.ToLower(name) {
+	case "gzip":
+		return AlgorithmGzip, nil
+	case "zstd":
+		return AlgorithmZstd, nil
+	case "none":
+		return AlgorithmNone, nil
+	}
+	return AlgorithmNone, fmt.Errorf("unknown compression algorithm \"%s\", expected \"gzip\", \"zstd\" or \"none\"", name)
+}
+
+func (a Algorithm) String() string {
+	switch a {
+	case AlgorithmGzip:
+		return "gzip"
+	case AlgorithmZstd:
+		return "zstd"
+	}
+	return "none"
+}
+
+// Compression algorithm of each bucket
+type Policy struct {
+	buckets map[string]Algorithm
+	// Algorithm of the buckets which aren't listed explicitly
+	fallback Algorithm
+}
+
+// Parses policy in format "bucket1:algorithm1,bucket2:algorithm2,...", where algorithm is "gzip", "zstd" or "none".
+// Bucket "*" sets algorithm of all buckets which aren't listed explicitly (they aren't compressed by default).
+func ParsePolicy(policy string) (*Policy, error) {
+	p := &Policy{buckets: make(map[string]Algorithm)}
+
+	empty := true
+	for _, entry := range strings.Split(policy, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		bucket, name, ok := strings.Cut(entry, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid compression policy entry, expected \"bucket:algorithm\", but got \"%s\"", entry)
+		}
+		algorithm, err := ParseAlgorithm(strings.TrimSpace(name))
+		if err != nil {
+			return nil, err
+		}
+		if bucket = strings.TrimSpace(bucket); bucket == "*" {
+			p.fallback = algorithm
+		} else {
+			p.buckets[bucket] = algorithm
+		}
+		empty = false
+	}
+
+	if empty {
+		return nil, ErrEmptyPolicy
+	}
+
+	return p, nil
+}
+
+func (p *Policy) Algorithm(bucket string) Algorithm {
+	if algorithm, ok := p.buckets[bucket]; ok {
+		return algorithm
+	}
+	return p.fallback
+}
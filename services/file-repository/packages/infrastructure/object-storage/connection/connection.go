@@ -28,6 +28,9 @@ type Config struct {
 	Password string
 	Token    string
 	Secure   bool
+	// PEM bundle of CAs which are trusted instead of the system ones, requires Secure.
+	// Bundle is reloaded once it's modified, so CAs can be rotated without reconnection.
+	CAFile string
 }
 
 type Manager interface {
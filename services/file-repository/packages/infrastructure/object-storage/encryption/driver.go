@@ -0,0 +1,342 @@
+// Transparent encryption of files at rest, which doesn't rely on the storage itself.
+package encryption
+
+import (
+	"errors"
+	"io"
+	FileApplication "vega_file_repository/packages/application/file"
+	"vega_file_repository/packages/domain/entity"
+	objectstorage "vega_file_repository/packages/infrastructure/object-storage"
+	"vega_file_repository/packages/infrastructure/object-storage/layer"
+
+	cqrs "github.com/abaxoth0/Vega/libs/go/packages/CQRS"
+	"github.com/abaxoth0/Vega/libs/go/packages/file"
+)
+
+var (
+	ErrResumableUploadOfEncryptedFile = errors.New("resumable uploads aren't supported in encrypted buckets")
+	ErrEncryptionMismatch             = errors.New("files can't be relocated between encrypted and unencrypted buckets")
+	ErrUnencryptedFile                = errors.New("file in encrypted bucket isn't encrypted")
+)
+
+// Wraps another driver: content of the files in encrypted buckets is encrypted before it's passed
+// to the wrapped driver and decrypted when it's read, so storage never receives it in plain form.
+// Each file is encrypted by its own data key, which is stored in the file itself wrapped by the master key.
+//
+// Files which aren't encrypted (e.g. stored before encryption was enabled for the bucket) can't be read,
+// unless it's explicitly allowed (see AllowUnencryptedFiles).
+// Bucket info is provided by the wrapped driver as is, so it reports size occupied in the storage.
+// Checksums of the content aren't stored, so they are unknown on download.
+type Driver struct {
+	objectstorage.ObjectStorageDriver
+	keyring *Keyring
+	// nil if all buckets are encrypted
+	buckets          map[string]bool
+	allowUnencrypted bool
+}
+
+// If buckets is empty, then all buckets are encrypted
+func NewDriver(driver objectstorage.ObjectStorageDriver, keyring *Keyring, buckets []string) *Driver {
+	d := &Driver{
+		ObjectStorageDriver: driver,
+		keyring:             keyring,
+	}
+	if len(buckets) > 0 {
+		d.buckets = make(map[string]bool, len(buckets))
+		for _, bucket := range buckets {
+			d.buckets[bucket] = true
+		}
+	}
+	return d
+}
+
+// Lets files which aren't encrypted be read from encrypted buckets as is, so files stored before
+// encryption was enabled stay available until they are rewritten. Such files aren't authenticated,
+// so anyone who can write into the storage can substitute their content, hence it's meant only for migration.
+// Listings can't distinguish such files from encrypted ones, so their sizes may be reported incorrectly.
+func (d *Driver) AllowUnencryptedFiles() {
+	d.allowUnencrypted = true
+}
+
+func (d *Driver) IsEncrypted(bucket string) bool {
+	return d.buckets == nil || d.buckets[bucket]
+}
+
+func (d *Driver) GetFileByPath(query *FileApplication.GetFileByPathQuery) (*entity.FileStream, error) {
+	if !d.IsEncrypted(query.Bucket) {
+		return d.ObjectStorageDriver.GetFileByPath(query)
+	}
+	if file.IsDirectory(query.Path) {
+		// Archive built by the wrapped driver would consist of encrypted files
+		if err := layer.ValidateArchiveQuery(query); err != nil {
+			return nil, err
+		}
+		return layer.GetDirectoryArchive(d, query)
+	}
+
+	h, storedSize, err := d.readHeader(query.Bucket, query.Path, query.VersionID, query.CommandQuery)
+	if err != nil {
+		return nil, err
+	}
+	if h == nil {
+		if !d.allowUnencrypted {
+			return nil, ErrUnencryptedFile
+		}
+		return d.ObjectStorageDriver.GetFileByPath(query)
+	}
+
+	size, err := contentSize(storedSize)
+	if err != nil {
+		return nil, err
+	}
+	byteRange, err := query.ResolveRange(size)
+	if err != nil {
+		return nil, err
+	}
+	offset, length := encryptedRange(byteRange)
+
+	stream, err := d.ObjectStorageDriver.GetFileByPath(&FileApplication.GetFileByPathQuery{
+		Bucket:       query.Bucket,
+		Path:         query.Path,
+		Offset:       offset,
+		Length:       length,
+		VersionID:    query.VersionID,
+		CommandQuery: query.CommandQuery,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := newDecryptingReader(d.keyring, h, stream.Content, size, byteRange)
+	if err != nil {
+		stream.Cancel()
+		return nil, err
+	}
+
+	return &entity.FileStream{
+		Content: content,
+		Size:    size,
+		Range:   byteRange,
+		Context: stream.Context,
+		Cancel:  stream.Cancel,
+	}, nil
+}
+
+// Returns header of the file (nil if file isn't encrypted) and size of the stored file
+func (d *Driver) readHeader(
+	bucket string,
+	path string,
+	versionID string,
+	commandQuery cqrs.CommandQuery,
+) (*header, int64, error) {
+	stream, err := d.ObjectStorageDriver.GetFileByPath(&FileApplication.GetFileByPathQuery{
+		Bucket:       bucket,
+		Path:         path,
+		Length:       headerSize,
+		VersionID:    versionID,
+		CommandQuery: commandQuery,
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	defer stream.Cancel()
+
+	data, err := io.ReadAll(stream.Content)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	h, ok := decodeHeader(data)
+	if !ok {
+		return nil, stream.Size, nil
+	}
+	return h, stream.Size, nil
+}
+
+func (d *Driver) ListDirectory(query *FileApplication.ListDirectoryQuery) (*entity.DirectoryListing, error) {
+	listing, err := d.ObjectStorageDriver.ListDirectory(query)
+	if err != nil || !d.IsEncrypted(query.Bucket) {
+		return listing, err
+	}
+
+	for i, entry := range listing.Entries {
+		if entry.IsDirectory {
+			continue
+		}
+		if size, err := contentSize(entry.Size); err == nil {
+			listing.Entries[i].Size = size
+		}
+	}
+
+	return listing, nil
+}
+
+func (d *Driver) ListFileVersions(query *FileApplication.ListFileVersionsQuery) ([]entity.FileVersion, error) {
+	versions, err := d.ObjectStorageDriver.ListFileVersions(query)
+	if err != nil || !d.IsEncrypted(query.Bucket) {
+		return versions, err
+	}
+
+	for i, version := range versions {
+		if version.IsDeleteMarker {
+			continue
+		}
+		if size, err := contentSize(version.Size); err == nil {
+			versions[i].Size = size
+		}
+	}
+
+	return versions, nil
+}
+
+func (d *Driver) StatFile(query *FileApplication.StatFileQuery) ([]entity.FileStat, error) {
+	stats, err := d.ObjectStorageDriver.StatFile(query)
+	if err != nil || !d.IsEncrypted(query.Bucket) {
+		return stats, err
+	}
+
+	for i, stat := range stats {
+		if !stat.Exists || stat.IsDirectory {
+			continue
+		}
+		if size, err := contentSize(stat.Size); err == nil {
+			stats[i].Size = size
+		}
+	}
+
+	return stats, nil
+}
+
+func (d *Driver) UploadFile(cmd *FileApplication.UploadFileCommand) (*entity.UploadResult, error) {
+	// Invalid commands are left for the wrapped driver to reject
+	if !d.IsEncrypted(cmd.Bucket) || cmd.ContentSize <= 0 {
+		return d.ObjectStorageDriver.UploadFile(cmd)
+	}
+	if cmd.ArchiveFormat != FileApplication.ArchiveFormatNone {
+		if !file.IsDirectory(cmd.Path) {
+			return nil, file.ErrFileIsNotDirectory
+		}
+		if cmd.Checksum != nil {
+			return nil, FileApplication.ErrChecksumOfArchive
+		}
+		if cmd.Preconditions.IsSet() {
+			return nil, FileApplication.ErrPreconditionsOfArchive
+		}
+		// Entries are extracted here instead of the wrapped driver, so each of them is encrypted separately
+		return layer.UploadArchive(d.ObjectStorageDriver, cmd, func(path string, content io.Reader, size int64) error {
+			_, err := d.uploadFile(cmd.Bucket, path, content, size, nil, FileApplication.WritePreconditions{}, cmd.CommandQuery)
+			return err
+		})
+	}
+	if file.IsDirectory(cmd.Path) {
+		return d.ObjectStorageDriver.UploadFile(cmd)
+	}
+
+	return d.uploadFile(cmd.Bucket, cmd.Path, cmd.Content, cmd.ContentSize, cmd.Checksum, cmd.Preconditions, cmd.CommandQuery)
+}
+
+func (d *Driver) uploadFile(
+	bucket string,
+	path string,
+	content io.Reader,
+	size int64,
+	expected *entity.Checksum,
+	preconditions FileApplication.WritePreconditions,
+	commandQuery cqrs.CommandQuery,
+) (*entity.UploadResult, error) {
+	return d.writeEncrypted(content, size, expected, func(encrypted io.Reader, encryptedSize int64) (*entity.UploadResult, error) {
+		return d.ObjectStorageDriver.UploadFile(&FileApplication.UploadFileCommand{
+			Bucket:        bucket,
+			Path:          path,
+			Content:       encrypted,
+			ContentSize:   encryptedSize,
+			Preconditions: preconditions,
+			CommandQuery:  commandQuery,
+		})
+	})
+}
+
+func (d *Driver) UpdateFileContent(cmd *FileApplication.UpdateFileContentCommand) (*entity.UploadResult, error) {
+	if !d.IsEncrypted(cmd.Bucket) || cmd.Size < 0 || file.IsDirectory(cmd.Path) {
+		return d.ObjectStorageDriver.UpdateFileContent(cmd)
+	}
+
+	return d.writeEncrypted(cmd.NewContent, cmd.Size, cmd.Checksum, func(encrypted io.Reader, encryptedSize int64) (*entity.UploadResult, error) {
+		return d.ObjectStorageDriver.UpdateFileContent(&FileApplication.UpdateFileContentCommand{
+			Bucket:        cmd.Bucket,
+			Path:          cmd.Path,
+			NewContent:    encrypted,
+			Size:          encryptedSize,
+			Preconditions: cmd.Preconditions,
+			CommandQuery:  cmd.CommandQuery,
+		})
+	})
+}
+
+// Checksum can be verified only before encryption, so wrapped driver receives no expected checksum
+// and returned checksum is replaced by the checksum of the original content.
+func (d *Driver) writeEncrypted(
+	content io.Reader,
+	size int64,
+	expected *entity.Checksum,
+	write func(encrypted io.Reader, encryptedSize int64) (*entity.UploadResult, error),
+) (*entity.UploadResult, error) {
+	checksumReader, err := FileApplication.NewChecksumReader(content, size, expected)
+	if err != nil {
+		return nil, err
+	}
+	encrypted, err := newEncryptingReader(d.keyring, checksumReader, size)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := write(encrypted, encryptedSize(size))
+	if err != nil {
+		return nil, checksumReader.ResolveError(err)
+	}
+
+	result.Checksum = checksumReader.Checksum()
+	return result, nil
+}
+
+func (d *Driver) CopyFiles(cmd *FileApplication.CopyFilesCommand) (*entity.RelocationReport, error) {
+	if err := d.checkRelocation(&cmd.FilesRelocation); err != nil {
+		return nil, err
+	}
+	return d.ObjectStorageDriver.CopyFiles(cmd)
+}
+
+func (d *Driver) MoveFiles(cmd *FileApplication.MoveFilesCommand) (*entity.RelocationReport, error) {
+	if err := d.checkRelocation(&cmd.FilesRelocation); err != nil {
+		return nil, err
+	}
+	return d.ObjectStorageDriver.MoveFiles(cmd)
+}
+
+// Files are relocated by the wrapped driver as is, so they can't be moved across encryption boundary
+func (d *Driver) checkRelocation(relocation *FileApplication.FilesRelocation) error {
+	destination := relocation.DestinationBucket
+	if destination == "" {
+		destination = relocation.SourceBucket
+	}
+	if d.IsEncrypted(relocation.SourceBucket) != d.IsEncrypted(destination) {
+		return ErrEncryptionMismatch
+	}
+	return nil
+}
+
+// Content of the files in encrypted buckets is accessible only through this driver
+func (d *Driver) CreatePresignedURL(query *FileApplication.CreatePresignedURLQuery) (*entity.PresignedURL, error) {
+	if d.IsEncrypted(query.Bucket) {
+		return nil, FileApplication.ErrPresignNotSupported
+	}
+	return d.ObjectStorageDriver.CreatePresignedURL(query)
+}
+
+// Parts are uploaded independently, so they can't be encrypted as chunks of the same file
+func (d *Driver) InitiateUpload(cmd *FileApplication.InitiateUploadCommand) (string, error) {
+	if d.IsEncrypted(cmd.Bucket) {
+		return "", ErrResumableUploadOfEncryptedFile
+	}
+	return d.ObjectStorageDriver.InitiateUpload(cmd)
+}
@@ -0,0 +1,412 @@
+package encryption
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"io"
+	"testing"
+	FileApplication "vega_file_repository/packages/application/file"
+	"vega_file_repository/packages/domain/entity"
+	objectstorage "vega_file_repository/packages/infrastructure/object-storage"
+	StorageConnection "vega_file_repository/packages/infrastructure/object-storage/connection"
+	"vega_file_repository/packages/infrastructure/object-storage/local"
+)
+
+func newMasterKey(t *testing.T, id string) string {
+	key := make([]byte, keySize)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatalf("Failed to generate master key: %v", err)
+	}
+	return id + ":" + base64.StdEncoding.EncodeToString(key)
+}
+
+func newKeyring(t *testing.T, keys string) *Keyring {
+	keyring, err := ParseKeyring(keys)
+	if err != nil {
+		t.Fatalf("Failed to parse keyring: %v", err)
+	}
+	return keyring
+}
+
+func read(driver objectstorage.ObjectStorageDriver, query *FileApplication.GetFileByPathQuery) ([]byte, *entity.FileStream, error) {
+	stream, err := driver.GetFileByPath(query)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer stream.Cancel()
+	data, err := io.ReadAll(stream.Content)
+	return data, stream, err
+}
+
+func TestEncryptionDriver(t *testing.T) {
+	storage := local.InitDriver()
+	if err := storage.Connect(&StorageConnection.Config{URL: t.TempDir()}); err != nil {
+		t.Fatalf("Connection failed: %v", err)
+	}
+	defer storage.Disconnect()
+
+	oldKey := newMasterKey(t, "old")
+	driver := NewDriver(storage, newKeyring(t, oldKey), []string{"encrypted"})
+
+	for _, bucket := range []string{"encrypted", "plain"} {
+		if err := driver.MakeBucket(&FileApplication.MakeBucketCommand{Name: bucket}); err != nil {
+			t.Fatalf("Failed to create bucket: %v", err)
+		}
+	}
+
+	// Spans several chunks and doesn't end on the chunk boundary
+	content := make([]byte, chunkSize*2+1000)
+	if _, err := rand.Read(content); err != nil {
+		t.Fatalf("Failed to generate content: %v", err)
+	}
+
+	t.Run("Upload and download", func(t *testing.T) {
+		result, err := driver.UploadFile(&FileApplication.UploadFileCommand{
+			Bucket:      "encrypted",
+			Path:        "/file.bin",
+			Content:     bytes.NewReader(content),
+			ContentSize: int64(len(content)),
+		})
+		if err != nil {
+			t.Fatalf("Failed to upload file: %v", err)
+		}
+		if result.Checksum == nil {
+			t.Errorf("Checksum of the content is missing")
+		}
+
+		stored, _, err := read(storage, &FileApplication.GetFileByPathQuery{Bucket: "encrypted", Path: "/file.bin"})
+		if err != nil {
+			t.Fatalf("Failed to read stored file: %v", err)
+		}
+		if int64(len(stored)) != encryptedSize(int64(len(content))) || bytes.Contains(stored, content[:1024]) {
+			t.Errorf("Stored file isn't encrypted")
+		}
+
+		data, stream, err := read(driver, &FileApplication.GetFileByPathQuery{Bucket: "encrypted", Path: "/file.bin"})
+		if err != nil {
+			t.Fatalf("Failed to read file: %v", err)
+		}
+		if !bytes.Equal(data, content) || stream.Size != int64(len(content)) {
+			t.Errorf("Decrypted content doesn't match original one")
+		}
+
+		listing, err := driver.ListDirectory(&FileApplication.ListDirectoryQuery{Bucket: "encrypted", Path: "/"})
+		if err != nil {
+			t.Fatalf("Failed to list bucket: %v", err)
+		}
+		if len(listing.Entries) != 1 || listing.Entries[0].Size != int64(len(content)) {
+			t.Errorf("Listing must report size of the content, but got: %+v", listing.Entries)
+		}
+	})
+
+	t.Run("Range", func(t *testing.T) {
+		ranges := []struct {
+			offset int64
+			length int64
+		}{
+			{offset: 0, length: 10},
+			{offset: chunkSize - 5, length: 10},
+			{offset: chunkSize, length: chunkSize},
+			{offset: chunkSize*2 + 999, length: 0},
+			{offset: 100, length: chunkSize * 3},
+		}
+		for _, r := range ranges {
+			data, stream, err := read(driver, &FileApplication.GetFileByPathQuery{
+				Bucket: "encrypted",
+				Path:   "/file.bin",
+				Offset: r.offset,
+				Length: r.length,
+			})
+			if err != nil {
+				t.Fatalf("Failed to read range %d+%d: %v", r.offset, r.length, err)
+			}
+			end := int64(len(content))
+			if r.length > 0 {
+				end = min(end, r.offset+r.length)
+			}
+			if !bytes.Equal(data, content[r.offset:end]) {
+				t.Errorf("Invalid content of range %d+%d", r.offset, r.length)
+			}
+			if stream.Range.Start != r.offset || stream.Range.End != end-1 {
+				t.Errorf("Invalid range %d+%d: %+v", r.offset, r.length, stream.Range)
+			}
+		}
+
+		_, err := driver.GetFileByPath(&FileApplication.GetFileByPathQuery{
+			Bucket: "encrypted",
+			Path:   "/file.bin",
+			Offset: int64(len(content)),
+		})
+		if !errors.Is(err, FileApplication.ErrRangeNotSatisfiable) {
+			t.Errorf("Expected unsatisfiable range, but got: %v", err)
+		}
+	})
+
+	t.Run("Empty file and checksum", func(t *testing.T) {
+		_, err := driver.UpdateFileContent(&FileApplication.UpdateFileContentCommand{
+			Bucket:     "encrypted",
+			Path:       "/empty.txt",
+			NewContent: bytes.NewReader(nil),
+		})
+		if err != nil {
+			t.Fatalf("Failed to write empty file: %v", err)
+		}
+		data, _, err := read(driver, &FileApplication.GetFileByPathQuery{Bucket: "encrypted", Path: "/empty.txt"})
+		if err != nil || len(data) != 0 {
+			t.Errorf("Invalid empty file: %q (%v)", data, err)
+		}
+
+		_, err = driver.UpdateFileContent(&FileApplication.UpdateFileContentCommand{
+			Bucket:     "encrypted",
+			Path:       "/empty.txt",
+			NewContent: bytes.NewReader([]byte("abc")),
+			Size:       3,
+			Checksum: &entity.Checksum{
+				Algorithm: FileApplication.ChecksumSHA256,
+				Value:     "0000000000000000000000000000000000000000000000000000000000000000",
+			},
+		})
+		if !errors.Is(err, FileApplication.ErrChecksumMismatch) {
+			t.Errorf("Expected checksum mismatch, but got: %v", err)
+		}
+	})
+
+	t.Run("Tampered file", func(t *testing.T) {
+		stored, _, err := read(storage, &FileApplication.GetFileByPathQuery{Bucket: "encrypted", Path: "/file.bin"})
+		if err != nil {
+			t.Fatalf("Failed to read stored file: %v", err)
+		}
+		stored[headerSize+encryptedChunkSize+10] ^= 1
+		_, err = storage.UploadFile(&FileApplication.UploadFileCommand{
+			Bucket:      "encrypted",
+			Path:        "/tampered.bin",
+			Content:     bytes.NewReader(stored),
+			ContentSize: int64(len(stored)),
+		})
+		if err != nil {
+			t.Fatalf("Failed to store tampered file: %v", err)
+		}
+
+		if _, _, err := read(driver, &FileApplication.GetFileByPathQuery{
+			Bucket: "encrypted",
+			Path:   "/tampered.bin",
+		}); !errors.Is(err, ErrCorruptedFile) {
+			t.Errorf("Expected corrupted file, but got: %v", err)
+		}
+		// Chunks which weren't tampered are still readable
+		data, _, err := read(driver, &FileApplication.GetFileByPathQuery{
+			Bucket: "encrypted",
+			Path:   "/tampered.bin",
+			Length: 100,
+		})
+		if err != nil || !bytes.Equal(data, content[:100]) {
+			t.Errorf("Failed to read untouched chunk: %v", err)
+		}
+	})
+
+	t.Run("Unencrypted files", func(t *testing.T) {
+		plain := []byte("plain content")
+		for _, bucket := range []string{"encrypted", "plain"} {
+			_, err := storage.UploadFile(&FileApplication.UploadFileCommand{
+				Bucket:      bucket,
+				Path:        "/plain.txt",
+				Content:     bytes.NewReader(plain),
+				ContentSize: int64(len(plain)),
+			})
+			if err != nil {
+				t.Fatalf("Failed to upload file: %v", err)
+			}
+		}
+
+		data, _, err := read(driver, &FileApplication.GetFileByPathQuery{Bucket: "plain", Path: "/plain.txt"})
+		if err != nil || !bytes.Equal(data, plain) {
+			t.Errorf("File in unencrypted bucket must be returned as is: %q (%v)", data, err)
+		}
+		if _, err := driver.GetFileByPath(&FileApplication.GetFileByPathQuery{Bucket: "encrypted", Path: "/plain.txt"}); !errors.Is(err, ErrUnencryptedFile) {
+			t.Errorf("Unencrypted file in encrypted bucket must be rejected, but got: %v", err)
+		}
+
+		migrating := NewDriver(storage, newKeyring(t, oldKey), []string{"encrypted"})
+		migrating.AllowUnencryptedFiles()
+		data, _, err = read(migrating, &FileApplication.GetFileByPathQuery{Bucket: "encrypted", Path: "/plain.txt"})
+		if err != nil || !bytes.Equal(data, plain) {
+			t.Errorf("Unencrypted file in encrypted bucket must be returned as is if it's allowed: %q (%v)", data, err)
+		}
+
+		_, err = driver.CopyFiles(&FileApplication.CopyFilesCommand{
+			FilesRelocation: FileApplication.FilesRelocation{
+				SourceBucket:      "encrypted",
+				SourcePath:        "/file.bin",
+				DestinationBucket: "plain",
+				DestinationPath:   "/file.bin",
+			},
+		})
+		if !errors.Is(err, ErrEncryptionMismatch) {
+			t.Errorf("Expected encryption mismatch, but got: %v", err)
+		}
+
+		_, err = driver.InitiateUpload(&FileApplication.InitiateUploadCommand{
+			Bucket: "encrypted",
+			Path:   "/resumable.bin",
+		})
+		if !errors.Is(err, ErrResumableUploadOfEncryptedFile) {
+			t.Errorf("Resumable upload into encrypted bucket must be rejected, but got: %v", err)
+		}
+	})
+
+	t.Run("Archive", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		writer := tar.NewWriter(buf)
+		writer.WriteHeader(&tar.Header{Name: "docs/", Typeflag: tar.TypeDir})
+		writer.WriteHeader(&tar.Header{Name: "docs/readme.txt", Typeflag: tar.TypeReg, Size: 6})
+		writer.Write([]byte("readme"))
+		if err := writer.Close(); err != nil {
+			t.Fatalf("Failed to create test archive: %v", err)
+		}
+
+		_, err := driver.UploadFile(&FileApplication.UploadFileCommand{
+			Bucket:        "encrypted",
+			Path:          "/archive/",
+			Content:       buf,
+			ContentSize:   int64(buf.Len()),
+			ArchiveFormat: FileApplication.ArchiveFormatTar,
+		})
+		if err != nil {
+			t.Fatalf("Failed to upload archive: %v", err)
+		}
+
+		stored, _, err := read(storage, &FileApplication.GetFileByPathQuery{Bucket: "encrypted", Path: "/archive/docs/readme.txt"})
+		if err != nil || bytes.Contains(stored, []byte("readme")) {
+			t.Errorf("Extracted file isn't encrypted (%v)", err)
+		}
+
+		data, _, err := read(driver, &FileApplication.GetFileByPathQuery{
+			Bucket:        "encrypted",
+			Path:          "/archive/",
+			ArchiveFormat: FileApplication.ArchiveFormatTar,
+		})
+		if err != nil {
+			t.Fatalf("Failed to download archive: %v", err)
+		}
+		reader := tar.NewReader(bytes.NewReader(data))
+		found := false
+		for {
+			header, err := reader.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatalf("Failed to read archive: %v", err)
+			}
+			if header.Name == "archive/docs/readme.txt" {
+				found = true
+				if content, _ := io.ReadAll(reader); string(content) != "readme" {
+					t.Errorf("Archived file isn't decrypted: %q", content)
+				}
+			}
+		}
+		if !found {
+			t.Errorf("Archived file is missing")
+		}
+	})
+
+	t.Run("Key rotation", func(t *testing.T) {
+		newKey := newMasterKey(t, "new")
+		rotating := NewDriver(storage, newKeyring(t, newKey+","+oldKey), []string{"encrypted"})
+
+		rotated, err := rotating.RotateKeys(context.Background(), "encrypted")
+		if err != nil {
+			t.Fatalf("Failed to rotate keys: %v", err)
+		}
+		// file.bin, tampered.bin, empty.txt and readme.txt, but not unencrypted plain.txt
+		if rotated != 4 {
+			t.Errorf("Expected 4 rewrapped files, but got %d", rotated)
+		}
+		if rotated, err := rotating.RotateKeys(context.Background(), "encrypted"); err != nil || rotated != 0 {
+			t.Errorf("Files must not be rewrapped twice, but got %d (%v)", rotated, err)
+		}
+
+		// Old key isn't needed anymore
+		rotated2 := NewDriver(storage, newKeyring(t, newKey), []string{"encrypted"})
+		data, _, err := read(rotated2, &FileApplication.GetFileByPathQuery{Bucket: "encrypted", Path: "/file.bin"})
+		if err != nil || !bytes.Equal(data, content) {
+			t.Errorf("Failed to read rewrapped file: %v", err)
+		}
+
+		_, err = driver.GetFileByPath(&FileApplication.GetFileByPathQuery{Bucket: "encrypted", Path: "/file.bin"})
+		if !errors.Is(err, ErrUnknownMasterKey) {
+			t.Errorf("Expected unknown master key, but got: %v", err)
+		}
+
+		// File is updated after rotation has read it, but before it's rewritten
+		newestKey := newMasterKey(t, "newest")
+		keyring := newKeyring(t, newestKey+","+newKey)
+		writer := NewDriver(storage, keyring, []string{"encrypted"})
+		racing := &racingStorage{
+			ObjectStorageDriver: storage,
+			path:                "/file.bin",
+			write: func() {
+				_, err := writer.UploadFile(&FileApplication.UploadFileCommand{
+					Bucket:      "encrypted",
+					Path:        "/file.bin",
+					Content:     bytes.NewReader([]byte("updated content")),
+					ContentSize: int64(len("updated content")),
+				})
+				if err != nil {
+					t.Errorf("Failed to update file: %v", err)
+				}
+			},
+		}
+		if _, err := NewDriver(racing, keyring, []string{"encrypted"}).RotateKeys(context.Background(), "encrypted"); err != nil {
+			t.Fatalf("Failed to rotate keys: %v", err)
+		}
+		data, _, err = read(writer, &FileApplication.GetFileByPathQuery{Bucket: "encrypted", Path: "/file.bin"})
+		if err != nil || string(data) != "updated content" {
+			t.Errorf("File updated during rotation must not be overwritten by its previous content (%v)", err)
+		}
+	})
+}
+
+// Writes the file right after it's opened for reading
+type racingStorage struct {
+	objectstorage.ObjectStorageDriver
+	path  string
+	write func()
+}
+
+func (s *racingStorage) GetFileByPath(query *FileApplication.GetFileByPathQuery) (*entity.FileStream, error) {
+	stream, err := s.ObjectStorageDriver.GetFileByPath(query)
+	if err == nil && query.Path == s.path {
+		s.write()
+	}
+	return stream, err
+}
+
+func TestParseKeyring(t *testing.T) {
+	key := newMasterKey(t, "a")
+	invalid := []string{
+		"",
+		"a",
+		"a:not-base64",
+		"a:" + base64.StdEncoding.EncodeToString([]byte("short")),
+		key + "," + key,
+		":" + key[2:],
+	}
+	for _, keys := range invalid {
+		if _, err := ParseKeyring(keys); err == nil {
+			t.Errorf("Keyring %q must be rejected", keys)
+		}
+	}
+
+	keyring, err := ParseKeyring(key + ", " + newMasterKey(t, "b"))
+	if err != nil {
+		t.Fatalf("Failed to parse keyring: %v", err)
+	}
+	if keyring.ActiveKeyID() != "a" {
+		t.Errorf("First key must be the active one, but got \"%s\"", keyring.ActiveKeyID())
+	}
+}
@@ -0,0 +1,151 @@
+package encryption
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+)
+
+// Encrypted file consists of the fixed size header followed by the content split into chunks:
+//
+//	magic (4) | version (1) | master key ID length (1) | master key ID padded by zeros (32) |
+//	wrapped data key (60) | nonce prefix (7) | chunk 0 | chunk 1 | ... | chunk N
+//
+// Each chunk is chunkSize bytes of the content (except the last one, which may be shorter or even empty)
+// sealed by AES-GCM using the data key. Nonce of the chunk is nonce prefix followed by the index of the chunk
+// and the flag of the last chunk, so chunks can't be reordered, and file can't be truncated unnoticed.
+// Since all chunks (except the last one) have the same size, any range of the file can be decrypted
+// by reading only chunks which cover this range.
+//
+// Header has fixed size, so size of the content can be computed from the size of the stored file.
+const (
+	formatVersion      byte  = 1
+	chunkSize          int64 = 64 * 1024
+	tagSize            int64 = 16
+	nonceSize                = 12
+	noncePrefixSize          = 7
+	wrappedKeySize           = nonceSize + keySize + int(tagSize)
+	encryptedChunkSize       = chunkSize + tagSize
+	headerSize         int64 = int64(len(magic) + 1 + 1 + maxKeyIDLength + wrappedKeySize + noncePrefixSize)
+)
+
+var magic = [4]byte{'V', 'E', 'N', 'C'}
+
+var (
+	ErrCorruptedFile = errors.New("encrypted file is corrupted")
+	ErrInvalidSize   = errors.New("encrypted file has invalid size")
+)
+
+type header struct {
+	keyID       string
+	wrappedKey  []byte
+	noncePrefix []byte
+}
+
+// Creates header with a new data key, wrapped by the active master key. Returns header and unwrapped data key.
+func newHeader(keyring *Keyring) (*header, []byte, error) {
+	dataKey := make([]byte, keySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, nil, err
+	}
+	noncePrefix := make([]byte, noncePrefixSize)
+	if _, err := rand.Read(noncePrefix); err != nil {
+		return nil, nil, err
+	}
+
+	keyID, wrappedKey, err := keyring.wrap(dataKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &header{
+		keyID:       keyID,
+		wrappedKey:  wrappedKey,
+		noncePrefix: noncePrefix,
+	}, dataKey, nil
+}
+
+func (h *header) encode() []byte {
+	buf := make([]byte, 0, headerSize)
+	buf = append(buf, magic[:]...)
+	buf = append(buf, formatVersion, byte(len(h.keyID)))
+	buf = append(buf, h.keyID...)
+	buf = append(buf, make([]byte, maxKeyIDLength-len(h.keyID))...)
+	buf = append(buf, h.wrappedKey...)
+	buf = append(buf, h.noncePrefix...)
+	return buf
+}
+
+// Returns false if data isn't header of the encrypted file (e.g. file was stored before encryption was enabled)
+func decodeHeader(data []byte) (*header, bool) {
+	if int64(len(data)) < headerSize || !bytes.Equal(data[:len(magic)], magic[:]) {
+		return nil, false
+	}
+	data = data[len(magic):]
+
+	version, keyIDLength := data[0], int(data[1])
+	if version != formatVersion || keyIDLength == 0 || keyIDLength > maxKeyIDLength {
+		return nil, false
+	}
+	data = data[2:]
+
+	h := &header{keyID: string(data[:keyIDLength])}
+	data = data[maxKeyIDLength:]
+	h.wrappedKey, data = data[:wrappedKeySize], data[wrappedKeySize:]
+	h.noncePrefix = data[:noncePrefixSize]
+
+	return h, true
+}
+
+// Same header, but with data key wrapped by the active master key
+func (h *header) rewrap(keyring *Keyring) (*header, error) {
+	dataKey, err := keyring.unwrap(h.keyID, h.wrappedKey)
+	if err != nil {
+		return nil, err
+	}
+	keyID, wrappedKey, err := keyring.wrap(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	return &header{
+		keyID:       keyID,
+		wrappedKey:  wrappedKey,
+		noncePrefix: h.noncePrefix,
+	}, nil
+}
+
+func (h *header) chunkNonce(index int64, last bool) []byte {
+	nonce := make([]byte, nonceSize)
+	copy(nonce, h.noncePrefix)
+	binary.BigEndian.PutUint32(nonce[noncePrefixSize:], uint32(index))
+	if last {
+		nonce[nonceSize-1] = 1
+	}
+	return nonce
+}
+
+// Empty content is still stored as single empty chunk, so it's authenticated as well
+func chunkCount(contentSize int64) int64 {
+	return max(1, (contentSize+chunkSize-1)/chunkSize)
+}
+
+func encryptedSize(contentSize int64) int64 {
+	return headerSize + contentSize + chunkCount(contentSize)*tagSize
+}
+
+// Reverse of encryptedSize()
+func contentSize(encryptedSize int64) (int64, error) {
+	body := encryptedSize - headerSize
+	if body < tagSize {
+		return 0, ErrInvalidSize
+	}
+	fullChunks, rest := body/encryptedChunkSize, body%encryptedChunkSize
+	if rest == 0 {
+		return fullChunks * chunkSize, nil
+	}
+	if rest < tagSize {
+		return 0, ErrInvalidSize
+	}
+	return fullChunks*chunkSize + rest - tagSize, nil
+}
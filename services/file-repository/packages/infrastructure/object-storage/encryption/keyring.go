@@ -0,0 +1,126 @@
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Master keys and data keys are both AES-256 keys
+const keySize = 32
+
+const maxKeyIDLength = 32
+
+var (
+	ErrNoMasterKeys     = errors.New("at least one master key must be specified")
+	ErrUnknownMasterKey = errors.New("master key which was used to encrypt the file isn't configured")
+)
+
+// Master keys are used only to wrap (encrypt) per-file data keys, content itself is encrypted by data keys.
+//
+// To rotate master key, new key must be added as the active one, while old keys must be kept
+// until all files are rewrapped (see Driver.RotateKeys), otherwise files encrypted by them can't be read.
+type Keyring struct {
+	active string
+	keys   map[string]cipher.AEAD
+}
+
+// Parses master keys in format "id1:base64key1,id2:base64key2,...".
+// First key is the active one: it's used to wrap data keys of all new files, others are used only for decryption.
+func ParseKeyring(keys string) (*Keyring, error) {
+	keyring := &Keyring{keys: make(map[string]cipher.AEAD)}
+
+	for _, entry := range strings.Split(keys, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		id, encoded, ok := strings.Cut(entry, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid master key entry, expected \"id:base64key\", but got \"%s\"", entry)
+		}
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("invalid master key \"%s\": %w", id, err)
+		}
+		if err := keyring.add(id, key); err != nil {
+			return nil, err
+		}
+	}
+
+	if keyring.active == "" {
+		return nil, ErrNoMasterKeys
+	}
+
+	return keyring, nil
+}
+
+// Adds master key to the keyring, first added key becomes the active one.
+func (k *Keyring) add(id string, key []byte) error {
+	if id == "" || len(id) > maxKeyIDLength {
+		return fmt.Errorf("master key ID must contain from 1 to %d bytes, but got \"%s\"", maxKeyIDLength, id)
+	}
+	if len(key) != keySize {
+		return fmt.Errorf("master key \"%s\" must be %d bytes long, but got %d", id, keySize, len(key))
+	}
+	if _, ok := k.keys[id]; ok {
+		return fmt.Errorf("duplicate master key \"%s\"", id)
+	}
+
+	aead, err := newAEAD(key)
+	if err != nil {
+		return err
+	}
+	k.keys[id] = aead
+	if k.active == "" {
+		k.active = id
+	}
+
+	return nil
+}
+
+// ID of the key which is used to wrap new data keys
+func (k *Keyring) ActiveKeyID() string {
+	return k.active
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// Encrypts data key by the active master key. Returns ID of this master key and wrapped key
+// (random nonce followed by encrypted data key), ID is authenticated as well.
+func (k *Keyring) wrap(dataKey []byte) (string, []byte, error) {
+	aead := k.keys[k.active]
+
+	nonce := make([]byte, aead.NonceSize(), wrappedKeySize)
+	if _, err := rand.Read(nonce); err != nil {
+		return "", nil, err
+	}
+
+	return k.active, aead.Seal(nonce, nonce, dataKey, []byte(k.active)), nil
+}
+
+// Reverse of wrap()
+func (k *Keyring) unwrap(keyID string, wrapped []byte) ([]byte, error) {
+	aead, ok := k.keys[keyID]
+	if !ok {
+		return nil, ErrUnknownMasterKey
+	}
+
+	nonce, encrypted := wrapped[:aead.NonceSize()], wrapped[aead.NonceSize():]
+	dataKey, err := aead.Open(nil, nonce, encrypted, []byte(keyID))
+	if err != nil {
+		return nil, ErrCorruptedFile
+	}
+
+	return dataKey, nil
+}
@@ -0,0 +1,125 @@
+package encryption
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"time"
+	FileApplication "vega_file_repository/packages/application/file"
+	"vega_file_repository/packages/domain/entity"
+
+	cqrs "github.com/abaxoth0/Vega/libs/go/packages/CQRS"
+)
+
+// Each file is rewritten as a whole, so default timeout isn't enough for big ones
+const rotationTimeout = time.Hour
+
+// Rewraps data keys of all files in the bucket which were wrapped by other master key than the active one,
+// after that old master keys aren't needed to read current versions of the files.
+// Returns amount of rewrapped files.
+//
+// Content isn't re-encrypted: only headers are replaced, but since storages can't update files partially,
+// each such file is rewritten. File is rewritten only if its ETag is still the same as when it was listed,
+// so files updated while rotation is in progress are skipped instead of being overwritten by their previous
+// content (they are either written with the active key already or will be rewrapped by the next rotation).
+// Hence rotation requires storage which supports write preconditions.
+// Previous versions of the files aren't rewrapped.
+func (d *Driver) RotateKeys(ctx context.Context, bucket string) (int, error) {
+	commandQuery := cqrs.CommandQuery{
+		Context:        ctx,
+		ContextTimeout: rotationTimeout,
+	}
+
+	rotated := 0
+	token := ""
+	for {
+		listing, err := d.ObjectStorageDriver.ListDirectory(&FileApplication.ListDirectoryQuery{
+			Bucket:            bucket,
+			Path:              "/",
+			Recursive:         true,
+			ContinuationToken: token,
+			CommandQuery:      commandQuery,
+		})
+		if err != nil {
+			return rotated, err
+		}
+
+		for _, entry := range listing.Entries {
+			if entry.IsDirectory {
+				continue
+			}
+			ok, err := d.rewrapFile(bucket, entry, commandQuery)
+			if err != nil {
+				return rotated, err
+			}
+			if ok {
+				rotated++
+			}
+		}
+
+		if !listing.IsTruncated {
+			return rotated, nil
+		}
+		token = listing.NextContinuationToken
+	}
+}
+
+// Returns false if file isn't encrypted, its data key is already wrapped by the active master key
+// or file was changed (or deleted) after it was listed.
+func (d *Driver) rewrapFile(bucket string, entry entity.FileInfo, commandQuery cqrs.CommandQuery) (bool, error) {
+	// Without ETag file can't be rewritten safely
+	if entry.ETag == "" {
+		return false, FileApplication.ErrPreconditionsNotSupported
+	}
+
+	stream, err := d.ObjectStorageDriver.GetFileByPath(&FileApplication.GetFileByPathQuery{
+		Bucket:       bucket,
+		Path:         entry.Path,
+		CommandQuery: commandQuery,
+	})
+	if err != nil {
+		if errors.Is(err, FileApplication.ErrFileDoesNotExist) {
+			return false, nil
+		}
+		return false, err
+	}
+	defer stream.Cancel()
+
+	data := make([]byte, headerSize)
+	if _, err := io.ReadFull(stream.Content, data); err != nil {
+		// File is too small to be encrypted
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return false, nil
+		}
+		return false, err
+	}
+	h, ok := decodeHeader(data)
+	if !ok || h.keyID == d.keyring.ActiveKeyID() {
+		return false, nil
+	}
+
+	rewrapped, err := h.rewrap(d.keyring)
+	if err != nil {
+		return false, err
+	}
+
+	// Rest of the file is streamed as is
+	_, err = d.ObjectStorageDriver.UpdateFileContent(&FileApplication.UpdateFileContentCommand{
+		Bucket:     bucket,
+		Path:       entry.Path,
+		NewContent: io.MultiReader(bytes.NewReader(rewrapped.encode()), stream.Content),
+		Size:       stream.Size,
+		// Content which was read may be already replaced by the new one
+		Preconditions: FileApplication.WritePreconditions{IfMatch: entry.ETag},
+		CommandQuery:  commandQuery,
+	})
+	if err != nil {
+		if errors.Is(err, FileApplication.ErrPreconditionFailed) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return true, nil
+}
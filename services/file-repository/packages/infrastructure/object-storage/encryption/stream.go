@@ -0,0 +1,193 @@
+package encryption
+
+import (
+	"crypto/cipher"
+	"errors"
+	"io"
+	"vega_file_repository/packages/domain/entity"
+)
+
+var ErrContentSizeMismatch = errors.New("size of the content doesn't match declared one")
+
+// Produces encrypted file (header and chunks) from the content of the specified size.
+// Content is read chunk by chunk, so only one chunk is kept in memory.
+type encryptingReader struct {
+	content   io.Reader
+	aead      cipher.AEAD
+	header    *header
+	remaining int64
+	index     int64
+	done      bool
+	chunk     []byte
+	sealed    []byte
+	// Part of the encrypted file which wasn't read yet
+	pending []byte
+}
+
+func newEncryptingReader(keyring *Keyring, content io.Reader, size int64) (*encryptingReader, error) {
+	h, dataKey, err := newHeader(keyring)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &encryptingReader{
+		content:   content,
+		aead:      aead,
+		header:    h,
+		remaining: size,
+		chunk:     make([]byte, min(size, chunkSize)),
+		sealed:    make([]byte, 0, min(size, chunkSize)+tagSize),
+		pending:   h.encode(),
+	}, nil
+}
+
+func (r *encryptingReader) Read(p []byte) (int, error) {
+	for len(r.pending) == 0 {
+		if r.done {
+			return 0, io.EOF
+		}
+		if err := r.sealNextChunk(); err != nil {
+			return 0, err
+		}
+	}
+
+	n := copy(p, r.pending)
+	r.pending = r.pending[n:]
+
+	return n, nil
+}
+
+func (r *encryptingReader) sealNextChunk() error {
+	chunk := r.chunk[:min(r.remaining, chunkSize)]
+	if _, err := io.ReadFull(r.content, chunk); err != nil {
+		if err == io.EOF {
+			return io.ErrUnexpectedEOF
+		}
+		return err
+	}
+	r.remaining -= int64(len(chunk))
+
+	last := r.remaining == 0
+	if last {
+		// Storages reject content which is bigger than declared, so must this reader
+		var extra [1]byte
+		if n, _ := io.ReadFull(r.content, extra[:]); n > 0 {
+			return ErrContentSizeMismatch
+		}
+	}
+
+	r.pending = r.aead.Seal(r.sealed[:0], r.header.chunkNonce(r.index, last), chunk, nil)
+	r.index++
+	r.done = last
+
+	return nil
+}
+
+// Decrypts specified range of the file from the encrypted chunks which cover this range.
+// Each chunk is authenticated before any of its bytes are returned.
+type decryptingReader struct {
+	encrypted io.Reader
+	aead      cipher.AEAD
+	header    *header
+	index     int64
+	lastIndex int64
+	// Size of the last encrypted chunk
+	lastSize int64
+	// Amount of bytes of the first chunk which precede the range
+	skip      int64
+	remaining int64
+	chunk     []byte
+	pending   []byte
+}
+
+// Returns offset and length of the encrypted file which must be read to decrypt the range
+func encryptedRange(byteRange entity.ByteRange) (int64, int64) {
+	first, last := byteRange.Start/chunkSize, max(byteRange.End, 0)/chunkSize
+	return headerSize + first*encryptedChunkSize, (last - first + 1) * encryptedChunkSize
+}
+
+// encrypted must start at the offset returned by encryptedRange()
+func newDecryptingReader(
+	keyring *Keyring,
+	h *header,
+	encrypted io.Reader,
+	contentSize int64,
+	byteRange entity.ByteRange,
+) (*decryptingReader, error) {
+	dataKey, err := keyring.unwrap(h.keyID, h.wrappedKey)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	lastIndex := chunkCount(contentSize) - 1
+
+	return &decryptingReader{
+		encrypted: encrypted,
+		aead:      aead,
+		header:    h,
+		index:     byteRange.Start / chunkSize,
+		lastIndex: lastIndex,
+		lastSize:  contentSize - lastIndex*chunkSize + tagSize,
+		skip:      byteRange.Start % chunkSize,
+		remaining: byteRange.Length(),
+		chunk:     make([]byte, encryptedChunkSize),
+	}, nil
+}
+
+func (r *decryptingReader) Read(p []byte) (int, error) {
+	for len(r.pending) == 0 {
+		if r.remaining <= 0 {
+			return 0, io.EOF
+		}
+		if err := r.openNextChunk(); err != nil {
+			return 0, err
+		}
+	}
+
+	n := copy(p, r.pending)
+	r.pending = r.pending[n:]
+
+	return n, nil
+}
+
+func (r *decryptingReader) openNextChunk() error {
+	size := encryptedChunkSize
+	if r.index == r.lastIndex {
+		size = r.lastSize
+	}
+	if r.index > r.lastIndex {
+		return ErrCorruptedFile
+	}
+
+	chunk := r.chunk[:size]
+	if _, err := io.ReadFull(r.encrypted, chunk); err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return ErrCorruptedFile
+		}
+		return err
+	}
+
+	content, err := r.aead.Open(chunk[:0], r.header.chunkNonce(r.index, r.index == r.lastIndex), chunk, nil)
+	if err != nil {
+		return ErrCorruptedFile
+	}
+	r.index++
+
+	content = content[r.skip:]
+	r.skip = 0
+	if int64(len(content)) > r.remaining {
+		content = content[:r.remaining]
+	}
+	r.remaining -= int64(len(content))
+	r.pending = content
+
+	return nil
+}
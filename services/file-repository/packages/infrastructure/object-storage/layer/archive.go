@@ -0,0 +1,188 @@
+// Helpers for layers which wrap object storage drivers and transform content of the files (e.g. encrypt it).
+// Drivers build and extract archives by themselves, bypassing such layers, so layers must do it on their own.
+package layer
+
+import (
+	"context"
+	"errors"
+	"io"
+	"path"
+	"strings"
+	"time"
+	FileApplication "vega_file_repository/packages/application/file"
+	"vega_file_repository/packages/domain/entity"
+	"vega_file_repository/packages/infrastructure/archive"
+
+	cqrs "github.com/abaxoth0/Vega/libs/go/packages/CQRS"
+)
+
+// Validates query of the directory archive, same as drivers do
+func ValidateArchiveQuery(query *FileApplication.GetFileByPathQuery) error {
+	if query.ArchiveFormat == FileApplication.ArchiveFormatNone {
+		return FileApplication.ErrArchiveFormatNotSpecified
+	}
+	if query.IsRanged() {
+		return FileApplication.ErrRangeOfDirectory
+	}
+	if query.VersionID != "" {
+		return FileApplication.ErrVersionOfDirectory
+	}
+	return nil
+}
+
+// Same as archives built by drivers, but each file is read via handler, so it's processed by the layer
+// before it's written into the archive. Directory in query must be validated beforehand.
+func GetDirectoryArchive(
+	handler FileApplication.QueryHandler,
+	query *FileApplication.GetFileByPathQuery,
+) (*entity.FileStream, error) {
+	if !query.CommandQuery.IsInit() {
+		cqrs.InitDefaultCommandQuery(&query.CommandQuery)
+	}
+
+	ctx, cancel := context.WithTimeout(query.Context, query.ContextTimeout)
+	commandQuery := cqrs.CommandQuery{Context: ctx, ContextTimeout: query.ContextTimeout}
+
+	// First page is requested before streaming is started, so errors like missing bucket are returned immediately
+	listing, err := handler.ListDirectory(&FileApplication.ListDirectoryQuery{
+		Bucket:       query.Bucket,
+		Path:         query.Path,
+		Recursive:    true,
+		CommandQuery: commandQuery,
+	})
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	pr, pw := io.Pipe()
+
+	writer, err := archive.NewWriter(query.ArchiveFormat, pw)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	go func() {
+		err := writeDirectoryArchive(handler, query, listing, commandQuery, writer)
+		if err == nil {
+			err = writer.Close()
+		}
+		pw.CloseWithError(err)
+	}()
+
+	return &entity.FileStream{
+		Content: pr,
+		Size:    -1,
+		Context: ctx,
+		Cancel: func() {
+			cancel()
+			// Unblocks archive writer if reader stopped reading before EOF
+			pr.Close()
+		},
+	}, nil
+}
+
+func writeDirectoryArchive(
+	handler FileApplication.QueryHandler,
+	query *FileApplication.GetFileByPathQuery,
+	listing *entity.DirectoryListing,
+	commandQuery cqrs.CommandQuery,
+	writer archive.Writer,
+) error {
+	// Entries are placed inside of the directory with the same name as requested one,
+	// so on extraction they won't be scattered around. Root of the bucket has no name though.
+	root := path.Base(query.Path) + "/"
+	if query.Path == "/" {
+		root = ""
+	}
+
+	if root != "" {
+		if err := writer.WriteDirectory(root, time.Now()); err != nil {
+			return err
+		}
+	}
+
+	for {
+		for _, entry := range listing.Entries {
+			name := root + strings.TrimPrefix(entry.Path, query.Path)
+
+			if entry.IsDirectory {
+				if err := writer.WriteDirectory(name, entry.LastModified); err != nil {
+					return err
+				}
+				continue
+			}
+
+			stream, err := handler.GetFileByPath(&FileApplication.GetFileByPathQuery{
+				Bucket:       query.Bucket,
+				Path:         entry.Path,
+				CommandQuery: commandQuery,
+			})
+			if err != nil {
+				return err
+			}
+			err = writer.WriteFile(name, stream.Size, entry.LastModified, stream.Content)
+			stream.Cancel()
+			if err != nil {
+				return err
+			}
+		}
+
+		if !listing.IsTruncated {
+			return nil
+		}
+
+		var err error
+		listing, err = handler.ListDirectory(&FileApplication.ListDirectoryQuery{
+			Bucket:            query.Bucket,
+			Path:              query.Path,
+			Recursive:         true,
+			ContinuationToken: listing.NextContinuationToken,
+			CommandQuery:      commandQuery,
+		})
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// Stores single extracted file
+type StoreFileFunc func(path string, content io.Reader, size int64) error
+
+// Extracts archive entries one by one: directories are created via handler, while files are stored via store.
+// Upload command must be validated beforehand.
+func UploadArchive(
+	handler FileApplication.UseCases,
+	cmd *FileApplication.UploadFileCommand,
+	store StoreFileFunc,
+) (*entity.UploadResult, error) {
+	if cmd.Content == nil {
+		return nil, errors.New("archive content is missing")
+	}
+
+	// Fails if bucket doesn't exist, otherwise each entry would fail instead
+	_, err := handler.ListDirectory(&FileApplication.ListDirectoryQuery{
+		Bucket:       cmd.Bucket,
+		Path:         "/",
+		Limit:        1,
+		CommandQuery: cmd.CommandQuery,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return archive.Extract(
+		cmd.ArchiveFormat, cmd.Content, cmd.ContentSize, cmd.Path,
+		func(path string, entry *archive.Entry, content io.Reader) error {
+			if entry.Kind == archive.EntryDirectory {
+				return handler.Mkdir(&FileApplication.MkdirCommand{
+					Bucket:       cmd.Bucket,
+					Path:         path,
+					CommandQuery: cmd.CommandQuery,
+				})
+			}
+			return store(path, content, entry.Size)
+		},
+	)
+}
@@ -0,0 +1,416 @@
+package localcommand
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"syscall"
+	"time"
+	FileApplication "vega_file_repository/packages/application/file"
+	"vega_file_repository/packages/domain/entity"
+	"vega_file_repository/packages/infrastructure/archive"
+	LocalCommon "vega_file_repository/packages/infrastructure/object-storage/local/common"
+
+	"github.com/abaxoth0/Vega/libs/go/packages/CQRS"
+	"github.com/abaxoth0/Vega/libs/go/packages/file"
+)
+
+var Handler FileApplication.CommandHandler = new(defaultCommandHandler)
+
+type defaultCommandHandler struct {
+}
+
+func (h *defaultCommandHandler) preprocessTargetedCommandQuery(
+	commandQuery *cqrs.CommandQuery, path string,
+) error {
+	if !commandQuery.IsInit() {
+		cqrs.InitDefaultCommandQuery(commandQuery)
+	}
+	if err := file.ValidatePathFormat(path); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (h *defaultCommandHandler) preprocessCommandQuery(
+	commandQuery *cqrs.CommandQuery,
+) (context.Context, context.CancelFunc) {
+	if !commandQuery.IsInit() {
+		cqrs.InitDefaultCommandQuery(commandQuery)
+	}
+
+	ctx, cancel := context.WithTimeout(commandQuery.Context, commandQuery.ContextTimeout)
+
+	return ctx, cancel
+}
+
+func (h *defaultCommandHandler) Mkdir(cmd *FileApplication.MkdirCommand) error {
+	if err := h.preprocessTargetedCommandQuery(&cmd.CommandQuery, cmd.Path); err != nil {
+		return err
+	}
+	if ok := file.IsDirectory(cmd.Path); !ok {
+		return file.ErrFileIsNotDirectory
+	}
+	// Root of the bucket always exists, object storages don't allow to create it as well
+	if cmd.Path == "/" {
+		return fmt.Errorf("%w: can't create root directory", FileApplication.ErrInvalidPath)
+	}
+	if err := LocalCommon.IsBucketExist(cmd.Bucket); err != nil {
+		return err
+	}
+
+	dirPath, err := LocalCommon.ObjectFilePath(cmd.Bucket, cmd.Path)
+	if err != nil {
+		return err
+	}
+
+	return LocalCommon.WriteFile(LocalCommon.MarkerFilePath(dirPath), nil, 0)
+}
+
+func (h *defaultCommandHandler) UploadFile(cmd *FileApplication.UploadFileCommand) (*entity.UploadResult, error) {
+	if err := h.preprocessTargetedCommandQuery(&cmd.CommandQuery, cmd.Path); err != nil {
+		return nil, err
+	}
+	if cmd.ContentSize <= 0 {
+		return nil, fmt.Errorf("%w, but got %d", FileApplication.ErrInvalidContentSize, cmd.ContentSize)
+	}
+	if cmd.ArchiveFormat != FileApplication.ArchiveFormatNone {
+		if !file.IsDirectory(cmd.Path) {
+			return nil, file.ErrFileIsNotDirectory
+		}
+		if cmd.Checksum != nil {
+			return nil, FileApplication.ErrChecksumOfArchive
+		}
+		if cmd.Preconditions.IsSet() {
+			return nil, FileApplication.ErrPreconditionsOfArchive
+		}
+		return h.uploadArchive(cmd)
+	}
+	if file.IsDirectory(cmd.Path) {
+		return nil, FileApplication.ErrUploadOfDirectory
+	}
+	if cmd.Content == nil {
+		cmd.Content = bytes.NewReader([]byte{})
+	}
+	if err := LocalCommon.IsBucketExist(cmd.Bucket); err != nil {
+		return nil, err
+	}
+
+	return h.writeFile(cmd.Bucket, cmd.Path, cmd.Content, cmd.ContentSize, cmd.Checksum, &cmd.Preconditions)
+}
+
+// Unlike object storages, checksum of the content is always stored, since file is written
+// into temporary file first, so checksum is already known when file is actually created.
+// Preconditions may be nil, otherwise they are checked right before written file replaces the current one.
+func (h *defaultCommandHandler) writeFile(
+	bucket string,
+	path string,
+	content io.Reader,
+	size int64,
+	expected *entity.Checksum,
+	preconditions *FileApplication.WritePreconditions,
+) (*entity.UploadResult, error) {
+	filePath, err := LocalCommon.ObjectFilePath(bucket, path)
+	if err != nil {
+		return nil, err
+	}
+
+	var condition LocalCommon.WriteCondition
+	if preconditions != nil && preconditions.IsSet() {
+		if err := preconditions.Validate(); err != nil {
+			return nil, err
+		}
+		condition = func(info os.FileInfo) error {
+			if info == nil || info.IsDir() {
+				return preconditions.Check(false, "", time.Time{})
+			}
+			return preconditions.Check(true, LocalCommon.ETag(info), info.ModTime())
+		}
+	}
+
+	reader, err := FileApplication.NewChecksumReader(content, size, expected)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := LocalCommon.PreserveVersion(bucket, path, filePath); err != nil {
+		return nil, err
+	}
+	info, err := LocalCommon.WriteFileIf(filePath, reader, size, condition)
+	if err != nil {
+		return nil, reader.ResolveError(err)
+	}
+
+	checksum := reader.Checksum()
+	if err := LocalCommon.StoreChecksum(bucket, path, filePath, checksum); err != nil {
+		return nil, err
+	}
+
+	return &entity.UploadResult{
+		Checksum: checksum,
+		ETag:     LocalCommon.ETag(info),
+	}, nil
+}
+
+// Extracts archive entries one by one into files under cmd.Path (see archive.Extract).
+func (h *defaultCommandHandler) uploadArchive(cmd *FileApplication.UploadFileCommand) (*entity.UploadResult, error) {
+	if cmd.Content == nil {
+		return nil, errors.New("archive content is missing")
+	}
+	if err := LocalCommon.IsBucketExist(cmd.Bucket); err != nil {
+		return nil, err
+	}
+
+	return archive.Extract(
+		cmd.ArchiveFormat, cmd.Content, cmd.ContentSize, cmd.Path,
+		func(path string, entry *archive.Entry, content io.Reader) error {
+			filePath, err := LocalCommon.ObjectFilePath(cmd.Bucket, path)
+			if err != nil {
+				return err
+			}
+			if entry.Kind == archive.EntryDirectory {
+				return LocalCommon.WriteFile(LocalCommon.MarkerFilePath(filePath), nil, 0)
+			}
+			_, err = h.writeFile(cmd.Bucket, path, content, entry.Size, nil, nil)
+			return err
+		},
+	)
+}
+
+// Files are always replaced as a whole (see MinIO driver), but unlike object storages
+// file system supports partial updates, so it may be reconsidered in the future.
+func (h *defaultCommandHandler) UpdateFileContent(cmd *FileApplication.UpdateFileContentCommand) (*entity.UploadResult, error) {
+	if err := h.preprocessTargetedCommandQuery(&cmd.CommandQuery, cmd.Path); err != nil {
+		return nil, err
+	}
+	if file.IsDirectory(cmd.Path) {
+		return nil, FileApplication.ErrUpdateOfDirectory
+	}
+	if err := LocalCommon.IsBucketExist(cmd.Bucket); err != nil {
+		return nil, err
+	}
+
+	return h.writeFile(cmd.Bucket, cmd.Path, cmd.NewContent, cmd.Size, cmd.Checksum, &cmd.Preconditions)
+}
+
+// Same as in MinIO driver, deletion of non-existing files isn't considered as error.
+func (h *defaultCommandHandler) DeleteFiles(cmd *FileApplication.DeleteFilesCommand) (*entity.DeletionReport, error) {
+	if !cmd.CommandQuery.IsInit() {
+		cqrs.InitDefaultCommandQuery(&cmd.CommandQuery)
+	}
+
+	for _, path := range cmd.Paths {
+		if err := file.ValidatePathFormat(path); err != nil {
+			return nil, err
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(cmd.Context, cmd.ContextTimeout)
+	defer cancel()
+
+	if err := LocalCommon.IsBucketExist(cmd.Bucket); err != nil {
+		return nil, err
+	}
+	bucketPath, err := LocalCommon.BucketFilePath(cmd.Bucket)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &entity.DeletionReport{
+		Results: make([]entity.DeletionResult, len(cmd.Paths)),
+	}
+	// Same file may be covered by several requested paths
+	deleted := make(map[string]bool)
+
+	for i, path := range cmd.Paths {
+		result := &report.Results[i]
+		result.Path = path
+
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+
+		filePath, err := LocalCommon.ObjectFilePath(cmd.Bucket, path)
+		if err != nil {
+			result.Failures = append(result.Failures, entity.DeletionFailure{Path: path, Reason: err.Error()})
+			continue
+		}
+
+		if !cmd.Recursive || !file.IsDirectory(path) {
+			if file.IsDirectory(path) {
+				filePath = LocalCommon.MarkerFilePath(filePath)
+			}
+			h.deleteFile(bucketPath, filePath, path, deleted, result)
+			LocalCommon.RemoveEmptyDirs(bucketPath, filepath.Dir(filePath))
+			continue
+		}
+
+		if err := h.deleteDirectory(ctx, bucketPath, filePath, deleted, result); err != nil {
+			return report, err
+		}
+	}
+
+	if len(report.Failures()) > 0 {
+		return report, FileApplication.ErrPartialDeletion
+	}
+
+	return report, nil
+}
+
+func (h *defaultCommandHandler) deleteFile(
+	bucketPath string,
+	filePath string,
+	path string,
+	deleted map[string]bool,
+	result *entity.DeletionResult,
+) {
+	if deleted[filePath] {
+		return
+	}
+	deleted[filePath] = true
+
+	// Directories can't be deleted as files (the same as in object storages, where "/a" and "/a/" are different keys)
+	if info, err := os.Stat(filePath); err == nil && info.IsDir() {
+		result.DeletedCount++
+		return
+	}
+
+	if err := LocalCommon.PreserveVersion(filepath.Base(bucketPath), path, filePath); err != nil {
+		result.Failures = append(result.Failures, entity.DeletionFailure{Path: path, Reason: err.Error()})
+		return
+	}
+	if err := os.Remove(filePath); err != nil && !LocalCommon.IsNotExist(err) {
+		result.Failures = append(result.Failures, entity.DeletionFailure{Path: path, Reason: err.Error()})
+		return
+	}
+	LocalCommon.PruneChecksums(filepath.Base(bucketPath), path, filePath)
+	result.DeletedCount++
+}
+
+// Deletes all files in subtree (including directory markers) and then all empty directories.
+func (h *defaultCommandHandler) deleteDirectory(
+	ctx context.Context,
+	bucketPath string,
+	dirPath string,
+	deleted map[string]bool,
+	result *entity.DeletionResult,
+) error {
+	// WalkDir() accepts files as well, but "/a" and "/a/" are different paths
+	if info, err := os.Stat(dirPath); err != nil || !info.IsDir() {
+		if err == nil || LocalCommon.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	dirs := []string{}
+
+	err := filepath.WalkDir(dirPath, func(filePath string, dirEntry fs.DirEntry, err error) error {
+		if err != nil {
+			if LocalCommon.IsNotExist(err) {
+				return nil
+			}
+			result.Failures = append(result.Failures, entity.DeletionFailure{
+				Path:   LocalCommon.ObjectPath(bucketPath, filePath, true),
+				Reason: err.Error(),
+			})
+			return filepath.SkipDir
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if dirEntry.IsDir() {
+			dirs = append(dirs, filePath)
+			return nil
+		}
+
+		path := LocalCommon.ObjectPath(bucketPath, filePath, false)
+		if dirEntry.Name() == LocalCommon.DirectoryMarkerName {
+			path = LocalCommon.ObjectPath(bucketPath, filepath.Dir(filePath), true)
+		}
+		h.deleteFile(bucketPath, filePath, path, deleted, result)
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// Children are visited after their parents, so removing in reverse order
+	// guarantees that directory is already emptied when it's removed.
+	for i := len(dirs) - 1; i >= 0; i-- {
+		if dirs[i] != bucketPath {
+			os.Remove(dirs[i])
+		}
+	}
+	LocalCommon.RemoveEmptyDirs(bucketPath, filepath.Dir(dirPath))
+
+	return nil
+}
+
+func (h *defaultCommandHandler) MakeBucket(cmd *FileApplication.MakeBucketCommand) error {
+	_, cancel := h.preprocessCommandQuery(&cmd.CommandQuery)
+	defer cancel()
+
+	bucketPath, err := LocalCommon.BucketFilePath(cmd.Name)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Mkdir(bucketPath, 0o755); err != nil {
+		if errors.Is(err, os.ErrExist) {
+			return LocalCommon.ErrBucketAlreadyExists
+		}
+		return err
+	}
+
+	return LocalCommon.WriteBucketMetadata(cmd.Name, &LocalCommon.BucketMetadata{
+		CreationDate: time.Now(),
+	})
+}
+
+func (h *defaultCommandHandler) DeleteBucket(cmd *FileApplication.DeleteBucketCommand) error {
+	_, cancel := h.preprocessCommandQuery(&cmd.CommandQuery)
+	defer cancel()
+
+	if err := LocalCommon.IsBucketExist(cmd.Name); err != nil {
+		return err
+	}
+	bucketPath, err := LocalCommon.BucketFilePath(cmd.Name)
+	if err != nil {
+		return err
+	}
+
+	if !cmd.Force {
+		// Bucket with previous versions of files isn't empty, even if it has no current ones
+		if entries, err := os.ReadDir(LocalCommon.VersionsDir(cmd.Name)); err == nil && len(entries) > 0 {
+			return LocalCommon.ErrBucketIsNotEmpty
+		}
+		if err := os.Remove(bucketPath); err != nil {
+			if errors.Is(err, fs.ErrExist) || errors.Is(err, syscall.ENOTEMPTY) {
+				return LocalCommon.ErrBucketIsNotEmpty
+			}
+			return err
+		}
+	} else if err := os.RemoveAll(bucketPath); err != nil {
+		return err
+	}
+
+	if err := LocalCommon.DeleteBucketMetadata(cmd.Name); err != nil {
+		return err
+	}
+	if err := os.RemoveAll(LocalCommon.VersionsDir(cmd.Name)); err != nil {
+		return err
+	}
+	if err := os.RemoveAll(LocalCommon.ChecksumsDir(cmd.Name)); err != nil {
+		return err
+	}
+
+	// Upload sessions of the deleted bucket can't be completed anymore
+	return os.RemoveAll(filepath.Join(LocalCommon.UploadsDir(), cmd.Name))
+}
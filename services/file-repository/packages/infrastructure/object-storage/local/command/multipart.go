@@ -0,0 +1,200 @@
+package localcommand
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+	FileApplication "vega_file_repository/packages/application/file"
+	"vega_file_repository/packages/domain/entity"
+	LocalCommon "vega_file_repository/packages/infrastructure/object-storage/local/common"
+
+	"github.com/abaxoth0/Vega/libs/go/packages/file"
+)
+
+func (h *defaultCommandHandler) InitiateUpload(cmd *FileApplication.InitiateUploadCommand) (string, error) {
+	if err := h.preprocessTargetedCommandQuery(&cmd.CommandQuery, cmd.Path); err != nil {
+		return "", err
+	}
+	if file.IsDirectory(cmd.Path) {
+		return "", FileApplication.ErrUploadOfDirectory
+	}
+	if err := LocalCommon.IsBucketExist(cmd.Bucket); err != nil {
+		return "", err
+	}
+	if _, err := LocalCommon.ObjectFilePath(cmd.Bucket, cmd.Path); err != nil {
+		return "", err
+	}
+
+	uploadID, err := LocalCommon.NewUploadID()
+	if err != nil {
+		return "", err
+	}
+	sessionDir, err := LocalCommon.UploadSessionDir(cmd.Bucket, uploadID)
+	if err != nil {
+		return "", err
+	}
+
+	err = LocalCommon.WriteUploadSession(sessionDir, &LocalCommon.UploadSession{
+		Path:      cmd.Path,
+		Initiated: time.Now(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return uploadID, nil
+}
+
+func (h *defaultCommandHandler) UploadPart(cmd *FileApplication.UploadPartCommand) (*entity.UploadPart, error) {
+	if err := h.preprocessTargetedCommandQuery(&cmd.CommandQuery, cmd.Path); err != nil {
+		return nil, err
+	}
+	if cmd.PartNumber < FileApplication.MinUploadPartNumber || cmd.PartNumber > FileApplication.MaxUploadPartNumber {
+		return nil, FileApplication.ErrInvalidUploadPartNumber
+	}
+	if cmd.Content == nil || cmd.Size <= 0 {
+		return nil, errors.New("part content is missing")
+	}
+
+	sessionDir, err := LocalCommon.OpenUploadSession(cmd.Bucket, cmd.Path, cmd.UploadID)
+	if err != nil {
+		return nil, err
+	}
+
+	partPath := LocalCommon.UploadPartFilePath(sessionDir, cmd.PartNumber)
+	if err := LocalCommon.WriteFile(partPath, cmd.Content, cmd.Size); err != nil {
+		return nil, err
+	}
+
+	info, err := os.Stat(partPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, FileApplication.ErrUploadDoesNotExist
+		}
+		return nil, err
+	}
+
+	return &entity.UploadPart{
+		PartNumber:   cmd.PartNumber,
+		ETag:         LocalCommon.ETag(info),
+		Size:         info.Size(),
+		LastModified: info.ModTime(),
+	}, nil
+}
+
+func (h *defaultCommandHandler) CompleteUpload(cmd *FileApplication.CompleteUploadCommand) error {
+	if err := h.preprocessTargetedCommandQuery(&cmd.CommandQuery, cmd.Path); err != nil {
+		return err
+	}
+
+	sessionDir, err := LocalCommon.OpenUploadSession(cmd.Bucket, cmd.Path, cmd.UploadID)
+	if err != nil {
+		return err
+	}
+	filePath, err := LocalCommon.ObjectFilePath(cmd.Bucket, cmd.Path)
+	if err != nil {
+		return err
+	}
+
+	parts, err := LocalCommon.UploadParts(sessionDir)
+	if err != nil {
+		return err
+	}
+	if len(parts) == 0 {
+		return FileApplication.ErrNoUploadParts
+	}
+
+	readers := make([]io.Reader, 0, len(parts))
+	size := int64(0)
+	for _, part := range parts {
+		f, err := os.Open(LocalCommon.UploadPartFilePath(sessionDir, part.PartNumber))
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return FileApplication.ErrUploadDoesNotExist
+			}
+			return err
+		}
+		defer f.Close()
+
+		readers = append(readers, f)
+		size += part.Size
+	}
+
+	if err := LocalCommon.PreserveVersion(cmd.Bucket, cmd.Path, filePath); err != nil {
+		return err
+	}
+	if err := LocalCommon.WriteFile(filePath, io.MultiReader(readers...), size); err != nil {
+		return err
+	}
+	if err := LocalCommon.StoreChecksum(cmd.Bucket, cmd.Path, filePath, nil); err != nil {
+		return err
+	}
+
+	return os.RemoveAll(sessionDir)
+}
+
+func (h *defaultCommandHandler) AbortUpload(cmd *FileApplication.AbortUploadCommand) error {
+	if err := h.preprocessTargetedCommandQuery(&cmd.CommandQuery, cmd.Path); err != nil {
+		return err
+	}
+
+	sessionDir, err := LocalCommon.OpenUploadSession(cmd.Bucket, cmd.Path, cmd.UploadID)
+	if err != nil {
+		return err
+	}
+
+	return os.RemoveAll(sessionDir)
+}
+
+func (h *defaultCommandHandler) AbortStaleUploads(cmd *FileApplication.AbortStaleUploadsCommand) (int, error) {
+	if cmd.MaxAge <= 0 {
+		return 0, errors.New("max age must be greater than 0")
+	}
+
+	ctx, cancel := h.preprocessCommandQuery(&cmd.CommandQuery)
+	defer cancel()
+
+	bucketDirs, err := os.ReadDir(LocalCommon.UploadsDir())
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	threshold := time.Now().Add(-cmd.MaxAge)
+	aborted := 0
+
+	for _, bucketDir := range bucketDirs {
+		sessionDirs, err := os.ReadDir(filepath.Join(LocalCommon.UploadsDir(), bucketDir.Name()))
+		if err != nil {
+			return aborted, err
+		}
+		for _, sessionDir := range sessionDirs {
+			if err := ctx.Err(); err != nil {
+				return aborted, err
+			}
+
+			sessionPath := filepath.Join(LocalCommon.UploadsDir(), bucketDir.Name(), sessionDir.Name())
+			session, err := LocalCommon.ReadUploadSession(sessionPath)
+			// Upload may be completed or aborted concurrently
+			if errors.Is(err, FileApplication.ErrUploadDoesNotExist) {
+				continue
+			}
+			if err != nil {
+				return aborted, err
+			}
+			if session.Initiated.After(threshold) {
+				continue
+			}
+			if err := os.RemoveAll(sessionPath); err != nil {
+				return aborted, err
+			}
+			aborted++
+		}
+	}
+
+	return aborted, nil
+}
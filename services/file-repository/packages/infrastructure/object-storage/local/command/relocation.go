@@ -0,0 +1,248 @@
+package localcommand
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	FileApplication "vega_file_repository/packages/application/file"
+	"vega_file_repository/packages/domain/entity"
+	LocalCommon "vega_file_repository/packages/infrastructure/object-storage/local/common"
+
+	cqrs "github.com/abaxoth0/Vega/libs/go/packages/CQRS"
+	errs "github.com/abaxoth0/Vega/libs/go/packages/erorrs"
+	"github.com/abaxoth0/Vega/libs/go/packages/file"
+)
+
+type relocatedFile struct {
+	sourcePath          string
+	sourceFilePath      string
+	destinationPath     string
+	destinationFilePath string
+}
+
+func (h *defaultCommandHandler) CopyFiles(cmd *FileApplication.CopyFilesCommand) (*entity.RelocationReport, error) {
+	return h.relocateFiles(&cmd.CommandQuery, &cmd.FilesRelocation, false)
+}
+
+func (h *defaultCommandHandler) MoveFiles(cmd *FileApplication.MoveFilesCommand) (*entity.RelocationReport, error) {
+	return h.relocateFiles(&cmd.CommandQuery, &cmd.FilesRelocation, true)
+}
+
+// Same as in MinIO driver files are relocated one by one. Files are moved by renaming,
+// so unlike object storages moved file is never present in both source and destination.
+func (h *defaultCommandHandler) relocateFiles(
+	commandQuery *cqrs.CommandQuery,
+	relocation *FileApplication.FilesRelocation,
+	move bool,
+) (*entity.RelocationReport, error) {
+	if err := relocation.Validate(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := h.preprocessCommandQuery(commandQuery)
+	defer cancel()
+
+	if err := LocalCommon.IsBucketExist(relocation.SourceBucket); err != nil {
+		return nil, err
+	}
+	if err := LocalCommon.IsBucketExist(relocation.DestinationBucket); err != nil {
+		return nil, err
+	}
+
+	files, err := h.listRelocatedFiles(relocation)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, errs.StatusNotFound
+	}
+
+	skip := make([]bool, len(files))
+	if relocation.Overwrite != FileApplication.OverwriteReplace {
+		for i, f := range files {
+			_, err := os.Stat(f.destinationFilePath)
+			if err != nil && !LocalCommon.IsNotExist(err) {
+				return nil, err
+			}
+			exists := err == nil
+			if exists && relocation.Overwrite == FileApplication.OverwriteFail {
+				return nil, FileApplication.ErrDestinationExists
+			}
+			skip[i] = exists
+		}
+	}
+
+	sourceBucketPath, err := LocalCommon.BucketFilePath(relocation.SourceBucket)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &entity.RelocationReport{
+		Files: make([]entity.RelocatedFile, len(files)),
+	}
+
+	for i, f := range files {
+		relocated := &report.Files[i]
+		relocated.SourcePath = f.sourcePath
+		relocated.DestinationPath = f.destinationPath
+
+		if skip[i] {
+			relocated.Skipped = true
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+
+		// Content isn't changed, so it has the same checksum
+		checksum, err := h.sourceChecksum(relocation, f)
+		if err != nil {
+			relocated.Error = err.Error()
+			continue
+		}
+		if err := h.preserveVersions(relocation, f, move); err != nil {
+			relocated.Error = err.Error()
+			continue
+		}
+
+		if move {
+			err = LocalCommon.MoveFile(f.sourceFilePath, f.destinationFilePath)
+			if err == nil {
+				LocalCommon.RemoveEmptyDirs(sourceBucketPath, filepath.Dir(f.sourceFilePath))
+				LocalCommon.PruneChecksums(relocation.SourceBucket, f.sourcePath, f.sourceFilePath)
+			}
+		} else {
+			err = h.copyFile(f.sourceFilePath, f.destinationFilePath)
+		}
+		if err == nil {
+			err = LocalCommon.StoreChecksum(relocation.DestinationBucket, f.destinationPath, f.destinationFilePath, checksum)
+		}
+		if err != nil {
+			relocated.Error = err.Error()
+		}
+	}
+
+	if len(report.Failures()) > 0 {
+		return report, FileApplication.ErrPartialRelocation
+	}
+
+	return report, nil
+}
+
+// Lists all files (including directory markers) which must be relocated.
+func (h *defaultCommandHandler) listRelocatedFiles(relocation *FileApplication.FilesRelocation) ([]relocatedFile, error) {
+	newRelocatedFile := func(sourcePath string) (relocatedFile, error) {
+		f := relocatedFile{
+			sourcePath:      sourcePath,
+			destinationPath: relocation.DestinationOf(sourcePath),
+		}
+		var err error
+		if f.sourceFilePath, err = LocalCommon.StoredFilePath(relocation.SourceBucket, f.sourcePath); err != nil {
+			return f, err
+		}
+		f.destinationFilePath, err = LocalCommon.StoredFilePath(relocation.DestinationBucket, f.destinationPath)
+		return f, err
+	}
+
+	if !file.IsDirectory(relocation.SourcePath) {
+		f, err := newRelocatedFile(relocation.SourcePath)
+		if err != nil {
+			return nil, err
+		}
+		if info, err := os.Stat(f.sourceFilePath); err != nil || info.IsDir() {
+			if err == nil || LocalCommon.IsNotExist(err) {
+				return []relocatedFile{}, nil
+			}
+			return nil, err
+		}
+		return []relocatedFile{f}, nil
+	}
+
+	bucketPath, err := LocalCommon.BucketFilePath(relocation.SourceBucket)
+	if err != nil {
+		return nil, err
+	}
+	dirPath, err := LocalCommon.ObjectFilePath(relocation.SourceBucket, relocation.SourcePath)
+	if err != nil {
+		return nil, err
+	}
+
+	files := []relocatedFile{}
+
+	if info, err := os.Stat(dirPath); err != nil || !info.IsDir() {
+		if err == nil || LocalCommon.IsNotExist(err) {
+			return files, nil
+		}
+		return nil, err
+	}
+
+	err = filepath.WalkDir(dirPath, func(filePath string, dirEntry fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if dirEntry.IsDir() {
+			return nil
+		}
+
+		path := LocalCommon.ObjectPath(bucketPath, filePath, false)
+		if dirEntry.Name() == LocalCommon.DirectoryMarkerName {
+			path = LocalCommon.ObjectPath(bucketPath, filepath.Dir(filePath), true)
+		}
+
+		f, err := newRelocatedFile(path)
+		if err != nil {
+			return err
+		}
+		files = append(files, f)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return files, nil
+}
+
+// Overwritten destination file and moved source file must be kept as versions if it's enabled.
+func (h *defaultCommandHandler) preserveVersions(
+	relocation *FileApplication.FilesRelocation,
+	f relocatedFile,
+	move bool,
+) error {
+	err := LocalCommon.PreserveVersion(relocation.DestinationBucket, f.destinationPath, f.destinationFilePath)
+	if err != nil || !move {
+		return err
+	}
+	return LocalCommon.PreserveVersion(relocation.SourceBucket, f.sourcePath, f.sourceFilePath)
+}
+
+func (h *defaultCommandHandler) sourceChecksum(
+	relocation *FileApplication.FilesRelocation,
+	f relocatedFile,
+) (*entity.Checksum, error) {
+	info, err := os.Stat(f.sourceFilePath)
+	if err != nil {
+		return nil, err
+	}
+	return LocalCommon.ReadChecksum(relocation.SourceBucket, f.sourcePath, info)
+}
+
+func (h *defaultCommandHandler) copyFile(source string, target string) error {
+	f, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	return LocalCommon.WriteFile(target, f, info.Size())
+}
@@ -0,0 +1,151 @@
+package localcommand
+
+import (
+	"os"
+	"path/filepath"
+	FileApplication "vega_file_repository/packages/application/file"
+	LocalCommon "vega_file_repository/packages/infrastructure/object-storage/local/common"
+
+	errs "github.com/abaxoth0/Vega/libs/go/packages/erorrs"
+)
+
+func (h *defaultCommandHandler) SetBucketVersioning(cmd *FileApplication.SetBucketVersioningCommand) error {
+	_, cancel := h.preprocessCommandQuery(&cmd.CommandQuery)
+	defer cancel()
+
+	if err := LocalCommon.IsBucketExist(cmd.Name); err != nil {
+		return err
+	}
+
+	metadata, err := LocalCommon.ReadBucketMetadata(cmd.Name)
+	if err != nil {
+		return err
+	}
+	metadata.Versioning = cmd.Enabled
+
+	return LocalCommon.WriteBucketMetadata(cmd.Name, metadata)
+}
+
+// Version is written over the file the same way as regular update, so it becomes a new current version.
+func (h *defaultCommandHandler) RestoreFileVersion(cmd *FileApplication.RestoreFileVersionCommand) (string, error) {
+	if err := cmd.Validate(); err != nil {
+		return "", err
+	}
+	_, cancel := h.preprocessCommandQuery(&cmd.CommandQuery)
+	defer cancel()
+
+	if err := LocalCommon.IsBucketExist(cmd.Bucket); err != nil {
+		return "", err
+	}
+
+	filePath, err := LocalCommon.ObjectFilePath(cmd.Bucket, cmd.Path)
+	if err != nil {
+		return "", err
+	}
+	versionPath, _, err := LocalCommon.VersionFilePath(cmd.Bucket, cmd.Path, cmd.VersionID)
+	if err != nil {
+		if LocalCommon.IsNotExist(err) {
+			return "", errs.StatusNotFound
+		}
+		return "", err
+	}
+
+	f, err := os.Open(versionPath)
+	if err != nil {
+		if LocalCommon.IsNotExist(err) {
+			return "", errs.StatusNotFound
+		}
+		return "", err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return "", err
+	}
+
+	checksum, err := LocalCommon.ReadChecksum(cmd.Bucket, cmd.Path, info)
+	if err != nil {
+		return "", err
+	}
+
+	if err := LocalCommon.PreserveVersion(cmd.Bucket, cmd.Path, filePath); err != nil {
+		return "", err
+	}
+	if err := LocalCommon.WriteFile(filePath, f, info.Size()); err != nil {
+		return "", err
+	}
+	if err := LocalCommon.StoreChecksum(cmd.Bucket, cmd.Path, filePath, checksum); err != nil {
+		return "", err
+	}
+
+	restored, err := os.Stat(filePath)
+	if err != nil {
+		return "", err
+	}
+
+	return LocalCommon.ETag(restored), nil
+}
+
+// Same as in MinIO driver, deletion of non-existing version isn't considered as error.
+func (h *defaultCommandHandler) DeleteFileVersion(cmd *FileApplication.DeleteFileVersionCommand) error {
+	if err := cmd.Validate(); err != nil {
+		return err
+	}
+	_, cancel := h.preprocessCommandQuery(&cmd.CommandQuery)
+	defer cancel()
+
+	if err := LocalCommon.IsBucketExist(cmd.Bucket); err != nil {
+		return err
+	}
+
+	versionPath, isCurrent, err := LocalCommon.VersionFilePath(cmd.Bucket, cmd.Path, cmd.VersionID)
+	if err != nil {
+		if LocalCommon.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	if err := os.Remove(versionPath); err != nil && !LocalCommon.IsNotExist(err) {
+		return err
+	}
+
+	if isCurrent {
+		if err := h.promotePreviousVersion(cmd.Bucket, cmd.Path, versionPath); err != nil {
+			return err
+		}
+	}
+
+	// Fails if there are other versions left
+	os.Remove(LocalCommon.FileVersionsDir(cmd.Bucket, cmd.Path))
+
+	filePath, err := LocalCommon.ObjectFilePath(cmd.Bucket, cmd.Path)
+	if err != nil {
+		return err
+	}
+	LocalCommon.PruneChecksums(cmd.Bucket, cmd.Path, filePath)
+
+	return nil
+}
+
+// Makes the newest of previous versions current (the same as object storages do
+// when the current version is deleted). Versions are renamed, so their IDs are kept.
+func (h *defaultCommandHandler) promotePreviousVersion(bucket string, path string, filePath string) error {
+	previous, err := LocalCommon.PreviousVersions(bucket, path)
+	if err != nil {
+		return err
+	}
+
+	bucketPath, err := LocalCommon.BucketFilePath(bucket)
+	if err != nil {
+		return err
+	}
+
+	if len(previous) == 0 {
+		LocalCommon.RemoveEmptyDirs(bucketPath, filepath.Dir(filePath))
+		return nil
+	}
+
+	return LocalCommon.MoveFile(filepath.Join(LocalCommon.FileVersionsDir(bucket, path), previous[0].Name()), filePath)
+}
@@ -0,0 +1,64 @@
+package localcommon
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// File system doesn't track creation time of directories (at least not portably),
+// so it's stored separately from the bucket.
+type BucketMetadata struct {
+	CreationDate time.Time `json:"creation_date"`
+	Versioning   bool      `json:"versioning"`
+}
+
+func bucketMetadataFilePath(bucket string) string {
+	return filepath.Join(storage.Root, systemDirName, "buckets", bucket+".json")
+}
+
+// If bucket has no metadata (e.g. its directory was created manually),
+// then modification time of its directory is used as creation date.
+func ReadBucketMetadata(bucket string) (*BucketMetadata, error) {
+	bucketPath, err := BucketFilePath(bucket)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(bucketMetadataFilePath(bucket))
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+		info, err := os.Stat(bucketPath)
+		if err != nil {
+			return nil, err
+		}
+		return &BucketMetadata{CreationDate: info.ModTime()}, nil
+	}
+
+	metadata := new(BucketMetadata)
+	if err := json.Unmarshal(data, metadata); err != nil {
+		return nil, err
+	}
+
+	return metadata, nil
+}
+
+func WriteBucketMetadata(bucket string, metadata *BucketMetadata) error {
+	data, err := json.Marshal(metadata)
+	if err != nil {
+		return err
+	}
+	return WriteFile(bucketMetadataFilePath(bucket), bytes.NewReader(data), int64(len(data)))
+}
+
+func DeleteBucketMetadata(bucket string) error {
+	if err := os.Remove(bucketMetadataFilePath(bucket)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
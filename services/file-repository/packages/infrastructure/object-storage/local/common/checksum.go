@@ -0,0 +1,104 @@
+package localcommon
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"vega_file_repository/packages/domain/entity"
+)
+
+// Checksums of files are stored as "<ChecksumsDir>/<hash of the file path>/<ETag of the file>".
+// Checksum is bound to ETag, so it's never mixed up with checksum of the other content of the same file.
+// Versions of the file have the same ETag as the file they were created from, so they share its checksum.
+
+type storedChecksum struct {
+	Algorithm string `json:"algorithm"`
+	Value     string `json:"value"`
+}
+
+func ChecksumsDir(bucket string) string {
+	return filepath.Join(storage.Root, systemDirName, "checksums", bucket)
+}
+
+func fileChecksumsDir(bucket string, path string) string {
+	return filepath.Join(ChecksumsDir(bucket), pathHash(path))
+}
+
+// Stores checksum of the current content of the file and removes checksums which aren't needed anymore.
+// If checksum is nil, then only the latter is done.
+func StoreChecksum(bucket string, path string, filePath string, checksum *entity.Checksum) error {
+	defer PruneChecksums(bucket, path, filePath)
+
+	if checksum == nil {
+		return nil
+	}
+
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(storedChecksum{
+		Algorithm: checksum.Algorithm,
+		Value:     checksum.Value,
+	})
+	if err != nil {
+		return err
+	}
+
+	return WriteFile(filepath.Join(fileChecksumsDir(bucket, path), ETag(info)), bytes.NewReader(data), int64(len(data)))
+}
+
+// Returns checksum of the file content described by info (it may be a version of the file as well),
+// if there is no such checksum returns nil.
+func ReadChecksum(bucket string, path string, info os.FileInfo) (*entity.Checksum, error) {
+	data, err := os.ReadFile(filepath.Join(fileChecksumsDir(bucket, path), ETag(info)))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	stored := storedChecksum{}
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return nil, err
+	}
+
+	return &entity.Checksum{
+		Algorithm: stored.Algorithm,
+		Value:     stored.Value,
+	}, nil
+}
+
+// Removes checksums of content which neither is the current content of the file nor one of its versions.
+// Must be called each time file is overwritten or deleted. Failures are ignored, since stale checksums
+// are never read (ETag of the new content is different), they just take up space.
+func PruneChecksums(bucket string, path string, filePath string) {
+	dir := fileChecksumsDir(bucket, path)
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+
+	current := ""
+	if info, err := os.Stat(filePath); err == nil && !info.IsDir() {
+		current = ETag(info)
+	}
+
+	for _, entry := range entries {
+		if entry.Name() == current {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(FileVersionsDir(bucket, path), entry.Name())); err == nil {
+			continue
+		}
+		os.Remove(filepath.Join(dir, entry.Name()))
+	}
+
+	// Fails if there are checksums left
+	os.Remove(dir)
+}
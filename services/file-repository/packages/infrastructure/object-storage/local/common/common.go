@@ -0,0 +1,314 @@
+package localcommon
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"syscall"
+	FileApplication "vega_file_repository/packages/application/file"
+	LocalConnection "vega_file_repository/packages/infrastructure/object-storage/local/connection"
+)
+
+var storage = LocalConnection.Manager
+
+// Buckets are stored as directories in the root of the storage. Bucket names can't start with '.',
+// so there won't be any collisions between buckets and this directory.
+const systemDirName = ".vega"
+
+// Directory markers (created by Mkdir) are stored as empty files with this name.
+// Objects (and directories) with this name can't be created in local storage.
+const DirectoryMarkerName = ".vega-directory"
+
+var (
+	ErrInvalidBucketName   = FileApplication.ErrInvalidBucketName
+	ErrBucketAlreadyExists = FileApplication.ErrBucketAlreadyExists
+	ErrBucketIsNotEmpty    = FileApplication.ErrBucketIsNotEmpty
+	ErrInvalidObjectPath   = fmt.Errorf("%w: it can't contain empty, \".\", \"..\" or \"%s\" segments", FileApplication.ErrInvalidPath, DirectoryMarkerName)
+	// Unlike object storages, file system can't have file and directory with the same path
+	ErrPathConflict = FileApplication.ErrPathConflict
+)
+
+// Same rules as in S3
+var bucketNameRegexp = regexp.MustCompile(`^[a-z0-9][a-z0-9.-]{1,61}[a-z0-9]$`)
+
+func ValidateBucketName(bucket string) error {
+	if !bucketNameRegexp.MatchString(bucket) || strings.Contains(bucket, "..") {
+		return ErrInvalidBucketName
+	}
+	return nil
+}
+
+func BucketFilePath(bucket string) (string, error) {
+	if err := ValidateBucketName(bucket); err != nil {
+		return "", err
+	}
+	return filepath.Join(storage.Root, bucket), nil
+}
+
+func IsBucketExist(bucket string) error {
+	bucketPath, err := BucketFilePath(bucket)
+	if err != nil {
+		return err
+	}
+	info, err := os.Stat(bucketPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return FileApplication.ErrBucketDoesNotExist
+		}
+		return err
+	}
+	if !info.IsDir() {
+		return FileApplication.ErrBucketDoesNotExist
+	}
+	return nil
+}
+
+// Returns paths of all existing buckets
+func BucketFilePaths() ([]string, error) {
+	entries, err := os.ReadDir(storage.Root)
+	if err != nil {
+		return nil, err
+	}
+
+	buckets := []string{}
+	for _, entry := range entries {
+		if entry.IsDir() && ValidateBucketName(entry.Name()) == nil {
+			buckets = append(buckets, filepath.Join(storage.Root, entry.Name()))
+		}
+	}
+
+	return buckets, nil
+}
+
+// Converts path of the object into path of the file where it's stored.
+// For directories returns path of the directory itself, not of its marker.
+func ObjectFilePath(bucket string, path string) (string, error) {
+	bucketPath, err := BucketFilePath(bucket)
+	if err != nil {
+		return "", err
+	}
+
+	key := strings.TrimPrefix(path, "/")
+	if key == "" {
+		return bucketPath, nil
+	}
+	for _, segment := range strings.Split(strings.TrimSuffix(key, "/"), "/") {
+		if segment == "" || segment == "." || segment == ".." || segment == DirectoryMarkerName {
+			return "", ErrInvalidObjectPath
+		}
+	}
+
+	return filepath.Join(bucketPath, filepath.FromSlash(key)), nil
+}
+
+// Reverse of ObjectFilePath
+func ObjectPath(bucketPath string, filePath string, isDirectory bool) string {
+	rel, err := filepath.Rel(bucketPath, filePath)
+	if err != nil || rel == "." {
+		return "/"
+	}
+	path := "/" + filepath.ToSlash(rel)
+	if isDirectory {
+		path += "/"
+	}
+	return path
+}
+
+// Besides regular "not exists" errors, path may be unreachable if one of its parents is a file.
+func IsNotExist(err error) bool {
+	return errors.Is(err, os.ErrNotExist) || errors.Is(err, syscall.ENOTDIR)
+}
+
+func MarkerFilePath(dirFilePath string) string {
+	return filepath.Join(dirFilePath, DirectoryMarkerName)
+}
+
+// Returns path of the file where object is stored, for directories it's a path of their marker.
+func StoredFilePath(bucket string, path string) (string, error) {
+	filePath, err := ObjectFilePath(bucket, path)
+	if err != nil {
+		return "", err
+	}
+	if strings.HasSuffix(path, "/") {
+		return MarkerFilePath(filePath), nil
+	}
+	return filePath, nil
+}
+
+// Files aren't hashed on write, so ETag is derived from the modification time and size
+// (the same way as many HTTP servers do). It still changes each time the file is overwritten.
+func ETag(info os.FileInfo) string {
+	return fmt.Sprintf("%x-%x", info.ModTime().UnixNano(), info.Size())
+}
+
+// Temporary files must be on the same file system as the storage itself,
+// otherwise they can't be atomically renamed.
+func TempDir() string {
+	return filepath.Join(storage.Root, systemDirName, "tmp")
+}
+
+// Upload sessions are stored as "<UploadsDir>/<bucket>/<upload id>/"
+func UploadsDir() string {
+	return filepath.Join(storage.Root, systemDirName, "uploads")
+}
+
+// Atomically writes exactly size bytes of content into file at target (parent directories
+// are created if needed). Content is written into temporary file first, which then renamed into target,
+// so readers will see either old or new content of the file, but never partially written one.
+func WriteFile(target string, content io.Reader, size int64) error {
+	_, err := WriteFileIf(target, content, size, nil)
+	return err
+}
+
+// Condition of the write, info is nil if target doesn't exist
+type WriteCondition func(info os.FileInfo) error
+
+// Same as WriteFile, but content is committed only if condition (may be nil) is satisfied,
+// otherwise error of the condition is returned. Commits of the same target are serialized,
+// so target can't be changed by other write between the check and the commit.
+// Returns info of the written file.
+func WriteFileIf(target string, content io.Reader, size int64, condition WriteCondition) (os.FileInfo, error) {
+	missingDir, err := makeParentDirs(target)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(TempDir(), 0o755); err != nil {
+		return nil, err
+	}
+
+	tmp, err := os.CreateTemp(TempDir(), "write-*")
+	if err != nil {
+		return nil, err
+	}
+	committed := false
+	defer func() {
+		if !committed {
+			tmp.Close()
+			os.Remove(tmp.Name())
+			removeParentDirs(missingDir, target)
+		}
+	}()
+
+	if content == nil {
+		content = strings.NewReader("")
+	}
+	n, err := io.Copy(tmp, io.LimitReader(content, size))
+	if err != nil {
+		return nil, err
+	}
+	if n != size {
+		return nil, fmt.Errorf("expected %d bytes of content, but got %d", size, n)
+	}
+	if extra, _ := content.Read(make([]byte, 1)); extra > 0 {
+		return nil, fmt.Errorf("content is bigger than declared size (%d bytes)", size)
+	}
+	if err := tmp.Sync(); err != nil {
+		return nil, err
+	}
+	if err := tmp.Close(); err != nil {
+		return nil, err
+	}
+
+	unlock := lockTarget(target)
+	defer unlock()
+
+	if condition != nil {
+		info, err := os.Stat(target)
+		if err != nil && !IsNotExist(err) {
+			return nil, err
+		}
+		if err := condition(info); err != nil {
+			return nil, err
+		}
+	}
+	if err := os.Rename(tmp.Name(), target); err != nil {
+		return nil, err
+	}
+	committed = true
+
+	return os.Stat(target)
+}
+
+// Commits are short, so all targets share small set of locks
+var targetLocks [256]sync.Mutex
+
+func lockTarget(target string) func() {
+	hash := fnv.New32a()
+	hash.Write([]byte(target))
+	mu := &targetLocks[hash.Sum32()%uint32(len(targetLocks))]
+	mu.Lock()
+	return mu.Unlock
+}
+
+// Atomically moves file from source to target (parent directories of target are created if needed).
+// Both must be on the same file system. Existing target file is replaced.
+func MoveFile(source string, target string) error {
+	missingDir, err := makeParentDirs(target)
+	if err != nil {
+		return err
+	}
+	if err := os.Rename(source, target); err != nil {
+		removeParentDirs(missingDir, target)
+		return err
+	}
+	return nil
+}
+
+// Creates all missing parent directories of the target file.
+// Returns the top-most of created directories (empty if none was created).
+func makeParentDirs(target string) (string, error) {
+	if info, err := os.Stat(target); err == nil && info.IsDir() {
+		return "", ErrPathConflict
+	}
+	missingDir := missingAncestor(filepath.Dir(target))
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		if errors.Is(err, syscall.ENOTDIR) || errors.Is(err, syscall.EEXIST) {
+			return "", ErrPathConflict
+		}
+		return "", err
+	}
+	return missingDir, nil
+}
+
+// Reverts makeParentDirs() if file wasn't written after all
+func removeParentDirs(missingDir string, target string) {
+	if missingDir != "" {
+		RemoveEmptyDirs(filepath.Dir(missingDir), filepath.Dir(target))
+	}
+}
+
+// Returns the top-most ancestor of dir (or dir itself) which doesn't exist,
+// or empty string if dir exists.
+func missingAncestor(dir string) string {
+	missing := ""
+	for {
+		if _, err := os.Stat(dir); err == nil {
+			return missing
+		}
+		missing = dir
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return missing
+		}
+		dir = parent
+	}
+}
+
+// Object storages have no real directories: they exist only while there are objects in them.
+// To keep the same behaviour, empty directories are removed starting from dir and up to
+// (but not including) the bucket directory.
+func RemoveEmptyDirs(bucketPath string, dir string) {
+	for dir != bucketPath && strings.HasPrefix(dir, bucketPath) {
+		// Fails if directory isn't empty
+		if err := os.Remove(dir); err != nil {
+			return
+		}
+		dir = filepath.Dir(dir)
+	}
+}
@@ -0,0 +1,138 @@
+package localcommon
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	FileApplication "vega_file_repository/packages/application/file"
+	"vega_file_repository/packages/domain/entity"
+)
+
+const (
+	uploadSessionFileName = "session.json"
+	uploadPartFilePrefix  = "part-"
+)
+
+type UploadSession struct {
+	Path      string    `json:"path"`
+	Initiated time.Time `json:"initiated"`
+}
+
+func NewUploadID() (string, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(id), nil
+}
+
+// Returns path of the directory where upload session is stored
+func UploadSessionDir(bucket string, uploadID string) (string, error) {
+	if err := ValidateBucketName(bucket); err != nil {
+		return "", err
+	}
+	// Upload ID is used as a directory name, so it must not contain anything except hex digits
+	if _, err := hex.DecodeString(uploadID); err != nil || uploadID == "" {
+		return "", FileApplication.ErrUploadDoesNotExist
+	}
+	return filepath.Join(UploadsDir(), bucket, uploadID), nil
+}
+
+func UploadPartFilePath(sessionDir string, partNumber int) string {
+	return filepath.Join(sessionDir, fmt.Sprintf("%s%05d", uploadPartFilePrefix, partNumber))
+}
+
+func WriteUploadSession(sessionDir string, session *UploadSession) error {
+	data, err := json.Marshal(session)
+	if err != nil {
+		return err
+	}
+	return WriteFile(filepath.Join(sessionDir, uploadSessionFileName), bytes.NewReader(data), int64(len(data)))
+}
+
+func ReadUploadSession(sessionDir string) (*UploadSession, error) {
+	data, err := os.ReadFile(filepath.Join(sessionDir, uploadSessionFileName))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, FileApplication.ErrUploadDoesNotExist
+		}
+		return nil, err
+	}
+
+	session := new(UploadSession)
+	if err := json.Unmarshal(data, session); err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+// Returns directory of the upload session if it exists and belongs to the specified path.
+func OpenUploadSession(bucket string, path string, uploadID string) (string, error) {
+	sessionDir, err := UploadSessionDir(bucket, uploadID)
+	if err != nil {
+		return "", err
+	}
+
+	session, err := ReadUploadSession(sessionDir)
+	if err != nil {
+		return "", err
+	}
+	if session.Path != path {
+		return "", FileApplication.ErrUploadDoesNotExist
+	}
+
+	return sessionDir, nil
+}
+
+// Returns parts ordered by their numbers
+func UploadParts(sessionDir string) ([]entity.UploadPart, error) {
+	dirEntries, err := os.ReadDir(sessionDir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, FileApplication.ErrUploadDoesNotExist
+		}
+		return nil, err
+	}
+
+	parts := []entity.UploadPart{}
+	for _, dirEntry := range dirEntries {
+		name, ok := strings.CutPrefix(dirEntry.Name(), uploadPartFilePrefix)
+		if !ok {
+			continue
+		}
+		partNumber, err := strconv.Atoi(name)
+		if err != nil {
+			continue
+		}
+		info, err := dirEntry.Info()
+		if err != nil {
+			// Upload was completed or aborted concurrently
+			if errors.Is(err, os.ErrNotExist) {
+				return nil, FileApplication.ErrUploadDoesNotExist
+			}
+			return nil, err
+		}
+		parts = append(parts, entity.UploadPart{
+			PartNumber:   partNumber,
+			ETag:         ETag(info),
+			Size:         info.Size(),
+			LastModified: info.ModTime(),
+		})
+	}
+
+	slices.SortFunc(parts, func(a, b entity.UploadPart) int {
+		return a.PartNumber - b.PartNumber
+	})
+
+	return parts, nil
+}
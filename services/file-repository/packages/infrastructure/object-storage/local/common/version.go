@@ -0,0 +1,142 @@
+package localcommon
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+
+	"github.com/abaxoth0/Vega/libs/go/packages/file"
+)
+
+// File system has no versions, so previous versions of the file are kept in the system directory
+// as "<VersionsDir>/<bucket>/<hash of the file path>/<version id>". Current version of the file is
+// the file itself. ID of the version is ETag of its content, since it's unique for each write
+// and isn't changed when file is linked or renamed (which is how versions are created and restored).
+//
+// Unlike object storages, deletion of the file doesn't create delete marker,
+// so deleted file just has no current version.
+
+var ErrInvalidVersionID = errors.New("invalid version ID")
+
+// Same format as ETag()
+var versionIDRegexp = regexp.MustCompile(`^[0-9a-f]+-[0-9a-f]+$`)
+
+func VersionsDir(bucket string) string {
+	return filepath.Join(storage.Root, systemDirName, "versions", bucket)
+}
+
+// Paths are hashed, so versions of "/a" and "/a/b" won't be mixed up
+func FileVersionsDir(bucket string, path string) string {
+	return filepath.Join(VersionsDir(bucket), pathHash(path))
+}
+
+func pathHash(path string) string {
+	hash := sha256.Sum256([]byte(path))
+	return hex.EncodeToString(hash[:])
+}
+
+func IsVersioningEnabled(bucket string) (bool, error) {
+	metadata, err := ReadBucketMetadata(bucket)
+	if err != nil {
+		return false, err
+	}
+	return metadata.Versioning, nil
+}
+
+// Must be called before the file at filePath is overwritten or deleted. If versioning of the bucket
+// is enabled, then current content of the file is hard linked into its versions directory,
+// so it stays available after the file itself is replaced. Directory markers have no versions.
+func PreserveVersion(bucket string, path string, filePath string) error {
+	if file.IsDirectory(path) {
+		return nil
+	}
+	enabled, err := IsVersioningEnabled(bucket)
+	if err != nil || !enabled {
+		return err
+	}
+
+	info, err := os.Stat(filePath)
+	if err != nil {
+		if IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	if info.IsDir() {
+		return nil
+	}
+
+	dir := FileVersionsDir(bucket, path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	// Version may be already preserved if previous write of the file has failed
+	if err := os.Link(filePath, filepath.Join(dir, ETag(info))); err != nil && !errors.Is(err, os.ErrExist) {
+		return err
+	}
+
+	return nil
+}
+
+// Returns previous versions of the file ordered from the newest to the oldest one.
+// Name of each returned file is its version ID.
+func PreviousVersions(bucket string, path string) ([]os.FileInfo, error) {
+	entries, err := os.ReadDir(FileVersionsDir(bucket, path))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return []os.FileInfo{}, nil
+		}
+		return nil, err
+	}
+
+	versions := make([]os.FileInfo, 0, len(entries))
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil {
+			// Version was deleted after directory was read
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return nil, err
+		}
+		versions = append(versions, info)
+	}
+
+	slices.SortFunc(versions, func(a, b os.FileInfo) int {
+		return b.ModTime().Compare(a.ModTime())
+	})
+
+	return versions, nil
+}
+
+// Returns path of the file where specified version of the object is stored, it's either
+// the object file itself (if it's the current version) or one of its previous versions.
+// Returns os.ErrNotExist if there is no such version.
+func VersionFilePath(bucket string, path string, versionID string) (filePath string, isCurrent bool, err error) {
+	if !versionIDRegexp.MatchString(versionID) {
+		return "", false, ErrInvalidVersionID
+	}
+
+	filePath, err = ObjectFilePath(bucket, path)
+	if err != nil {
+		return "", false, err
+	}
+	info, err := os.Stat(filePath)
+	if err != nil && !IsNotExist(err) {
+		return "", false, err
+	}
+	if err == nil && !info.IsDir() && ETag(info) == versionID {
+		return filePath, true, nil
+	}
+
+	versionPath := filepath.Join(FileVersionsDir(bucket, path), versionID)
+	if _, err := os.Stat(versionPath); err != nil {
+		return "", false, err
+	}
+
+	return versionPath, false, nil
+}
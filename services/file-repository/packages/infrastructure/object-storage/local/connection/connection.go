@@ -0,0 +1,69 @@
+package localconnection
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	StorageConnection "vega_file_repository/packages/infrastructure/object-storage/connection"
+)
+
+var Manager = &defaultConnectionManager{
+	status: StorageConnection.Disconnected,
+}
+
+type defaultConnectionManager struct {
+	// Absolute path to the directory where all buckets are stored
+	Root   string
+	status StorageConnection.Status
+}
+
+func (m *defaultConnectionManager) Status() StorageConnection.Status {
+	return m.status
+}
+
+// For local storage cfg.URL is a path to the root directory (optionally prefixed with "file://"),
+// it will be created if it doesn't exist. Credentials are ignored.
+func (m *defaultConnectionManager) Connect(cfg *StorageConnection.Config) error {
+	root := strings.TrimPrefix(cfg.URL, "file://")
+	if root == "" {
+		return errors.New("root directory of the local storage isn't specified")
+	}
+
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return err
+	}
+
+	m.Root = root
+	m.status = StorageConnection.Connected
+
+	return nil
+}
+
+// There is nothing to close, since files are opened only for the duration of operations.
+func (m *defaultConnectionManager) Disconnect() error {
+	m.status = StorageConnection.Disconnected
+	return nil
+}
+
+// Local disk has no network latency, so timeout is ignored.
+func (m *defaultConnectionManager) Ping(timeout time.Duration) error {
+	if m.status != StorageConnection.Connected {
+		return errors.New("local storage isn't connected")
+	}
+
+	info, err := os.Stat(m.Root)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return errors.New("root of the local storage isn't a directory")
+	}
+
+	return nil
+}
@@ -0,0 +1,25 @@
+package local
+
+import (
+	FileApplication "vega_file_repository/packages/application/file"
+	StorageConnection "vega_file_repository/packages/infrastructure/object-storage/connection"
+	LocalCommand "vega_file_repository/packages/infrastructure/object-storage/local/command"
+	LocalConnection "vega_file_repository/packages/infrastructure/object-storage/local/connection"
+	LocalQuery "vega_file_repository/packages/infrastructure/object-storage/local/query"
+)
+
+// Stores buckets as directories on local disk. Intended for development and tests,
+// so they can be run without object storage containers.
+type Driver struct {
+	StorageConnection.Manager
+	FileApplication.QueryHandler
+	FileApplication.CommandHandler
+}
+
+func InitDriver() *Driver {
+	return &Driver{
+		Manager:        LocalConnection.Manager,
+		QueryHandler:   LocalQuery.Handler,
+		CommandHandler: LocalCommand.Handler,
+	}
+}
@@ -0,0 +1,1352 @@
+package local
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+	FileApplication "vega_file_repository/packages/application/file"
+	"vega_file_repository/packages/domain/entity"
+	StorageConnection "vega_file_repository/packages/infrastructure/object-storage/connection"
+	LocalCommon "vega_file_repository/packages/infrastructure/object-storage/local/common"
+
+	cqrs "github.com/abaxoth0/Vega/libs/go/packages/CQRS"
+)
+
+func connect(t *testing.T, driver *Driver) error {
+	return driver.Connect(&StorageConnection.Config{
+		URL: t.TempDir(),
+	})
+}
+
+func TestObjectStorageDriver(t *testing.T) {
+	driver := InitDriver()
+
+	t.Run("Connection", func(t *testing.T) {
+		t.Log("Checking default driver status...")
+		if status := driver.Status(); status != StorageConnection.Disconnected {
+			t.Errorf("Invalid driver status, expected \"disconnected\", but got \"%s\"", status.String())
+		}
+		t.Log("Checking default driver status: OK")
+
+		t.Log("Testing driver.Connect()...")
+		if err := connect(t, driver); err != nil {
+			t.Errorf("Connection failed: %v", err)
+		}
+		t.Log("Testing Driver.Connect(): OK")
+
+		t.Log("Checking updated driver status...")
+		if status := driver.Status(); status != StorageConnection.Connected {
+			t.Errorf("Invalid driver status, expected \"connected\", but got \"%s\"", status.String())
+		}
+		t.Log("Checking updated driver status: OK")
+
+		t.Log("Ping connection...")
+		if err := driver.Ping(time.Second * 5); err != nil {
+			t.Errorf("Failed to ping storage: %v", err)
+		}
+		t.Log("Ping connection: OK")
+
+		t.Log("Testing Driver.Disconnect()...")
+		if err := driver.Disconnect(); err != nil {
+			t.Errorf("Failed to gracefully disconnected from storage: %v", err)
+		}
+		t.Log("Testing Driver.Disconnect(): OK")
+	})
+}
+
+// Calls handler over each item of iter. Runs each iteration in new goroutine.
+// Blocks until all handlers finish their jobs.
+func asyncProcess[T any](iter []T, handler func(index int, value T)) {
+	wg := new(sync.WaitGroup)
+	for i, v := range iter {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			handler(i, v)
+		}()
+	}
+	time.Sleep(time.Millisecond * 10)
+	wg.Wait()
+}
+
+func TestUseCasesImplementation(t *testing.T) {
+	driver := InitDriver()
+	if err := connect(t, driver); err != nil {
+		t.Fatalf("Connection failed")
+	}
+	bucketName := "vega-auto-test-" + strconv.FormatInt(time.Now().UnixMilli(), 10)
+	t.Log("Test bucket name: " + bucketName)
+
+	t.Run("MakeBucket()", func(t *testing.T) {
+		t.Log("Testing Driver.MakeBucket()...")
+		err := driver.MakeBucket(&FileApplication.MakeBucketCommand{
+			Name: bucketName,
+		})
+		if err != nil {
+			t.Errorf("Failed to create a new bucket: %v", err)
+		}
+		t.Log("Testing Driver.MakeBucket(): OK")
+
+	})
+
+	type testInputs struct {
+		path    string
+		invalid bool
+		empty   bool
+		size    int64
+	}
+
+	pathOverflow := new(strings.Builder)
+	segmentOverflow := new(strings.Builder)
+	pathOverflow.WriteByte('/')
+	segmentOverflow.WriteByte('/')
+
+	for i := range 1100 {
+		pathOverflow.WriteByte('a')
+
+		segmentOverflow.WriteByte('a')
+		if i%300 == 0 {
+			segmentOverflow.WriteByte('/')
+		}
+	}
+
+	commonInvalidInputs := []testInputs{
+		{path: "", invalid: true},
+		{path: "/", invalid: true},
+		{path: "//", invalid: true},
+		{path: "///", invalid: true},
+		{path: ".", invalid: true},
+		{path: ".", invalid: true},
+		{path: "./", invalid: true},
+		{path: pathOverflow.String(), invalid: true},
+		{path: segmentOverflow.String(), invalid: true},
+	}
+
+	var err error
+
+	t.Run("Mkdir()", func(t *testing.T) {
+		dirInputs := append(commonInvalidInputs, []testInputs{
+			{path: "/direcotry/"},
+			{path: "/direcotry", invalid: true},
+		}...)
+
+		for _, input := range dirInputs {
+			err = driver.Mkdir(&FileApplication.MkdirCommand{
+				Bucket: bucketName,
+				Path:   input.path,
+			})
+			if (err != nil && input.invalid) || (err == nil && !input.invalid) {
+				continue
+			}
+			t.Errorf("Invalid Driver.Mkdir() result. Should fail - %t. Error: %v", input.invalid, err)
+		}
+	})
+
+	filesPaths := []string{}
+	filesPathsMu := new(sync.Mutex)
+
+	fileContent := "Lorem ipsum dolor sit amet, consectetur adipiscing elit, sed do eiusmod tempor incididunt ut labore et dolore magna aliqua. Ut enim ad minim veniam, quis nostrud exercitation ullamco laboris nisi ut aliquip ex ea commodo consequat. Duis aute irure dolor in reprehenderit in voluptate velit esse cillum dolore eu fugiat nulla pariatur. Excepteur sint occaecat cupidatat non proident, sunt in culpa qui officia deserunt mollit anim id est laborum."
+
+	t.Run("UploadFile()", func(t *testing.T) {
+		fileInputs := append(commonInvalidInputs, []testInputs{
+			{path: "/test-file.txt"},
+			{path: "/f"},
+			{path: "/dir/file.txt"},
+			{path: "/dir/some.dir/f"},
+			{path: "/some-file", empty: true},
+			{path: "/some/dir/file.txt", empty: true},
+			{path: "/and/another/one", size: 100},
+		}...)
+
+		asyncProcess(fileInputs, func(i int, input testInputs) {
+			t.Logf("Uploading \"%s\"...", input.path)
+			cmd := FileApplication.UploadFileCommand{
+				Bucket: bucketName,
+				Path:   input.path,
+			}
+			if !input.empty {
+				cmd.Content = strings.NewReader(fileContent)
+			} else {
+				input.invalid = true
+			}
+			if input.size == 0 {
+				cmd.ContentSize = int64(len(fileContent))
+			} else {
+				cmd.ContentSize = input.size
+				if cmd.ContentSize != int64(len(fileContent)) {
+					input.invalid = true
+				}
+			}
+			if !input.invalid {
+				filesPathsMu.Lock()
+				filesPaths = append(filesPaths, input.path)
+				filesPathsMu.Unlock()
+			}
+			_, e := driver.UploadFile(&cmd)
+			// Allow invalid inputs to be used, but ignore the result.
+			// Just to see will it cause panic or some unexpected behaviour or not.
+			if e != nil && !input.invalid {
+				t.Errorf("Failed to upload file \"%s\": %v", input.path, e)
+			}
+		})
+	})
+
+	t.Run("Path traversal", func(t *testing.T) {
+		paths := []string{"/../escape.txt", "/dir/../../escape.txt", "/./escape.txt", "/dir//escape.txt", "/" + LocalCommon.DirectoryMarkerName}
+		for _, path := range paths {
+			_, err := driver.UploadFile(&FileApplication.UploadFileCommand{
+				Bucket:      bucketName,
+				Path:        path,
+				Content:     strings.NewReader(fileContent),
+				ContentSize: int64(len(fileContent)),
+			})
+			if !errors.Is(err, LocalCommon.ErrInvalidObjectPath) {
+				t.Errorf("Upload of \"%s\" must be rejected, but got: %v", path, err)
+			}
+		}
+		_, err := driver.GetFileByPath(&FileApplication.GetFileByPathQuery{
+			Bucket: bucketName,
+			Path:   "/../" + bucketName + "/test-file.txt",
+		})
+		if !errors.Is(err, LocalCommon.ErrInvalidObjectPath) {
+			t.Errorf("Reading outside of the bucket must be rejected, but got: %v", err)
+		}
+	})
+
+	t.Run("Failed write", func(t *testing.T) {
+		_, err := driver.UploadFile(&FileApplication.UploadFileCommand{
+			Bucket:      bucketName,
+			Path:        "/failed/write.txt",
+			Content:     strings.NewReader(fileContent),
+			ContentSize: int64(len(fileContent)) + 1,
+		})
+		if err == nil {
+			t.Fatalf("Upload with invalid size must fail")
+		}
+		listing, err := driver.ListDirectory(&FileApplication.ListDirectoryQuery{
+			Bucket: bucketName,
+			Path:   "/",
+		})
+		if err != nil {
+			t.Fatalf("Failed to list directory: %v", err)
+		}
+		for _, entry := range listing.Entries {
+			if strings.HasPrefix(entry.Path, "/failed/") {
+				t.Errorf("Failed write left \"%s\" behind", entry.Path)
+			}
+		}
+	})
+
+	t.Run("GetFileByPath()", func(t *testing.T) {
+		asyncProcess(filesPaths, func(_ int, path string) {
+			cmd := FileApplication.GetFileByPathQuery{
+				Bucket: bucketName,
+				Path:   path,
+			}
+			file, err := driver.GetFileByPath(&cmd)
+			if err != nil {
+				t.Errorf("Failed to get file \"%s\": %v", path, err)
+			}
+			if file.Size > 0 {
+				content, err := io.ReadAll(file.Content)
+				if err != nil {
+					t.Errorf("Failed to read content of \"%s\": %v", path, err)
+				}
+				if string(content) != fileContent {
+					t.Errorf("File content doesn't match")
+				}
+			}
+		})
+	})
+
+	t.Run("GetFileByPath() range", func(t *testing.T) {
+		rangeInputs := []struct {
+			offset   int64
+			length   int64
+			expected string
+			invalid  bool
+		}{
+			{offset: 6, length: 5, expected: fileContent[6:11]},
+			{offset: 0, length: 5, expected: fileContent[:5]},
+			{offset: int64(len(fileContent)) - 8, expected: fileContent[len(fileContent)-8:]},
+			{offset: int64(len(fileContent)) - 8, length: 100, expected: fileContent[len(fileContent)-8:]},
+			{offset: int64(len(fileContent)), invalid: true},
+			{offset: -1, invalid: true},
+			{length: -1, invalid: true},
+		}
+		for _, input := range rangeInputs {
+			stream, err := driver.GetFileByPath(&FileApplication.GetFileByPathQuery{
+				Bucket: bucketName,
+				Path:   "/test-file.txt",
+				Offset: input.offset,
+				Length: input.length,
+			})
+			if input.invalid {
+				if err == nil {
+					stream.Cancel()
+					t.Errorf("Range (offset %d, length %d) must be invalid", input.offset, input.length)
+				}
+				continue
+			}
+			if err != nil {
+				t.Errorf("Failed to get range (offset %d, length %d): %v", input.offset, input.length, err)
+				continue
+			}
+			content, err := io.ReadAll(stream.Content)
+			stream.Cancel()
+			if err != nil {
+				t.Errorf("Failed to read range (offset %d, length %d): %v", input.offset, input.length, err)
+				continue
+			}
+			if string(content) != input.expected {
+				t.Errorf("Range content doesn't match, expected \"%s\", but got \"%s\"", input.expected, content)
+			}
+			if stream.Range.Length() != int64(len(input.expected)) {
+				t.Errorf("Invalid served range: %+v", stream.Range)
+			}
+		}
+	})
+
+	t.Run("GetFileByPath() directory archive", func(t *testing.T) {
+		stream, err := driver.GetFileByPath(&FileApplication.GetFileByPathQuery{
+			Bucket:        bucketName,
+			Path:          "/dir/",
+			ArchiveFormat: FileApplication.ArchiveFormatTar,
+		})
+		if err != nil {
+			t.Fatalf("Failed to get directory archive: %v", err)
+		}
+		defer stream.Cancel()
+
+		expected := map[string]bool{
+			"dir/":           false,
+			"dir/file.txt":   false,
+			"dir/some.dir/f": false,
+		}
+		reader := tar.NewReader(stream.Content)
+		for {
+			header, err := reader.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatalf("Failed to read archive: %v", err)
+			}
+			if _, ok := expected[header.Name]; !ok {
+				t.Errorf("Unexpected archive entry \"%s\"", header.Name)
+				continue
+			}
+			expected[header.Name] = true
+			if header.Typeflag == tar.TypeReg {
+				content, err := io.ReadAll(reader)
+				if err != nil {
+					t.Fatalf("Failed to read archive entry \"%s\": %v", header.Name, err)
+				}
+				if string(content) != fileContent {
+					t.Errorf("Content of archive entry \"%s\" doesn't match", header.Name)
+				}
+			}
+		}
+		for name, found := range expected {
+			if !found {
+				t.Errorf("Archive entry \"%s\" is missing", name)
+			}
+		}
+
+		_, err = driver.GetFileByPath(&FileApplication.GetFileByPathQuery{
+			Bucket: bucketName,
+			Path:   "/dir/",
+		})
+		if err == nil {
+			t.Errorf("Directory must not be sent without archive format")
+		}
+	})
+
+	t.Run("ListDirectory()", func(t *testing.T) {
+		listing, err := driver.ListDirectory(&FileApplication.ListDirectoryQuery{
+			Bucket: bucketName,
+			Path:   "/dir/",
+		})
+		if err != nil {
+			t.Fatalf("Failed to list directory: %v", err)
+		}
+		expected := map[string]bool{
+			"/dir/file.txt":  false,
+			"/dir/some.dir/": true,
+		}
+		if len(listing.Entries) != len(expected) {
+			t.Fatalf("Expected %d entries, but got %d: %+v", len(expected), len(listing.Entries), listing.Entries)
+		}
+		for _, entry := range listing.Entries {
+			isDir, ok := expected[entry.Path]
+			if !ok {
+				t.Errorf("Unexpected entry \"%s\"", entry.Path)
+				continue
+			}
+			if entry.IsDirectory != isDir {
+				t.Errorf("Invalid IsDirectory for \"%s\", expected %t", entry.Path, isDir)
+			}
+			if !isDir && entry.Size != int64(len(fileContent)) {
+				t.Errorf("Invalid size of \"%s\": %d", entry.Path, entry.Size)
+			}
+		}
+
+		listing, err = driver.ListDirectory(&FileApplication.ListDirectoryQuery{
+			Bucket:    bucketName,
+			Path:      "/dir/",
+			Recursive: true,
+		})
+		if err != nil {
+			t.Fatalf("Failed to list directory recursively: %v", err)
+		}
+		if len(listing.Entries) != 2 {
+			t.Errorf("Expected 2 entries in recursive listing, but got %d: %+v", len(listing.Entries), listing.Entries)
+		}
+
+		t.Log("Testing pagination...")
+		total := 0
+		token := ""
+		for {
+			listing, err = driver.ListDirectory(&FileApplication.ListDirectoryQuery{
+				Bucket:            bucketName,
+				Path:              "/",
+				Recursive:         true,
+				Limit:             1,
+				ContinuationToken: token,
+			})
+			if err != nil {
+				t.Fatalf("Failed to list directory page: %v", err)
+			}
+			if len(listing.Entries) > 1 {
+				t.Fatalf("Page limit exceeded: %d entries", len(listing.Entries))
+			}
+			total += len(listing.Entries)
+			if !listing.IsTruncated {
+				break
+			}
+			token = listing.NextContinuationToken
+		}
+		// uploaded files + directory created by Mkdir()
+		if total != len(filesPaths)+1 {
+			t.Errorf("Expected %d entries in total, but got %d", len(filesPaths)+1, total)
+		}
+		t.Log("Testing pagination: OK")
+
+		_, err = driver.ListDirectory(&FileApplication.ListDirectoryQuery{
+			Bucket: bucketName,
+			Path:   "/test-file.txt",
+		})
+		if err == nil {
+			t.Errorf("Listing of non-directory path must fail")
+		}
+
+		listing, err = driver.ListDirectory(&FileApplication.ListDirectoryQuery{
+			Bucket: bucketName,
+			Path:   "/missing-dir/",
+		})
+		if err != nil || len(listing.Entries) != 0 {
+			t.Errorf("Missing directory must be listed as empty one, but got: %+v (%v)", listing, err)
+		}
+	})
+
+	t.Run("UploadFile() archive", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		writer := tar.NewWriter(buf)
+		entries := []struct {
+			name     string
+			typeflag byte
+			content  string
+		}{
+			{name: "./", typeflag: tar.TypeDir},
+			{name: "./docs/", typeflag: tar.TypeDir},
+			{name: "./docs/readme.txt", typeflag: tar.TypeReg, content: fileContent},
+			{name: "../escape.txt", typeflag: tar.TypeReg, content: fileContent},
+			{name: "link", typeflag: tar.TypeSymlink},
+		}
+		for _, entry := range entries {
+			err := writer.WriteHeader(&tar.Header{
+				Name:     entry.name,
+				Typeflag: entry.typeflag,
+				Size:     int64(len(entry.content)),
+				Linkname: "/etc/passwd",
+			})
+			if err != nil {
+				t.Fatalf("Failed to create test archive: %v", err)
+			}
+			if _, err := writer.Write([]byte(entry.content)); err != nil {
+				t.Fatalf("Failed to create test archive: %v", err)
+			}
+		}
+		if err := writer.Close(); err != nil {
+			t.Fatalf("Failed to create test archive: %v", err)
+		}
+
+		result, err := driver.UploadFile(&FileApplication.UploadFileCommand{
+			Bucket:        bucketName,
+			Path:          "/extracted/",
+			Content:       buf,
+			ContentSize:   int64(buf.Len()),
+			ArchiveFormat: FileApplication.ArchiveFormatTar,
+		})
+		if !errors.Is(err, FileApplication.ErrPartialExtraction) {
+			t.Fatalf("Expected partial extraction, but got: %v", err)
+		}
+		if len(result.Entries) != 4 {
+			t.Fatalf("Expected 4 entries in result, but got %d: %+v", len(result.Entries), result.Entries)
+		}
+		if failed := result.FailedEntries(); len(failed) != 2 {
+			t.Errorf("Expected 2 rejected entries, but got %d: %+v", len(failed), failed)
+		}
+
+		stream, err := driver.GetFileByPath(&FileApplication.GetFileByPathQuery{
+			Bucket: bucketName,
+			Path:   "/extracted/docs/readme.txt",
+		})
+		if err != nil {
+			t.Fatalf("Failed to get extracted file: %v", err)
+		}
+		defer stream.Cancel()
+		content, err := io.ReadAll(stream.Content)
+		if err != nil {
+			t.Fatalf("Failed to read extracted file: %v", err)
+		}
+		if string(content) != fileContent {
+			t.Errorf("Content of extracted file doesn't match")
+		}
+	})
+
+	t.Run("Resumable upload", func(t *testing.T) {
+		const path = "/multipart.bin"
+
+		uploadID, err := driver.InitiateUpload(&FileApplication.InitiateUploadCommand{
+			Bucket: bucketName,
+			Path:   path,
+		})
+		if err != nil {
+			t.Fatalf("Failed to initiate upload: %v", err)
+		}
+
+		parts := [][]byte{
+			bytes.Repeat([]byte{'a'}, 1024),
+			[]byte(fileContent),
+		}
+		uploadPart := func(number int, content []byte) error {
+			_, err := driver.UploadPart(&FileApplication.UploadPartCommand{
+				Bucket:     bucketName,
+				Path:       path,
+				UploadID:   uploadID,
+				PartNumber: number,
+				Content:    bytes.NewReader(content),
+				Size:       int64(len(content)),
+				CommandQuery: cqrs.CommandQuery{
+					Context:        context.Background(),
+					ContextTimeout: time.Second * 30,
+				},
+			})
+			return err
+		}
+		for i, part := range parts {
+			if err := uploadPart(i+1, part); err != nil {
+				t.Fatalf("Failed to upload part %d: %v", i+1, err)
+			}
+		}
+		t.Log("Retrying last part...")
+		if err := uploadPart(len(parts), parts[len(parts)-1]); err != nil {
+			t.Fatalf("Failed to reupload part: %v", err)
+		}
+		if err := uploadPart(0, parts[0]); err == nil {
+			t.Errorf("Part with invalid number was uploaded")
+		}
+
+		uploaded, err := driver.ListUploadedParts(&FileApplication.ListUploadedPartsQuery{
+			Bucket:   bucketName,
+			Path:     path,
+			UploadID: uploadID,
+		})
+		if err != nil {
+			t.Fatalf("Failed to list uploaded parts: %v", err)
+		}
+		if len(uploaded) != len(parts) {
+			t.Fatalf("Expected %d uploaded parts, but got %d", len(parts), len(uploaded))
+		}
+
+		err = driver.CompleteUpload(&FileApplication.CompleteUploadCommand{
+			Bucket:   bucketName,
+			Path:     path,
+			UploadID: uploadID,
+		})
+		if err != nil {
+			t.Fatalf("Failed to complete upload: %v", err)
+		}
+
+		stream, err := driver.GetFileByPath(&FileApplication.GetFileByPathQuery{
+			Bucket: bucketName,
+			Path:   path,
+		})
+		if err != nil {
+			t.Fatalf("Failed to get uploaded file: %v", err)
+		}
+		stream.Cancel()
+		if stream.Size != int64(len(parts[0])+len(parts[1])) {
+			t.Errorf("Invalid size of uploaded file: %d", stream.Size)
+		}
+
+		t.Log("Testing stale uploads collection...")
+		abortedID, err := driver.InitiateUpload(&FileApplication.InitiateUploadCommand{
+			Bucket: bucketName,
+			Path:   "/abandoned.bin",
+		})
+		if err != nil {
+			t.Fatalf("Failed to initiate upload: %v", err)
+		}
+		time.Sleep(time.Millisecond * 10)
+		if _, err := driver.AbortStaleUploads(&FileApplication.AbortStaleUploadsCommand{
+			MaxAge: time.Millisecond,
+		}); err != nil {
+			t.Fatalf("Failed to abort stale uploads: %v", err)
+		}
+		_, err = driver.ListUploadedParts(&FileApplication.ListUploadedPartsQuery{
+			Bucket:   bucketName,
+			Path:     "/abandoned.bin",
+			UploadID: abortedID,
+		})
+		if !errors.Is(err, FileApplication.ErrUploadDoesNotExist) {
+			t.Errorf("Stale upload wasn't aborted: %v", err)
+		}
+		t.Log("Testing stale uploads collection: OK")
+	})
+
+	readFile := func(t *testing.T, path string) string {
+		stream, err := driver.GetFileByPath(&FileApplication.GetFileByPathQuery{
+			Bucket: bucketName,
+			Path:   path,
+		})
+		if err != nil {
+			t.Fatalf("Failed to get file \"%s\": %v", path, err)
+		}
+		defer stream.Cancel()
+		content, err := io.ReadAll(stream.Content)
+		if err != nil {
+			t.Fatalf("Failed to read content of \"%s\": %v", path, err)
+		}
+		return string(content)
+	}
+
+	t.Run("CopyFiles()", func(t *testing.T) {
+		copyFiles := func(source string, destination string, overwrite FileApplication.OverwriteMode) (*entity.RelocationReport, error) {
+			return driver.CopyFiles(&FileApplication.CopyFilesCommand{
+				FilesRelocation: FileApplication.FilesRelocation{
+					SourceBucket:    bucketName,
+					SourcePath:      source,
+					DestinationPath: destination,
+					Overwrite:       overwrite,
+				},
+			})
+		}
+
+		report, err := copyFiles("/dir/", "/copied/", FileApplication.OverwriteFail)
+		if err != nil {
+			t.Fatalf("Failed to copy directory: %v", err)
+		}
+		if report.RelocatedCount() != 2 {
+			t.Errorf("Expected 2 copied files, but got %d: %+v", report.RelocatedCount(), report.Files)
+		}
+		for _, path := range []string{"/dir/file.txt", "/copied/file.txt", "/copied/some.dir/f"} {
+			if content := readFile(t, path); content != fileContent {
+				t.Errorf("Content of \"%s\" doesn't match", path)
+			}
+		}
+
+		t.Log("Testing overwrite modes...")
+		if _, err := copyFiles("/dir/", "/copied/", FileApplication.OverwriteFail); !errors.Is(err, FileApplication.ErrDestinationExists) {
+			t.Errorf("Expected ErrDestinationExists, but got: %v", err)
+		}
+		report, err = copyFiles("/dir/", "/copied/", FileApplication.OverwriteSkip)
+		if err != nil {
+			t.Fatalf("Failed to copy directory: %v", err)
+		}
+		if report.SkippedCount() != 2 || report.RelocatedCount() != 0 {
+			t.Errorf("Expected 2 skipped files, but got %d (%d copied)", report.SkippedCount(), report.RelocatedCount())
+		}
+		report, err = copyFiles("/test-file.txt", "/copied/file.txt", FileApplication.OverwriteReplace)
+		if err != nil {
+			t.Fatalf("Failed to replace file: %v", err)
+		}
+		if report.RelocatedCount() != 1 {
+			t.Errorf("Expected 1 copied file, but got %d", report.RelocatedCount())
+		}
+		t.Log("Testing overwrite modes: OK")
+
+		if _, err := copyFiles("/dir/", "/dir/nested/", FileApplication.OverwriteFail); !errors.Is(err, FileApplication.ErrRelocationIntoItself) {
+			t.Errorf("Directory must not be copied into itself, but got: %v", err)
+		}
+		if _, err := copyFiles("/dir/", "/file-destination", FileApplication.OverwriteFail); !errors.Is(err, FileApplication.ErrRelocationTypeMismatch) {
+			t.Errorf("Directory must not be copied into file, but got: %v", err)
+		}
+		if _, err := copyFiles("/missing.txt", "/copied/missing.txt", FileApplication.OverwriteFail); err == nil {
+			t.Errorf("Copying of non-existing file must fail")
+		}
+	})
+
+	t.Run("MoveFiles()", func(t *testing.T) {
+		moveFiles := func(source string, destination string) (*entity.RelocationReport, error) {
+			return driver.MoveFiles(&FileApplication.MoveFilesCommand{
+				FilesRelocation: FileApplication.FilesRelocation{
+					SourceBucket:    bucketName,
+					SourcePath:      source,
+					DestinationPath: destination,
+				},
+			})
+		}
+
+		report, err := moveFiles("/copied/", "/moved/")
+		if err != nil {
+			t.Fatalf("Failed to move directory: %v", err)
+		}
+		if report.RelocatedCount() != 2 {
+			t.Errorf("Expected 2 moved files, but got %d: %+v", report.RelocatedCount(), report.Files)
+		}
+		listing, err := driver.ListDirectory(&FileApplication.ListDirectoryQuery{
+			Bucket:    bucketName,
+			Path:      "/copied/",
+			Recursive: true,
+		})
+		if err != nil {
+			t.Fatalf("Failed to list directory: %v", err)
+		}
+		if len(listing.Entries) != 0 {
+			t.Errorf("Source directory wasn't fully moved, remaining entries: %+v", listing.Entries)
+		}
+
+		t.Log("Testing rename...")
+		if _, err := moveFiles("/moved/file.txt", "/moved/renamed.txt"); err != nil {
+			t.Fatalf("Failed to rename file: %v", err)
+		}
+		if content := readFile(t, "/moved/renamed.txt"); content != fileContent {
+			t.Errorf("Content of renamed file doesn't match")
+		}
+		_, err = driver.GetFileByPath(&FileApplication.GetFileByPathQuery{
+			Bucket: bucketName,
+			Path:   "/moved/file.txt",
+		})
+		if err == nil {
+			t.Errorf("Renamed file still exists under old path")
+		}
+		t.Log("Testing rename: OK")
+	})
+
+	newFileContent := []byte("some new file content")
+
+	t.Run("UpdateFileContent()", func(t *testing.T) {
+		asyncProcess(filesPaths, func(_ int, path string) {
+			_, err := driver.UpdateFileContent(&FileApplication.UpdateFileContentCommand{
+				Path:       path,
+				Bucket:     bucketName,
+				NewContent: bytes.NewReader(newFileContent),
+				Size:       int64(len(newFileContent)),
+			})
+			if err != nil {
+				t.Fatalf("Failed to update file content \"%s\": %v", path, err)
+			}
+			file, err := driver.GetFileByPath(&FileApplication.GetFileByPathQuery{
+				Bucket: bucketName,
+				Path:   path,
+			})
+			if err != nil {
+				t.Fatalf("Failed to get file \"%s\": %v", path, err)
+			}
+			if file.Size == 0 {
+				t.Fatalf("Missing file contnent. File \"%s\"", path)
+			}
+			content, err := io.ReadAll(file.Content)
+			if err != nil {
+				t.Fatalf("Failed to read content of \"%s\": %v", path, err)
+			}
+			if string(content) != string(newFileContent) {
+				t.Errorf("New file content doesn't match")
+			}
+		})
+	})
+
+	t.Run("StatFile()", func(t *testing.T) {
+		paths := append([]string{"/", "/missing-file.txt", "/missing-dir/"}, filesPaths...)
+		stats, err := driver.StatFile(&FileApplication.StatFileQuery{
+			Bucket: bucketName,
+			Paths:  paths,
+		})
+		if err != nil {
+			t.Fatalf("Failed to stat files: %v", err)
+		}
+		if len(stats) != len(paths) {
+			t.Fatalf("Expected %d stats, but got %d", len(paths), len(stats))
+		}
+		if !stats[0].Exists || !stats[0].IsDirectory {
+			t.Errorf("Root of the bucket must exist: %+v", stats[0])
+		}
+		if stats[1].Exists || stats[2].Exists {
+			t.Errorf("Missing files must be reported as not existing: %+v, %+v", stats[1], stats[2])
+		}
+		for i, stat := range stats[3:] {
+			if stat.Path != filesPaths[i] {
+				t.Errorf("Stats must be ordered as requested paths, expected \"%s\", but got \"%s\"", filesPaths[i], stat.Path)
+			}
+			if !stat.Exists || stat.Size != int64(len(newFileContent)) || stat.ETag == "" || stat.LastModified.IsZero() {
+				t.Errorf("Invalid stat of \"%s\": %+v", stat.Path, stat)
+			}
+		}
+
+		invalid := []*FileApplication.StatFileQuery{
+			{Bucket: bucketName},
+			{Bucket: bucketName, Paths: []string{"no-slash"}},
+			{Bucket: bucketName, Paths: []string{"/a", "/b"}, VersionID: "1"},
+		}
+		for _, query := range invalid {
+			if _, err := driver.StatFile(query); err == nil {
+				t.Errorf("Query must be rejected: %+v", query)
+			}
+		}
+		if _, err := driver.StatFile(&FileApplication.StatFileQuery{Bucket: bucketName + "-missing", Paths: []string{"/"}}); err == nil {
+			t.Errorf("Stat in missing bucket must fail")
+		}
+	})
+
+	t.Run("Conditional writes", func(t *testing.T) {
+		path := "/conditional.txt"
+		write := func(content string, preconditions FileApplication.WritePreconditions) (*entity.UploadResult, error) {
+			return driver.UpdateFileContent(&FileApplication.UpdateFileContentCommand{
+				Bucket:        bucketName,
+				Path:          path,
+				NewContent:    strings.NewReader(content),
+				Size:          int64(len(content)),
+				Preconditions: preconditions,
+			})
+		}
+
+		created, err := driver.UploadFile(&FileApplication.UploadFileCommand{
+			Bucket:        bucketName,
+			Path:          path,
+			Content:       strings.NewReader("v1"),
+			ContentSize:   2,
+			Preconditions: FileApplication.WritePreconditions{IfNoneMatch: true},
+		})
+		if err != nil {
+			t.Fatalf("Failed to create file: %v", err)
+		}
+		if created.ETag == "" {
+			t.Fatalf("ETag of the written file must be returned")
+		}
+		if _, err := write("v1-again", FileApplication.WritePreconditions{IfNoneMatch: true}); !errors.Is(err, FileApplication.ErrPreconditionFailed) {
+			t.Errorf("Create-only write of existing file must fail with ErrPreconditionFailed, but got: %v", err)
+		}
+		if _, err := write("stale", FileApplication.WritePreconditions{IfMatch: "stale-etag"}); !errors.Is(err, FileApplication.ErrPreconditionFailed) {
+			t.Errorf("Write with stale ETag must fail with ErrPreconditionFailed, but got: %v", err)
+		}
+
+		updated, err := write("v2-content", FileApplication.WritePreconditions{IfMatch: "\"" + created.ETag + "\""})
+		if err != nil {
+			t.Fatalf("Write with current ETag must succeed: %v", err)
+		}
+		if updated.ETag == "" || updated.ETag == created.ETag {
+			t.Errorf("New ETag must be returned, got \"%s\" (previous one is \"%s\")", updated.ETag, created.ETag)
+		}
+		if _, err := write("v3", FileApplication.WritePreconditions{IfMatch: created.ETag}); !errors.Is(err, FileApplication.ErrPreconditionFailed) {
+			t.Errorf("Write with outdated ETag must fail with ErrPreconditionFailed, but got: %v", err)
+		}
+		if _, err := write("v3", FileApplication.WritePreconditions{IfUnmodifiedSince: time.Now().Add(-time.Hour)}); !errors.Is(err, FileApplication.ErrPreconditionFailed) {
+			t.Errorf("Write of file modified since must fail with ErrPreconditionFailed, but got: %v", err)
+		}
+		if _, err := write("v3-content", FileApplication.WritePreconditions{IfUnmodifiedSince: time.Now().Add(time.Hour)}); err != nil {
+			t.Errorf("Write of unmodified file must succeed: %v", err)
+		}
+
+		stats, err := driver.StatFile(&FileApplication.StatFileQuery{Bucket: bucketName, Paths: []string{path}})
+		if err != nil {
+			t.Fatalf("Failed to stat file: %v", err)
+		}
+		if stats[0].Size != int64(len("v3-content")) {
+			t.Errorf("Failed writes mustn't change the file, expected size %d, but got %d", len("v3-content"), stats[0].Size)
+		}
+
+		// Only one of the writers which saw the same version of the file may succeed
+		var succeeded atomic.Int32
+		asyncProcess(make([]string, 8), func(i int, _ string) {
+			_, err := write("concurrent-"+strconv.Itoa(i), FileApplication.WritePreconditions{IfMatch: stats[0].ETag})
+			if err == nil {
+				succeeded.Add(1)
+			} else if !errors.Is(err, FileApplication.ErrPreconditionFailed) {
+				t.Errorf("Concurrent write failed with unexpected error: %v", err)
+			}
+		})
+		if n := succeeded.Load(); n != 1 {
+			t.Errorf("Exactly one of the concurrent conditional writes must succeed, but %d did", n)
+		}
+
+		missing := "/conditional-missing.txt"
+		_, err = driver.UpdateFileContent(&FileApplication.UpdateFileContentCommand{
+			Bucket:        bucketName,
+			Path:          missing,
+			NewContent:    strings.NewReader("content"),
+			Size:          7,
+			Preconditions: FileApplication.WritePreconditions{IfMatch: "*"},
+		})
+		if !errors.Is(err, FileApplication.ErrPreconditionFailed) {
+			t.Errorf("Write of missing file with \"If-Match: *\" must fail with ErrPreconditionFailed, but got: %v", err)
+		}
+		if _, err := write("v4", FileApplication.WritePreconditions{IfNoneMatch: true, IfMatch: "*"}); !errors.Is(err, FileApplication.ErrConflictingPreconditions) {
+			t.Errorf("Conflicting preconditions must be rejected, but got: %v", err)
+		}
+
+		if _, err := driver.DeleteFiles(&FileApplication.DeleteFilesCommand{Bucket: bucketName, Paths: []string{path}}); err != nil {
+			t.Errorf("Failed to delete file: %v", err)
+		}
+	})
+
+	t.Run("DeleteFiles()", func(t *testing.T) {
+		asyncProcess(filesPaths, func(_ int, path string) {
+			_, err := driver.DeleteFiles(&FileApplication.DeleteFilesCommand{
+				Bucket: bucketName,
+				Paths:  []string{path},
+			})
+			if err != nil {
+				t.Errorf("Failed to delete file \"%s\": %v", path, err)
+			}
+		})
+	})
+
+	t.Run("DeleteFiles() recursive", func(t *testing.T) {
+		err = driver.Mkdir(&FileApplication.MkdirCommand{
+			Bucket: bucketName,
+			Path:   "/recursive/",
+		})
+		if err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		for _, path := range []string{"/recursive/a.txt", "/recursive/sub/b.txt"} {
+			_, err = driver.UploadFile(&FileApplication.UploadFileCommand{
+				Bucket:      bucketName,
+				Path:        path,
+				Content:     strings.NewReader(fileContent),
+				ContentSize: int64(len(fileContent)),
+			})
+			if err != nil {
+				t.Fatalf("Failed to upload file \"%s\": %v", path, err)
+			}
+		}
+
+		report, err := driver.DeleteFiles(&FileApplication.DeleteFilesCommand{
+			Bucket:    bucketName,
+			Paths:     []string{"/recursive/"},
+			Recursive: true,
+		})
+		if err != nil {
+			t.Fatalf("Failed to delete directory recursively: %v", err)
+		}
+		// 2 files + directory marker
+		if report.DeletedCount() != 3 {
+			t.Errorf("Expected 3 deleted objects, but got %d", report.DeletedCount())
+		}
+
+		listing, err := driver.ListDirectory(&FileApplication.ListDirectoryQuery{
+			Bucket:    bucketName,
+			Path:      "/recursive/",
+			Recursive: true,
+		})
+		if err != nil {
+			t.Fatalf("Failed to list directory: %v", err)
+		}
+		if len(listing.Entries) != 0 {
+			t.Errorf("Directory wasn't fully deleted, remaining entries: %+v", listing.Entries)
+		}
+	})
+
+	t.Run("Directory markers", func(t *testing.T) {
+		err = driver.Mkdir(&FileApplication.MkdirCommand{
+			Bucket: bucketName,
+			Path:   "/marked/",
+		})
+		if err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		_, err = driver.UploadFile(&FileApplication.UploadFileCommand{
+			Bucket:      bucketName,
+			Path:        "/marked/file.txt",
+			Content:     strings.NewReader(fileContent),
+			ContentSize: int64(len(fileContent)),
+		})
+		if err != nil {
+			t.Fatalf("Failed to upload file: %v", err)
+		}
+
+		isListed := func(path string) bool {
+			listing, err := driver.ListDirectory(&FileApplication.ListDirectoryQuery{
+				Bucket: bucketName,
+				Path:   "/",
+			})
+			if err != nil {
+				t.Fatalf("Failed to list directory: %v", err)
+			}
+			for _, entry := range listing.Entries {
+				if entry.Path == path {
+					return true
+				}
+			}
+			return false
+		}
+
+		// Only marker is deleted, directory still has a file in it
+		if _, err := driver.DeleteFiles(&FileApplication.DeleteFilesCommand{
+			Bucket: bucketName,
+			Paths:  []string{"/marked/"},
+		}); err != nil {
+			t.Fatalf("Failed to delete directory marker: %v", err)
+		}
+		if !isListed("/marked/") {
+			t.Errorf("Non-empty directory disappeared after deletion of its marker")
+		}
+
+		if _, err := driver.DeleteFiles(&FileApplication.DeleteFilesCommand{
+			Bucket: bucketName,
+			Paths:  []string{"/marked/file.txt"},
+		}); err != nil {
+			t.Fatalf("Failed to delete file: %v", err)
+		}
+		if isListed("/marked/") {
+			t.Errorf("Empty directory without marker must not exist")
+		}
+	})
+
+	t.Run("Versioning", func(t *testing.T) {
+		path := "/versioned-file.txt"
+
+		readVersion := func(versionID string) (string, error) {
+			stream, err := driver.GetFileByPath(&FileApplication.GetFileByPathQuery{
+				Bucket:    bucketName,
+				Path:      path,
+				VersionID: versionID,
+			})
+			if err != nil {
+				return "", err
+			}
+			defer stream.Cancel()
+			content, err := io.ReadAll(stream.Content)
+			return string(content), err
+		}
+		listVersions := func() []entity.FileVersion {
+			versions, err := driver.ListFileVersions(&FileApplication.ListFileVersionsQuery{
+				Bucket: bucketName,
+				Path:   path,
+			})
+			if err != nil {
+				t.Fatalf("Failed to list versions: %v", err)
+			}
+			return versions
+		}
+
+		err := driver.SetBucketVersioning(&FileApplication.SetBucketVersioningCommand{
+			Name:    bucketName,
+			Enabled: true,
+		})
+		if err != nil {
+			t.Fatalf("Failed to enable versioning: %v", err)
+		}
+		info, err := driver.GetBucketInfo(&FileApplication.GetBucketInfoQuery{Name: bucketName})
+		if err != nil {
+			t.Fatalf("Failed to get bucket info: %v", err)
+		}
+		if !info.VersioningEnabled {
+			t.Errorf("Versioning must be enabled")
+		}
+
+		_, err = driver.UploadFile(&FileApplication.UploadFileCommand{
+			Bucket:      bucketName,
+			Path:        path,
+			Content:     strings.NewReader("first"),
+			ContentSize: 5,
+		})
+		if err != nil {
+			t.Fatalf("Failed to upload file: %v", err)
+		}
+		_, err = driver.UpdateFileContent(&FileApplication.UpdateFileContentCommand{
+			Bucket:     bucketName,
+			Path:       path,
+			NewContent: strings.NewReader("second"),
+			Size:       6,
+		})
+		if err != nil {
+			t.Fatalf("Failed to update file: %v", err)
+		}
+
+		versions := listVersions()
+		if len(versions) != 2 {
+			t.Fatalf("Expected 2 versions, but got %d", len(versions))
+		}
+		if !versions[0].IsLatest || versions[1].IsLatest {
+			t.Errorf("Only the newest version must be latest")
+		}
+		if content, err := readVersion(versions[1].VersionID); err != nil || content != "first" {
+			t.Errorf("Invalid content of previous version: %q (%v)", content, err)
+		}
+		if content, err := readVersion(""); err != nil || content != "second" {
+			t.Errorf("Invalid content of current version: %q (%v)", content, err)
+		}
+		if _, err := readVersion(versions[1].VersionID + "0"); err == nil {
+			t.Errorf("Reading of non-existing version must fail")
+		}
+
+		restoredID, err := driver.RestoreFileVersion(&FileApplication.RestoreFileVersionCommand{
+			FileVersionTarget: FileApplication.FileVersionTarget{
+				Bucket:    bucketName,
+				Path:      path,
+				VersionID: versions[1].VersionID,
+			},
+		})
+		if err != nil {
+			t.Fatalf("Failed to restore version: %v", err)
+		}
+		if content, err := readVersion(""); err != nil || content != "first" {
+			t.Errorf("Invalid content of restored version: %q (%v)", content, err)
+		}
+		versions = listVersions()
+		if len(versions) != 3 || versions[0].VersionID != restoredID {
+			t.Errorf("Restored version must be a new current version, but got: %+v", versions)
+		}
+
+		// Previous version must become current
+		err = driver.DeleteFileVersion(&FileApplication.DeleteFileVersionCommand{
+			FileVersionTarget: FileApplication.FileVersionTarget{
+				Bucket:    bucketName,
+				Path:      path,
+				VersionID: restoredID,
+			},
+		})
+		if err != nil {
+			t.Fatalf("Failed to delete version: %v", err)
+		}
+		if content, err := readVersion(""); err != nil || content != "second" {
+			t.Errorf("Invalid content of current version after deletion of the latest one: %q (%v)", content, err)
+		}
+
+		_, err = driver.DeleteFiles(&FileApplication.DeleteFilesCommand{
+			Bucket: bucketName,
+			Paths:  []string{path},
+		})
+		if err != nil {
+			t.Fatalf("Failed to delete file: %v", err)
+		}
+		if _, err := readVersion(""); err == nil {
+			t.Errorf("Deleted file must not have current version")
+		}
+		versions = listVersions()
+		if len(versions) < 2 {
+			t.Errorf("Versions must be kept after deletion of the file, but got: %+v", versions)
+		}
+
+		for _, version := range versions {
+			err := driver.DeleteFileVersion(&FileApplication.DeleteFileVersionCommand{
+				FileVersionTarget: FileApplication.FileVersionTarget{
+					Bucket:    bucketName,
+					Path:      path,
+					VersionID: version.VersionID,
+				},
+			})
+			if err != nil {
+				t.Errorf("Failed to delete version: %v", err)
+			}
+		}
+		_, err = driver.ListFileVersions(&FileApplication.ListFileVersionsQuery{
+			Bucket: bucketName,
+			Path:   path,
+		})
+		if err == nil {
+			t.Errorf("File must have no versions left")
+		}
+
+		err = driver.SetBucketVersioning(&FileApplication.SetBucketVersioningCommand{
+			Name:    bucketName,
+			Enabled: false,
+		})
+		if err != nil {
+			t.Errorf("Failed to disable versioning: %v", err)
+		}
+	})
+
+	t.Run("Checksum verification", func(t *testing.T) {
+		path := "/checksum-test.txt"
+		missingPath := "/checksum-mismatch-test.txt"
+		content := "verified content"
+		digest := sha256.Sum256([]byte(content))
+		checksum := entity.Checksum{
+			Algorithm: FileApplication.ChecksumSHA256,
+			Value:     hex.EncodeToString(digest[:]),
+		}
+		wrongChecksum := entity.Checksum{
+			Algorithm: FileApplication.ChecksumSHA256,
+			Value:     strings.Repeat("0", len(checksum.Value)),
+		}
+
+		expected := checksum
+		result, err := driver.UploadFile(&FileApplication.UploadFileCommand{
+			Bucket:      bucketName,
+			Path:        path,
+			Content:     strings.NewReader(content),
+			ContentSize: int64(len(content)),
+			Checksum:    &expected,
+		})
+		if err != nil {
+			t.Fatalf("Failed to upload file with valid checksum: %v", err)
+		}
+		if result.Checksum == nil || *result.Checksum != checksum {
+			t.Errorf("Invalid computed checksum: %+v", result.Checksum)
+		}
+
+		stream, err := driver.GetFileByPath(&FileApplication.GetFileByPathQuery{
+			Bucket: bucketName,
+			Path:   path,
+		})
+		if err != nil {
+			t.Fatalf("Failed to get file: %v", err)
+		}
+		stream.Cancel()
+		if stream.Checksum == nil || *stream.Checksum != checksum {
+			t.Errorf("Invalid stored checksum: %+v", stream.Checksum)
+		}
+
+		mismatched := wrongChecksum
+		_, err = driver.UploadFile(&FileApplication.UploadFileCommand{
+			Bucket:      bucketName,
+			Path:        missingPath,
+			Content:     strings.NewReader(content),
+			ContentSize: int64(len(content)),
+			Checksum:    &mismatched,
+		})
+		if !errors.Is(err, FileApplication.ErrChecksumMismatch) {
+			t.Errorf("Expected checksum mismatch, but got: %v", err)
+		}
+		if _, err := driver.GetFileByPath(&FileApplication.GetFileByPathQuery{
+			Bucket: bucketName,
+			Path:   missingPath,
+		}); err == nil {
+			t.Errorf("File with mismatched checksum must not be stored")
+		}
+
+		mismatched = wrongChecksum
+		_, err = driver.UpdateFileContent(&FileApplication.UpdateFileContentCommand{
+			Bucket:     bucketName,
+			Path:       path,
+			NewContent: strings.NewReader("other content"),
+			Size:       int64(len("other content")),
+			Checksum:   &mismatched,
+		})
+		if !errors.Is(err, FileApplication.ErrChecksumMismatch) {
+			t.Errorf("Expected checksum mismatch, but got: %v", err)
+		}
+		stream, err = driver.GetFileByPath(&FileApplication.GetFileByPathQuery{
+			Bucket: bucketName,
+			Path:   path,
+		})
+		if err != nil {
+			t.Fatalf("Failed to get file: %v", err)
+		}
+		data, err := io.ReadAll(stream.Content)
+		stream.Cancel()
+		if err != nil || string(data) != content {
+			t.Errorf("File must be left untouched after failed update, but got: %q (%v)", data, err)
+		}
+
+		_, err = driver.DeleteFiles(&FileApplication.DeleteFilesCommand{
+			Bucket: bucketName,
+			Paths:  []string{path},
+		})
+		if err != nil {
+			t.Errorf("Failed to delete file: %v", err)
+		}
+	})
+
+	t.Run("ListBuckets() and GetBucketInfo()", func(t *testing.T) {
+		buckets, err := driver.ListBuckets(&FileApplication.ListBucketsQuery{})
+		if err != nil {
+			t.Fatalf("Failed to list buckets: %v", err)
+		}
+		found := false
+		for _, bucket := range buckets {
+			if bucket.Name == bucketName {
+				found = true
+				if bucket.CreationDate.IsZero() {
+					t.Errorf("Creation date of the bucket is missing")
+				}
+			}
+		}
+		if !found {
+			t.Fatalf("Test bucket is missing in the list of buckets")
+		}
+
+		info, err := driver.GetBucketInfo(&FileApplication.GetBucketInfoQuery{
+			Name: bucketName,
+		})
+		if err != nil {
+			t.Fatalf("Failed to get bucket info: %v", err)
+		}
+		listing, err := driver.ListDirectory(&FileApplication.ListDirectoryQuery{
+			Bucket:    bucketName,
+			Path:      "/",
+			Recursive: true,
+		})
+		if err != nil {
+			t.Fatalf("Failed to list bucket: %v", err)
+		}
+		var totalSize int64
+		for _, entry := range listing.Entries {
+			totalSize += entry.Size
+		}
+		if info.ObjectCount != int64(len(listing.Entries)) || info.TotalSize != totalSize {
+			t.Errorf(
+				"Invalid bucket info, expected %d objects of %d bytes, but got %d objects of %d bytes",
+				len(listing.Entries), totalSize, info.ObjectCount, info.TotalSize,
+			)
+		}
+
+		_, err = driver.GetBucketInfo(&FileApplication.GetBucketInfoQuery{
+			Name: bucketName + "-missing",
+		})
+		if err == nil {
+			t.Errorf("Getting info of non-existing bucket must fail")
+		}
+	})
+
+	t.Run("DeleteBucket()", func(t *testing.T) {
+		err = driver.Mkdir(&FileApplication.MkdirCommand{
+			Bucket: bucketName,
+			Path:   "/prevent-bucket-deletion/"},
+		)
+		if err != nil {
+			t.Errorf("Error: faield to create directory for preventing bucket deletion")
+		}
+		err = driver.DeleteBucket(&FileApplication.DeleteBucketCommand{
+			Name: bucketName,
+		})
+		if err == nil {
+			t.Errorf("Error: non-empty bucket was deleted")
+		}
+		err = driver.DeleteBucket(&FileApplication.DeleteBucketCommand{
+			Name:  bucketName,
+			Force: true,
+		})
+		if err != nil {
+			t.Errorf("Failed to delete bucket: %v", err)
+		}
+	})
+
+	if err := driver.Disconnect(); err != nil {
+		t.Logf("Failed to disconnect: %v", err)
+	}
+}
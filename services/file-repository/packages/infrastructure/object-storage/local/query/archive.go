@@ -0,0 +1,139 @@
+package localquery
+
+import (
+	"context"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+	FileApplication "vega_file_repository/packages/application/file"
+	"vega_file_repository/packages/domain/entity"
+	"vega_file_repository/packages/infrastructure/archive"
+	LocalCommon "vega_file_repository/packages/infrastructure/object-storage/local/common"
+)
+
+// Same as in MinIO driver: archive is built on the fly and written into the pipe,
+// so size of the returned stream is always -1.
+func (h *defaultQueryHandler) getDirectoryArchive(query *FileApplication.GetFileByPathQuery) (*entity.FileStream, error) {
+	if err := LocalCommon.IsBucketExist(query.Bucket); err != nil {
+		return nil, err
+	}
+
+	dirPath, err := LocalCommon.ObjectFilePath(query.Bucket, query.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	pr, pw := io.Pipe()
+
+	writer, err := archive.NewWriter(query.ArchiveFormat, pw)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(query.Context, query.ContextTimeout)
+
+	go func() {
+		err := h.writeDirectoryArchive(ctx, query.Path, dirPath, writer)
+		if err == nil {
+			err = writer.Close()
+		}
+		pw.CloseWithError(err)
+	}()
+
+	return &entity.FileStream{
+		Content: pr,
+		Size:    -1,
+		Context: ctx,
+		Cancel: func() {
+			cancel()
+			// Unblocks archive writer if reader stopped reading before EOF
+			pr.Close()
+		},
+	}, nil
+}
+
+func (h *defaultQueryHandler) writeDirectoryArchive(
+	ctx context.Context,
+	dirPath string,
+	dirFilePath string,
+	writer archive.Writer,
+) error {
+	// See MinIO driver
+	root := path.Base(dirPath) + "/"
+	if dirPath == "/" {
+		root = ""
+	}
+
+	if root != "" {
+		if err := writer.WriteDirectory(root, time.Now()); err != nil {
+			return err
+		}
+	}
+
+	if info, err := os.Stat(dirFilePath); err != nil || !info.IsDir() {
+		if err == nil || LocalCommon.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	return filepath.WalkDir(dirFilePath, func(filePath string, dirEntry fs.DirEntry, err error) error {
+		if err != nil {
+			if LocalCommon.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		// Like in object storages, only directories with markers are archived explicitly
+		if dirEntry.IsDir() {
+			return nil
+		}
+
+		if dirEntry.Name() == LocalCommon.DirectoryMarkerName {
+			markedDir := filepath.Dir(filePath)
+			if markedDir == dirFilePath {
+				return nil
+			}
+			info, err := dirEntry.Info()
+			if err != nil {
+				if LocalCommon.IsNotExist(err) {
+					return nil
+				}
+				return err
+			}
+			return writer.WriteDirectory(archiveEntryName(root, dirFilePath, markedDir)+"/", info.ModTime())
+		}
+
+		return h.writeArchiveFile(filePath, archiveEntryName(root, dirFilePath, filePath), writer)
+	})
+}
+
+func archiveEntryName(root string, dirFilePath string, filePath string) string {
+	rel, _ := filepath.Rel(dirFilePath, filePath)
+	return root + filepath.ToSlash(rel)
+}
+
+func (h *defaultQueryHandler) writeArchiveFile(filePath string, name string, writer archive.Writer) error {
+	f, err := os.Open(filePath)
+	if err != nil {
+		// File was deleted after directory was read
+		if LocalCommon.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	defer f.Close()
+
+	stat, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	return writer.WriteFile(name, stat.Size(), stat.ModTime(), f)
+}
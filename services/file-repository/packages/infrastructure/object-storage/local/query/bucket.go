@@ -0,0 +1,104 @@
+package localquery
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	FileApplication "vega_file_repository/packages/application/file"
+	"vega_file_repository/packages/domain/entity"
+	LocalCommon "vega_file_repository/packages/infrastructure/object-storage/local/common"
+
+	cqrs "github.com/abaxoth0/Vega/libs/go/packages/CQRS"
+)
+
+func (h *defaultQueryHandler) ListBuckets(query *FileApplication.ListBucketsQuery) ([]entity.Bucket, error) {
+	if !query.CommandQuery.IsInit() {
+		cqrs.InitDefaultCommandQuery(&query.CommandQuery)
+	}
+
+	// Already sorted, since os.ReadDir() sorts entries by names
+	bucketPaths, err := LocalCommon.BucketFilePaths()
+	if err != nil {
+		return nil, err
+	}
+
+	buckets := make([]entity.Bucket, 0, len(bucketPaths))
+	for _, bucketPath := range bucketPaths {
+		name := filepath.Base(bucketPath)
+		metadata, err := LocalCommon.ReadBucketMetadata(name)
+		if err != nil {
+			// Bucket was deleted concurrently
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return nil, err
+		}
+		buckets = append(buckets, entity.Bucket{
+			Name:         name,
+			CreationDate: metadata.CreationDate,
+		})
+	}
+
+	return buckets, nil
+}
+
+func (h *defaultQueryHandler) GetBucketInfo(query *FileApplication.GetBucketInfoQuery) (*entity.BucketInfo, error) {
+	if !query.CommandQuery.IsInit() {
+		cqrs.InitDefaultCommandQuery(&query.CommandQuery)
+	}
+
+	ctx, cancel := context.WithTimeout(query.Context, query.ContextTimeout)
+	defer cancel()
+
+	if err := LocalCommon.IsBucketExist(query.Name); err != nil {
+		return nil, err
+	}
+	bucketPath, err := LocalCommon.BucketFilePath(query.Name)
+	if err != nil {
+		return nil, err
+	}
+	metadata, err := LocalCommon.ReadBucketMetadata(query.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &entity.BucketInfo{
+		Bucket: entity.Bucket{
+			Name:         query.Name,
+			CreationDate: metadata.CreationDate,
+		},
+		VersioningEnabled: metadata.Versioning,
+	}
+
+	err = filepath.WalkDir(bucketPath, func(filePath string, dirEntry fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if dirEntry.IsDir() {
+			return nil
+		}
+		fileInfo, err := dirEntry.Info()
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		info.ObjectCount++
+		info.TotalSize += fileInfo.Size()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return info, nil
+}
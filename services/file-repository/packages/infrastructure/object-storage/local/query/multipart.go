@@ -0,0 +1,20 @@
+package localquery
+
+import (
+	FileApplication "vega_file_repository/packages/application/file"
+	"vega_file_repository/packages/domain/entity"
+	LocalCommon "vega_file_repository/packages/infrastructure/object-storage/local/common"
+)
+
+func (h *defaultQueryHandler) ListUploadedParts(query *FileApplication.ListUploadedPartsQuery) ([]entity.UploadPart, error) {
+	if err := h.preprocessQuery(&query.CommandQuery, query.Path); err != nil {
+		return nil, err
+	}
+
+	sessionDir, err := LocalCommon.OpenUploadSession(query.Bucket, query.Path, query.UploadID)
+	if err != nil {
+		return nil, err
+	}
+
+	return LocalCommon.UploadParts(sessionDir)
+}
@@ -0,0 +1,11 @@
+package localquery
+
+import (
+	FileApplication "vega_file_repository/packages/application/file"
+	"vega_file_repository/packages/domain/entity"
+)
+
+// Files are accessible only through the service itself, so it must sign URLs by itself as well
+func (h *defaultQueryHandler) CreatePresignedURL(query *FileApplication.CreatePresignedURLQuery) (*entity.PresignedURL, error) {
+	return nil, FileApplication.ErrPresignNotSupported
+}
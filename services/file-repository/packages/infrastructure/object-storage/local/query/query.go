@@ -0,0 +1,283 @@
+package localquery
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	FileApplication "vega_file_repository/packages/application/file"
+	"vega_file_repository/packages/domain/entity"
+	LocalCommon "vega_file_repository/packages/infrastructure/object-storage/local/common"
+
+	cqrs "github.com/abaxoth0/Vega/libs/go/packages/CQRS"
+	errs "github.com/abaxoth0/Vega/libs/go/packages/erorrs"
+	"github.com/abaxoth0/Vega/libs/go/packages/file"
+)
+
+var Handler FileApplication.QueryHandler = new(defaultQueryHandler)
+
+type defaultQueryHandler struct {
+}
+
+func (h *defaultQueryHandler) preprocessQuery(commandQuery *cqrs.CommandQuery, path string) error {
+	if !commandQuery.IsInit() {
+		cqrs.InitDefaultCommandQuery(commandQuery)
+	}
+
+	err := file.ValidatePathFormat(path)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (h *defaultQueryHandler) GetFileByPath(query *FileApplication.GetFileByPathQuery) (*entity.FileStream, error) {
+	if err := h.preprocessQuery(&query.CommandQuery, query.Path); err != nil {
+		return nil, err
+	}
+	if file.IsDirectory(query.Path) {
+		if query.ArchiveFormat == FileApplication.ArchiveFormatNone {
+			return nil, FileApplication.ErrArchiveFormatNotSpecified
+		}
+		if query.IsRanged() {
+			return nil, FileApplication.ErrRangeOfDirectory
+		}
+		if query.VersionID != "" {
+			return nil, FileApplication.ErrVersionOfDirectory
+		}
+		return h.getDirectoryArchive(query)
+	}
+
+	if err := LocalCommon.IsBucketExist(query.Bucket); err != nil {
+		return nil, err
+	}
+
+	filePath, err := LocalCommon.ObjectFilePath(query.Bucket, query.Path)
+	if err != nil {
+		return nil, err
+	}
+	if query.VersionID != "" {
+		filePath, _, err = LocalCommon.VersionFilePath(query.Bucket, query.Path, query.VersionID)
+		if err != nil {
+			if LocalCommon.IsNotExist(err) {
+				return nil, errs.StatusNotFound
+			}
+			return nil, err
+		}
+	}
+
+	f, err := os.Open(filePath)
+	if err != nil {
+		if LocalCommon.IsNotExist(err) {
+			return nil, errs.StatusNotFound
+		}
+		return nil, err
+	}
+
+	stat, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	// Same as in object storages: directory can be requested only by path with trailing '/'
+	if stat.IsDir() {
+		f.Close()
+		return nil, errs.StatusNotFound
+	}
+
+	byteRange, err := query.ResolveRange(stat.Size())
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	checksum, err := LocalCommon.ReadChecksum(query.Bucket, query.Path, stat)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(query.Context, query.ContextTimeout)
+
+	return &entity.FileStream{
+		Content:  io.NewSectionReader(f, byteRange.Start, byteRange.Length()),
+		Size:     stat.Size(),
+		Range:    byteRange,
+		Checksum: checksum,
+		Context:  ctx,
+		Cancel: func() {
+			cancel()
+			f.Close()
+		},
+	}, nil
+}
+
+func (h *defaultQueryHandler) ListDirectory(query *FileApplication.ListDirectoryQuery) (*entity.DirectoryListing, error) {
+	if !query.CommandQuery.IsInit() {
+		cqrs.InitDefaultCommandQuery(&query.CommandQuery)
+	}
+	if err := file.ValidatePathFormat(query.Path); err != nil {
+		return nil, err
+	}
+	if !file.IsDirectory(query.Path) {
+		return nil, file.ErrFileIsNotDirectory
+	}
+
+	limit := query.Limit
+	if limit <= 0 {
+		limit = FileApplication.DefaultListDirectoryLimit
+	}
+	if limit > FileApplication.MaxListDirectoryLimit {
+		limit = FileApplication.MaxListDirectoryLimit
+	}
+
+	if err := LocalCommon.IsBucketExist(query.Bucket); err != nil {
+		return nil, err
+	}
+
+	bucketPath, err := LocalCommon.BucketFilePath(query.Bucket)
+	if err != nil {
+		return nil, err
+	}
+	dirPath, err := LocalCommon.ObjectFilePath(query.Bucket, query.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []entity.FileInfo
+	if query.Recursive {
+		entries, err = listSubtree(bucketPath, dirPath)
+	} else {
+		entries, err = listChildren(bucketPath, dirPath)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Entries are ordered the same way as keys in object storages,
+	// so continuation token is just a path of the last returned entry.
+	slices.SortFunc(entries, func(a, b entity.FileInfo) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+	if query.ContinuationToken != "" {
+		start, _ := slices.BinarySearchFunc(entries, query.ContinuationToken, func(e entity.FileInfo, token string) int {
+			if e.Path <= token {
+				return -1
+			}
+			return 1
+		})
+		entries = entries[start:]
+	}
+
+	listing := &entity.DirectoryListing{Entries: entries}
+	if len(entries) > limit {
+		listing.Entries = entries[:limit]
+		listing.IsTruncated = true
+		listing.NextContinuationToken = entries[limit-1].Path
+	}
+
+	return listing, nil
+}
+
+// Lists direct children of the directory, subdirectories are listed regardless of having markers.
+func listChildren(bucketPath string, dirPath string) ([]entity.FileInfo, error) {
+	dirEntries, err := os.ReadDir(dirPath)
+	if err != nil {
+		if LocalCommon.IsNotExist(err) {
+			return []entity.FileInfo{}, nil
+		}
+		return nil, err
+	}
+
+	entries := make([]entity.FileInfo, 0, len(dirEntries))
+	for _, dirEntry := range dirEntries {
+		filePath := filepath.Join(dirPath, dirEntry.Name())
+		if dirEntry.IsDir() {
+			entries = append(entries, entity.FileInfo{
+				Path:        LocalCommon.ObjectPath(bucketPath, filePath, true),
+				IsDirectory: true,
+			})
+			continue
+		}
+		// Marker of the listed directory itself
+		if dirEntry.Name() == LocalCommon.DirectoryMarkerName {
+			continue
+		}
+		info, err := dirEntry.Info()
+		if err != nil {
+			// File was deleted after directory was read
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return nil, err
+		}
+		entries = append(entries, fileInfo(bucketPath, filePath, info, false))
+	}
+
+	return entries, nil
+}
+
+// Lists all files in subtree. Like in object storages, subdirectories are listed only if they have markers.
+func listSubtree(bucketPath string, dirPath string) ([]entity.FileInfo, error) {
+	entries := []entity.FileInfo{}
+
+	// WalkDir() accepts files as well, but they must not be listed
+	if info, err := os.Stat(dirPath); err != nil || !info.IsDir() {
+		if err == nil || LocalCommon.IsNotExist(err) {
+			return entries, nil
+		}
+		return nil, err
+	}
+
+	err := filepath.WalkDir(dirPath, func(filePath string, dirEntry fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if dirEntry.IsDir() {
+			return nil
+		}
+
+		info, err := dirEntry.Info()
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+
+		if dirEntry.Name() == LocalCommon.DirectoryMarkerName {
+			markedDir := filepath.Dir(filePath)
+			if markedDir != dirPath {
+				entries = append(entries, fileInfo(bucketPath, markedDir, info, true))
+			}
+			return nil
+		}
+		entries = append(entries, fileInfo(bucketPath, filePath, info, false))
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+func fileInfo(bucketPath string, filePath string, info os.FileInfo, isDirectory bool) entity.FileInfo {
+	return entity.FileInfo{
+		Path:         LocalCommon.ObjectPath(bucketPath, filePath, isDirectory),
+		Size:         info.Size(),
+		StoredSize:   info.Size(),
+		LastModified: info.ModTime(),
+		ETag:         LocalCommon.ETag(info),
+		IsDirectory:  isDirectory,
+	}
+}
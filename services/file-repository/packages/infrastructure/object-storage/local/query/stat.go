@@ -0,0 +1,95 @@
+package localquery
+
+import (
+	"mime"
+	"os"
+	"path/filepath"
+	FileApplication "vega_file_repository/packages/application/file"
+	"vega_file_repository/packages/domain/entity"
+	LocalCommon "vega_file_repository/packages/infrastructure/object-storage/local/common"
+
+	cqrs "github.com/abaxoth0/Vega/libs/go/packages/CQRS"
+	"github.com/abaxoth0/Vega/libs/go/packages/file"
+)
+
+func (h *defaultQueryHandler) StatFile(query *FileApplication.StatFileQuery) ([]entity.FileStat, error) {
+	if !query.CommandQuery.IsInit() {
+		cqrs.InitDefaultCommandQuery(&query.CommandQuery)
+	}
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+	if err := LocalCommon.IsBucketExist(query.Bucket); err != nil {
+		return nil, err
+	}
+
+	versioning, err := LocalCommon.IsVersioningEnabled(query.Bucket)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := make([]entity.FileStat, len(query.Paths))
+	for i, path := range query.Paths {
+		stat, err := statFile(query.Bucket, path, query.VersionID, versioning)
+		if err != nil {
+			return nil, err
+		}
+		stats[i] = *stat
+	}
+
+	return stats, nil
+}
+
+func statFile(bucket string, path string, versionID string, versioning bool) (*entity.FileStat, error) {
+	stat := &entity.FileStat{Path: path}
+
+	filePath, err := LocalCommon.ObjectFilePath(bucket, path)
+	if err != nil {
+		return nil, err
+	}
+	if versionID != "" {
+		filePath, _, err = LocalCommon.VersionFilePath(bucket, path, versionID)
+		if err != nil {
+			if LocalCommon.IsNotExist(err) {
+				return stat, nil
+			}
+			return nil, err
+		}
+	}
+
+	info, err := os.Stat(filePath)
+	if err != nil {
+		if LocalCommon.IsNotExist(err) {
+			return stat, nil
+		}
+		return nil, err
+	}
+	// Same as in object storages: directory can be requested only by path with trailing '/' and vice versa
+	if info.IsDir() != file.IsDirectory(path) {
+		return stat, nil
+	}
+
+	stat.Exists = true
+	stat.IsDirectory = info.IsDir()
+	stat.LastModified = info.ModTime()
+	if stat.IsDirectory {
+		return stat, nil
+	}
+
+	stat.Size = info.Size()
+	stat.StoredSize = info.Size()
+	stat.ETag = LocalCommon.ETag(info)
+	// File system doesn't store content type, so it's guessed by extension, same as HTTP servers do
+	stat.ContentType = mime.TypeByExtension(filepath.Ext(path))
+	// ID of the version is its ETag (see LocalCommon.VersionFilePath)
+	if versioning || versionID != "" {
+		stat.VersionID = stat.ETag
+	}
+
+	stat.Checksum, err = LocalCommon.ReadChecksum(bucket, path, info)
+	if err != nil {
+		return nil, err
+	}
+
+	return stat, nil
+}
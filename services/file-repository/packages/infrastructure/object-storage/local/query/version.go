@@ -0,0 +1,68 @@
+package localquery
+
+import (
+	"os"
+	FileApplication "vega_file_repository/packages/application/file"
+	"vega_file_repository/packages/domain/entity"
+	LocalCommon "vega_file_repository/packages/infrastructure/object-storage/local/common"
+
+	errs "github.com/abaxoth0/Vega/libs/go/packages/erorrs"
+	"github.com/abaxoth0/Vega/libs/go/packages/file"
+)
+
+func (h *defaultQueryHandler) ListFileVersions(query *FileApplication.ListFileVersionsQuery) ([]entity.FileVersion, error) {
+	if err := h.preprocessQuery(&query.CommandQuery, query.Path); err != nil {
+		return nil, err
+	}
+	if file.IsDirectory(query.Path) {
+		return nil, FileApplication.ErrVersionOfDirectory
+	}
+	if err := LocalCommon.IsBucketExist(query.Bucket); err != nil {
+		return nil, err
+	}
+
+	filePath, err := LocalCommon.ObjectFilePath(query.Bucket, query.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	versions := []entity.FileVersion{}
+
+	current := ""
+	info, err := os.Stat(filePath)
+	if err != nil && !LocalCommon.IsNotExist(err) {
+		return nil, err
+	}
+	if err == nil && !info.IsDir() {
+		current = LocalCommon.ETag(info)
+		versions = append(versions, fileVersion(info, current, true))
+	}
+
+	previous, err := LocalCommon.PreviousVersions(query.Bucket, query.Path)
+	if err != nil {
+		return nil, err
+	}
+	for _, info := range previous {
+		// Current version may be already preserved if its overwrite has failed
+		if info.Name() == current {
+			continue
+		}
+		versions = append(versions, fileVersion(info, info.Name(), false))
+	}
+
+	if len(versions) == 0 {
+		return nil, errs.StatusNotFound
+	}
+
+	return versions, nil
+}
+
+func fileVersion(info os.FileInfo, versionID string, isLatest bool) entity.FileVersion {
+	return entity.FileVersion{
+		VersionID:    versionID,
+		Size:         info.Size(),
+		LastModified: info.ModTime(),
+		ETag:         versionID,
+		IsLatest:     isLatest,
+	}
+}
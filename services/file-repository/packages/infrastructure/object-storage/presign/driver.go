@@ -0,0 +1,44 @@
+// Presigned URLs which are signed and served by the service itself.
+package presign
+
+import (
+	"errors"
+	"net/http"
+	FileApplication "vega_file_repository/packages/application/file"
+	"vega_file_repository/packages/domain/entity"
+	objectstorage "vega_file_repository/packages/infrastructure/object-storage"
+)
+
+// Wraps another driver: URLs which it can't presign (see FileApplication.ErrPresignNotSupported)
+// are signed by Signer instead. Such URLs are served by Handler, which accesses files through
+// the wrapped driver, so all of its layers (e.g. encryption or quotas) are applied as usual.
+type Driver struct {
+	objectstorage.ObjectStorageDriver
+	signer *Signer
+}
+
+func NewDriver(driver objectstorage.ObjectStorageDriver, signer *Signer) *Driver {
+	return &Driver{
+		ObjectStorageDriver: driver,
+		signer:              signer,
+	}
+}
+
+func (d *Driver) CreatePresignedURL(query *FileApplication.CreatePresignedURLQuery) (*entity.PresignedURL, error) {
+	presigned, err := d.ObjectStorageDriver.CreatePresignedURL(query)
+	if !errors.Is(err, FileApplication.ErrPresignNotSupported) {
+		return presigned, err
+	}
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+	return d.signer.Sign(query), nil
+}
+
+// Serves URLs signed by this driver, must be mounted at PathPrefix of the base URL of the Signer.
+func (d *Driver) Handler() http.Handler {
+	return &handler{
+		driver: d.ObjectStorageDriver,
+		signer: d.signer,
+	}
+}
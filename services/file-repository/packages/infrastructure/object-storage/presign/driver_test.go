@@ -0,0 +1,216 @@
+package presign
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+	FileApplication "vega_file_repository/packages/application/file"
+	"vega_file_repository/packages/domain/entity"
+	"vega_file_repository/packages/infrastructure/object-storage/compression"
+	StorageConnection "vega_file_repository/packages/infrastructure/object-storage/connection"
+	"vega_file_repository/packages/infrastructure/object-storage/local"
+)
+
+const testSecret = "0123456789abcdef0123456789abcdef"
+
+func send(t *testing.T, presigned *entity.PresignedURL, body []byte, headers map[string]string) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest(presigned.Method, presigned.URL, bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("Failed to create request: %v", err)
+	}
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+func TestPresignDriver(t *testing.T) {
+	storage := local.InitDriver()
+	if err := storage.Connect(&StorageConnection.Config{URL: t.TempDir()}); err != nil {
+		t.Fatalf("Connection failed: %v", err)
+	}
+	defer storage.Disconnect()
+
+	for _, bucket := range []string{"plain", "compressed"} {
+		if err := storage.MakeBucket(&FileApplication.MakeBucketCommand{Name: bucket}); err != nil {
+			t.Fatalf("Failed to create bucket: %v", err)
+		}
+	}
+	policy, err := compression.ParsePolicy("compressed:gzip")
+	if err != nil {
+		t.Fatalf("Failed to parse policy: %v", err)
+	}
+
+	// Handler must be known before base URL, so it's set once server is started
+	var driver *Driver
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		driver.Handler().ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	signer, err := NewSigner(testSecret, server.URL+"/")
+	if err != nil {
+		t.Fatalf("Failed to create signer: %v", err)
+	}
+	driver = NewDriver(compression.NewDriver(storage, policy), signer)
+
+	content := []byte(strings.Repeat("presigned content\n", 100))
+
+	presign := func(t *testing.T, query *FileApplication.CreatePresignedURLQuery) *entity.PresignedURL {
+		t.Helper()
+		presigned, err := driver.CreatePresignedURL(query)
+		if err != nil {
+			t.Fatalf("Failed to presign URL: %v", err)
+		}
+		if !strings.HasPrefix(presigned.URL, server.URL+PathPrefix) {
+			t.Fatalf("URL must be signed by the service, but got %s", presigned.URL)
+		}
+		return presigned
+	}
+
+	t.Run("Upload and download", func(t *testing.T) {
+		for _, bucket := range []string{"plain", "compressed"} {
+			upload := presign(t, &FileApplication.CreatePresignedURLQuery{
+				Bucket:      bucket,
+				Path:        "/dir/file name.txt",
+				Method:      http.MethodPut,
+				ContentType: "text/plain",
+				Size:        int64(len(content)),
+			})
+			if upload.Headers["Content-Type"] != "text/plain" {
+				t.Errorf("Restrictions of the upload must be returned as headers: %v", upload.Headers)
+			}
+			if resp := send(t, upload, content, upload.Headers); resp.StatusCode != http.StatusOK || resp.Header.Get("ETag") == "" {
+				t.Fatalf("Upload failed: %s", resp.Status)
+			}
+
+			download := presign(t, &FileApplication.CreatePresignedURLQuery{
+				Bucket: bucket,
+				Path:   "/dir/file name.txt",
+				Method: http.MethodGet,
+			})
+			resp := send(t, download, nil, nil)
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("Download failed: %s", resp.Status)
+			}
+			downloaded, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatalf("Failed to read response: %v", err)
+			}
+			// Content of the compressed bucket must be served decompressed
+			if !bytes.Equal(downloaded, content) {
+				t.Errorf("Downloaded content of bucket \"%s\" doesn't match uploaded one", bucket)
+			}
+		}
+	})
+
+	t.Run("Upload restrictions", func(t *testing.T) {
+		upload := presign(t, &FileApplication.CreatePresignedURLQuery{
+			Bucket:      "plain",
+			Path:        "/restricted.txt",
+			Method:      http.MethodPut,
+			ContentType: "text/plain",
+			Size:        int64(len(content)),
+		})
+		if resp := send(t, upload, content, map[string]string{"Content-Type": "text/html"}); resp.StatusCode != http.StatusForbidden {
+			t.Errorf("Upload with another content type must be forbidden, but got %s", resp.Status)
+		}
+		if resp := send(t, upload, content[1:], upload.Headers); resp.StatusCode != http.StatusForbidden {
+			t.Errorf("Upload with another size must be forbidden, but got %s", resp.Status)
+		}
+
+		_, err := driver.CreatePresignedURL(&FileApplication.CreatePresignedURLQuery{
+			Bucket:      "plain",
+			Path:        "/restricted.txt",
+			Method:      http.MethodGet,
+			ContentType: "text/plain",
+		})
+		if !errors.Is(err, FileApplication.ErrRestrictionsOfDownload) {
+			t.Errorf("Restrictions of download must be rejected, but got: %v", err)
+		}
+	})
+
+	t.Run("Invalid URLs", func(t *testing.T) {
+		download := presign(t, &FileApplication.CreatePresignedURLQuery{
+			Bucket: "plain",
+			Path:   "/dir/file name.txt",
+			Method: http.MethodGet,
+		})
+
+		tampered := *download
+		tampered.URL = strings.Replace(download.URL, "file%20name", "other", 1)
+		if resp := send(t, &tampered, nil, nil); resp.StatusCode != http.StatusForbidden {
+			t.Errorf("URL with another path must be forbidden, but got %s", resp.Status)
+		}
+
+		wrongMethod := *download
+		wrongMethod.Method = http.MethodPut
+		if resp := send(t, &wrongMethod, content, nil); resp.StatusCode != http.StatusForbidden {
+			t.Errorf("URL used with another method must be forbidden, but got %s", resp.Status)
+		}
+
+		expired := signer.Sign(&FileApplication.CreatePresignedURLQuery{
+			Bucket: "plain",
+			Path:   "/dir/file name.txt",
+			Method: http.MethodGet,
+			Expiry: -time.Minute,
+		})
+		if resp := send(t, expired, nil, nil); resp.StatusCode != http.StatusForbidden {
+			t.Errorf("Expired URL must be forbidden, but got %s", resp.Status)
+		}
+
+		forged, err := url.Parse(expired.URL)
+		if err != nil {
+			t.Fatalf("Failed to parse URL: %v", err)
+		}
+		params := forged.Query()
+		params.Set("expires", "9999999999")
+		forged.RawQuery = params.Encode()
+		if resp := send(t, &entity.PresignedURL{URL: forged.String(), Method: http.MethodGet}, nil, nil); resp.StatusCode != http.StatusForbidden {
+			t.Errorf("URL with forged expiration time must be forbidden, but got %s", resp.Status)
+		}
+
+		missing := presign(t, &FileApplication.CreatePresignedURLQuery{
+			Bucket: "plain",
+			Path:   "/missing.txt",
+			Method: http.MethodGet,
+		})
+		if resp := send(t, missing, nil, nil); resp.StatusCode != http.StatusNotFound {
+			t.Errorf("Download of missing file must fail with 404, but got %s", resp.Status)
+		}
+	})
+
+	t.Run("Invalid queries", func(t *testing.T) {
+		invalid := []*FileApplication.CreatePresignedURLQuery{
+			{Bucket: "plain", Path: "/dir/", Method: http.MethodGet},
+			{Bucket: "plain", Path: "/file.txt", Method: http.MethodDelete},
+			{Bucket: "plain", Path: "/file.txt", Method: http.MethodGet, Expiry: FileApplication.MaxPresignedURLExpiry + time.Second},
+		}
+		for _, query := range invalid {
+			if _, err := driver.CreatePresignedURL(query); err == nil {
+				t.Errorf("Query must be rejected: %+v", query)
+			}
+		}
+	})
+}
+
+func TestNewSigner(t *testing.T) {
+	if _, err := NewSigner("short", "http://localhost"); !errors.Is(err, ErrWeakSecret) {
+		t.Errorf("Short secret must be rejected, but got: %v", err)
+	}
+	if _, err := NewSigner(testSecret, "localhost:8080"); err == nil {
+		t.Errorf("Base URL without scheme must be rejected")
+	}
+}
@@ -0,0 +1,132 @@
+package presign
+
+import (
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	FileApplication "vega_file_repository/packages/application/file"
+	objectstorage "vega_file_repository/packages/infrastructure/object-storage"
+
+	cqrs "github.com/abaxoth0/Vega/libs/go/packages/CQRS"
+	errs "github.com/abaxoth0/Vega/libs/go/packages/erorrs"
+)
+
+// The same as timeouts of gRPC transfers
+const (
+	downloadTimeout = time.Hour
+	uploadTimeout   = time.Hour
+)
+
+type handler struct {
+	driver objectstorage.ObjectStorageDriver
+	signer *Signer
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rest, ok := strings.CutPrefix(r.URL.Path, PathPrefix)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	bucket, path, ok := strings.Cut(rest, "/")
+	if !ok || bucket == "" {
+		http.NotFound(w, r)
+		return
+	}
+	path = "/" + path
+
+	t, err := h.signer.verify(r.Method, bucket, path, r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
+	if t.method == http.MethodGet {
+		h.download(w, r, t)
+	} else {
+		h.upload(w, r, t)
+	}
+}
+
+func (h *handler) download(w http.ResponseWriter, r *http.Request, t *token) {
+	stream, err := h.driver.GetFileByPath(&FileApplication.GetFileByPathQuery{
+		Bucket: t.bucket,
+		Path:   t.path,
+		CommandQuery: cqrs.CommandQuery{
+			Context:        r.Context(),
+			ContextTimeout: downloadTimeout,
+		},
+	})
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	defer stream.Cancel()
+
+	contentType := mime.TypeByExtension(filepath.Ext(t.path))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	w.Header().Set("Content-Type", contentType)
+	if stream.Size >= 0 {
+		w.Header().Set("Content-Length", strconv.FormatInt(stream.Size, 10))
+	}
+	w.WriteHeader(http.StatusOK)
+
+	// Response is already started, so failure can be reported only by breaking it
+	io.Copy(w, stream.Content)
+}
+
+func (h *handler) upload(w http.ResponseWriter, r *http.Request, t *token) {
+	if t.contentType != "" && r.Header.Get("Content-Type") != t.contentType {
+		http.Error(w, "content type of the upload doesn't match the signed one", http.StatusForbidden)
+		return
+	}
+	if r.ContentLength < 0 {
+		http.Error(w, "size of the upload must be known beforehand", http.StatusLengthRequired)
+		return
+	}
+	if t.size > 0 && r.ContentLength != t.size {
+		http.Error(w, "size of the upload doesn't match the signed one", http.StatusForbidden)
+		return
+	}
+
+	result, err := h.driver.UploadFile(&FileApplication.UploadFileCommand{
+		Bucket:      t.bucket,
+		Path:        t.path,
+		Content:     r.Body,
+		ContentSize: r.ContentLength,
+		CommandQuery: cqrs.CommandQuery{
+			Context:        r.Context(),
+			ContextTimeout: uploadTimeout,
+		},
+	})
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	if result.ETag != "" {
+		w.Header().Set("ETag", "\""+result.ETag+"\"")
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	var statusErr *errs.Status
+	switch {
+	case errors.As(err, &statusErr):
+		status = statusErr.Status()
+	case errors.Is(err, FileApplication.ErrQuotaExceeded):
+		status = http.StatusInsufficientStorage
+	case errors.Is(err, FileApplication.ErrChecksumMismatch):
+		status = http.StatusBadRequest
+	}
+	http.Error(w, err.Error(), status)
+}
@@ -0,0 +1,149 @@
+package presign
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	FileApplication "vega_file_repository/packages/application/file"
+	"vega_file_repository/packages/domain/entity"
+)
+
+// Secret is used as HMAC-SHA256 key, so it must be at least as long as the hash
+const minSecretLength = sha256.Size
+
+// Path of the URLs signed by the service, followed by bucket and path of the file
+const PathPrefix = "/presigned/"
+
+var (
+	ErrWeakSecret       = errors.New("presign secret must be at least 32 bytes long")
+	ErrInvalidSignature = errors.New("invalid signature of the presigned URL")
+	ErrURLExpired       = errors.New("presigned URL is expired")
+)
+
+// Signs URLs which are served by the service itself (see Handler).
+// Signature covers everything URL grants: method, bucket, path, expiration time and restrictions of the upload.
+type Signer struct {
+	secret  []byte
+	baseURL *url.URL
+}
+
+// Base URL is the public address of the Handler, signed URLs are built by appending PathPrefix to it
+func NewSigner(secret string, baseURL string) (*Signer, error) {
+	if len(secret) < minSecretLength {
+		return nil, ErrWeakSecret
+	}
+	parsed, err := url.Parse(strings.TrimSuffix(baseURL, "/"))
+	if err != nil {
+		return nil, err
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return nil, fmt.Errorf("base URL of presigned URLs must be absolute HTTP(S) URL, but got \"%s\"", baseURL)
+	}
+	return &Signer{
+		secret:  []byte(secret),
+		baseURL: parsed,
+	}, nil
+}
+
+// Grant of the presigned URL
+type token struct {
+	method      string
+	bucket      string
+	path        string
+	expiresAt   time.Time
+	contentType string
+	size        int64
+}
+
+func (s *Signer) signature(t *token) string {
+	mac := hmac.New(sha256.New, s.secret)
+	// Fields are separated by the character which can't appear in any of them
+	fmt.Fprintf(mac, "%s\n%s\n%s\n%d\n%s\n%d", t.method, t.bucket, t.path, t.expiresAt.Unix(), t.contentType, t.size)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Query must be already validated
+func (s *Signer) Sign(query *FileApplication.CreatePresignedURLQuery) *entity.PresignedURL {
+	t := &token{
+		method:      query.Method,
+		bucket:      query.Bucket,
+		path:        query.Path,
+		expiresAt:   time.Now().Add(query.Expiry),
+		contentType: query.ContentType,
+		size:        query.Size,
+	}
+
+	params := url.Values{}
+	params.Set("method", t.method)
+	params.Set("expires", strconv.FormatInt(t.expiresAt.Unix(), 10))
+	if t.contentType != "" {
+		params.Set("content_type", t.contentType)
+	}
+	if t.size > 0 {
+		params.Set("size", strconv.FormatInt(t.size, 10))
+	}
+	params.Set("signature", s.signature(t))
+
+	signed := *s.baseURL
+	signed.Path += PathPrefix + t.bucket + t.path
+	signed.RawQuery = params.Encode()
+
+	headers := map[string]string{}
+	if t.contentType != "" {
+		headers["Content-Type"] = t.contentType
+	}
+	if t.size > 0 {
+		headers["Content-Length"] = strconv.FormatInt(t.size, 10)
+	}
+
+	return &entity.PresignedURL{
+		URL:       signed.String(),
+		Method:    t.method,
+		ExpiresAt: time.Unix(t.expiresAt.Unix(), 0),
+		Headers:   headers,
+	}
+}
+
+// Returns grant of the URL if its signature is valid and it isn't expired yet.
+// Method of the request must be the same as the signed one.
+func (s *Signer) verify(method string, bucket string, path string, params url.Values) (*token, error) {
+	if params.Get("method") != method {
+		return nil, ErrInvalidSignature
+	}
+	expires, err := strconv.ParseInt(params.Get("expires"), 10, 64)
+	if err != nil {
+		return nil, ErrInvalidSignature
+	}
+	t := &token{
+		method:      method,
+		bucket:      bucket,
+		path:        path,
+		expiresAt:   time.Unix(expires, 0),
+		contentType: params.Get("content_type"),
+	}
+	if size := params.Get("size"); size != "" {
+		if t.size, err = strconv.ParseInt(size, 10, 64); err != nil {
+			return nil, ErrInvalidSignature
+		}
+	}
+
+	if !hmac.Equal([]byte(s.signature(t)), []byte(params.Get("signature"))) {
+		return nil, ErrInvalidSignature
+	}
+	// Checked only after signature, so expiration time can't be forged
+	if time.Now().After(t.expiresAt) {
+		return nil, ErrURLExpired
+	}
+	if t.method != http.MethodGet && t.method != http.MethodPut {
+		return nil, ErrInvalidSignature
+	}
+
+	return t, nil
+}
@@ -0,0 +1,101 @@
+package quota
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+var ErrEmptyConfig = errors.New("quota config must define at least one bucket or owner quota")
+
+// Limits of the quota, 0 means that there is no limit
+type Limit struct {
+	// Max total size of the files in bytes
+	MaxBytes   int64 `json:"max_bytes"`
+	MaxObjects int64 `json:"max_objects"`
+}
+
+type OwnerQuota struct {
+	Limit
+	// Buckets of the owner, each bucket may belong only to one owner
+	Buckets []string `json:"buckets"`
+}
+
+// Quotas of the buckets and owners, limits of the bucket and of its owner are enforced independently.
+type Config struct {
+	Buckets map[string]Limit      `json:"buckets"`
+	Owners  map[string]OwnerQuota `json:"owners"`
+
+	ownerOf map[string]string
+}
+
+// Parses config in JSON format:
+//
+//	{
+//	  "buckets": {"bucket": {"max_bytes": 1073741824, "max_objects": 10000}},
+//	  "owners": {"owner": {"max_bytes": 10737418240, "buckets": ["bucket", "another-bucket"]}}
+//	}
+func ParseConfig(data []byte) (*Config, error) {
+	config := &Config{}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(config); err != nil {
+		return nil, fmt.Errorf("invalid quota config: %v", err)
+	}
+
+	if len(config.Buckets) == 0 && len(config.Owners) == 0 {
+		return nil, ErrEmptyConfig
+	}
+	for bucket, limit := range config.Buckets {
+		if err := limit.validate(); err != nil {
+			return nil, fmt.Errorf("invalid quota of bucket \"%s\": %v", bucket, err)
+		}
+	}
+
+	config.ownerOf = make(map[string]string)
+	for owner, quota := range config.Owners {
+		if err := quota.validate(); err != nil {
+			return nil, fmt.Errorf("invalid quota of owner \"%s\": %v", owner, err)
+		}
+		if len(quota.Buckets) == 0 {
+			return nil, fmt.Errorf("owner \"%s\" has no buckets", owner)
+		}
+		for _, bucket := range quota.Buckets {
+			if other, ok := config.ownerOf[bucket]; ok {
+				return nil, fmt.Errorf("bucket \"%s\" belongs to both \"%s\" and \"%s\" owners", bucket, other, owner)
+			}
+			config.ownerOf[bucket] = owner
+		}
+	}
+
+	return config, nil
+}
+
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseConfig(data)
+}
+
+func (l Limit) validate() error {
+	if l.MaxBytes < 0 || l.MaxObjects < 0 {
+		return errors.New("limits can't be negative")
+	}
+	return nil
+}
+
+// Returns empty string if bucket has no owner
+func (c *Config) Owner(bucket string) string {
+	return c.ownerOf[bucket]
+}
+
+// Usage is tracked only for buckets which have quota or belong to an owner
+func (c *Config) IsTracked(bucket string) bool {
+	_, ok := c.Buckets[bucket]
+	return ok || c.Owner(bucket) != ""
+}
@@ -0,0 +1,411 @@
+// Storage quotas of the buckets and their owners.
+package quota
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	FileApplication "vega_file_repository/packages/application/file"
+	"vega_file_repository/packages/domain/entity"
+	objectstorage "vega_file_repository/packages/infrastructure/object-storage"
+
+	cqrs "github.com/abaxoth0/Vega/libs/go/packages/CQRS"
+	"github.com/abaxoth0/Vega/libs/go/packages/file"
+)
+
+// Wraps another driver: enforces quotas of the buckets and their owners (see Config). Writes which would
+// exceed any of them are rejected with FileApplication.ErrQuotaExceeded before content is read, writes which
+// reduce usage are always allowed, even if quota is already exceeded.
+//
+// Usage of the bucket is computed by listing all of its files once it's needed for the first time,
+// after that it's kept in memory and updated on each upload, update and deletion of the file.
+// Effect of archive uploads, relocations, completion of resumable uploads and operations with versions
+// isn't known beforehand, so they aren't limited (except archive and parts of resumable uploads themselves
+// must fit into quota), instead usage of affected buckets is computed again when it's needed next time.
+// Concurrent writes of the same file may leave usage of its bucket slightly inaccurate till it's recomputed.
+//
+// Size of the file is the size of its content, even if it's transformed before it's stored (e.g. compressed),
+// so this driver must wrap all other layers. Previous versions of the files and directory markers aren't counted.
+type Driver struct {
+	objectstorage.ObjectStorageDriver
+	config *Config
+
+	mu sync.Mutex
+	// Buckets whose usage wasn't computed yet (or was invalidated) are missing
+	usages map[string]*usage
+	// Serializes computation of usage of each bucket, so it's computed only once
+	loading map[string]*sync.Mutex
+}
+
+type usage struct {
+	bytes   int64
+	objects int64
+}
+
+func NewDriver(driver objectstorage.ObjectStorageDriver, config *Config) *Driver {
+	return &Driver{
+		ObjectStorageDriver: driver,
+		config:              config,
+		usages:              make(map[string]*usage),
+		loading:             make(map[string]*sync.Mutex),
+	}
+}
+
+// Delta isn't allowed only if it increases usage above the limit
+func (l Limit) allows(current usage, delta usage) bool {
+	if delta.bytes > 0 && l.MaxBytes > 0 && current.bytes+delta.bytes > l.MaxBytes {
+		return false
+	}
+	if delta.objects > 0 && l.MaxObjects > 0 && current.objects+delta.objects > l.MaxObjects {
+		return false
+	}
+	return true
+}
+
+// Computes usage of the bucket if it isn't known yet
+func (d *Driver) load(bucket string, commandQuery cqrs.CommandQuery) error {
+	d.mu.Lock()
+	if _, ok := d.usages[bucket]; ok {
+		d.mu.Unlock()
+		return nil
+	}
+	lock, ok := d.loading[bucket]
+	if !ok {
+		lock = new(sync.Mutex)
+		d.loading[bucket] = lock
+	}
+	d.mu.Unlock()
+
+	lock.Lock()
+	defer lock.Unlock()
+
+	// Could be computed while lock was awaited
+	d.mu.Lock()
+	_, ok = d.usages[bucket]
+	d.mu.Unlock()
+	if ok {
+		return nil
+	}
+
+	computed, err := d.compute(bucket, commandQuery)
+	if err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	d.usages[bucket] = computed
+	d.mu.Unlock()
+
+	return nil
+}
+
+func (d *Driver) compute(bucket string, commandQuery cqrs.CommandQuery) (*usage, error) {
+	computed := &usage{}
+
+	token := ""
+	for {
+		listing, err := d.ObjectStorageDriver.ListDirectory(&FileApplication.ListDirectoryQuery{
+			Bucket:            bucket,
+			Path:              "/",
+			Recursive:         true,
+			ContinuationToken: token,
+			CommandQuery:      commandQuery,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range listing.Entries {
+			if entry.IsDirectory {
+				continue
+			}
+			computed.bytes += entry.Size
+			computed.objects++
+		}
+		if listing.NextContinuationToken == "" {
+			return computed, nil
+		}
+		token = listing.NextContinuationToken
+	}
+}
+
+// Usage of the buckets will be computed again when it's needed next time
+func (d *Driver) invalidate(buckets ...string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, bucket := range buckets {
+		delete(d.usages, bucket)
+	}
+}
+
+// Must be called under d.mu. Buckets whose usage isn't known are skipped.
+func (d *Driver) total(buckets ...string) usage {
+	var total usage
+	for _, bucket := range buckets {
+		if u, ok := d.usages[bucket]; ok {
+			total.bytes += u.bytes
+			total.objects += u.objects
+		}
+	}
+	return total
+}
+
+// Must be called under d.mu
+func (d *Driver) add(bucket string, delta usage) {
+	if u, ok := d.usages[bucket]; ok {
+		u.bytes += delta.bytes
+		u.objects += delta.objects
+	}
+}
+
+// Checks if delta fits into quotas of the bucket and its owner. If apply is true, then delta is added
+// to usage of the bucket right away, so concurrent writes can't exceed quota together,
+// returned function reverts it (e.g. if write fails).
+func (d *Driver) reserve(bucket string, delta usage, apply bool, commandQuery cqrs.CommandQuery) (func(), error) {
+	noop := func() {}
+	if !d.config.IsTracked(bucket) {
+		return noop, nil
+	}
+
+	owner := d.config.Owner(bucket)
+	buckets := []string{bucket}
+	if owner != "" {
+		buckets = d.config.Owners[owner].Buckets
+	}
+	for _, bucket := range buckets {
+		if err := d.load(bucket, commandQuery); err != nil {
+			return nil, err
+		}
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if limit, ok := d.config.Buckets[bucket]; ok && !limit.allows(d.total(bucket), delta) {
+		return nil, fmt.Errorf("%w: quota of bucket \"%s\" doesn't allow this write", FileApplication.ErrQuotaExceeded, bucket)
+	}
+	if owner != "" && !d.config.Owners[owner].allows(d.total(buckets...), delta) {
+		return nil, fmt.Errorf("%w: quota of owner \"%s\" doesn't allow this write", FileApplication.ErrQuotaExceeded, owner)
+	}
+	if !apply {
+		return noop, nil
+	}
+
+	d.add(bucket, delta)
+
+	return func() {
+		d.mu.Lock()
+		d.add(bucket, usage{bytes: -delta.bytes, objects: -delta.objects})
+		d.mu.Unlock()
+	}, nil
+}
+
+// Reserves difference between size of the current file (if any) and the new one
+func (d *Driver) reserveWrite(bucket string, path string, size int64, commandQuery cqrs.CommandQuery) (func(), error) {
+	if !d.config.IsTracked(bucket) {
+		return func() {}, nil
+	}
+
+	stats, err := d.ObjectStorageDriver.StatFile(&FileApplication.StatFileQuery{
+		Bucket:       bucket,
+		Paths:        []string{path},
+		CommandQuery: commandQuery,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	delta := usage{bytes: size - stats[0].Size}
+	if !stats[0].Exists {
+		delta.objects = 1
+	}
+
+	return d.reserve(bucket, delta, true, commandQuery)
+}
+
+func (d *Driver) UploadFile(cmd *FileApplication.UploadFileCommand) (*entity.UploadResult, error) {
+	if cmd.ArchiveFormat != FileApplication.ArchiveFormatNone {
+		// Size of extracted entries is unknown till archive is extracted
+		if _, err := d.reserve(cmd.Bucket, usage{bytes: cmd.ContentSize}, false, cmd.CommandQuery); err != nil {
+			return nil, err
+		}
+		defer d.invalidate(cmd.Bucket)
+		return d.ObjectStorageDriver.UploadFile(cmd)
+	}
+	if file.IsDirectory(cmd.Path) {
+		return d.ObjectStorageDriver.UploadFile(cmd)
+	}
+
+	release, err := d.reserveWrite(cmd.Bucket, cmd.Path, cmd.ContentSize, cmd.CommandQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := d.ObjectStorageDriver.UploadFile(cmd)
+	if err != nil {
+		release()
+	}
+	return result, err
+}
+
+func (d *Driver) UpdateFileContent(cmd *FileApplication.UpdateFileContentCommand) (*entity.UploadResult, error) {
+	if file.IsDirectory(cmd.Path) {
+		return d.ObjectStorageDriver.UpdateFileContent(cmd)
+	}
+
+	release, err := d.reserveWrite(cmd.Bucket, cmd.Path, cmd.Size, cmd.CommandQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := d.ObjectStorageDriver.UpdateFileContent(cmd)
+	if err != nil {
+		release()
+	}
+	return result, err
+}
+
+// Sizes of deleted files are known only if all paths are files, since directories are deleted with their subtrees.
+// Otherwise usage of the bucket is computed again.
+func (d *Driver) DeleteFiles(cmd *FileApplication.DeleteFilesCommand) (*entity.DeletionReport, error) {
+	if !d.config.IsTracked(cmd.Bucket) {
+		return d.ObjectStorageDriver.DeleteFiles(cmd)
+	}
+
+	statted := len(cmd.Paths) > 0 && len(cmd.Paths) <= FileApplication.MaxStatFilePaths
+	for _, path := range cmd.Paths {
+		if file.IsDirectory(path) {
+			statted = false
+			break
+		}
+	}
+
+	var stats []entity.FileStat
+	if statted {
+		var err error
+		stats, err = d.ObjectStorageDriver.StatFile(&FileApplication.StatFileQuery{
+			Bucket:       cmd.Bucket,
+			Paths:        cmd.Paths,
+			CommandQuery: cmd.CommandQuery,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	report, err := d.ObjectStorageDriver.DeleteFiles(cmd)
+	if !statted || report == nil {
+		d.invalidate(cmd.Bucket)
+		return report, err
+	}
+
+	existing := make(map[string]entity.FileStat, len(stats))
+	for _, stat := range stats {
+		if stat.Exists {
+			existing[stat.Path] = stat
+		}
+	}
+
+	var delta usage
+	for _, result := range report.Results {
+		// Storages may report deletion of missing files as successful
+		if stat, ok := existing[result.Path]; ok && result.DeletedCount > 0 {
+			delta.bytes -= stat.Size
+			delta.objects--
+			delete(existing, result.Path)
+		}
+	}
+
+	d.mu.Lock()
+	d.add(cmd.Bucket, delta)
+	d.mu.Unlock()
+
+	return report, err
+}
+
+func (d *Driver) CopyFiles(cmd *FileApplication.CopyFilesCommand) (*entity.RelocationReport, error) {
+	defer d.invalidate(cmd.SourceBucket, cmd.DestinationBucket)
+	return d.ObjectStorageDriver.CopyFiles(cmd)
+}
+
+func (d *Driver) MoveFiles(cmd *FileApplication.MoveFilesCommand) (*entity.RelocationReport, error) {
+	defer d.invalidate(cmd.SourceBucket, cmd.DestinationBucket)
+	return d.ObjectStorageDriver.MoveFiles(cmd)
+}
+
+// Direct uploads would bypass quotas, so they can't be presigned in buckets with quotas
+func (d *Driver) CreatePresignedURL(query *FileApplication.CreatePresignedURLQuery) (*entity.PresignedURL, error) {
+	if query.Method == http.MethodPut && d.config.IsTracked(query.Bucket) {
+		return nil, FileApplication.ErrPresignNotSupported
+	}
+	return d.ObjectStorageDriver.CreatePresignedURL(query)
+}
+
+// Parts aren't counted till upload is completed, but each of them must fit into quota
+func (d *Driver) UploadPart(cmd *FileApplication.UploadPartCommand) (*entity.UploadPart, error) {
+	if _, err := d.reserve(cmd.Bucket, usage{bytes: cmd.Size}, false, cmd.CommandQuery); err != nil {
+		return nil, err
+	}
+	return d.ObjectStorageDriver.UploadPart(cmd)
+}
+
+func (d *Driver) CompleteUpload(cmd *FileApplication.CompleteUploadCommand) error {
+	defer d.invalidate(cmd.Bucket)
+	return d.ObjectStorageDriver.CompleteUpload(cmd)
+}
+
+func (d *Driver) DeleteBucket(cmd *FileApplication.DeleteBucketCommand) error {
+	defer d.invalidate(cmd.Name)
+	return d.ObjectStorageDriver.DeleteBucket(cmd)
+}
+
+func (d *Driver) RestoreFileVersion(cmd *FileApplication.RestoreFileVersionCommand) (string, error) {
+	defer d.invalidate(cmd.Bucket)
+	return d.ObjectStorageDriver.RestoreFileVersion(cmd)
+}
+
+func (d *Driver) DeleteFileVersion(cmd *FileApplication.DeleteFileVersionCommand) error {
+	defer d.invalidate(cmd.Bucket)
+	return d.ObjectStorageDriver.DeleteFileVersion(cmd)
+}
+
+// Usage of the bucket without its own quota is reported without limits, but only if it belongs to an owner.
+func (d *Driver) GetQuotaUsage(query *FileApplication.GetQuotaUsageQuery) (*entity.QuotaUsage, error) {
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+
+	var limit Limit
+	var buckets []string
+	if query.Bucket != "" {
+		if !d.config.IsTracked(query.Bucket) {
+			return nil, fmt.Errorf("%w for bucket \"%s\"", FileApplication.ErrNoQuota, query.Bucket)
+		}
+		limit = d.config.Buckets[query.Bucket]
+		buckets = []string{query.Bucket}
+	} else {
+		owner, ok := d.config.Owners[query.Owner]
+		if !ok {
+			return nil, fmt.Errorf("%w for owner \"%s\"", FileApplication.ErrNoQuota, query.Owner)
+		}
+		limit = owner.Limit
+		buckets = owner.Buckets
+	}
+
+	for _, bucket := range buckets {
+		if err := d.load(bucket, query.CommandQuery); err != nil {
+			return nil, err
+		}
+	}
+
+	d.mu.Lock()
+	total := d.total(buckets...)
+	d.mu.Unlock()
+
+	return &entity.QuotaUsage{
+		UsedBytes:   total.bytes,
+		UsedObjects: total.objects,
+		MaxBytes:    limit.MaxBytes,
+		MaxObjects:  limit.MaxObjects,
+	}, nil
+}
@@ -0,0 +1,221 @@
+package quota
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+	FileApplication "vega_file_repository/packages/application/file"
+	"vega_file_repository/packages/domain/entity"
+	objectstorage "vega_file_repository/packages/infrastructure/object-storage"
+	StorageConnection "vega_file_repository/packages/infrastructure/object-storage/connection"
+	"vega_file_repository/packages/infrastructure/object-storage/local"
+)
+
+// Rejected writes mustn't read their content
+type unreadableContent struct {
+	t *testing.T
+}
+
+func (r unreadableContent) Read(p []byte) (int, error) {
+	r.t.Errorf("Content of the rejected write was read")
+	return 0, io.EOF
+}
+
+func upload(driver objectstorage.ObjectStorageDriver, bucket string, path string, size int) error {
+	_, err := driver.UploadFile(&FileApplication.UploadFileCommand{
+		Bucket:      bucket,
+		Path:        path,
+		Content:     bytes.NewReader(bytes.Repeat([]byte{'x'}, size)),
+		ContentSize: int64(size),
+	})
+	return err
+}
+
+func expectUsage(t *testing.T, driver *Driver, query *FileApplication.GetQuotaUsageQuery, expected entity.QuotaUsage) {
+	t.Helper()
+	usage, err := driver.GetQuotaUsage(query)
+	if err != nil {
+		t.Fatalf("Failed to get quota usage: %v", err)
+	}
+	if *usage != expected {
+		t.Errorf("Expected usage %+v, but got %+v", expected, *usage)
+	}
+}
+
+func TestQuotaDriver(t *testing.T) {
+	storage := local.InitDriver()
+	if err := storage.Connect(&StorageConnection.Config{URL: t.TempDir()}); err != nil {
+		t.Fatalf("Connection failed: %v", err)
+	}
+	defer storage.Disconnect()
+
+	config, err := ParseConfig([]byte(`{
+		"buckets": {"photos": {"max_bytes": 100, "max_objects": 3}},
+		"owners": {"alice": {"max_bytes": 150, "buckets": ["photos", "docs"]}}
+	}`))
+	if err != nil {
+		t.Fatalf("Failed to parse config: %v", err)
+	}
+
+	for _, bucket := range []string{"photos", "docs", "free"} {
+		if err := storage.MakeBucket(&FileApplication.MakeBucketCommand{Name: bucket}); err != nil {
+			t.Fatalf("Failed to create bucket: %v", err)
+		}
+	}
+	// Stored before quotas were enabled
+	if err := upload(storage, "photos", "/old.txt", 10); err != nil {
+		t.Fatalf("Failed to upload file: %v", err)
+	}
+	if err := storage.Mkdir(&FileApplication.MkdirCommand{Bucket: "photos", Path: "/album/"}); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+
+	driver := NewDriver(storage, config)
+	photos := &FileApplication.GetQuotaUsageQuery{Bucket: "photos"}
+	alice := &FileApplication.GetQuotaUsageQuery{Owner: "alice"}
+
+	t.Run("Existing files", func(t *testing.T) {
+		expectUsage(t, driver, photos, entity.QuotaUsage{UsedBytes: 10, UsedObjects: 1, MaxBytes: 100, MaxObjects: 3})
+	})
+
+	t.Run("Bucket quota", func(t *testing.T) {
+		if err := upload(driver, "photos", "/a.txt", 50); err != nil {
+			t.Fatalf("Upload within quota failed: %v", err)
+		}
+		_, err := driver.UploadFile(&FileApplication.UploadFileCommand{
+			Bucket:      "photos",
+			Path:        "/b.txt",
+			Content:     unreadableContent{t},
+			ContentSize: 50,
+		})
+		if !errors.Is(err, FileApplication.ErrQuotaExceeded) {
+			t.Errorf("Upload exceeding quota must fail with ErrQuotaExceeded, but got: %v", err)
+		}
+
+		// Only difference with the current content is counted
+		_, err = driver.UpdateFileContent(&FileApplication.UpdateFileContentCommand{
+			Bucket:     "photos",
+			Path:       "/a.txt",
+			NewContent: bytes.NewReader(bytes.Repeat([]byte{'y'}, 80)),
+			Size:       80,
+		})
+		if err != nil {
+			t.Fatalf("Update within quota failed: %v", err)
+		}
+		_, err = driver.UpdateFileContent(&FileApplication.UpdateFileContentCommand{
+			Bucket:     "photos",
+			Path:       "/a.txt",
+			NewContent: unreadableContent{t},
+			Size:       91,
+		})
+		if !errors.Is(err, FileApplication.ErrQuotaExceeded) {
+			t.Errorf("Update exceeding quota must fail with ErrQuotaExceeded, but got: %v", err)
+		}
+
+		if err := upload(driver, "photos", "/c.txt", 5); err != nil {
+			t.Fatalf("Upload within quota failed: %v", err)
+		}
+		if err := upload(driver, "photos", "/d.txt", 1); !errors.Is(err, FileApplication.ErrQuotaExceeded) {
+			t.Errorf("Upload exceeding object limit must fail with ErrQuotaExceeded, but got: %v", err)
+		}
+
+		expectUsage(t, driver, photos, entity.QuotaUsage{UsedBytes: 95, UsedObjects: 3, MaxBytes: 100, MaxObjects: 3})
+	})
+
+	t.Run("Owner quota", func(t *testing.T) {
+		if err := upload(driver, "docs", "/x.txt", 50); err != nil {
+			t.Fatalf("Upload within quota failed: %v", err)
+		}
+		if err := upload(driver, "docs", "/y.txt", 10); !errors.Is(err, FileApplication.ErrQuotaExceeded) {
+			t.Errorf("Upload exceeding quota of the owner must fail with ErrQuotaExceeded, but got: %v", err)
+		}
+
+		expectUsage(t, driver, alice, entity.QuotaUsage{UsedBytes: 145, UsedObjects: 4, MaxBytes: 150})
+		expectUsage(t, driver, &FileApplication.GetQuotaUsageQuery{Bucket: "docs"}, entity.QuotaUsage{UsedBytes: 50, UsedObjects: 1})
+	})
+
+	t.Run("Deletion", func(t *testing.T) {
+		_, err := driver.DeleteFiles(&FileApplication.DeleteFilesCommand{
+			Bucket: "photos",
+			Paths:  []string{"/c.txt", "/missing.txt"},
+		})
+		if err != nil {
+			t.Fatalf("Failed to delete files: %v", err)
+		}
+		expectUsage(t, driver, photos, entity.QuotaUsage{UsedBytes: 90, UsedObjects: 2, MaxBytes: 100, MaxObjects: 3})
+
+		if err := upload(driver, "docs", "/y.txt", 5); err != nil {
+			t.Errorf("Upload within freed quota failed: %v", err)
+		}
+	})
+
+	t.Run("Relocation", func(t *testing.T) {
+		_, err := driver.CopyFiles(&FileApplication.CopyFilesCommand{
+			FilesRelocation: FileApplication.FilesRelocation{
+				SourceBucket:      "photos",
+				SourcePath:        "/old.txt",
+				DestinationBucket: "docs",
+				DestinationPath:   "/old.txt",
+			},
+		})
+		if err != nil {
+			t.Fatalf("Failed to copy file: %v", err)
+		}
+		expectUsage(t, driver, &FileApplication.GetQuotaUsageQuery{Bucket: "docs"}, entity.QuotaUsage{UsedBytes: 65, UsedObjects: 3})
+		expectUsage(t, driver, alice, entity.QuotaUsage{UsedBytes: 155, UsedObjects: 5, MaxBytes: 150})
+
+		// Quota is already exceeded, but writes which reduce usage are allowed anyway
+		_, err = driver.UpdateFileContent(&FileApplication.UpdateFileContentCommand{
+			Bucket:     "docs",
+			Path:       "/x.txt",
+			NewContent: bytes.NewReader([]byte("shrunk")),
+			Size:       6,
+		})
+		if err != nil {
+			t.Errorf("Write reducing usage must be allowed: %v", err)
+		}
+	})
+
+	t.Run("Untracked bucket", func(t *testing.T) {
+		if err := upload(driver, "free", "/big.txt", 1000); err != nil {
+			t.Errorf("Upload into bucket without quota failed: %v", err)
+		}
+		if _, err := driver.GetQuotaUsage(&FileApplication.GetQuotaUsageQuery{Bucket: "free"}); !errors.Is(err, FileApplication.ErrNoQuota) {
+			t.Errorf("Usage of bucket without quota must fail with ErrNoQuota, but got: %v", err)
+		}
+		if _, err := driver.GetQuotaUsage(&FileApplication.GetQuotaUsageQuery{Owner: "bob"}); !errors.Is(err, FileApplication.ErrNoQuota) {
+			t.Errorf("Usage of unknown owner must fail with ErrNoQuota, but got: %v", err)
+		}
+		if _, err := driver.GetQuotaUsage(&FileApplication.GetQuotaUsageQuery{Bucket: "photos", Owner: "alice"}); err == nil {
+			t.Errorf("Query with both bucket and owner must be rejected")
+		}
+	})
+}
+
+func TestParseConfig(t *testing.T) {
+	config, err := ParseConfig([]byte(`{"buckets": {"a": {"max_objects": 1}}, "owners": {"bob": {"buckets": ["b"]}}}`))
+	if err != nil {
+		t.Fatalf("Failed to parse config: %v", err)
+	}
+	if !config.IsTracked("a") || !config.IsTracked("b") || config.IsTracked("c") {
+		t.Errorf("Only buckets with quota or owner must be tracked")
+	}
+	if config.Owner("b") != "bob" || config.Owner("a") != "" {
+		t.Errorf("Invalid owners of the buckets")
+	}
+
+	invalid := []string{
+		``,
+		`{}`,
+		`{"buckets": {"a": {"max_bytes": -1}}}`,
+		`{"buckets": {"a": {"max_size": 1}}}`,
+		`{"owners": {"bob": {"max_bytes": 1}}}`,
+		`{"owners": {"bob": {"buckets": ["a"]}, "alice": {"buckets": ["a"]}}}`,
+	}
+	for _, data := range invalid {
+		if _, err := ParseConfig([]byte(data)); err == nil {
+			t.Errorf("Config must be rejected: %s", data)
+		}
+	}
+}
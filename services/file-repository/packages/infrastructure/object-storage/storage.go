@@ -1,9 +1,11 @@
 package objectstorage
 
 import (
+	"fmt"
 	FileApplication "vega_file_repository/packages/application/file"
 	minio "vega_file_repository/packages/infrastructure/object-storage/MinIO"
 	StorageConnection "vega_file_repository/packages/infrastructure/object-storage/connection"
+	"vega_file_repository/packages/infrastructure/object-storage/local"
 )
 
 type ObjectStorageDriver interface {
@@ -11,4 +13,30 @@ type ObjectStorageDriver interface {
 	FileApplication.UseCases
 }
 
+type DriverName string
+
+const (
+	MinIODriver DriverName = "minio"
+	// MinIO driver which deduplicates content of uploaded files
+	MinIODeduplicatingDriver DriverName = "minio-dedup"
+	// Stores buckets as directories on local disk, doesn't require any containers
+	LocalDriver DriverName = "local"
+)
+
 var Driver ObjectStorageDriver = minio.InitDriver()
+
+// Replaces Driver with the one with specified name, if name is empty then MinIO driver is used.
+// Must be called before Driver is connected.
+func SelectDriver(name DriverName) error {
+	switch name {
+	case MinIODriver, "":
+		Driver = minio.InitDriver()
+	case MinIODeduplicatingDriver:
+		Driver = minio.InitDeduplicatingDriver()
+	case LocalDriver:
+		Driver = local.InitDriver()
+	default:
+		return fmt.Errorf("unknown object storage driver: \"%s\"", name)
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package ratelimit
+
+import (
+	"time"
+)
+
+// Classic token bucket: it's refilled with rate tokens per second up to burst tokens.
+// It isn't safe for concurrent use.
+type tokenBucket struct {
+	rate    float64
+	burst   float64
+	tokens  float64
+	updated time.Time
+}
+
+func newTokenBucket(rate float64, burst float64, now time.Time) *tokenBucket {
+	return &tokenBucket{
+		rate:    rate,
+		burst:   burst,
+		tokens:  burst,
+		updated: now,
+	}
+}
+
+func (b *tokenBucket) refill(now time.Time) {
+	if elapsed := now.Sub(b.updated); elapsed > 0 {
+		b.tokens = min(b.burst, b.tokens+elapsed.Seconds()*b.rate)
+		b.updated = now
+	}
+}
+
+func (b *tokenBucket) durationOf(tokens float64) time.Duration {
+	return time.Duration(tokens / b.rate * float64(time.Second))
+}
+
+// Takes n tokens if they are available, otherwise returns time after which they will be
+func (b *tokenBucket) take(n float64, now time.Time) time.Duration {
+	b.refill(now)
+	if b.tokens >= n {
+		b.tokens -= n
+		return 0
+	}
+	return b.durationOf(n - b.tokens)
+}
+
+// Takes n tokens even if they aren't available (so bucket may go into debt),
+// returns time after which debt will be paid off.
+func (b *tokenBucket) consume(n float64, now time.Time) time.Duration {
+	b.refill(now)
+	b.tokens -= n
+	return b.debt()
+}
+
+func (b *tokenBucket) debt() time.Duration {
+	if b.tokens >= 0 {
+		return 0
+	}
+	return b.durationOf(-b.tokens)
+}
@@ -0,0 +1,84 @@
+package ratelimit
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+var ErrEmptyConfig = errors.New("rate limits config must define default limits or limits of at least one caller")
+
+// Limits of a single caller, 0 means that there is no limit
+type Limits struct {
+	RequestsPerSecond float64 `json:"requests_per_second"`
+	// Max number of requests which can be made at once, by default it's RequestsPerSecond (at least 1)
+	RequestBurst   int64 `json:"request_burst"`
+	BytesPerSecond int64 `json:"bytes_per_second"`
+	// By default it's BytesPerSecond
+	ByteBurst            int64 `json:"byte_burst"`
+	MaxConcurrentStreams int   `json:"max_concurrent_streams"`
+}
+
+// Default limits are applied to all callers which don't have their own limits.
+// Callers are identified by subject of their token or, if request isn't authenticated, by IP address.
+type Config struct {
+	Default *Limits           `json:"default"`
+	Callers map[string]Limits `json:"callers"`
+}
+
+// Parses config in JSON format:
+//
+//	{
+//	  "default": {"requests_per_second": 50, "bytes_per_second": 52428800, "max_concurrent_streams": 4},
+//	  "callers": {"backup-service": {"requests_per_second": 200, "request_burst": 500, "max_concurrent_streams": 32}}
+//	}
+func ParseConfig(data []byte) (*Config, error) {
+	config := &Config{}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(config); err != nil {
+		return nil, fmt.Errorf("invalid rate limits config: %v", err)
+	}
+
+	if config.Default == nil && len(config.Callers) == 0 {
+		return nil, ErrEmptyConfig
+	}
+	if config.Default != nil {
+		if err := config.Default.validate(); err != nil {
+			return nil, fmt.Errorf("invalid default limits: %v", err)
+		}
+	}
+	for caller, limits := range config.Callers {
+		if err := limits.validate(); err != nil {
+			return nil, fmt.Errorf("invalid limits of caller \"%s\": %v", caller, err)
+		}
+	}
+
+	return config, nil
+}
+
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseConfig(data)
+}
+
+func (l Limits) validate() error {
+	if l.RequestsPerSecond < 0 || l.RequestBurst < 0 || l.BytesPerSecond < 0 || l.ByteBurst < 0 || l.MaxConcurrentStreams < 0 {
+		return errors.New("limits can't be negative")
+	}
+	return nil
+}
+
+// Returns nil if caller isn't limited at all
+func (c *Config) limitsOf(caller string) *Limits {
+	if limits, ok := c.Callers[caller]; ok {
+		return &limits
+	}
+	return c.Default
+}
@@ -0,0 +1,175 @@
+// Per caller limits of request rate, transfer rate and number of concurrent streams.
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// State of the callers which weren't seen for this long is dropped
+const idleCallerTTL = time.Minute * 10
+
+// Number of concurrent streams isn't refilled over time, so clients are advised to retry after this delay
+const streamRetryAfter = time.Second
+
+var ErrRateLimited = errors.New("rate limit exceeded")
+
+// Returned if caller exceeded one of its limits
+type LimitError struct {
+	Caller string
+	// Name of the exceeded limit (the same as in the config)
+	Limit string
+	// How long caller should wait before retrying
+	RetryAfter time.Duration
+}
+
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("%s: %s of \"%s\" exceeded, retry after %s", ErrRateLimited, e.Limit, e.Caller, e.RetryAfter)
+}
+
+func (e *LimitError) Is(target error) bool {
+	return target == ErrRateLimited
+}
+
+type callerState struct {
+	// nil if corresponding rate isn't limited
+	requests *tokenBucket
+	bytes    *tokenBucket
+
+	streams  int
+	lastSeen time.Time
+}
+
+type Limiter struct {
+	config *Config
+
+	mu          sync.Mutex
+	callers     map[string]*callerState
+	lastCleanup time.Time
+}
+
+func NewLimiter(config *Config) *Limiter {
+	return &Limiter{
+		config:      config,
+		callers:     make(map[string]*callerState),
+		lastCleanup: time.Now(),
+	}
+}
+
+// Must be called with mu locked, returns nil if caller isn't limited
+func (l *Limiter) stateOf(caller string, now time.Time) (*callerState, *Limits) {
+	limits := l.config.limitsOf(caller)
+	if limits == nil {
+		return nil, nil
+	}
+
+	if now.Sub(l.lastCleanup) > idleCallerTTL {
+		for key, state := range l.callers {
+			if state.streams == 0 && now.Sub(state.lastSeen) > idleCallerTTL {
+				delete(l.callers, key)
+			}
+		}
+		l.lastCleanup = now
+	}
+
+	state, ok := l.callers[caller]
+	if !ok {
+		state = &callerState{}
+		if limits.RequestsPerSecond > 0 {
+			burst := float64(limits.RequestBurst)
+			if burst == 0 {
+				burst = max(limits.RequestsPerSecond, 1)
+			}
+			state.requests = newTokenBucket(limits.RequestsPerSecond, burst, now)
+		}
+		if limits.BytesPerSecond > 0 {
+			burst := limits.ByteBurst
+			if burst == 0 {
+				burst = limits.BytesPerSecond
+			}
+			state.bytes = newTokenBucket(float64(limits.BytesPerSecond), float64(burst), now)
+		}
+		l.callers[caller] = state
+	}
+	state.lastSeen = now
+
+	return state, limits
+}
+
+// Must be called before each request. Requests are also rejected while caller has transferred
+// more bytes than its byte rate allows (since transfers of the running streams aren't rejected, but only slowed down).
+func (l *Limiter) AllowRequest(caller string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	state, _ := l.stateOf(caller, now)
+	if state == nil {
+		return nil
+	}
+
+	if state.bytes != nil {
+		state.bytes.refill(now)
+		if debt := state.bytes.debt(); debt > 0 {
+			return &LimitError{Caller: caller, Limit: "bytes_per_second", RetryAfter: debt}
+		}
+	}
+	if state.requests != nil {
+		if retryAfter := state.requests.take(1, now); retryAfter > 0 {
+			return &LimitError{Caller: caller, Limit: "requests_per_second", RetryAfter: retryAfter}
+		}
+	}
+	return nil
+}
+
+// Returned release function must be called once stream is finished
+func (l *Limiter) AcquireStream(caller string) (release func(), err error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	state, limits := l.stateOf(caller, time.Now())
+	if state == nil {
+		return func() {}, nil
+	}
+	if limits.MaxConcurrentStreams > 0 && state.streams >= limits.MaxConcurrentStreams {
+		return nil, &LimitError{Caller: caller, Limit: "max_concurrent_streams", RetryAfter: streamRetryAfter}
+	}
+	state.streams++
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			l.mu.Lock()
+			defer l.mu.Unlock()
+			state.streams--
+		})
+	}, nil
+}
+
+// Accounts n transferred bytes and blocks until caller is within its byte rate again.
+// Returns error only if context is done before that.
+func (l *Limiter) WaitBytes(ctx context.Context, caller string, n int) error {
+	l.mu.Lock()
+	var wait time.Duration
+	state, _ := l.stateOf(caller, time.Now())
+	if state != nil && state.bytes != nil {
+		wait = state.bytes.consume(float64(n), time.Now())
+	}
+	l.mu.Unlock()
+
+	if wait <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
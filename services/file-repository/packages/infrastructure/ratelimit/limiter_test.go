@@ -0,0 +1,106 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestLimiter(t *testing.T) {
+	config, err := ParseConfig([]byte(`{
+		"default": {"requests_per_second": 10, "request_burst": 2, "max_concurrent_streams": 1},
+		"callers": {
+			"unlimited": {},
+			"uploader": {"bytes_per_second": 1000}
+		}
+	}`))
+	if err != nil {
+		t.Fatalf("Failed to parse config: %v", err)
+	}
+	limiter := NewLimiter(config)
+
+	t.Run("Requests", func(t *testing.T) {
+		for i := 0; i < 2; i++ {
+			if err := limiter.AllowRequest("client"); err != nil {
+				t.Fatalf("Requests within burst must be allowed: %v", err)
+			}
+		}
+		err := limiter.AllowRequest("client")
+		var limitErr *LimitError
+		if !errors.As(err, &limitErr) || !errors.Is(err, ErrRateLimited) {
+			t.Fatalf("Request exceeding burst must be rejected, but got: %v", err)
+		}
+		if limitErr.Limit != "requests_per_second" || limitErr.RetryAfter <= 0 || limitErr.RetryAfter > time.Second/10 {
+			t.Errorf("Unexpected limit error: %+v", limitErr)
+		}
+		if err := limiter.AllowRequest("another-client"); err != nil {
+			t.Errorf("Callers must be limited independently: %v", err)
+		}
+
+		time.Sleep(limitErr.RetryAfter)
+		if err := limiter.AllowRequest("client"); err != nil {
+			t.Errorf("Request must be allowed after retry delay: %v", err)
+		}
+
+		for i := 0; i < 100; i++ {
+			if err := limiter.AllowRequest("unlimited"); err != nil {
+				t.Fatalf("Caller with own limits must not be limited by default ones: %v", err)
+			}
+		}
+	})
+
+	t.Run("Streams", func(t *testing.T) {
+		release, err := limiter.AcquireStream("client")
+		if err != nil {
+			t.Fatalf("First stream must be allowed: %v", err)
+		}
+		if _, err := limiter.AcquireStream("client"); !errors.Is(err, ErrRateLimited) {
+			t.Errorf("Stream exceeding the limit must be rejected, but got: %v", err)
+		}
+		release()
+		release()
+		release, err = limiter.AcquireStream("client")
+		if err != nil {
+			t.Fatalf("Stream must be allowed once previous one is released: %v", err)
+		}
+		release()
+	})
+
+	t.Run("Bytes", func(t *testing.T) {
+		start := time.Now()
+		// Burst is 1000 bytes, so the first wait is immediate and the second one must pay off 200 bytes
+		if err := limiter.WaitBytes(context.Background(), "uploader", 1000); err != nil {
+			t.Fatalf("Transfer within burst must not fail: %v", err)
+		}
+		if err := limiter.WaitBytes(context.Background(), "uploader", 200); err != nil {
+			t.Fatalf("Transfer must be slowed down, not failed: %v", err)
+		}
+		if elapsed := time.Since(start); elapsed < time.Millisecond*150 {
+			t.Errorf("Transfer exceeding byte rate must be slowed down, but took only %s", elapsed)
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		if err := limiter.WaitBytes(ctx, "uploader", 1000); !errors.Is(err, context.Canceled) {
+			t.Errorf("Wait must be interrupted by context, but got: %v", err)
+		}
+		if err := limiter.AllowRequest("uploader"); !errors.Is(err, ErrRateLimited) {
+			t.Errorf("New requests must be rejected while caller exceeds byte rate, but got: %v", err)
+		}
+	})
+}
+
+func TestParseConfig(t *testing.T) {
+	invalid := []string{
+		`{}`,
+		`{"default": {"requests_per_second": -1}}`,
+		`{"callers": {"client": {"max_concurrent_streams": -1}}}`,
+		`{"default": {"rps": 10}}`,
+	}
+	for _, data := range invalid {
+		if _, err := ParseConfig([]byte(data)); err == nil {
+			t.Errorf("Config must be rejected: %s", data)
+		}
+	}
+}
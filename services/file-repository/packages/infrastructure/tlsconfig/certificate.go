@@ -0,0 +1,57 @@
+package tlsconfig
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+)
+
+var ErrNoCertificates = errors.New("CA bundle doesn't contain any PEM certificates")
+
+// Certificate with its private key, both in PEM format
+type Certificate struct {
+	files *watchedFiles[*tls.Certificate]
+}
+
+func LoadCertificate(certFile string, keyFile string) (*Certificate, error) {
+	files, err := watchFiles(func(contents [][]byte) (*tls.Certificate, error) {
+		cert, err := tls.X509KeyPair(contents[0], contents[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid certificate: %v", err)
+		}
+		return &cert, nil
+	}, certFile, keyFile)
+	if err != nil {
+		return nil, err
+	}
+	return &Certificate{files: files}, nil
+}
+
+// Can be used as tls.Config.GetCertificate
+func (c *Certificate) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return c.files.current(), nil
+}
+
+// Bundle of trusted CA certificates in PEM format
+type CAPool struct {
+	files *watchedFiles[*x509.CertPool]
+}
+
+func LoadCAPool(path string) (*CAPool, error) {
+	files, err := watchFiles(func(contents [][]byte) (*x509.CertPool, error) {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(contents[0]) {
+			return nil, ErrNoCertificates
+		}
+		return pool, nil
+	}, path)
+	if err != nil {
+		return nil, err
+	}
+	return &CAPool{files: files}, nil
+}
+
+func (p *CAPool) Pool() *x509.CertPool {
+	return p.files.current()
+}
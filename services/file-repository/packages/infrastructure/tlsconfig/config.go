@@ -0,0 +1,61 @@
+package tlsconfig
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+)
+
+// Config of the server which presents cert. If clientCAs isn't nil, then clients must present
+// certificates signed by one of them (mutual TLS), otherwise client certificates aren't requested.
+func ServerConfig(cert *Certificate, clientCAs *CAPool) *tls.Config {
+	config := &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: cert.GetCertificate,
+	}
+	if clientCAs != nil {
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+		// ClientCAs are fixed once config is created, so config with the current bundle is made for each handshake
+		config.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			return &tls.Config{
+				MinVersion:     tls.VersionTLS12,
+				GetCertificate: cert.GetCertificate,
+				ClientAuth:     tls.RequireAndVerifyClientCert,
+				ClientCAs:      clientCAs.Pool(),
+			}, nil
+		}
+	}
+	return config
+}
+
+// Config of the client which trusts only CAs of the pool (instead of the system ones).
+// Certificate of the server must be issued for serverName, which is either host name or IP address.
+func ClientConfig(rootCAs *CAPool, serverName string) *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: serverName,
+		// RootCAs are fixed once config is created, so default verification is replaced
+		// with the same one, but against the current bundle. Connection is still verified.
+		InsecureSkipVerify: true,
+		VerifyConnection: func(state tls.ConnectionState) error {
+			if len(state.PeerCertificates) == 0 {
+				return errors.New("server didn't present any certificates")
+			}
+			// state.ServerName isn't used, since it's empty if server is addressed by IP (there is no SNI then),
+			// and verification of the certificate would skip the check of its names
+			if serverName == "" {
+				return errors.New("name of the server to verify isn't specified")
+			}
+			intermediates := x509.NewCertPool()
+			for _, cert := range state.PeerCertificates[1:] {
+				intermediates.AddCert(cert)
+			}
+			_, err := state.PeerCertificates[0].Verify(x509.VerifyOptions{
+				DNSName:       serverName,
+				Roots:         rootCAs.Pool(),
+				Intermediates: intermediates,
+			})
+			return err
+		},
+	}
+}
@@ -0,0 +1,103 @@
+// TLS configs of the servers and storage clients whose certificates are reloaded from disk once they rotate.
+package tlsconfig
+
+import (
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Files are checked for modification lazily (during handshakes), but not more often than this.
+// Variable instead of constant, so tests don't have to wait for too long.
+var reloadCheckInterval = time.Second * 10
+
+// Content parsed from files, which is parsed again once any of the files is modified.
+// If new content can't be parsed (e.g. certificate is already replaced, but its key isn't yet),
+// then the previous one is kept until the next check.
+type watchedFiles[T any] struct {
+	paths []string
+	parse func(contents [][]byte) (T, error)
+
+	mu       sync.Mutex
+	value    T
+	modTimes []time.Time
+	checked  time.Time
+}
+
+func watchFiles[T any](parse func(contents [][]byte) (T, error), paths ...string) (*watchedFiles[T], error) {
+	w := &watchedFiles[T]{
+		paths: paths,
+		parse: parse,
+	}
+	value, modTimes, err := w.load()
+	if err != nil {
+		return nil, err
+	}
+	w.value = value
+	w.modTimes = modTimes
+	w.checked = time.Now()
+	return w, nil
+}
+
+func (w *watchedFiles[T]) load() (T, []time.Time, error) {
+	var zero T
+
+	contents := make([][]byte, len(w.paths))
+	modTimes := make([]time.Time, len(w.paths))
+	for i, path := range w.paths {
+		// Stat follows symlinks, so rotation via symlink swap (e.g. Kubernetes secrets) is detected as well
+		info, err := os.Stat(path)
+		if err != nil {
+			return zero, nil, err
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return zero, nil, err
+		}
+		contents[i] = content
+		modTimes[i] = info.ModTime()
+	}
+
+	value, err := w.parse(contents)
+	if err != nil {
+		return zero, nil, err
+	}
+	return value, modTimes, nil
+}
+
+func (w *watchedFiles[T]) modified() bool {
+	for i, path := range w.paths {
+		info, err := os.Stat(path)
+		if err != nil || !info.ModTime().Equal(w.modTimes[i]) {
+			return true
+		}
+	}
+	return false
+}
+
+func (w *watchedFiles[T]) current() T {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(w.checked) < reloadCheckInterval {
+		return w.value
+	}
+	w.checked = now
+
+	if !w.modified() {
+		return w.value
+	}
+	value, modTimes, err := w.load()
+	if err != nil {
+		log.Printf("Failed to reload %s, previous version is kept: %v\n", strings.Join(w.paths, ", "), err)
+		return w.value
+	}
+	w.value = value
+	w.modTimes = modTimes
+	log.Printf("Reloaded %s\n", strings.Join(w.paths, ", "))
+
+	return w.value
+}
@@ -0,0 +1,268 @@
+package tlsconfig
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newTestCA(t *testing.T, name string) *testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Failed to create CA certificate: %v", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("Failed to parse CA certificate: %v", err)
+	}
+	return &testCA{
+		cert: cert,
+		key:  key,
+		pem:  pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+	}
+}
+
+// Returns certificate and key in PEM format, which are valid for localhost and specified IPs
+func (ca *testCA) issue(t *testing.T, name string, ips ...net.IP) ([]byte, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  ips,
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatalf("Failed to create certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("Failed to marshal key: %v", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+// Writes file and moves its modification time forward, so it's detected even on filesystems with coarse timestamps
+func writeFile(t *testing.T, path string, content []byte) {
+	if err := os.WriteFile(path, content, 0o600); err != nil {
+		t.Fatalf("Failed to write %s: %v", path, err)
+	}
+	modTime := time.Now().Add(time.Second)
+	if info, err := os.Stat(path); err == nil && !info.ModTime().Before(modTime) {
+		modTime = info.ModTime().Add(time.Second)
+	}
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatalf("Failed to change modification time of %s: %v", path, err)
+	}
+}
+
+// Performs handshake between server and client configs, returns error of the client side
+func handshake(t *testing.T, serverConfig *tls.Config, clientConfig *tls.Config) error {
+	// Connections of net.Pipe aren't buffered, so failed handshake may block both sides on writing alerts
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	defer listener.Close()
+
+	go func() {
+		serverConn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		server := tls.Server(serverConn, serverConfig)
+		server.Handshake()
+		// Client learns whether its certificate is accepted only once it reads from the connection
+		server.Write([]byte{0})
+		server.Close()
+	}()
+
+	clientConn, err := net.Dial("tcp", listener.Addr().String())
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer clientConn.Close()
+
+	client := tls.Client(clientConn, clientConfig)
+	if err := client.Handshake(); err != nil {
+		return err
+	}
+	_, err = client.Read(make([]byte, 1))
+	return err
+}
+
+func TestTLSConfig(t *testing.T) {
+	defaultInterval := reloadCheckInterval
+	reloadCheckInterval = 0
+	defer func() { reloadCheckInterval = defaultInterval }()
+
+	dir := t.TempDir()
+	path := func(name string) string { return filepath.Join(dir, name) }
+
+	serverCA := newTestCA(t, "server-ca")
+	clientCA := newTestCA(t, "client-ca")
+
+	certPEM, keyPEM := serverCA.issue(t, "server")
+	writeFile(t, path("server.crt"), certPEM)
+	writeFile(t, path("server.key"), keyPEM)
+	writeFile(t, path("server-ca.pem"), serverCA.pem)
+	writeFile(t, path("client-ca.pem"), clientCA.pem)
+
+	cert, err := LoadCertificate(path("server.crt"), path("server.key"))
+	if err != nil {
+		t.Fatalf("Failed to load certificate: %v", err)
+	}
+	clientCAs, err := LoadCAPool(path("client-ca.pem"))
+	if err != nil {
+		t.Fatalf("Failed to load client CA pool: %v", err)
+	}
+	rootCAs, err := LoadCAPool(path("server-ca.pem"))
+	if err != nil {
+		t.Fatalf("Failed to load root CA pool: %v", err)
+	}
+
+	newClientConfig := func(clientCert *tls.Certificate) *tls.Config {
+		config := ClientConfig(rootCAs, "localhost")
+		if clientCert != nil {
+			config.Certificates = []tls.Certificate{*clientCert}
+		}
+		return config
+	}
+	clientCertPEM, clientKeyPEM := clientCA.issue(t, "client")
+	clientCert, err := tls.X509KeyPair(clientCertPEM, clientKeyPEM)
+	if err != nil {
+		t.Fatalf("Failed to parse client certificate: %v", err)
+	}
+
+	t.Run("TLS", func(t *testing.T) {
+		if err := handshake(t, ServerConfig(cert, nil), newClientConfig(nil)); err != nil {
+			t.Errorf("Handshake must succeed: %v", err)
+		}
+
+		if err := handshake(t, ServerConfig(cert, nil), ClientConfig(rootCAs, "example.com")); err == nil {
+			t.Errorf("Certificate issued for another name must be rejected")
+		}
+	})
+
+	t.Run("IP endpoint", func(t *testing.T) {
+		// Client sends no SNI for IP addresses, so the name to verify must come from the endpoint itself
+		if err := handshake(t, ServerConfig(cert, nil), ClientConfig(rootCAs, "127.0.0.1")); err == nil {
+			t.Errorf("Certificate which isn't issued for the IP address must be rejected")
+		}
+
+		ipCertPEM, ipKeyPEM := serverCA.issue(t, "ip-server", net.ParseIP("127.0.0.1"))
+		writeFile(t, path("ip-server.crt"), ipCertPEM)
+		writeFile(t, path("ip-server.key"), ipKeyPEM)
+		ipCert, err := LoadCertificate(path("ip-server.crt"), path("ip-server.key"))
+		if err != nil {
+			t.Fatalf("Failed to load certificate: %v", err)
+		}
+		if err := handshake(t, ServerConfig(ipCert, nil), ClientConfig(rootCAs, "127.0.0.1")); err != nil {
+			t.Errorf("Certificate issued for the IP address must be accepted: %v", err)
+		}
+		if err := handshake(t, ServerConfig(ipCert, nil), ClientConfig(rootCAs, "127.0.0.2")); err == nil {
+			t.Errorf("Certificate issued for another IP address must be rejected")
+		}
+	})
+
+	t.Run("Mutual TLS", func(t *testing.T) {
+		if err := handshake(t, ServerConfig(cert, clientCAs), newClientConfig(&clientCert)); err != nil {
+			t.Errorf("Handshake with trusted client certificate must succeed: %v", err)
+		}
+		if err := handshake(t, ServerConfig(cert, clientCAs), newClientConfig(nil)); err == nil {
+			t.Errorf("Handshake without client certificate must fail")
+		}
+
+		untrustedCertPEM, untrustedKeyPEM := serverCA.issue(t, "untrusted-client")
+		untrustedCert, err := tls.X509KeyPair(untrustedCertPEM, untrustedKeyPEM)
+		if err != nil {
+			t.Fatalf("Failed to parse client certificate: %v", err)
+		}
+		if err := handshake(t, ServerConfig(cert, clientCAs), newClientConfig(&untrustedCert)); err == nil {
+			t.Errorf("Handshake with untrusted client certificate must fail")
+		}
+	})
+
+	t.Run("Reload", func(t *testing.T) {
+		rotatedCertPEM, rotatedKeyPEM := serverCA.issue(t, "rotated-server")
+		writeFile(t, path("server.crt"), rotatedCertPEM)
+		// Key doesn't match the certificate yet, so the previous pair must be kept
+		current, _ := cert.GetCertificate(nil)
+		if leaf, err := x509.ParseCertificate(current.Certificate[0]); err != nil || leaf.Subject.CommonName != "server" {
+			t.Errorf("Previous certificate must be kept until its key is rotated as well")
+		}
+		writeFile(t, path("server.key"), rotatedKeyPEM)
+		current, _ = cert.GetCertificate(nil)
+		if leaf, err := x509.ParseCertificate(current.Certificate[0]); err != nil || leaf.Subject.CommonName != "rotated-server" {
+			t.Errorf("Certificate must be reloaded once it's rotated")
+		}
+
+		// Server certificate is still signed by the previous CA
+		writeFile(t, path("server-ca.pem"), newTestCA(t, "another-ca").pem)
+		if err := handshake(t, ServerConfig(cert, nil), newClientConfig(nil)); err == nil {
+			t.Errorf("Server certificate must be rejected once its CA is removed from the bundle")
+		}
+
+		writeFile(t, path("client-ca.pem"), serverCA.pem)
+		if err := handshake(t, ServerConfig(cert, clientCAs), newClientConfig(&clientCert)); err == nil {
+			t.Errorf("Client certificate must be rejected once its CA is removed from the bundle")
+		}
+
+		writeFile(t, path("client-ca.pem"), []byte("not a certificate"))
+		if rootCAs.Pool() == nil || clientCAs.Pool() == nil {
+			t.Errorf("Invalid bundle must not replace the previous one")
+		}
+	})
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "empty.pem"), []byte{})
+
+	if _, err := LoadCAPool(filepath.Join(dir, "empty.pem")); err != ErrNoCertificates {
+		t.Errorf("Expected ErrNoCertificates, but got: %v", err)
+	}
+	if _, err := LoadCAPool(filepath.Join(dir, "missing.pem")); err == nil {
+		t.Errorf("Missing bundle must be rejected")
+	}
+	if _, err := LoadCertificate(filepath.Join(dir, "empty.pem"), filepath.Join(dir, "empty.pem")); err == nil {
+		t.Errorf("Invalid certificate must be rejected")
+	}
+}
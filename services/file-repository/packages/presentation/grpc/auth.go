@@ -0,0 +1,252 @@
+package grpc
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"vega_file_repository/packages/infrastructure/auth"
+
+	file_repository "github.com/abaxoth0/Vega/common/protobuf/generated/go/services/file-repository"
+	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	reflectionalphapb "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+)
+
+// Bucket and access to it which is required by the request
+type bucketAccess struct {
+	bucket string
+	access auth.Access
+}
+
+// Returns all buckets which are accessed by the request,
+// empty result means that request requires only authentication.
+type accessRule func(req any) []bucketAccess
+
+type bucketRequest interface {
+	GetBucket() string
+}
+
+type bucketNameRequest interface {
+	GetName() string
+}
+
+func bucketOf(req any) string {
+	switch r := req.(type) {
+	case *file_repository.FileContentRequest:
+		return r.GetHeader().GetBucket()
+	case *file_repository.UploadPartRequest:
+		return r.GetHeader().GetBucket()
+	case bucketRequest:
+		return r.GetBucket()
+	case bucketNameRequest:
+		return r.GetName()
+	}
+	return ""
+}
+
+func bucketRule(access auth.Access) accessRule {
+	return func(req any) []bucketAccess {
+		return []bucketAccess{{bucketOf(req), access}}
+	}
+}
+
+func relocationRule(sourceAccess auth.Access) accessRule {
+	return func(req any) []bucketAccess {
+		r := req.(*file_repository.RelocateFilesRequest)
+		return []bucketAccess{
+			{r.GetSourceBucket(), sourceAccess},
+			{r.GetDestinationBucket(), auth.AccessWrite},
+		}
+	}
+}
+
+var (
+	readRule  = bucketRule(auth.AccessRead)
+	writeRule = bucketRule(auth.AccessWrite)
+	adminRule = bucketRule(auth.AccessAdmin)
+)
+
+// Methods which aren't listed here (except public ones) are denied
+var accessRules = map[string]accessRule{
+	file_repository.FileRepositoryService_GetFileByPath_FullMethodName:     readRule,
+	file_repository.FileRepositoryService_ListDirectory_FullMethodName:     readRule,
+	file_repository.FileRepositoryService_ListUploadedParts_FullMethodName: readRule,
+	file_repository.FileRepositoryService_StatFile_FullMethodName:          readRule,
+	file_repository.FileRepositoryService_GetBucketInfo_FullMethodName:     readRule,
+	file_repository.FileRepositoryService_ListFileVersions_FullMethodName:  readRule,
+
+	file_repository.FileRepositoryService_Mkdir_FullMethodName:              writeRule,
+	file_repository.FileRepositoryService_UploadFile_FullMethodName:         writeRule,
+	file_repository.FileRepositoryService_UpdateFileContent_FullMethodName:  writeRule,
+	file_repository.FileRepositoryService_DeleteFiles_FullMethodName:        writeRule,
+	file_repository.FileRepositoryService_InitiateUpload_FullMethodName:     writeRule,
+	file_repository.FileRepositoryService_UploadPart_FullMethodName:         writeRule,
+	file_repository.FileRepositoryService_CompleteUpload_FullMethodName:     writeRule,
+	file_repository.FileRepositoryService_AbortUpload_FullMethodName:        writeRule,
+	file_repository.FileRepositoryService_RestoreFileVersion_FullMethodName: writeRule,
+	file_repository.FileRepositoryService_DeleteFileVersion_FullMethodName:  writeRule,
+
+	file_repository.FileRepositoryService_CreateBucket_FullMethodName:        adminRule,
+	file_repository.FileRepositoryService_DeleteBucket_FullMethodName:        adminRule,
+	file_repository.FileRepositoryService_SetBucketVersioning_FullMethodName: adminRule,
+
+	file_repository.FileRepositoryService_CopyFiles_FullMethodName: relocationRule(auth.AccessRead),
+	file_repository.FileRepositoryService_MoveFiles_FullMethodName: relocationRule(auth.AccessWrite),
+
+	// Presigned URL grants the same access as the request itself
+	file_repository.FileRepositoryService_CreatePresignedURL_FullMethodName: func(req any) []bucketAccess {
+		r := req.(*file_repository.CreatePresignedURLRequest)
+		access := auth.AccessRead
+		if strings.ToUpper(r.GetMethod()) != http.MethodGet {
+			access = auth.AccessWrite
+		}
+		return []bucketAccess{{r.GetBucket(), access}}
+	},
+	// Quota of the owner covers several buckets, so it's available only to those who can administrate all of them
+	file_repository.FileRepositoryService_GetQuotaUsage_FullMethodName: func(req any) []bucketAccess {
+		r := req.(*file_repository.GetQuotaUsageRequest)
+		if r.GetOwner() != "" {
+			return []bucketAccess{{auth.Wildcard, auth.AccessAdmin}}
+		}
+		return []bucketAccess{{r.GetBucket(), auth.AccessRead}}
+	},
+	// Response contains only buckets which caller can read (see filterBuckets)
+	file_repository.FileRepositoryService_ListBuckets_FullMethodName: func(req any) []bucketAccess {
+		return nil
+	},
+}
+
+// Methods which are available without authentication (and aren't rate limited),
+// health checks are used by probes which have no tokens, reflection is disabled by default.
+var publicMethods = map[string]bool{
+	file_repository.FileRepositoryService_HealthCheck_FullMethodName: true,
+
+	healthpb.Health_Check_FullMethodName: true,
+	healthpb.Health_List_FullMethodName:  true,
+	healthpb.Health_Watch_FullMethodName: true,
+
+	reflectionpb.ServerReflection_ServerReflectionInfo_FullMethodName:      true,
+	reflectionalphapb.ServerReflection_ServerReflectionInfo_FullMethodName: true,
+}
+
+// Authenticates requests via bearer token in "authorization" metadata
+// and authorizes them according to the policy before they reach the storage.
+type authorizer struct {
+	verifier *auth.Verifier
+	policy   *auth.Policy
+}
+
+func (a *authorizer) authenticate(ctx context.Context) (*auth.Identity, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return nil, auth.ErrMissingToken
+	}
+	token, ok := strings.CutPrefix(values[0], "Bearer ")
+	if !ok {
+		return nil, auth.ErrMissingToken
+	}
+	return a.verifier.Verify(strings.TrimSpace(token))
+}
+
+func (a *authorizer) authorize(identity *auth.Identity, method string, req any) error {
+	rule, ok := accessRules[method]
+	if !ok {
+		return statusError(auth.ErrPermissionDenied, "", "")
+	}
+	for _, required := range rule(req) {
+		if !a.policy.Allows(identity.Subject, required.bucket, required.access) {
+			return statusError(auth.ErrPermissionDenied, required.bucket, "")
+		}
+	}
+	return nil
+}
+
+func (a *authorizer) filterBuckets(identity *auth.Identity, resp *file_repository.ListBucketsResponse) {
+	buckets := make([]*file_repository.Bucket, 0, len(resp.GetBuckets()))
+	for _, bucket := range resp.GetBuckets() {
+		if a.policy.Allows(identity.Subject, bucket.GetName(), auth.AccessRead) {
+			buckets = append(buckets, bucket)
+		}
+	}
+	resp.Buckets = buckets
+}
+
+func (a *authorizer) unaryInterceptor(
+	ctx context.Context,
+	req any,
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (any, error) {
+	if publicMethods[info.FullMethod] {
+		return handler(ctx, req)
+	}
+
+	identity, err := a.authenticate(ctx)
+	if err != nil {
+		return nil, statusError(err, "", "")
+	}
+	if err := a.authorize(identity, info.FullMethod, req); err != nil {
+		return nil, err
+	}
+
+	resp, err := handler(auth.WithIdentity(ctx, identity), req)
+	if list, ok := resp.(*file_repository.ListBucketsResponse); ok && err == nil {
+		a.filterBuckets(identity, list)
+	}
+	return resp, err
+}
+
+// Buckets of streamed requests are known only after the first message is received,
+// so it's authorized before handler gets it.
+type authorizedStream struct {
+	grpc.ServerStream
+	ctx        context.Context
+	authorizer *authorizer
+	identity   *auth.Identity
+	method     string
+	authorized bool
+}
+
+func (s *authorizedStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *authorizedStream) RecvMsg(m any) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	if !s.authorized {
+		if err := s.authorizer.authorize(s.identity, s.method, m); err != nil {
+			return err
+		}
+		s.authorized = true
+	}
+	return nil
+}
+
+func (a *authorizer) streamInterceptor(
+	srv any,
+	stream grpc.ServerStream,
+	info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler,
+) error {
+	if publicMethods[info.FullMethod] {
+		return handler(srv, stream)
+	}
+
+	identity, err := a.authenticate(stream.Context())
+	if err != nil {
+		return statusError(err, "", "")
+	}
+
+	return handler(srv, &authorizedStream{
+		ServerStream: stream,
+		ctx:          auth.WithIdentity(stream.Context(), identity),
+		authorizer:   a,
+		identity:     identity,
+		method:       info.FullMethod,
+	})
+}
@@ -0,0 +1,149 @@
+package grpc
+
+import (
+	"context"
+	"net/http"
+	"time"
+	fileapplication "vega_file_repository/packages/application/file"
+
+	file_repository "github.com/abaxoth0/Vega/common/protobuf/generated/go/services/file-repository"
+	cqrs "github.com/abaxoth0/Vega/libs/go/packages/CQRS"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Force deletion and bucket info require processing of all objects in the bucket
+const bucketProcessingTimeout = time.Minute * 10
+
+func (s *Server) CreateBucket(
+	ctx context.Context,
+	req *file_repository.CreateBucketRequest,
+) (*file_repository.StatusResponse, error) {
+	err := s.storage.MakeBucket(&fileapplication.MakeBucketCommand{
+		Name: req.GetName(),
+		// Default timeout will be used
+		CommandQuery: cqrs.CommandQuery{Context: ctx},
+	})
+	if err != nil {
+		return nil, statusError(err, req.GetName(), "")
+	}
+	return &file_repository.StatusResponse{
+		Status: http.StatusCreated,
+	}, nil
+}
+
+func (s *Server) DeleteBucket(
+	ctx context.Context,
+	req *file_repository.DeleteBucketRequest,
+) (*file_repository.StatusResponse, error) {
+	err := s.storage.DeleteBucket(&fileapplication.DeleteBucketCommand{
+		Name:  req.GetName(),
+		Force: req.GetForce(),
+		CommandQuery: cqrs.CommandQuery{
+			Context:        ctx,
+			ContextTimeout: bucketProcessingTimeout,
+		},
+	})
+	if err != nil {
+		return nil, statusError(err, req.GetName(), "")
+	}
+	return &file_repository.StatusResponse{
+		Status: http.StatusOK,
+	}, nil
+}
+
+func (s *Server) ListBuckets(
+	ctx context.Context,
+	req *file_repository.ListBucketsRequest,
+) (*file_repository.ListBucketsResponse, error) {
+	buckets, err := s.storage.ListBuckets(&fileapplication.ListBucketsQuery{
+		// Default timeout will be used
+		CommandQuery: cqrs.CommandQuery{Context: ctx},
+	})
+	if err != nil {
+		return nil, statusError(err, "", "")
+	}
+
+	result := make([]*file_repository.Bucket, len(buckets))
+	for i, bucket := range buckets {
+		result[i] = &file_repository.Bucket{
+			Name:         bucket.Name,
+			CreationDate: timestamppb.New(bucket.CreationDate),
+		}
+	}
+
+	return &file_repository.ListBucketsResponse{
+		Buckets: result,
+	}, nil
+}
+
+func (s *Server) GetBucketInfo(
+	ctx context.Context,
+	req *file_repository.GetBucketInfoRequest,
+) (*file_repository.BucketInfo, error) {
+	info, err := s.storage.GetBucketInfo(&fileapplication.GetBucketInfoQuery{
+		Name: req.GetName(),
+		CommandQuery: cqrs.CommandQuery{
+			Context:        ctx,
+			ContextTimeout: bucketProcessingTimeout,
+		},
+	})
+	if err != nil {
+		return nil, statusError(err, req.GetName(), "")
+	}
+
+	return &file_repository.BucketInfo{
+		Name:              info.Name,
+		CreationDate:      timestamppb.New(info.CreationDate),
+		ObjectCount:       info.ObjectCount,
+		TotalSize:         info.TotalSize,
+		VersioningEnabled: info.VersioningEnabled,
+	}, nil
+}
+
+// Quota usage requires processing of all objects in the bucket when it's requested for the first time
+func (s *Server) GetQuotaUsage(
+	ctx context.Context,
+	req *file_repository.GetQuotaUsageRequest,
+) (*file_repository.QuotaUsage, error) {
+	reader, ok := s.storage.(fileapplication.QuotaUsageReader)
+	if !ok {
+		return nil, statusError(errQuotasDisabled, req.GetBucket(), "")
+	}
+
+	usage, err := reader.GetQuotaUsage(&fileapplication.GetQuotaUsageQuery{
+		Bucket: req.GetBucket(),
+		Owner:  req.GetOwner(),
+		CommandQuery: cqrs.CommandQuery{
+			Context:        ctx,
+			ContextTimeout: bucketProcessingTimeout,
+		},
+	})
+	if err != nil {
+		return nil, statusError(err, req.GetBucket(), "")
+	}
+
+	return &file_repository.QuotaUsage{
+		UsedBytes:   usage.UsedBytes,
+		UsedObjects: usage.UsedObjects,
+		MaxBytes:    usage.MaxBytes,
+		MaxObjects:  usage.MaxObjects,
+	}, nil
+}
+
+func (s *Server) SetBucketVersioning(
+	ctx context.Context,
+	req *file_repository.SetBucketVersioningRequest,
+) (*file_repository.StatusResponse, error) {
+	err := s.storage.SetBucketVersioning(&fileapplication.SetBucketVersioningCommand{
+		Name:    req.GetName(),
+		Enabled: req.GetEnabled(),
+		// Default timeout will be used
+		CommandQuery: cqrs.CommandQuery{Context: ctx},
+	})
+	if err != nil {
+		return nil, statusError(err, req.GetName(), "")
+	}
+	return &file_repository.StatusResponse{
+		Status: http.StatusOK,
+	}, nil
+}
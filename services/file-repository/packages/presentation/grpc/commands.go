@@ -6,9 +6,12 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"time"
 	fileapplication "vega_file_repository/packages/application/file"
+	"vega_file_repository/packages/domain/entity"
 
 	file_repository "github.com/abaxoth0/Vega/common/protobuf/generated/go/services/file-repository"
+	cqrs "github.com/abaxoth0/Vega/libs/go/packages/CQRS"
 	"google.golang.org/grpc"
 )
 
@@ -21,48 +24,46 @@ func (s *Server) Mkdir(
 		Path: req.GetPath(),
 	})
 	if err != nil {
-		return nil, err
+		return nil, statusError(err, req.GetBucket(), req.GetPath())
 	}
 	return &file_repository.StatusResponse{
-		Status: http.StatusOK,
+		Status: http.StatusCreated,
 	}, nil
 }
 
+// The same as downloadTimeout, but for uploads
+const uploadTimeout = time.Hour
+
 type fileContent struct {
 	Header 	*file_repository.FileContentHeader
 	Reader io.Reader
 }
 
-func fileContentFromStream(
-    stream grpc.BidiStreamingServer[file_repository.FileContentRequest, file_repository.StatusResponse],
-) (*fileContent, error) {
-    firstMsg, err := stream.Recv()
-    if err != nil {
-        return nil, fmt.Errorf("failed to receive first message: %v", err)
-    }
-
-    header := firstMsg.GetHeader()
-    if header == nil {
-        return nil, errors.New("first message must be a header")
-    }
+type chunkMessage interface {
+	GetChunk() []byte
+}
 
+// Writes chunks of all messages received from the stream into returned reader.
+// first is a message which was already received (usually it's a header, so it has no chunk).
+func pipeStreamChunks[T chunkMessage](first T, recv func() (T, error)) io.Reader {
     pr, pw := io.Pipe()
 
     go func() {
-        defer pw.Close()
-
-        if chunk := firstMsg.GetChunk(); chunk != nil {
+        if chunk := first.GetChunk(); chunk != nil {
             if _, err := pw.Write(chunk); err != nil {
                 return
             }
         }
 
         for {
-            msg, err := stream.Recv()
+            msg, err := recv()
             if err == io.EOF {
+                pw.Close()
                 return
             }
             if err != nil {
+                // Otherwise reader will treat incomplete content as a whole
+                pw.CloseWithError(err)
                 return
             }
 
@@ -74,33 +75,137 @@ func fileContentFromStream(
         }
     }()
 
+    return pr
+}
+
+func fileContentFromStream(
+    stream grpc.BidiStreamingServer[file_repository.FileContentRequest, file_repository.StatusResponse],
+) (*fileContent, error) {
+    firstMsg, err := stream.Recv()
+    if err != nil {
+        return nil, fmt.Errorf("failed to receive first message: %w", err)
+    }
+
+    header := firstMsg.GetHeader()
+    if header == nil {
+        return nil, newRequestError("header", errors.New("first message must be a header"))
+    }
+
 	return &fileContent{
 		Header: header,
-		Reader: pr,
+		Reader: pipeStreamChunks(firstMsg, stream.Recv),
 	}, nil
 }
 
+// Returns nil if header has no checksum
+func checksumFromHeader(header *file_repository.FileContentHeader) *entity.Checksum {
+	if header.GetChecksum() == "" {
+		return nil
+	}
+	return &entity.Checksum{
+		Algorithm: header.GetChecksumType(),
+		Value:     header.GetChecksum(),
+	}
+}
+
+// Sets checksum fields of the response if checksum isn't nil
+func setResponseChecksum(resp *file_repository.StatusResponse, checksum *entity.Checksum) *file_repository.StatusResponse {
+	if checksum != nil {
+		resp.Checksum = checksum.Value
+		resp.ChecksumType = checksum.Algorithm
+	}
+	return resp
+}
+
+func preconditionsFromHeader(header *file_repository.FileContentHeader) (fileapplication.WritePreconditions, error) {
+	preconditions := fileapplication.WritePreconditions{
+		IfMatch: header.GetIfMatch(),
+	}
+	switch header.GetIfNoneMatch() {
+	case "":
+	case "*":
+		preconditions.IfNoneMatch = true
+	default:
+		return preconditions, newRequestError(
+			"if_none_match",
+			fmt.Errorf("unsupported if_none_match value: %q, only \"*\" is supported", header.GetIfNoneMatch()),
+		)
+	}
+	if header.IfUnmodifiedSince != nil {
+		preconditions.IfUnmodifiedSince = header.GetIfUnmodifiedSince().AsTime()
+	}
+	return preconditions, nil
+}
+
+func extractedEntriesToProto(entries []entity.ExtractedEntry) []*file_repository.ExtractedEntry {
+	result := make([]*file_repository.ExtractedEntry, len(entries))
+	for i, entry := range entries {
+		result[i] = &file_repository.ExtractedEntry{
+			Path:        entry.Path,
+			Size:        entry.Size,
+			IsDirectory: entry.IsDirectory,
+			Error:       entry.Error,
+		}
+	}
+	return result
+}
+
 func (s *Server) UploadFile(
     stream grpc.BidiStreamingServer[file_repository.FileContentRequest, file_repository.StatusResponse],
 ) error {
 	content, err := fileContentFromStream(stream)
 	if err != nil {
-		return err
+		return statusError(err, "", "")
 	}
 
-    err = s.storage.UploadFile(&fileapplication.UploadFileCommand{
-        Bucket:      content.Header.Bucket,
-        Path:        content.Header.Path,
-        ContentSize: content.Header.Size,
-        Content:     content.Reader,
+	archiveFormat, ok := archiveFormatMap[content.Header.GetArchiveFormat()]
+	if !ok {
+		return statusError(
+			newRequestError("archive_format", fmt.Errorf("unknown archive format: %s", content.Header.GetArchiveFormat().String())),
+			content.Header.Bucket, content.Header.Path,
+		)
+	}
+
+	preconditions, err := preconditionsFromHeader(content.Header)
+	if err != nil {
+		return statusError(err, content.Header.Bucket, content.Header.Path)
+	}
+
+	progress, err := newUploadProgress(content.Header, content.Reader)
+	if err != nil {
+		return statusError(err, content.Header.Bucket, content.Header.Path)
+	}
+	progress.start(stream)
+
+    result, err := s.storage.UploadFile(&fileapplication.UploadFileCommand{
+        Bucket:        content.Header.Bucket,
+        Path:          content.Header.Path,
+        ContentSize:   content.Header.Size,
+        Content:       progress,
+        ArchiveFormat: archiveFormat,
+        Checksum:      checksumFromHeader(content.Header),
+        Preconditions: preconditions,
+        CommandQuery: cqrs.CommandQuery{
+            Context:        stream.Context(),
+            ContextTimeout: uploadTimeout,
+        },
     })
+    progress.stop()
+    if err != nil && !errors.Is(err, fileapplication.ErrPartialExtraction) {
+        return statusError(err, content.Header.Bucket, content.Header.Path)
+    }
+
+    status := http.StatusOK
     if err != nil {
-        return fmt.Errorf("file upload failed: %v", err)
+        status = http.StatusMultiStatus
     }
 
-    return stream.Send(&file_repository.StatusResponse{
-        Status: http.StatusOK,
-    })
+    return stream.Send(setResponseChecksum(&file_repository.StatusResponse{
+        Status:   int32(status),
+        Entries:  extractedEntriesToProto(result.Entries),
+        Etag:     result.ETag,
+        Progress: progress.toProto(),
+    }, result.Checksum))
 }
 
 func (s *Server) UpdateFileContent(
@@ -108,36 +213,184 @@ func (s *Server) UpdateFileContent(
 ) error {
 	content, err := fileContentFromStream(stream)
 	if err != nil {
-		return err
+		return statusError(err, "", "")
+	}
+
+	preconditions, err := preconditionsFromHeader(content.Header)
+	if err != nil {
+		return statusError(err, content.Header.Bucket, content.Header.Path)
+	}
+
+	progress, err := newUploadProgress(content.Header, content.Reader)
+	if err != nil {
+		return statusError(err, content.Header.Bucket, content.Header.Path)
 	}
+	progress.start(stream)
 
-    err = s.storage.UpdateFileContent(&fileapplication.UpdateFileContentCommand{
+    result, err := s.storage.UpdateFileContent(&fileapplication.UpdateFileContentCommand{
         Bucket:      content.Header.Bucket,
         Path:        content.Header.Path,
 		Size: 		 content.Header.Size,
-        NewContent:  content.Reader,
+        NewContent:  progress,
+        Checksum:    checksumFromHeader(content.Header),
+        Preconditions: preconditions,
+        CommandQuery: cqrs.CommandQuery{
+            Context:        stream.Context(),
+            ContextTimeout: uploadTimeout,
+        },
     })
+    progress.stop()
     if err != nil {
-        return fmt.Errorf("file update failed: %v", err)
+        return statusError(err, content.Header.Bucket, content.Header.Path)
     }
 
-    return stream.Send(&file_repository.StatusResponse{
-        Status: http.StatusOK,
-    })
+    return stream.Send(setResponseChecksum(&file_repository.StatusResponse{
+        Status:   http.StatusOK,
+        Etag:     result.ETag,
+        Progress: progress.toProto(),
+    }, result.Checksum))
+}
+
+func deletionFailuresToProto(failures []entity.DeletionFailure) []*file_repository.DeletionFailure {
+	result := make([]*file_repository.DeletionFailure, len(failures))
+	for i, failure := range failures {
+		result[i] = &file_repository.DeletionFailure{
+			Path:   failure.Path,
+			Reason: failure.Reason,
+		}
+	}
+	return result
 }
 
 func (s *Server) DeleteFiles(
 	ctx context.Context,
 	req *file_repository.DeleteFilesRequest,
-) (*file_repository.StatusResponse, error) {
-	err := s.storage.DeleteFiles(&fileapplication.DeleteFilesCommand{
-		Bucket: req.GetBucket(),
-		Paths: req.GetPaths(),
+) (*file_repository.DeleteFilesResponse, error) {
+	report, err := s.storage.DeleteFiles(&fileapplication.DeleteFilesCommand{
+		Bucket:    req.GetBucket(),
+		Paths:     req.GetPaths(),
+		Recursive: req.GetRecursive(),
 	})
+	if err != nil && !errors.Is(err, fileapplication.ErrPartialDeletion) {
+		return nil, statusError(err, req.GetBucket(), singlePath(req.GetPaths()))
+	}
+
+	results := make([]*file_repository.DeletionResult, len(report.Results))
+	for i, result := range report.Results {
+		results[i] = &file_repository.DeletionResult{
+			Path:         result.Path,
+			DeletedCount: result.DeletedCount,
+			Failures:     deletionFailuresToProto(result.Failures),
+		}
+	}
+
+	status := http.StatusOK
 	if err != nil {
-		return nil, err
+		status = http.StatusMultiStatus
 	}
-	return &file_repository.StatusResponse{
-		Status: http.StatusOK,
+
+	return &file_repository.DeleteFilesResponse{
+		Status:       int32(status),
+		Results:      results,
+		DeletedCount: report.DeletedCount(),
+		Failures:     deletionFailuresToProto(report.Failures()),
 	}, nil
 }
+
+var overwriteModeMap = map[file_repository.OverwriteMode]fileapplication.OverwriteMode{
+	file_repository.OverwriteMode_OVERWRITE_MODE_FAIL:    fileapplication.OverwriteFail,
+	file_repository.OverwriteMode_OVERWRITE_MODE_SKIP:    fileapplication.OverwriteSkip,
+	file_repository.OverwriteMode_OVERWRITE_MODE_REPLACE: fileapplication.OverwriteReplace,
+}
+
+// Copying of large directories may take a while, it's limited by client's deadline anyway
+const relocationTimeout = time.Hour
+
+func filesRelocationFromProto(req *file_repository.RelocateFilesRequest) (*fileapplication.FilesRelocation, error) {
+	overwrite, ok := overwriteModeMap[req.GetOverwrite()]
+	if !ok {
+		return nil, newRequestError("overwrite", fmt.Errorf("unknown overwrite mode: %s", req.GetOverwrite().String()))
+	}
+	return &fileapplication.FilesRelocation{
+		SourceBucket:      req.GetSourceBucket(),
+		SourcePath:        req.GetSourcePath(),
+		DestinationBucket: req.GetDestinationBucket(),
+		DestinationPath:   req.GetDestinationPath(),
+		Overwrite:         overwrite,
+	}, nil
+}
+
+func relocationReportToProto(
+	req *file_repository.RelocateFilesRequest,
+	report *entity.RelocationReport,
+	err error,
+) (*file_repository.RelocateFilesResponse, error) {
+	if errors.Is(err, fileapplication.ErrDestinationExists) {
+		return nil, statusError(err, req.GetDestinationBucket(), req.GetDestinationPath())
+	}
+	if err != nil && !errors.Is(err, fileapplication.ErrPartialRelocation) {
+		return nil, statusError(err, req.GetSourceBucket(), req.GetSourcePath())
+	}
+
+	files := make([]*file_repository.RelocatedFile, len(report.Files))
+	for i, f := range report.Files {
+		files[i] = &file_repository.RelocatedFile{
+			SourcePath:      f.SourcePath,
+			DestinationPath: f.DestinationPath,
+			Skipped:         f.Skipped,
+			Error:           f.Error,
+		}
+	}
+
+	status := http.StatusOK
+	if err != nil {
+		status = http.StatusMultiStatus
+	}
+
+	return &file_repository.RelocateFilesResponse{
+		Status:         int32(status),
+		Files:          files,
+		RelocatedCount: report.RelocatedCount(),
+		SkippedCount:   report.SkippedCount(),
+	}, nil
+}
+
+func (s *Server) CopyFiles(
+	ctx context.Context,
+	req *file_repository.RelocateFilesRequest,
+) (*file_repository.RelocateFilesResponse, error) {
+	relocation, err := filesRelocationFromProto(req)
+	if err != nil {
+		return nil, statusError(err, req.GetSourceBucket(), req.GetSourcePath())
+	}
+
+	report, err := s.storage.CopyFiles(&fileapplication.CopyFilesCommand{
+		FilesRelocation: *relocation,
+		CommandQuery: cqrs.CommandQuery{
+			Context:        ctx,
+			ContextTimeout: relocationTimeout,
+		},
+	})
+
+	return relocationReportToProto(req, report, err)
+}
+
+func (s *Server) MoveFiles(
+	ctx context.Context,
+	req *file_repository.RelocateFilesRequest,
+) (*file_repository.RelocateFilesResponse, error) {
+	relocation, err := filesRelocationFromProto(req)
+	if err != nil {
+		return nil, statusError(err, req.GetSourceBucket(), req.GetSourcePath())
+	}
+
+	report, err := s.storage.MoveFiles(&fileapplication.MoveFilesCommand{
+		FilesRelocation: *relocation,
+		CommandQuery: cqrs.CommandQuery{
+			Context:        ctx,
+			ContextTimeout: relocationTimeout,
+		},
+	})
+
+	return relocationReportToProto(req, report, err)
+}
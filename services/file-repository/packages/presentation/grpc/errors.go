@@ -0,0 +1,234 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	fileapplication "vega_file_repository/packages/application/file"
+	"vega_file_repository/packages/infrastructure/auth"
+	"vega_file_repository/packages/infrastructure/ratelimit"
+
+	file_repository "github.com/abaxoth0/Vega/common/protobuf/generated/go/services/file-repository"
+	errs "github.com/abaxoth0/Vega/libs/go/packages/erorrs"
+	"github.com/abaxoth0/Vega/libs/go/packages/file"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// Domain of ErrorInfo details
+const errorDomain = "file-repository.vega"
+
+var errQuotasDisabled = errors.New("quotas aren't enabled")
+
+type errorKind struct {
+	code   codes.Code
+	reason file_repository.ErrorReason
+}
+
+var (
+	invalidArgument = errorKind{codes.InvalidArgument, file_repository.ErrorReason_ERROR_REASON_INVALID_ARGUMENT}
+	invalidPath     = errorKind{codes.InvalidArgument, file_repository.ErrorReason_ERROR_REASON_INVALID_PATH}
+	invalidBucket   = errorKind{codes.InvalidArgument, file_repository.ErrorReason_ERROR_REASON_INVALID_BUCKET_NAME}
+	notSupported    = errorKind{codes.Unimplemented, file_repository.ErrorReason_ERROR_REASON_NOT_SUPPORTED}
+)
+
+// Errors of use cases which can be reported to clients, all other errors are reported as unknown ones
+var errorKinds = []struct {
+	err  error
+	kind errorKind
+}{
+	{fileapplication.ErrFileDoesNotExist, errorKind{codes.NotFound, file_repository.ErrorReason_ERROR_REASON_FILE_NOT_FOUND}},
+	{fileapplication.ErrBucketDoesNotExist, errorKind{codes.NotFound, file_repository.ErrorReason_ERROR_REASON_BUCKET_NOT_FOUND}},
+	{fileapplication.ErrUploadDoesNotExist, errorKind{codes.NotFound, file_repository.ErrorReason_ERROR_REASON_UPLOAD_NOT_FOUND}},
+	{fileapplication.ErrPreconditionFailed, errorKind{codes.FailedPrecondition, file_repository.ErrorReason_ERROR_REASON_PRECONDITION_FAILED}},
+	{fileapplication.ErrDestinationExists, errorKind{codes.AlreadyExists, file_repository.ErrorReason_ERROR_REASON_DESTINATION_EXISTS}},
+	{fileapplication.ErrBucketAlreadyExists, errorKind{codes.AlreadyExists, file_repository.ErrorReason_ERROR_REASON_BUCKET_ALREADY_EXISTS}},
+	{fileapplication.ErrBucketIsNotEmpty, errorKind{codes.FailedPrecondition, file_repository.ErrorReason_ERROR_REASON_BUCKET_NOT_EMPTY}},
+	{fileapplication.ErrPathConflict, errorKind{codes.FailedPrecondition, file_repository.ErrorReason_ERROR_REASON_PATH_CONFLICT}},
+	{fileapplication.ErrQuotaExceeded, errorKind{codes.ResourceExhausted, file_repository.ErrorReason_ERROR_REASON_QUOTA_EXCEEDED}},
+	{fileapplication.ErrRangeNotSatisfiable, errorKind{codes.OutOfRange, file_repository.ErrorReason_ERROR_REASON_RANGE_NOT_SATISFIABLE}},
+	{fileapplication.ErrChecksumMismatch, errorKind{codes.InvalidArgument, file_repository.ErrorReason_ERROR_REASON_CHECKSUM_MISMATCH}},
+	{context.DeadlineExceeded, errorKind{codes.DeadlineExceeded, file_repository.ErrorReason_ERROR_REASON_TIMEOUT}},
+	{context.Canceled, errorKind{codes.Canceled, file_repository.ErrorReason_ERROR_REASON_CANCELED}},
+	{auth.ErrUnauthenticated, errorKind{codes.Unauthenticated, file_repository.ErrorReason_ERROR_REASON_UNAUTHENTICATED}},
+	{auth.ErrPermissionDenied, errorKind{codes.PermissionDenied, file_repository.ErrorReason_ERROR_REASON_PERMISSION_DENIED}},
+	{ratelimit.ErrRateLimited, errorKind{codes.ResourceExhausted, file_repository.ErrorReason_ERROR_REASON_RATE_LIMITED}},
+
+	{fileapplication.ErrNoQuota, errorKind{codes.NotFound, file_repository.ErrorReason_ERROR_REASON_QUOTA_NOT_DEFINED}},
+	{fileapplication.ErrPresignNotSupported, notSupported},
+	{fileapplication.ErrPreconditionsNotSupported, notSupported},
+	{errQuotasDisabled, notSupported},
+
+	{file.ErrEmptyPath, invalidPath},
+	{file.ErrInvalidPathFormat, invalidPath},
+	{file.ErrMaxPathLengthExceeded, invalidPath},
+	{file.ErrMaxPathSegmentLengthExceeded, invalidPath},
+	{file.ErrFileIsNotDirectory, invalidPath},
+	{fileapplication.ErrInvalidPath, invalidPath},
+	{fileapplication.ErrUploadOfDirectory, invalidPath},
+	{fileapplication.ErrUpdateOfDirectory, invalidPath},
+
+	{fileapplication.ErrInvalidBucketName, invalidBucket},
+	{fileapplication.ErrReservedBucket, invalidBucket},
+
+	{fileapplication.ErrArchiveFormatNotSpecified, invalidArgument},
+	{fileapplication.ErrVersionOfDirectory, invalidArgument},
+	{fileapplication.ErrVersionIDNotSpecified, invalidArgument},
+	{fileapplication.ErrInvalidRange, invalidArgument},
+	{fileapplication.ErrRangeOfDirectory, invalidArgument},
+	{fileapplication.ErrNoPathsToStat, invalidArgument},
+	{fileapplication.ErrTooManyPathsToStat, invalidArgument},
+	{fileapplication.ErrVersionOfMultipleFiles, invalidArgument},
+	{fileapplication.ErrInvalidChecksum, invalidArgument},
+	{fileapplication.ErrUnsupportedChecksumAlgorithm, invalidArgument},
+	{fileapplication.ErrChecksumOfArchive, invalidArgument},
+	{fileapplication.ErrConflictingPreconditions, invalidArgument},
+	{fileapplication.ErrPreconditionsOfArchive, invalidArgument},
+	{fileapplication.ErrInvalidUploadPartNumber, invalidArgument},
+	{fileapplication.ErrNoUploadParts, invalidArgument},
+	{fileapplication.ErrInvalidContentSize, invalidArgument},
+	{fileapplication.ErrRelocationTypeMismatch, invalidArgument},
+	{fileapplication.ErrRelocationIntoItself, invalidArgument},
+	{fileapplication.ErrUnknownOverwriteMode, invalidArgument},
+	{fileapplication.ErrInvalidQuotaUsageTarget, invalidArgument},
+	{fileapplication.ErrInvalidPresignMethod, invalidArgument},
+	{fileapplication.ErrPresignedURLExpiry, invalidArgument},
+	{fileapplication.ErrPresignOfDirectory, invalidArgument},
+	{fileapplication.ErrRestrictionsOfDownload, invalidArgument},
+}
+
+// Some use cases (and storages) report failures via errs.Status
+var httpStatusKinds = map[int]errorKind{
+	http.StatusNotFound:       {codes.NotFound, file_repository.ErrorReason_ERROR_REASON_FILE_NOT_FOUND},
+	http.StatusRequestTimeout: {codes.DeadlineExceeded, file_repository.ErrorReason_ERROR_REASON_TIMEOUT},
+}
+
+func errorKindOf(err error) (errorKind, bool) {
+	for _, known := range errorKinds {
+		if errors.Is(err, known.err) {
+			return known.kind, true
+		}
+	}
+	var statusErr *errs.Status
+	if errors.As(err, &statusErr) {
+		kind, ok := httpStatusKinds[statusErr.Status()]
+		return kind, ok
+	}
+	return errorKind{}, false
+}
+
+// Error of the request itself (e.g. unknown enum value), field is the name of its invalid field
+type requestError struct {
+	field string
+	err   error
+}
+
+func (e *requestError) Error() string {
+	return e.err.Error()
+}
+
+func (e *requestError) Unwrap() error {
+	return e.err
+}
+
+func newRequestError(field string, err error) error {
+	return &requestError{field: field, err: err}
+}
+
+// Converts error into gRPC status with details, so clients can handle it without parsing of the message.
+// bucket and path identify resource which request failed on, they may be empty if it isn't known.
+// Errors which are already gRPC statuses (e.g. failures of the stream itself) are returned as is.
+func statusError(err error, bucket string, path string) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	var reqErr *requestError
+	isRequestErr := errors.As(err, &reqErr)
+
+	kind, ok := errorKindOf(err)
+	if !ok {
+		if !isRequestErr {
+			return status.Error(codes.Unknown, err.Error())
+		}
+		kind = invalidArgument
+	}
+
+	metadata := map[string]string{}
+	if bucket != "" {
+		metadata["bucket"] = bucket
+	}
+	if path != "" {
+		metadata["path"] = path
+	}
+	details := []protoadapt.MessageV1{
+		&errdetails.ErrorInfo{
+			Reason:   kind.reason.String(),
+			Domain:   errorDomain,
+			Metadata: metadata,
+		},
+	}
+
+	switch {
+	case isRequestErr:
+		details = append(details, &errdetails.BadRequest{
+			FieldViolations: []*errdetails.BadRequest_FieldViolation{{Field: reqErr.field, Description: err.Error()}},
+		})
+	case kind == invalidPath:
+		details = append(details, &errdetails.BadRequest{
+			FieldViolations: []*errdetails.BadRequest_FieldViolation{{Field: "path", Description: err.Error()}},
+		})
+	case kind == invalidBucket:
+		details = append(details, &errdetails.BadRequest{
+			FieldViolations: []*errdetails.BadRequest_FieldViolation{{Field: "bucket", Description: err.Error()}},
+		})
+	case kind.code == codes.FailedPrecondition:
+		details = append(details, &errdetails.PreconditionFailure{
+			Violations: []*errdetails.PreconditionFailure_Violation{{
+				Type:        kind.reason.String(),
+				Subject:     bucket + ":" + path,
+				Description: err.Error(),
+			}},
+		})
+	case kind.reason == file_repository.ErrorReason_ERROR_REASON_RATE_LIMITED:
+		var limitErr *ratelimit.LimitError
+		if errors.As(err, &limitErr) {
+			details = append(details,
+				&errdetails.RetryInfo{RetryDelay: durationpb.New(limitErr.RetryAfter)},
+				&errdetails.QuotaFailure{
+					Violations: []*errdetails.QuotaFailure_Violation{{
+						Subject:     "caller:" + limitErr.Caller,
+						Description: limitErr.Limit + " exceeded",
+					}},
+				},
+			)
+		}
+	case kind.reason == file_repository.ErrorReason_ERROR_REASON_QUOTA_EXCEEDED:
+		details = append(details, &errdetails.QuotaFailure{
+			Violations: []*errdetails.QuotaFailure_Violation{{
+				Subject:     "bucket:" + bucket,
+				Description: err.Error(),
+			}},
+		})
+	}
+
+	st, detailsErr := status.New(kind.code, err.Error()).WithDetails(details...)
+	if detailsErr != nil {
+		return status.Error(kind.code, err.Error())
+	}
+	return st.Err()
+}
+
+// Returns path if it's the only one, since otherwise it's unknown which of them caused the failure
+func singlePath(paths []string) string {
+	if len(paths) == 1 {
+		return paths[0]
+	}
+	return ""
+}
@@ -2,24 +2,44 @@ package grpc
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"os"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
+	fileapplication "vega_file_repository/packages/application/file"
+	"vega_file_repository/packages/infrastructure/auth"
+	"vega_file_repository/packages/infrastructure/auth/authtest"
 	objectstorage "vega_file_repository/packages/infrastructure/object-storage"
+	miniocommon "vega_file_repository/packages/infrastructure/object-storage/MinIO/common"
 	storageconnection "vega_file_repository/packages/infrastructure/object-storage/connection"
+	localcommon "vega_file_repository/packages/infrastructure/object-storage/local/common"
+	"vega_file_repository/packages/infrastructure/ratelimit"
 
 	file_repository "github.com/abaxoth0/Vega/common/protobuf/generated/go/services/file-repository"
 	errs "github.com/abaxoth0/Vega/libs/go/packages/erorrs"
+	"github.com/minio/minio-go/v7"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 const testPort uint16 = 50001
 
+const testJWTKey = "0123456789abcdef0123456789abcdef"
+
 type closeFunc = func() error
 
 func new_grpc_clinet() (file_repository.FileRepositoryServiceClient, closeFunc) {
@@ -86,14 +106,44 @@ func TestServer(t *testing.T) {
 	})
 }
 
-func TestRPC(t *testing.T) {
-	err := objectstorage.Driver.Connect(&storageconnection.Config{
-		URL:      "localhost:9000",
-		Login:    "minioadmin",
-		Password: "minioadmin",
-		Token:    "",
-		Secure:   false,
+// By default tests are run against MinIO (see docker-compose.yaml),
+// set VEGA_STORAGE_DRIVER=local to run them without any containers.
+func connectStorage(t *testing.T, testBucket string) error {
+	driver := objectstorage.DriverName(os.Getenv("VEGA_STORAGE_DRIVER"))
+	if err := objectstorage.SelectDriver(driver); err != nil {
+		return err
+	}
+
+	if driver != objectstorage.LocalDriver {
+		return objectstorage.Driver.Connect(&storageconnection.Config{
+			URL:      "localhost:9000",
+			Login:    "minioadmin",
+			Password: "minioadmin",
+			Token:    "",
+			Secure:   false,
+		})
+	}
+
+	if err := objectstorage.Driver.Connect(&storageconnection.Config{URL: t.TempDir()}); err != nil {
+		return err
+	}
+	// Local storage is created from scratch, so files expected by tests must be created as well
+	if err := objectstorage.Driver.MakeBucket(&fileapplication.MakeBucketCommand{Name: testBucket}); err != nil {
+		return err
+	}
+	_, err := objectstorage.Driver.UploadFile(&fileapplication.UploadFileCommand{
+		Bucket:      testBucket,
+		Path:        "/file.txt",
+		Content:     strings.NewReader("test file content"),
+		ContentSize: int64(len("test file content")),
 	})
+	return err
+}
+
+func TestRPC(t *testing.T) {
+	const testBucket string = "test-bucket"
+
+	err := connectStorage(t, testBucket)
 	if err != nil {
 		t.Fatalf("Failed to connect to object storage: %v", err)
 	}
@@ -103,8 +153,6 @@ func TestRPC(t *testing.T) {
 		}
 	}()
 
-	const testBucket string = "test-bucket"
-
 	t.Run("GetFileByPath()", func(t *testing.T) {
 		withClient(t, func(client file_repository.FileRepositoryServiceClient) {
 			ctx, cancel := newRPCContext()
@@ -163,6 +211,56 @@ func TestRPC(t *testing.T) {
 		})
 	})
 
+	t.Run("ListDirectory()", func(t *testing.T) {
+		withClient(t, func(client file_repository.FileRepositoryServiceClient) {
+			ctx, cancel := newRPCContext()
+			defer cancel()
+
+			resp, err := client.ListDirectory(ctx, &file_repository.ListDirectoryRequest{
+				Bucket: testBucket,
+				Path:   "/",
+			})
+			if err != nil {
+				t.Fatalf("ListDirectory() RPC failed: %v", err)
+			}
+			found := false
+			for _, entry := range resp.GetEntries() {
+				if entry.GetPath() == testFilePath {
+					found = true
+					break
+				}
+			}
+			if !found {
+				t.Fatalf("Uploaded file \"%s\" is missing in directory listing", testFilePath)
+			}
+		})
+	})
+
+	t.Run("StatFile()", func(t *testing.T) {
+		withClient(t, func(client file_repository.FileRepositoryServiceClient) {
+			ctx, cancel := newRPCContext()
+			defer cancel()
+
+			resp, err := client.StatFile(ctx, &file_repository.StatFileRequest{
+				Bucket: testBucket,
+				Paths:  []string{testFilePath, "/missing-file.txt"},
+			})
+			if err != nil {
+				t.Fatalf("StatFile() RPC failed: %v", err)
+			}
+			files := resp.GetFiles()
+			if len(files) != 2 {
+				t.Fatalf("Expected 2 stats, but got %d", len(files))
+			}
+			if !files[0].GetExists() || files[0].GetSize() == 0 || files[0].GetLastModified() == nil {
+				t.Errorf("Invalid stat of uploaded file: %+v", files[0])
+			}
+			if files[1].GetExists() {
+				t.Errorf("Missing file must be reported as not existing")
+			}
+		})
+	})
+
 	t.Run("UpdateFileContent()", func(t *testing.T) {
 		withClient(t, func(client file_repository.FileRepositoryServiceClient) {
 			err := testFileStream(
@@ -177,6 +275,335 @@ func TestRPC(t *testing.T) {
 		})
 	})
 
+	t.Run("Checksum verification", func(t *testing.T) {
+		withClient(t, func(client file_repository.FileRepositoryServiceClient) {
+			ctx, cancel := newRPCContext()
+			defer cancel()
+
+			content := []byte("checksum test content")
+			digest := sha256.Sum256(content)
+			checksum := hex.EncodeToString(digest[:])
+
+			stream, err := client.UpdateFileContent(ctx)
+			if err != nil {
+				t.Fatalf("UpdateFileContent() RPC failed: %v", err)
+			}
+			err = stream.Send(&file_repository.FileContentRequest{
+				Data: &file_repository.FileContentRequest_Header{
+					Header: &file_repository.FileContentHeader{
+						Path:         testFilePath,
+						Bucket:       testBucket,
+						Size:         int64(len(content)),
+						Checksum:     checksum,
+						ChecksumType: fileapplication.ChecksumSHA256,
+					},
+				},
+			})
+			if err != nil {
+				t.Fatalf("Failed to send header: %v", err)
+			}
+			err = stream.Send(&file_repository.FileContentRequest{
+				Data: &file_repository.FileContentRequest_Chunk{Chunk: content},
+			})
+			if err != nil {
+				t.Fatalf("Failed to send chunk: %v", err)
+			}
+			if err := stream.CloseSend(); err != nil {
+				t.Fatalf("Failed to close stream: %v", err)
+			}
+			resp, err := stream.Recv()
+			if err != nil {
+				t.Fatalf("UpdateFileContent() RPC failed: %v", err)
+			}
+			if resp.GetChecksum() != checksum || resp.GetChecksumType() != fileapplication.ChecksumSHA256 {
+				t.Errorf("Invalid checksum in response: %v", resp)
+			}
+
+			fileStream, err := client.GetFileByPath(ctx, &file_repository.GetFileByPathRequest{
+				Bucket: testBucket,
+				Path:   testFilePath,
+			})
+			if err != nil {
+				t.Fatalf("GetFileByPath() RPC failed: %v", err)
+			}
+			var last *file_repository.FileChunk
+			for {
+				chunk, err := fileStream.Recv()
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					t.Fatalf("File stream failed: %v", err)
+				}
+				last = chunk
+			}
+			if last == nil || last.GetChecksum() != checksum {
+				t.Errorf("Last chunk must contain stored checksum, but got: %v", last)
+			}
+		})
+	})
+
+	t.Run("Conditional writes", func(t *testing.T) {
+		withClient(t, func(client file_repository.FileRepositoryServiceClient) {
+			ctx, cancel := newRPCContext()
+			defer cancel()
+
+			write := func(header *file_repository.FileContentHeader, content []byte) (*file_repository.StatusResponse, error) {
+				header.Bucket = testBucket
+				header.Path = testFilePath
+				header.Size = int64(len(content))
+
+				stream, err := client.UpdateFileContent(ctx)
+				if err != nil {
+					return nil, err
+				}
+				err = stream.Send(&file_repository.FileContentRequest{
+					Data: &file_repository.FileContentRequest_Header{Header: header},
+				})
+				if err != nil {
+					return nil, err
+				}
+				err = stream.Send(&file_repository.FileContentRequest{
+					Data: &file_repository.FileContentRequest_Chunk{Chunk: content},
+				})
+				if err != nil {
+					return nil, err
+				}
+				if err := stream.CloseSend(); err != nil {
+					return nil, err
+				}
+				return stream.Recv()
+			}
+
+			stat, err := client.StatFile(ctx, &file_repository.StatFileRequest{
+				Bucket: testBucket,
+				Paths:  []string{testFilePath},
+			})
+			if err != nil {
+				t.Fatalf("StatFile() RPC failed: %v", err)
+			}
+			etag := stat.GetFiles()[0].GetEtag()
+
+			_, err = write(&file_repository.FileContentHeader{IfMatch: "stale-etag"}, []byte("stale content"))
+			if status.Code(err) != codes.FailedPrecondition {
+				t.Errorf("Write with stale ETag must fail with FailedPrecondition, but got: %v", err)
+			}
+			_, err = write(&file_repository.FileContentHeader{IfNoneMatch: "*"}, []byte("create-only content"))
+			if status.Code(err) != codes.FailedPrecondition {
+				t.Errorf("Create-only write of existing file must fail with FailedPrecondition, but got: %v", err)
+			}
+			if _, err = write(&file_repository.FileContentHeader{IfNoneMatch: etag}, []byte("content")); err == nil {
+				t.Errorf("if_none_match other than \"*\" must be rejected")
+			}
+
+			resp, err := write(&file_repository.FileContentHeader{
+				IfMatch:           etag,
+				IfUnmodifiedSince: timestamppb.New(time.Now().Add(time.Hour)),
+			}, []byte("conditionally updated content"))
+			if err != nil {
+				t.Fatalf("Write with current ETag must succeed: %v", err)
+			}
+			if resp.GetEtag() == "" || resp.GetEtag() == etag {
+				t.Errorf("New ETag must be returned, got \"%s\" (previous one is \"%s\")", resp.GetEtag(), etag)
+			}
+		})
+	})
+
+	t.Run("CopyFiles() and MoveFiles()", func(t *testing.T) {
+		withClient(t, func(client file_repository.FileRepositoryServiceClient) {
+			ctx, cancel := newRPCContext()
+			defer cancel()
+
+			copyPath := testFilePath + "-copy"
+			movedPath := testFilePath + "-moved"
+
+			resp, err := client.CopyFiles(ctx, &file_repository.RelocateFilesRequest{
+				SourceBucket:    testBucket,
+				SourcePath:      testFilePath,
+				DestinationPath: copyPath,
+			})
+			if err != nil {
+				t.Fatalf("CopyFiles() RPC failed: %v", err)
+			}
+			if resp.GetStatus() != http.StatusOK || resp.GetRelocatedCount() != 1 {
+				t.Fatalf("Unexpected CopyFiles() response: %v", resp)
+			}
+
+			resp, err = client.MoveFiles(ctx, &file_repository.RelocateFilesRequest{
+				SourceBucket:    testBucket,
+				SourcePath:      copyPath,
+				DestinationPath: movedPath,
+				Overwrite:       file_repository.OverwriteMode_OVERWRITE_MODE_REPLACE,
+			})
+			if err != nil {
+				t.Fatalf("MoveFiles() RPC failed: %v", err)
+			}
+			if resp.GetStatus() != http.StatusOK || resp.GetRelocatedCount() != 1 {
+				t.Fatalf("Unexpected MoveFiles() response: %v", resp)
+			}
+
+			_, err = client.DeleteFiles(ctx, &file_repository.DeleteFilesRequest{
+				Bucket: testBucket,
+				Paths:  []string{movedPath},
+			})
+			if err != nil {
+				t.Fatalf("Failed to delete moved file: %v", err)
+			}
+		})
+	})
+
+	t.Run("Upload progress", func(t *testing.T) {
+		withClient(t, func(client file_repository.FileRepositoryServiceClient) {
+			ctx, cancel := newRPCContext()
+			defer cancel()
+
+			const chunkSize = 64 * 1024
+			content := []byte(strings.Repeat("progress", chunkSize/8*16))
+
+			stream, err := client.UploadFile(ctx)
+			if err != nil {
+				t.Fatalf("UploadFile() RPC failed: %v", err)
+			}
+			err = stream.Send(&file_repository.FileContentRequest{
+				Data: &file_repository.FileContentRequest_Header{Header: &file_repository.FileContentHeader{
+					Bucket:                testBucket,
+					Path:                  testFilePath + "-progress",
+					Size:                  int64(len(content)),
+					ProgressIntervalBytes: chunkSize * 4,
+				}},
+			})
+			if err != nil {
+				t.Fatalf("Failed to send header: %v", err)
+			}
+			for offset := 0; offset < len(content); offset += chunkSize {
+				err := stream.Send(&file_repository.FileContentRequest{
+					Data: &file_repository.FileContentRequest_Chunk{Chunk: content[offset : offset+chunkSize]},
+				})
+				if err != nil {
+					t.Fatalf("Failed to send chunk: %v", err)
+				}
+			}
+			if err := stream.CloseSend(); err != nil {
+				t.Fatalf("Failed to close stream: %v", err)
+			}
+
+			var committed int64
+			progressMessages := 0
+			for {
+				resp, err := stream.Recv()
+				if err != nil {
+					t.Fatalf("Upload failed: %v", err)
+				}
+				if resp.GetProgress().GetCommittedBytes() < committed {
+					t.Errorf("Committed bytes can't decrease: %d -> %d", committed, resp.GetProgress().GetCommittedBytes())
+				}
+				committed = resp.GetProgress().GetCommittedBytes()
+				if resp.GetStatus() != http.StatusProcessing {
+					if resp.GetStatus() != http.StatusOK {
+						t.Errorf("Expected final status 200, but got %d", resp.GetStatus())
+					}
+					break
+				}
+				progressMessages++
+			}
+			if progressMessages == 0 {
+				t.Errorf("At least one progress message must be sent")
+			}
+			if committed != int64(len(content)) {
+				t.Errorf("Final message must report all %d bytes as committed, but got %d", len(content), committed)
+			}
+
+			stream, err = client.UploadFile(ctx)
+			if err != nil {
+				t.Fatalf("UploadFile() RPC failed: %v", err)
+			}
+			err = stream.Send(&file_repository.FileContentRequest{
+				Data: &file_repository.FileContentRequest_Header{Header: &file_repository.FileContentHeader{
+					Bucket:             testBucket,
+					Path:               testFilePath + "-progress",
+					Size:               1,
+					ProgressIntervalMs: -1,
+				}},
+			})
+			if err != nil {
+				t.Fatalf("Failed to send header: %v", err)
+			}
+			if _, err := stream.Recv(); status.Code(err) != codes.InvalidArgument {
+				t.Errorf("Negative progress interval must be rejected, but got: %v", err)
+			}
+		})
+	})
+
+	t.Run("Error details", func(t *testing.T) {
+		withClient(t, func(client file_repository.FileRepositoryServiceClient) {
+			ctx, cancel := newRPCContext()
+			defer cancel()
+
+			recvError := func(req *file_repository.GetFileByPathRequest) error {
+				stream, err := client.GetFileByPath(ctx, req)
+				if err != nil {
+					return err
+				}
+				_, err = stream.Recv()
+				return err
+			}
+
+			cases := []struct {
+				req    *file_repository.GetFileByPathRequest
+				code   codes.Code
+				reason file_repository.ErrorReason
+			}{
+				{
+					&file_repository.GetFileByPathRequest{Bucket: testBucket, Path: "/missing-file.txt"},
+					codes.NotFound, file_repository.ErrorReason_ERROR_REASON_FILE_NOT_FOUND,
+				},
+				{
+					&file_repository.GetFileByPathRequest{Bucket: "missing-bucket", Path: "/file.txt"},
+					codes.NotFound, file_repository.ErrorReason_ERROR_REASON_BUCKET_NOT_FOUND,
+				},
+				{
+					&file_repository.GetFileByPathRequest{Bucket: testBucket, Path: "file.txt"},
+					codes.InvalidArgument, file_repository.ErrorReason_ERROR_REASON_INVALID_PATH,
+				},
+				{
+					&file_repository.GetFileByPathRequest{Bucket: testBucket, Path: "/file.txt", Offset: 1 << 20},
+					codes.OutOfRange, file_repository.ErrorReason_ERROR_REASON_RANGE_NOT_SATISFIABLE,
+				},
+				{
+					&file_repository.GetFileByPathRequest{Bucket: testBucket, Path: "/file.txt", ArchiveFormat: 100},
+					codes.InvalidArgument, file_repository.ErrorReason_ERROR_REASON_INVALID_ARGUMENT,
+				},
+			}
+			for _, c := range cases {
+				st := status.Convert(recvError(c.req))
+				if st.Code() != c.code {
+					t.Errorf("%s:%s: expected code %s, but got: %v", c.req.Bucket, c.req.Path, c.code, st.Err())
+					continue
+				}
+				var info *errdetails.ErrorInfo
+				var badRequest *errdetails.BadRequest
+				for _, detail := range st.Details() {
+					switch d := detail.(type) {
+					case *errdetails.ErrorInfo:
+						info = d
+					case *errdetails.BadRequest:
+						badRequest = d
+					}
+				}
+				if info == nil || info.GetReason() != c.reason.String() {
+					t.Errorf("%s:%s: expected reason %s, but got: %v", c.req.Bucket, c.req.Path, c.reason, info)
+					continue
+				}
+				if info.GetMetadata()["bucket"] != c.req.Bucket || info.GetMetadata()["path"] != c.req.Path {
+					t.Errorf("Failed request must be identified by metadata, but got: %v", info.GetMetadata())
+				}
+				if c.code == codes.InvalidArgument && len(badRequest.GetFieldViolations()) == 0 {
+					t.Errorf("%s:%s: invalid field must be reported", c.req.Bucket, c.req.Path)
+				}
+			}
+		})
+	})
+
 	t.Run("DeleteFiles()", func(t *testing.T) {
 		withClient(t, func(client file_repository.FileRepositoryServiceClient) {
 			ctx, cancel := newRPCContext()
@@ -204,6 +631,565 @@ func TestRPC(t *testing.T) {
 	})
 }
 
+func TestBucketsRPC(t *testing.T) {
+	if err := connectStorage(t, "test-bucket"); err != nil {
+		t.Fatalf("Failed to connect to object storage: %v", err)
+	}
+	defer func() {
+		if err := objectstorage.Driver.Disconnect(); err != nil {
+			t.Fatalf("Failed to disconnect from object storage")
+		}
+	}()
+
+	bucketName := "vega-grpc-test-" + strconv.FormatInt(time.Now().UnixMilli(), 10)
+
+	withClient(t, func(client file_repository.FileRepositoryServiceClient) {
+		ctx, cancel := newRPCContext()
+		defer cancel()
+
+		if _, err := client.CreateBucket(ctx, &file_repository.CreateBucketRequest{Name: bucketName}); err != nil {
+			t.Fatalf("CreateBucket() RPC failed: %v", err)
+		}
+
+		resp, err := client.ListBuckets(ctx, &file_repository.ListBucketsRequest{})
+		if err != nil {
+			t.Fatalf("ListBuckets() RPC failed: %v", err)
+		}
+		found := false
+		for _, bucket := range resp.GetBuckets() {
+			if bucket.GetName() == bucketName {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("Created bucket is missing in ListBuckets() response")
+		}
+
+		info, err := client.GetBucketInfo(ctx, &file_repository.GetBucketInfoRequest{Name: bucketName})
+		if err != nil {
+			t.Fatalf("GetBucketInfo() RPC failed: %v", err)
+		}
+		if info.GetObjectCount() != 0 || info.GetTotalSize() != 0 {
+			t.Errorf("New bucket must be empty, but got: %v", info)
+		}
+
+		if _, err := client.DeleteBucket(ctx, &file_repository.DeleteBucketRequest{Name: bucketName}); err != nil {
+			t.Fatalf("DeleteBucket() RPC failed: %v", err)
+		}
+	})
+}
+
+func TestFileVersionsRPC(t *testing.T) {
+	if err := connectStorage(t, "test-bucket"); err != nil {
+		t.Fatalf("Failed to connect to object storage: %v", err)
+	}
+	defer func() {
+		if err := objectstorage.Driver.Disconnect(); err != nil {
+			t.Fatalf("Failed to disconnect from object storage")
+		}
+	}()
+
+	bucketName := "vega-grpc-versions-test-" + strconv.FormatInt(time.Now().UnixMilli(), 10)
+	filePath := "/versioned-file.txt"
+
+	withClient(t, func(client file_repository.FileRepositoryServiceClient) {
+		ctx, cancel := newRPCContext()
+		defer cancel()
+
+		if _, err := client.CreateBucket(ctx, &file_repository.CreateBucketRequest{Name: bucketName}); err != nil {
+			t.Fatalf("CreateBucket() RPC failed: %v", err)
+		}
+		defer client.DeleteBucket(ctx, &file_repository.DeleteBucketRequest{Name: bucketName, Force: true})
+
+		_, err := client.SetBucketVersioning(ctx, &file_repository.SetBucketVersioningRequest{
+			Name:    bucketName,
+			Enabled: true,
+		})
+		if err != nil {
+			t.Fatalf("SetBucketVersioning() RPC failed: %v", err)
+		}
+
+		if err := testFileStream(client.UploadFile, bucketName, filePath, []byte("first")); err != nil {
+			t.Fatalf("UploadFile() RPC failed: %v", err)
+		}
+		if err := testFileStream(client.UpdateFileContent, bucketName, filePath, []byte("second")); err != nil {
+			t.Fatalf("UpdateFileContent() RPC failed: %v", err)
+		}
+
+		resp, err := client.ListFileVersions(ctx, &file_repository.ListFileVersionsRequest{
+			Bucket: bucketName,
+			Path:   filePath,
+		})
+		if err != nil {
+			t.Fatalf("ListFileVersions() RPC failed: %v", err)
+		}
+		versions := resp.GetVersions()
+		if len(versions) != 2 {
+			t.Fatalf("Expected 2 versions, but got: %v", versions)
+		}
+
+		restored, err := client.RestoreFileVersion(ctx, &file_repository.FileVersionRequest{
+			Bucket:    bucketName,
+			Path:      filePath,
+			VersionId: versions[1].GetVersionId(),
+		})
+		if err != nil {
+			t.Fatalf("RestoreFileVersion() RPC failed: %v", err)
+		}
+		if restored.GetVersionId() == "" {
+			t.Errorf("ID of the restored version is missing")
+		}
+
+		_, err = client.DeleteFileVersion(ctx, &file_repository.FileVersionRequest{
+			Bucket:    bucketName,
+			Path:      filePath,
+			VersionId: versions[0].GetVersionId(),
+		})
+		if err != nil {
+			t.Fatalf("DeleteFileVersion() RPC failed: %v", err)
+		}
+	})
+}
+
+type bearerToken string
+
+func (t bearerToken) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{"authorization": "Bearer " + string(t)}, nil
+}
+
+func (t bearerToken) RequireTransportSecurity() bool {
+	return false
+}
+
+func TestAuthRPC(t *testing.T) {
+	const testBucket string = "test-bucket"
+
+	if err := connectStorage(t, testBucket); err != nil {
+		t.Fatalf("Failed to connect to object storage: %v", err)
+	}
+	defer func() {
+		if err := objectstorage.Driver.Disconnect(); err != nil {
+			t.Fatalf("Failed to disconnect from object storage")
+		}
+	}()
+
+	verifier, err := auth.NewVerifier(testJWTKey)
+	if err != nil {
+		t.Fatalf("Failed to create verifier: %v", err)
+	}
+	policy, err := auth.ParsePolicy([]byte(`{
+		"subjects": {
+			"reader": {"` + testBucket + `": "read"},
+			"operator": {"*": "admin"}
+		}
+	}`))
+	if err != nil {
+		t.Fatalf("Failed to parse policy: %v", err)
+	}
+
+	server, err := NewServer(objectstorage.Driver)
+	if err != nil {
+		t.Fatalf("Failed to create gRPC server: %v", err)
+	}
+	if err := server.EnableAuth(verifier, policy); err != nil {
+		t.Fatalf("Failed to enable auth: %v", err)
+	}
+	go server.Start(testPort)
+	time.Sleep(time.Millisecond * 20)
+	defer server.Stop()
+
+	newClient := func(token string) file_repository.FileRepositoryServiceClient {
+		options := []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
+		if token != "" {
+			options = append(options, grpc.WithPerRPCCredentials(bearerToken(token)))
+		}
+		conn, err := grpc.NewClient("localhost:"+strconv.Itoa(int(testPort)), options...)
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+		t.Cleanup(func() { conn.Close() })
+		return file_repository.NewFileRepositoryServiceClient(conn)
+	}
+
+	anonymous := newClient("")
+	reader := newClient(authtest.Issue(testJWTKey, "reader", time.Minute))
+	operator := newClient(authtest.Issue(testJWTKey, "operator", time.Minute))
+	expired := newClient(authtest.Issue(testJWTKey, "operator", -time.Hour))
+
+	ctx, cancel := newRPCContext()
+	defer cancel()
+
+	if _, err := anonymous.HealthCheck(ctx, &file_repository.HealthCheckRequest{}); err != nil {
+		t.Errorf("Health check must be available without authentication: %v", err)
+	}
+	if _, err := anonymous.ListBuckets(ctx, &file_repository.ListBucketsRequest{}); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("Request without token must fail with Unauthenticated, but got: %v", err)
+	}
+	if _, err := expired.ListBuckets(ctx, &file_repository.ListBucketsRequest{}); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("Request with expired token must fail with Unauthenticated, but got: %v", err)
+	}
+
+	stream, err := reader.GetFileByPath(ctx, &file_repository.GetFileByPathRequest{Bucket: testBucket, Path: "/file.txt"})
+	if err != nil {
+		t.Fatalf("GetFileByPath() RPC failed: %v", err)
+	}
+	if _, err := stream.Recv(); err != nil {
+		t.Errorf("Reader must be able to download files: %v", err)
+	}
+
+	err = testFileStream(reader.UploadFile, testBucket, "/denied.txt", []byte("content"))
+	if status.Code(err) != codes.PermissionDenied {
+		t.Errorf("Reader must not be able to upload files, but got: %v", err)
+	}
+	_, err = reader.DeleteBucket(ctx, &file_repository.DeleteBucketRequest{Name: testBucket})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Errorf("Reader must not be able to delete bucket, but got: %v", err)
+	}
+
+	bucketName := "vega-auth-test-" + strconv.FormatInt(time.Now().UnixMilli(), 10)
+	if _, err := operator.CreateBucket(ctx, &file_repository.CreateBucketRequest{Name: bucketName}); err != nil {
+		t.Fatalf("Operator must be able to create buckets: %v", err)
+	}
+	if err := testFileStream(operator.UploadFile, bucketName, "/file.txt", []byte("content")); err != nil {
+		t.Errorf("Operator must be able to upload files: %v", err)
+	}
+	_, err = reader.CopyFiles(ctx, &file_repository.RelocateFilesRequest{
+		SourceBucket:      bucketName,
+		SourcePath:        "/file.txt",
+		DestinationBucket: testBucket,
+		DestinationPath:   "/copied.txt",
+	})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Errorf("Reader must not be able to copy files from unknown bucket, but got: %v", err)
+	}
+
+	resp, err := reader.ListBuckets(ctx, &file_repository.ListBucketsRequest{})
+	if err != nil {
+		t.Fatalf("ListBuckets() RPC failed: %v", err)
+	}
+	for _, bucket := range resp.GetBuckets() {
+		if bucket.GetName() != testBucket {
+			t.Errorf("Only readable buckets must be listed, but got \"%s\"", bucket.GetName())
+		}
+	}
+
+	if _, err := operator.DeleteBucket(ctx, &file_repository.DeleteBucketRequest{Name: bucketName, Force: true}); err != nil {
+		t.Errorf("Operator must be able to delete buckets: %v", err)
+	}
+}
+
+func TestRateLimitRPC(t *testing.T) {
+	const testBucket string = "test-bucket"
+
+	if err := connectStorage(t, testBucket); err != nil {
+		t.Fatalf("Failed to connect to object storage: %v", err)
+	}
+	defer func() {
+		if err := objectstorage.Driver.Disconnect(); err != nil {
+			t.Fatalf("Failed to disconnect from object storage")
+		}
+	}()
+
+	config, err := ratelimit.ParseConfig([]byte(`{"default": {"requests_per_second": 1, "request_burst": 3, "max_concurrent_streams": 1}}`))
+	if err != nil {
+		t.Fatalf("Failed to parse config: %v", err)
+	}
+
+	server, err := NewServer(objectstorage.Driver)
+	if err != nil {
+		t.Fatalf("Failed to create gRPC server: %v", err)
+	}
+	if err := server.EnableRateLimits(ratelimit.NewLimiter(config)); err != nil {
+		t.Fatalf("Failed to enable rate limits: %v", err)
+	}
+	go server.Start(testPort)
+	time.Sleep(time.Millisecond * 20)
+	defer server.Stop()
+
+	client, closeClient := new_grpc_clinet()
+	defer closeClient()
+
+	ctx, cancel := newRPCContext()
+	defer cancel()
+
+	// The first stream isn't finished, so the second one exceeds the limit of concurrent streams
+	first, err := client.UploadFile(ctx)
+	if err != nil {
+		t.Fatalf("UploadFile() RPC failed: %v", err)
+	}
+	err = first.Send(&file_repository.FileContentRequest{
+		Data: &file_repository.FileContentRequest_Header{Header: &file_repository.FileContentHeader{
+			Bucket: testBucket,
+			Path:   "/rate-limited.txt",
+			Size:   1,
+		}},
+	})
+	if err != nil {
+		t.Fatalf("Failed to send header: %v", err)
+	}
+	time.Sleep(time.Millisecond * 50)
+
+	second, err := client.GetFileByPath(ctx, &file_repository.GetFileByPathRequest{Bucket: testBucket, Path: "/file.txt"})
+	if err != nil {
+		t.Fatalf("GetFileByPath() RPC failed: %v", err)
+	}
+	if _, err := second.Recv(); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("Stream exceeding concurrency limit must fail with ResourceExhausted, but got: %v", err)
+	}
+	first.CloseSend()
+	first.Recv()
+
+	// Two requests of the burst are already spent by the streams above
+	client.ListBuckets(ctx, &file_repository.ListBucketsRequest{})
+	if _, err = client.ListBuckets(ctx, &file_repository.ListBucketsRequest{}); status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("Request exceeding rate limit must fail with ResourceExhausted, but got: %v", err)
+	}
+	var retryInfo *errdetails.RetryInfo
+	for _, detail := range status.Convert(err).Details() {
+		if info, ok := detail.(*errdetails.RetryInfo); ok {
+			retryInfo = info
+		}
+	}
+	if retryInfo == nil || retryInfo.GetRetryDelay().AsDuration() <= 0 {
+		t.Errorf("Rate limited request must carry retry delay, but got: %v", status.Convert(err).Details())
+	}
+
+	if _, err := client.HealthCheck(ctx, &file_repository.HealthCheckRequest{}); err != nil {
+		t.Errorf("Health checks must not be rate limited: %v", err)
+	}
+}
+
+// Storage which becomes unreachable on demand
+type flakyStorage struct {
+	objectstorage.ObjectStorageDriver
+	down  atomic.Bool
+	pings atomic.Int32
+}
+
+func (s *flakyStorage) Ping(timeout time.Duration) error {
+	s.pings.Add(1)
+	if s.down.Load() {
+		return errors.New("storage is unreachable")
+	}
+	return s.ObjectStorageDriver.Ping(timeout)
+}
+
+func TestHealthRPC(t *testing.T) {
+	const testBucket string = "test-bucket"
+
+	if err := connectStorage(t, testBucket); err != nil {
+		t.Fatalf("Failed to connect to object storage: %v", err)
+	}
+	defer func() {
+		if err := objectstorage.Driver.Disconnect(); err != nil {
+			t.Fatalf("Failed to disconnect from object storage")
+		}
+	}()
+
+	defaultInterval := healthCheckInterval
+	healthCheckInterval = time.Millisecond * 10
+	defer func() { healthCheckInterval = defaultInterval }()
+
+	storage := &flakyStorage{ObjectStorageDriver: objectstorage.Driver}
+	server, err := NewServer(storage)
+	if err != nil {
+		t.Fatalf("Failed to create gRPC server: %v", err)
+	}
+	server.EnableReflection()
+	go server.Start(testPort)
+	time.Sleep(time.Millisecond * 20)
+	defer server.Stop()
+
+	conn, err := grpc.NewClient("localhost:"+strconv.Itoa(int(testPort)), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer conn.Close()
+	client := file_repository.NewFileRepositoryServiceClient(conn)
+	healthClient := healthpb.NewHealthClient(conn)
+
+	ctx, cancel := newRPCContext()
+	defer cancel()
+
+	expectStatus := func(expected healthpb.HealthCheckResponse_ServingStatus) {
+		t.Helper()
+		for _, service := range []string{"", healthServiceName} {
+			resp, err := healthClient.Check(ctx, &healthpb.HealthCheckRequest{Service: service})
+			if err != nil {
+				t.Fatalf("Check() RPC failed: %v", err)
+			}
+			if resp.GetStatus() != expected {
+				t.Errorf("Expected status of service \"%s\" to be %s, but got %s", service, expected, resp.GetStatus())
+			}
+		}
+		resp, err := client.HealthCheck(ctx, &file_repository.HealthCheckRequest{})
+		if err != nil {
+			t.Fatalf("HealthCheck() RPC failed: %v", err)
+		}
+		if resp.GetStatus() != expected.String() {
+			t.Errorf("Expected HealthCheck() status to be %s, but got %s", expected, resp.GetStatus())
+		}
+	}
+
+	expectStatus(healthpb.HealthCheckResponse_SERVING)
+
+	storage.down.Store(true)
+	time.Sleep(time.Millisecond * 50)
+	expectStatus(healthpb.HealthCheckResponse_NOT_SERVING)
+
+	storage.down.Store(false)
+	time.Sleep(time.Millisecond * 50)
+	expectStatus(healthpb.HealthCheckResponse_SERVING)
+
+	t.Run("Reflection", func(t *testing.T) {
+		stream, err := reflectionpb.NewServerReflectionClient(conn).ServerReflectionInfo(ctx)
+		if err != nil {
+			t.Fatalf("ServerReflectionInfo() RPC failed: %v", err)
+		}
+		err = stream.Send(&reflectionpb.ServerReflectionRequest{
+			MessageRequest: &reflectionpb.ServerReflectionRequest_ListServices{},
+		})
+		if err != nil {
+			t.Fatalf("Failed to send reflection request: %v", err)
+		}
+		resp, err := stream.Recv()
+		if err != nil {
+			t.Fatalf("Failed to receive reflection response: %v", err)
+		}
+		services := make(map[string]bool)
+		for _, service := range resp.GetListServicesResponse().GetService() {
+			services[service.GetName()] = true
+		}
+		if !services[healthServiceName] || !services[healthpb.Health_ServiceDesc.ServiceName] {
+			t.Errorf("Reflection must list all services, but got: %v", services)
+		}
+	})
+
+	t.Run("Stop", func(t *testing.T) {
+		if err := server.Stop(); err != nil {
+			t.Fatalf("Failed to stop server: %v", err)
+		}
+		pings := storage.pings.Load()
+		time.Sleep(time.Millisecond * 50)
+		if storage.pings.Load() != pings {
+			t.Errorf("Storage must not be pinged once server is stopped")
+		}
+	})
+}
+
+func TestGracefulShutdownRPC(t *testing.T) {
+	const testBucket string = "test-bucket"
+
+	if err := connectStorage(t, testBucket); err != nil {
+		t.Fatalf("Failed to connect to object storage: %v", err)
+	}
+	defer func() {
+		if err := objectstorage.Driver.Disconnect(); err != nil {
+			t.Fatalf("Failed to disconnect from object storage")
+		}
+	}()
+
+	// Starts upload, which can't be finished until the rest of content is sent
+	startUpload := func(t *testing.T, ctx context.Context, client file_repository.FileRepositoryServiceClient, path string) grpc.BidiStreamingClient[file_repository.FileContentRequest, file_repository.StatusResponse] {
+		stream, err := client.UploadFile(ctx)
+		if err != nil {
+			t.Fatalf("UploadFile() RPC failed: %v", err)
+		}
+		err = stream.Send(&file_repository.FileContentRequest{
+			Data: &file_repository.FileContentRequest_Header{Header: &file_repository.FileContentHeader{
+				Bucket: testBucket,
+				Path:   path,
+				Size:   int64(len("in-flight content")),
+			}},
+		})
+		if err != nil {
+			t.Fatalf("Failed to send header: %v", err)
+		}
+		err = stream.Send(&file_repository.FileContentRequest{
+			Data: &file_repository.FileContentRequest_Chunk{Chunk: []byte("in-flight")},
+		})
+		if err != nil {
+			t.Fatalf("Failed to send chunk: %v", err)
+		}
+		time.Sleep(time.Millisecond * 20)
+		return stream
+	}
+
+	t.Run("Drain", func(t *testing.T) {
+		server, err := NewServer(objectstorage.Driver)
+		if err != nil {
+			t.Fatalf("Failed to create gRPC server: %v", err)
+		}
+		go server.Start(testPort)
+		time.Sleep(time.Millisecond * 20)
+
+		client, closeClient := new_grpc_clinet()
+		defer closeClient()
+
+		ctx, cancel := newRPCContext()
+		defer cancel()
+
+		stream := startUpload(t, ctx, client, "/in-flight.txt")
+
+		shutdownErr := make(chan error, 1)
+		go func() {
+			shutdownErr <- server.Shutdown(time.Second * 5)
+		}()
+		time.Sleep(time.Millisecond * 50)
+
+		newClient, closeNewClient := new_grpc_clinet()
+		defer closeNewClient()
+		if _, err := newClient.HealthCheck(ctx, &file_repository.HealthCheckRequest{}); err == nil {
+			t.Errorf("New requests must be rejected during shutdown")
+		}
+
+		err = stream.Send(&file_repository.FileContentRequest{
+			Data: &file_repository.FileContentRequest_Chunk{Chunk: []byte(" content")},
+		})
+		if err != nil {
+			t.Fatalf("Failed to send chunk: %v", err)
+		}
+		if err := stream.CloseSend(); err != nil {
+			t.Fatalf("Failed to close stream: %v", err)
+		}
+		resp, err := stream.Recv()
+		if err != nil {
+			t.Fatalf("In-flight upload must be finished during shutdown, but got: %v", err)
+		}
+		if resp.GetStatus() != http.StatusOK {
+			t.Errorf("Expected upload status to be %d, but got %d", http.StatusOK, resp.GetStatus())
+		}
+
+		if err := <-shutdownErr; err != nil {
+			t.Errorf("Shutdown must be graceful, but got: %v", err)
+		}
+	})
+
+	t.Run("Timeout", func(t *testing.T) {
+		server, err := NewServer(objectstorage.Driver)
+		if err != nil {
+			t.Fatalf("Failed to create gRPC server: %v", err)
+		}
+		go server.Start(testPort)
+		time.Sleep(time.Millisecond * 20)
+
+		client, closeClient := new_grpc_clinet()
+		defer closeClient()
+
+		ctx, cancel := newRPCContext()
+		defer cancel()
+
+		stream := startUpload(t, ctx, client, "/abandoned.txt")
+
+		if err := server.Shutdown(time.Millisecond * 50); !errors.Is(err, ErrShutdownTimeout) {
+			t.Errorf("Expected ErrShutdownTimeout, but got: %v", err)
+		}
+		if _, err := stream.Recv(); err == nil {
+			t.Errorf("Unfinished upload must be cut off after shutdown timeout")
+		}
+	})
+}
+
 type fileStreamFunc = func (ctx context.Context, opts ...grpc.CallOption) (
 	grpc.BidiStreamingClient[file_repository.FileContentRequest, file_repository.StatusResponse],
 	error,
@@ -255,3 +1241,54 @@ func testFileStream(streamFunc fileStreamFunc, bucket string, filePath string, c
 
 	return nil
 }
+
+func TestStatusError(t *testing.T) {
+	bucketError := func(code string) error {
+		return miniocommon.BucketError(minio.ErrorResponse{Code: code, Message: code})
+	}
+
+	cases := []struct {
+		err    error
+		code   codes.Code
+		reason file_repository.ErrorReason
+	}{
+		{bucketError(minio.NoSuchBucket), codes.NotFound, file_repository.ErrorReason_ERROR_REASON_BUCKET_NOT_FOUND},
+		{bucketError(minio.BucketAlreadyOwnedByYou), codes.AlreadyExists, file_repository.ErrorReason_ERROR_REASON_BUCKET_ALREADY_EXISTS},
+		{bucketError(minio.BucketAlreadyExists), codes.AlreadyExists, file_repository.ErrorReason_ERROR_REASON_BUCKET_ALREADY_EXISTS},
+		{bucketError(minio.BucketNotEmpty), codes.FailedPrecondition, file_repository.ErrorReason_ERROR_REASON_BUCKET_NOT_EMPTY},
+		{bucketError(minio.InvalidBucketName), codes.InvalidArgument, file_repository.ErrorReason_ERROR_REASON_INVALID_BUCKET_NAME},
+		{miniocommon.ErrReservedBucket, codes.InvalidArgument, file_repository.ErrorReason_ERROR_REASON_INVALID_BUCKET_NAME},
+
+		{localcommon.ErrBucketAlreadyExists, codes.AlreadyExists, file_repository.ErrorReason_ERROR_REASON_BUCKET_ALREADY_EXISTS},
+		{localcommon.ErrBucketIsNotEmpty, codes.FailedPrecondition, file_repository.ErrorReason_ERROR_REASON_BUCKET_NOT_EMPTY},
+		{localcommon.ErrInvalidBucketName, codes.InvalidArgument, file_repository.ErrorReason_ERROR_REASON_INVALID_BUCKET_NAME},
+		{localcommon.ErrInvalidObjectPath, codes.InvalidArgument, file_repository.ErrorReason_ERROR_REASON_INVALID_PATH},
+		{localcommon.ErrPathConflict, codes.FailedPrecondition, file_repository.ErrorReason_ERROR_REASON_PATH_CONFLICT},
+
+		{fileapplication.ErrUploadOfDirectory, codes.InvalidArgument, file_repository.ErrorReason_ERROR_REASON_INVALID_PATH},
+		{fileapplication.ErrUpdateOfDirectory, codes.InvalidArgument, file_repository.ErrorReason_ERROR_REASON_INVALID_PATH},
+		{fmt.Errorf("%w, but got -1", fileapplication.ErrInvalidContentSize), codes.InvalidArgument, file_repository.ErrorReason_ERROR_REASON_INVALID_ARGUMENT},
+		{fileapplication.ErrNoUploadParts, codes.InvalidArgument, file_repository.ErrorReason_ERROR_REASON_INVALID_ARGUMENT},
+	}
+	for _, c := range cases {
+		st := status.Convert(statusError(c.err, "bucket", "/path"))
+		if st.Code() != c.code {
+			t.Errorf("%v: expected code %s, but got %s", c.err, c.code, st.Code())
+			continue
+		}
+		var info *errdetails.ErrorInfo
+		for _, detail := range st.Details() {
+			if d, ok := detail.(*errdetails.ErrorInfo); ok {
+				info = d
+			}
+		}
+		if info == nil || info.GetReason() != c.reason.String() {
+			t.Errorf("%v: expected reason %s, but got: %v", c.err, c.reason, info)
+		}
+	}
+
+	// Errors of the storage which aren't known are still reported as unknown ones
+	if code := status.Code(statusError(bucketError(minio.AccessDenied), "bucket", "")); code != codes.Unknown {
+		t.Errorf("Unknown storage error must be reported as such, but got %s", code)
+	}
+}
@@ -0,0 +1,54 @@
+package grpc
+
+import (
+	"context"
+	"log"
+	"time"
+	storageconnection "vega_file_repository/packages/infrastructure/object-storage/connection"
+
+	file_repository "github.com/abaxoth0/Vega/common/protobuf/generated/go/services/file-repository"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// How often availability of the storage is checked.
+// Variable instead of constant, so tests don't have to wait for too long.
+var healthCheckInterval = time.Second * 5
+
+const healthCheckTimeout = time.Second * 3
+
+// Name under which status of the file repository is reported by grpc.health.v1 service.
+// Overall status of the server (empty service name) is always the same.
+var healthServiceName = file_repository.FileRepositoryService_ServiceDesc.ServiceName
+
+// Server is serving only while its storage is reachable
+func (s *Server) storageStatus() healthpb.HealthCheckResponse_ServingStatus {
+	// Not connected drivers may have no client at all, so they can't be pinged
+	if s.storage.Status() != storageconnection.Connected {
+		return healthpb.HealthCheckResponse_NOT_SERVING
+	}
+	if err := s.storage.Ping(healthCheckTimeout); err != nil {
+		log.Printf("Storage health check failed: %v\n", err)
+		return healthpb.HealthCheckResponse_NOT_SERVING
+	}
+	return healthpb.HealthCheckResponse_SERVING
+}
+
+func (s *Server) setServingStatus(status healthpb.HealthCheckResponse_ServingStatus) {
+	s.health.SetServingStatus("", status)
+	s.health.SetServingStatus(healthServiceName, status)
+}
+
+// Updates status of the health server until ctx is done
+func (s *Server) watchHealth(ctx context.Context) {
+	ticker := time.NewTicker(healthCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.setServingStatus(s.storageStatus())
+		}
+	}
+}
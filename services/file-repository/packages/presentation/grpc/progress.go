@@ -0,0 +1,128 @@
+package grpc
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	file_repository "github.com/abaxoth0/Vega/common/protobuf/generated/go/services/file-repository"
+)
+
+// Progress messages aren't sent more often than this, whatever intervals are requested
+const (
+	minProgressInterval      = time.Millisecond * 100
+	minProgressIntervalBytes = 64 * 1024
+)
+
+type progressStream interface {
+	Send(*file_repository.StatusResponse) error
+}
+
+// Counts bytes of the upload content which were read by the storage
+// and periodically reports them to the client (see FileContentHeader.progress_interval_bytes).
+type uploadProgress struct {
+	content   io.Reader
+	total     int64
+	committed atomic.Int64
+
+	interval      time.Duration
+	intervalBytes int64
+	// Accessed only by the reader of the content
+	nextNotification int64
+
+	notify   chan struct{}
+	done     chan struct{}
+	finished chan struct{}
+}
+
+func newUploadProgress(header *file_repository.FileContentHeader, content io.Reader) (*uploadProgress, error) {
+	if header.GetProgressIntervalBytes() < 0 {
+		return nil, newRequestError("progress_interval_bytes", errors.New("progress interval can't be negative"))
+	}
+	if header.GetProgressIntervalMs() < 0 {
+		return nil, newRequestError("progress_interval_ms", errors.New("progress interval can't be negative"))
+	}
+
+	p := &uploadProgress{
+		content:  content,
+		total:    header.GetSize(),
+		notify:   make(chan struct{}, 1),
+		done:     make(chan struct{}),
+		finished: make(chan struct{}),
+	}
+	if header.GetProgressIntervalBytes() > 0 {
+		p.intervalBytes = max(header.GetProgressIntervalBytes(), minProgressIntervalBytes)
+		p.nextNotification = p.intervalBytes
+	}
+	if header.GetProgressIntervalMs() > 0 {
+		p.interval = max(time.Duration(header.GetProgressIntervalMs())*time.Millisecond, minProgressInterval)
+	}
+	return p, nil
+}
+
+func (p *uploadProgress) Read(b []byte) (int, error) {
+	n, err := p.content.Read(b)
+	committed := p.committed.Add(int64(n))
+
+	if p.intervalBytes > 0 && committed >= p.nextNotification {
+		p.nextNotification = committed - committed%p.intervalBytes + p.intervalBytes
+		// If previous notification isn't handled yet, then it will report this progress as well
+		select {
+		case p.notify <- struct{}{}:
+		default:
+		}
+	}
+
+	return n, err
+}
+
+func (p *uploadProgress) toProto() *file_repository.UploadProgress {
+	return &file_repository.UploadProgress{
+		CommittedBytes: p.committed.Load(),
+		TotalBytes:     p.total,
+	}
+}
+
+// Starts sending of progress messages (if they were requested)
+func (p *uploadProgress) start(stream progressStream) {
+	if p.interval == 0 && p.intervalBytes == 0 {
+		close(p.finished)
+		return
+	}
+
+	go func() {
+		defer close(p.finished)
+
+		var ticks <-chan time.Time
+		if p.interval > 0 {
+			ticker := time.NewTicker(p.interval)
+			defer ticker.Stop()
+			ticks = ticker.C
+		}
+
+		for {
+			select {
+			case <-p.done:
+				return
+			case <-p.notify:
+			case <-ticks:
+			}
+			err := stream.Send(&file_repository.StatusResponse{
+				Status:   http.StatusProcessing,
+				Progress: p.toProto(),
+			})
+			// Client is gone, storage will notice it via canceled context
+			if err != nil {
+				return
+			}
+		}
+	}()
+}
+
+// Must be called before the final response is sent, since messages can't be sent concurrently
+func (p *uploadProgress) stop() {
+	close(p.done)
+	<-p.finished
+}
@@ -1,26 +1,59 @@
 package grpc
 
 import (
+	"context"
+	"fmt"
 	"io"
 	"log"
+	"strings"
+	"time"
 	FileApplication "vega_file_repository/packages/application/file"
 
 	file_repository "github.com/abaxoth0/Vega/common/protobuf/generated/go/services/file-repository"
+	cqrs "github.com/abaxoth0/Vega/libs/go/packages/CQRS"
 	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 const downloadChunkSize int64 = 64 * 1024
 
+// Streamed files (and especially directory archives) may be really big,
+// so default command query timeout isn't enough for them.
+const downloadTimeout = time.Hour
+
+var archiveFormatMap = map[file_repository.ArchiveFormat]FileApplication.ArchiveFormat{
+	file_repository.ArchiveFormat_ARCHIVE_FORMAT_NONE:     FileApplication.ArchiveFormatNone,
+	file_repository.ArchiveFormat_ARCHIVE_FORMAT_TAR:      FileApplication.ArchiveFormatTar,
+	file_repository.ArchiveFormat_ARCHIVE_FORMAT_TAR_GZIP: FileApplication.ArchiveFormatTarGzip,
+	file_repository.ArchiveFormat_ARCHIVE_FORMAT_ZIP:      FileApplication.ArchiveFormatZip,
+}
+
 func (s *Server) GetFileByPath(
 	req *file_repository.GetFileByPathRequest,
 	stream grpc.ServerStreamingServer[file_repository.FileChunk],
 ) error {
+	archiveFormat, ok := archiveFormatMap[req.GetArchiveFormat()]
+	if !ok {
+		return statusError(
+			newRequestError("archive_format", fmt.Errorf("unknown archive format: %s", req.GetArchiveFormat().String())),
+			req.GetBucket(), req.GetPath(),
+		)
+	}
+
 	fileStream, err := s.storage.GetFileByPath(&FileApplication.GetFileByPathQuery{
-		Bucket: req.GetBucket(),
-		Path:   req.GetPath(),
+		Bucket:        req.GetBucket(),
+		Path:          req.GetPath(),
+		ArchiveFormat: archiveFormat,
+		Offset:        req.GetOffset(),
+		Length:        req.GetLength(),
+		VersionID:     req.GetVersionId(),
+		CommandQuery: cqrs.CommandQuery{
+			Context:        stream.Context(),
+			ContextTimeout: downloadTimeout,
+		},
 	})
 	if err != nil {
-		return err
+		return statusError(err, req.GetBucket(), req.GetPath())
 	}
 	defer fileStream.Cancel()
 
@@ -31,32 +64,49 @@ func (s *Server) GetFileByPath(
 
 	buf := make([]byte, chunkSize)
 	var chunkIndex int64
-	var totalChunks int64
 
-	if fileStream.Size == 0 {
-		totalChunks = 1
+	if fileStream.Size < 0 {
+		log.Printf(
+			"Sending \"%s\" as %s archive: chunk size %d\n",
+			req.GetPath(), archiveFormat.String(), chunkSize,
+		)
 	} else {
-		totalChunks = (fileStream.Size-1)/chunkSize + 1
-	}
+		length := fileStream.Range.Length()
+		var totalChunks int64
+		if length == 0 {
+			totalChunks = 1
+		} else {
+			totalChunks = (length-1)/chunkSize + 1
+		}
 
-	log.Printf(
-		"Sending file \"%s\": file size %d bytes; total chunks %d; chunk size %d\n",
-		req.GetPath(), fileStream.Size, totalChunks, chunkSize,
-	)
+		log.Printf(
+			"Sending file \"%s\": file size %d bytes; range %d-%d; total chunks %d; chunk size %d\n",
+			req.GetPath(), fileStream.Size, fileStream.Range.Start, fileStream.Range.End, totalChunks, chunkSize,
+		)
+	}
 
 	streaming := true
 	for streaming {
-		n, err := fileStream.Content.Read(buf)
-		if err == io.EOF {
+		// Archives are written into the stream by small pieces (e.g. tar headers),
+		// so to avoid sending lots of tiny chunks buffer must be filled up completely.
+		n, err := io.ReadFull(fileStream.Content, buf)
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
 			streaming = false
 		} else if err != nil {
-			return err
+			return statusError(err, req.GetBucket(), req.GetPath())
 		}
 
 		chunk := &file_repository.FileChunk{
 			Content:    buf[:n],
 			ChunkIndex: chunkIndex,
 			TotalSize:  fileStream.Size,
+			RangeStart: fileStream.Range.Start,
+			RangeEnd:   fileStream.Range.End,
+		}
+		// Client can verify received file only when it's received completely
+		if !streaming && fileStream.Checksum != nil {
+			chunk.Checksum = fileStream.Checksum.Value
+			chunk.ChecksumType = fileStream.Checksum.Algorithm
 		}
 		if err := stream.Send(chunk); err != nil {
 			return err
@@ -66,3 +116,103 @@ func (s *Server) GetFileByPath(
 
 	return nil
 }
+
+func (s *Server) ListDirectory(
+	ctx context.Context,
+	req *file_repository.ListDirectoryRequest,
+) (*file_repository.ListDirectoryResponse, error) {
+	listing, err := s.storage.ListDirectory(&FileApplication.ListDirectoryQuery{
+		Bucket:            req.GetBucket(),
+		Path:              req.GetPath(),
+		Recursive:         req.GetRecursive(),
+		Limit:             int(req.GetLimit()),
+		ContinuationToken: req.GetContinuationToken(),
+	})
+	if err != nil {
+		return nil, statusError(err, req.GetBucket(), req.GetPath())
+	}
+
+	entries := make([]*file_repository.DirectoryEntry, len(listing.Entries))
+	for i, entry := range listing.Entries {
+		entries[i] = &file_repository.DirectoryEntry{
+			Path:        entry.Path,
+			Size:        entry.Size,
+			StoredSize:  entry.StoredSize,
+			Etag:        entry.ETag,
+			IsDirectory: entry.IsDirectory,
+		}
+		if !entry.LastModified.IsZero() {
+			entries[i].LastModified = timestamppb.New(entry.LastModified)
+		}
+	}
+
+	return &file_repository.ListDirectoryResponse{
+		Entries:               entries,
+		NextContinuationToken: listing.NextContinuationToken,
+		IsTruncated:           listing.IsTruncated,
+	}, nil
+}
+
+func (s *Server) StatFile(
+	ctx context.Context,
+	req *file_repository.StatFileRequest,
+) (*file_repository.StatFileResponse, error) {
+	stats, err := s.storage.StatFile(&FileApplication.StatFileQuery{
+		Bucket:    req.GetBucket(),
+		Paths:     req.GetPaths(),
+		VersionID: req.GetVersionId(),
+	})
+	if err != nil {
+		return nil, statusError(err, req.GetBucket(), singlePath(req.GetPaths()))
+	}
+
+	files := make([]*file_repository.FileStat, len(stats))
+	for i, stat := range stats {
+		files[i] = &file_repository.FileStat{
+			Path:        stat.Path,
+			Exists:      stat.Exists,
+			Size:        stat.Size,
+			StoredSize:  stat.StoredSize,
+			Etag:        stat.ETag,
+			ContentType: stat.ContentType,
+			Metadata:    stat.Metadata,
+			VersionId:   stat.VersionID,
+			IsDirectory: stat.IsDirectory,
+		}
+		if !stat.LastModified.IsZero() {
+			files[i].LastModified = timestamppb.New(stat.LastModified)
+		}
+		if stat.Checksum != nil {
+			files[i].Checksum = stat.Checksum.Value
+			files[i].ChecksumType = stat.Checksum.Algorithm
+		}
+	}
+
+	return &file_repository.StatFileResponse{Files: files}, nil
+}
+
+func (s *Server) CreatePresignedURL(
+	ctx context.Context,
+	req *file_repository.CreatePresignedURLRequest,
+) (*file_repository.PresignedURL, error) {
+	presigned, err := s.storage.CreatePresignedURL(&FileApplication.CreatePresignedURLQuery{
+		Bucket:      req.GetBucket(),
+		Path:        req.GetPath(),
+		Method:      strings.ToUpper(req.GetMethod()),
+		Expiry:      time.Duration(req.GetExpiresIn()) * time.Second,
+		ContentType: req.GetContentType(),
+		Size:        req.GetSize(),
+		// Default timeout will be used
+		CommandQuery: cqrs.CommandQuery{Context: ctx},
+	})
+	if err != nil {
+		return nil, statusError(err, req.GetBucket(), req.GetPath())
+	}
+
+	return &file_repository.PresignedURL{
+		Url:       presigned.URL,
+		Method:    presigned.Method,
+		ExpiresAt: timestamppb.New(presigned.ExpiresAt),
+		Headers:   presigned.Headers,
+	}, nil
+}
@@ -0,0 +1,104 @@
+package grpc
+
+import (
+	"context"
+	"net"
+	"vega_file_repository/packages/infrastructure/auth"
+	"vega_file_repository/packages/infrastructure/ratelimit"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/protobuf/proto"
+)
+
+// Callers are identified by subject of their token, so limits follow them across addresses.
+// Unauthenticated callers are identified by IP address (without port, since each connection has its own).
+func callerOf(ctx context.Context) string {
+	if identity, ok := auth.IdentityFromContext(ctx); ok {
+		return identity.Subject
+	}
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
+
+// Must be run after authentication, otherwise all callers are identified by their address
+type rateLimiter struct {
+	limiter *ratelimit.Limiter
+}
+
+func (l *rateLimiter) unaryInterceptor(
+	ctx context.Context,
+	req any,
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (any, error) {
+	if publicMethods[info.FullMethod] {
+		return handler(ctx, req)
+	}
+	if err := l.limiter.AllowRequest(callerOf(ctx)); err != nil {
+		return nil, statusError(err, "", "")
+	}
+	return handler(ctx, req)
+}
+
+// Transfer of the stream is slowed down to the byte rate of the caller instead of being rejected
+type rateLimitedStream struct {
+	grpc.ServerStream
+	limiter *ratelimit.Limiter
+	caller  string
+}
+
+func (s *rateLimitedStream) RecvMsg(m any) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	if msg, ok := m.(proto.Message); ok {
+		if err := s.limiter.WaitBytes(s.Context(), s.caller, proto.Size(msg)); err != nil {
+			return statusError(err, "", "")
+		}
+	}
+	return nil
+}
+
+func (s *rateLimitedStream) SendMsg(m any) error {
+	if msg, ok := m.(proto.Message); ok {
+		if err := s.limiter.WaitBytes(s.Context(), s.caller, proto.Size(msg)); err != nil {
+			return statusError(err, "", "")
+		}
+	}
+	return s.ServerStream.SendMsg(m)
+}
+
+func (l *rateLimiter) streamInterceptor(
+	srv any,
+	stream grpc.ServerStream,
+	info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler,
+) error {
+	if publicMethods[info.FullMethod] {
+		return handler(srv, stream)
+	}
+
+	caller := callerOf(stream.Context())
+	if err := l.limiter.AllowRequest(caller); err != nil {
+		return statusError(err, "", "")
+	}
+	release, err := l.limiter.AcquireStream(caller)
+	if err != nil {
+		return statusError(err, "", "")
+	}
+	defer release()
+
+	return handler(srv, &rateLimitedStream{
+		ServerStream: stream,
+		limiter:      l.limiter,
+		caller:       caller,
+	})
+}
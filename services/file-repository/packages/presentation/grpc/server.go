@@ -2,23 +2,45 @@ package grpc
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
 	"log"
 	"net"
 	"strconv"
+	"sync"
 	"time"
+	"vega_file_repository/packages/infrastructure/auth"
 	objectstorage "vega_file_repository/packages/infrastructure/object-storage"
+	"vega_file_repository/packages/infrastructure/ratelimit"
 
 	file_repository "github.com/abaxoth0/Vega/common/protobuf/generated/go/services/file-repository"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
 )
 
-var ErrServerNotStarted = errors.New("Server is not started, hence can't be stopped.")
+var (
+	ErrServerNotStarted = errors.New("Server is not started, hence can't be stopped.")
+	ErrShutdownTimeout  = errors.New("in-flight requests weren't finished before shutdown timeout, so they were cut off")
+)
 
 type Server struct {
+	// Server is usually started in its own goroutine, so it may be stopped concurrently with start
+	mu        sync.Mutex
 	listening bool
 	server    *grpc.Server
 	storage   objectstorage.ObjectStorageDriver
+	// All are nil if corresponding feature is disabled
+	tlsConfig   *tls.Config
+	authorizer  *authorizer
+	rateLimiter *rateLimiter
+	reflection  bool
+
+	// Standard grpc.health.v1 service, its status reflects availability of the storage
+	health     *health.Server
+	stopHealth func()
 
 	file_repository.UnimplementedFileRepositoryServiceServer
 }
@@ -31,19 +53,105 @@ func NewServer(storage objectstorage.ObjectStorageDriver) (*Server, error) {
 	return &Server{storage: storage}, nil
 }
 
+// Requires all requests (except health checks) to be authenticated via JWT
+// and authorized according to the policy. Must be called before server is started.
+func (s *Server) EnableAuth(verifier *auth.Verifier, policy *auth.Policy) error {
+	if verifier == nil || policy == nil {
+		return errors.New("both verifier and policy are required")
+	}
+	s.authorizer = &authorizer{
+		verifier: verifier,
+		policy:   policy,
+	}
+	return nil
+}
+
+// Limits rate of requests, transfer rate and number of concurrent streams of each caller.
+// Must be called before server is started.
+func (s *Server) EnableRateLimits(limiter *ratelimit.Limiter) error {
+	if limiter == nil {
+		return errors.New("limiter is nil")
+	}
+	s.rateLimiter = &rateLimiter{limiter: limiter}
+	return nil
+}
+
+// Serves requests only over TLS (see tlsconfig.ServerConfig), which is mutual if config requires client certificates.
+// Must be called before server is started.
+func (s *Server) EnableTLS(config *tls.Config) error {
+	if config == nil {
+		return errors.New("TLS config is nil")
+	}
+	s.tlsConfig = config
+	return nil
+}
+
+// Registers server reflection service, so tools like grpcurl can be used without proto files.
+// Reflection doesn't require authentication. Must be called before server is started.
+func (s *Server) EnableReflection() {
+	s.reflection = true
+}
+
 func (s *Server) Start(port uint16) error {
 	listener, err := net.Listen("tcp", ":"+strconv.Itoa(int(port)))
 	if err != nil {
 		return err
 	}
 
-	s.server = grpc.NewServer()
-	file_repository.RegisterFileRepositoryServiceServer(s.server, s)
+	// Callers are limited by their identity, so authentication must go first
+	var options []grpc.ServerOption
+	if s.tlsConfig != nil {
+		options = append(options, grpc.Creds(credentials.NewTLS(s.tlsConfig)))
+	}
+	if s.authorizer != nil {
+		options = append(options,
+			grpc.ChainUnaryInterceptor(s.authorizer.unaryInterceptor),
+			grpc.ChainStreamInterceptor(s.authorizer.streamInterceptor),
+		)
+	}
+	if s.rateLimiter != nil {
+		options = append(options,
+			grpc.ChainUnaryInterceptor(s.rateLimiter.unaryInterceptor),
+			grpc.ChainStreamInterceptor(s.rateLimiter.streamInterceptor),
+		)
+	}
+
+	s.mu.Lock()
 
+	server := grpc.NewServer(options...)
+	file_repository.RegisterFileRepositoryServiceServer(server, s)
+
+	s.health = health.NewServer()
+	s.setServingStatus(s.storageStatus())
+	healthpb.RegisterHealthServer(server, s.health)
+	if s.reflection {
+		reflection.Register(server)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	watcherDone := make(chan struct{})
+	go func() {
+		defer close(watcherDone)
+		s.watchHealth(ctx)
+	}()
+	// Storage mustn't be pinged once server is stopped (e.g. while it's being reconnected),
+	// so watcher is not only cancelled, but also awaited.
+	stopHealth := func() {
+		cancel()
+		<-watcherDone
+	}
+	defer stopHealth()
+
+	s.server = server
+	s.stopHealth = stopHealth
 	s.listening = true
 
-	if err := s.server.Serve(listener); err != nil {
+	s.mu.Unlock()
+
+	if err := server.Serve(listener); err != nil {
+		s.mu.Lock()
 		s.listening = false
+		s.mu.Unlock()
 		return err
 	}
 
@@ -51,20 +159,64 @@ func (s *Server) Start(port uint16) error {
 }
 
 func (s *Server) Stop() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	if !s.listening {
 		return ErrServerNotStarted
 	}
+	s.listening = false
+	s.stopHealth()
 	s.server.Stop()
 	return nil
 }
 
+// Stops accepting new connections and requests, then waits until in-flight requests (e.g. uploads) are finished.
+// Requests which aren't finished within timeout are cut off (as by Stop) and ErrShutdownTimeout is returned.
+func (s *Server) Shutdown(timeout time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.listening {
+		return ErrServerNotStarted
+	}
+	s.listening = false
+
+	// Probes must see that server is going away before it stops accepting requests.
+	// Status isn't updated by health watcher after that.
+	s.health.Shutdown()
+	s.stopHealth()
+
+	done := make(chan struct{})
+	go func() {
+		s.server.GracefulStop()
+		close(done)
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case <-done:
+		return nil
+	case <-timer.C:
+		s.server.Stop()
+		<-done
+		return ErrShutdownTimeout
+	}
+}
+
 func (s *Server) HealthCheck(
 	ctx context.Context,
 	req *file_repository.HealthCheckRequest,
 ) (*file_repository.HealthCheckResponse, error) {
 	log.Printf("Health check called for service: %s", req.GetService())
+	health, err := s.health.Check(ctx, &healthpb.HealthCheckRequest{Service: healthServiceName})
+	if err != nil {
+		return nil, err
+	}
 	return &file_repository.HealthCheckResponse{
-		Status:    "SERVING",
+		Status:    health.GetStatus().String(),
 		Timestamp: time.Now().Format(time.RFC3339),
 	}, nil
 }
@@ -0,0 +1,131 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	fileapplication "vega_file_repository/packages/application/file"
+	"vega_file_repository/packages/domain/entity"
+
+	file_repository "github.com/abaxoth0/Vega/common/protobuf/generated/go/services/file-repository"
+	cqrs "github.com/abaxoth0/Vega/libs/go/packages/CQRS"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func uploadPartToProto(part *entity.UploadPart) *file_repository.UploadPart {
+	return &file_repository.UploadPart{
+		PartNumber:   int32(part.PartNumber),
+		Etag:         part.ETag,
+		Size:         part.Size,
+		LastModified: timestamppb.New(part.LastModified),
+	}
+}
+
+func (s *Server) InitiateUpload(
+	ctx context.Context,
+	req *file_repository.InitiateUploadRequest,
+) (*file_repository.InitiateUploadResponse, error) {
+	uploadID, err := s.storage.InitiateUpload(&fileapplication.InitiateUploadCommand{
+		Bucket: req.GetBucket(),
+		Path:   req.GetPath(),
+	})
+	if err != nil {
+		return nil, statusError(err, req.GetBucket(), req.GetPath())
+	}
+	return &file_repository.InitiateUploadResponse{
+		UploadId: uploadID,
+	}, nil
+}
+
+func (s *Server) UploadPart(
+	stream grpc.ClientStreamingServer[file_repository.UploadPartRequest, file_repository.UploadPartResponse],
+) error {
+	firstMsg, err := stream.Recv()
+	if err != nil {
+		return fmt.Errorf("failed to receive first message: %w", err)
+	}
+
+	header := firstMsg.GetHeader()
+	if header == nil {
+		return statusError(newRequestError("header", errors.New("first message must be a header")), "", "")
+	}
+
+	part, err := s.storage.UploadPart(&fileapplication.UploadPartCommand{
+		Bucket:     header.GetBucket(),
+		Path:       header.GetPath(),
+		UploadID:   header.GetUploadId(),
+		PartNumber: int(header.GetPartNumber()),
+		Size:       header.GetSize(),
+		Content:    pipeStreamChunks(firstMsg, stream.Recv),
+		CommandQuery: cqrs.CommandQuery{
+			Context:        stream.Context(),
+			ContextTimeout: uploadTimeout,
+		},
+	})
+	if err != nil {
+		return statusError(err, header.GetBucket(), header.GetPath())
+	}
+
+	return stream.SendAndClose(&file_repository.UploadPartResponse{
+		Part: uploadPartToProto(part),
+	})
+}
+
+func (s *Server) ListUploadedParts(
+	ctx context.Context,
+	req *file_repository.UploadSessionRequest,
+) (*file_repository.ListUploadedPartsResponse, error) {
+	parts, err := s.storage.ListUploadedParts(&fileapplication.ListUploadedPartsQuery{
+		Bucket:   req.GetBucket(),
+		Path:     req.GetPath(),
+		UploadID: req.GetUploadId(),
+	})
+	if err != nil {
+		return nil, statusError(err, req.GetBucket(), req.GetPath())
+	}
+
+	result := make([]*file_repository.UploadPart, len(parts))
+	for i := range parts {
+		result[i] = uploadPartToProto(&parts[i])
+	}
+
+	return &file_repository.ListUploadedPartsResponse{
+		Parts: result,
+	}, nil
+}
+
+func (s *Server) CompleteUpload(
+	ctx context.Context,
+	req *file_repository.UploadSessionRequest,
+) (*file_repository.StatusResponse, error) {
+	err := s.storage.CompleteUpload(&fileapplication.CompleteUploadCommand{
+		Bucket:   req.GetBucket(),
+		Path:     req.GetPath(),
+		UploadID: req.GetUploadId(),
+	})
+	if err != nil {
+		return nil, statusError(err, req.GetBucket(), req.GetPath())
+	}
+	return &file_repository.StatusResponse{
+		Status: http.StatusOK,
+	}, nil
+}
+
+func (s *Server) AbortUpload(
+	ctx context.Context,
+	req *file_repository.UploadSessionRequest,
+) (*file_repository.StatusResponse, error) {
+	err := s.storage.AbortUpload(&fileapplication.AbortUploadCommand{
+		Bucket:   req.GetBucket(),
+		Path:     req.GetPath(),
+		UploadID: req.GetUploadId(),
+	})
+	if err != nil {
+		return nil, statusError(err, req.GetBucket(), req.GetPath())
+	}
+	return &file_repository.StatusResponse{
+		Status: http.StatusOK,
+	}, nil
+}
@@ -0,0 +1,91 @@
+package grpc
+
+import (
+	"context"
+	"net/http"
+	"time"
+	fileapplication "vega_file_repository/packages/application/file"
+
+	file_repository "github.com/abaxoth0/Vega/common/protobuf/generated/go/services/file-repository"
+	cqrs "github.com/abaxoth0/Vega/libs/go/packages/CQRS"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Restored version is copied, so it may take a while for big files
+const restoreVersionTimeout = time.Hour
+
+func (s *Server) ListFileVersions(
+	ctx context.Context,
+	req *file_repository.ListFileVersionsRequest,
+) (*file_repository.ListFileVersionsResponse, error) {
+	versions, err := s.storage.ListFileVersions(&fileapplication.ListFileVersionsQuery{
+		Bucket: req.GetBucket(),
+		Path:   req.GetPath(),
+		// Default timeout will be used
+		CommandQuery: cqrs.CommandQuery{Context: ctx},
+	})
+	if err != nil {
+		return nil, statusError(err, req.GetBucket(), req.GetPath())
+	}
+
+	result := make([]*file_repository.FileVersion, len(versions))
+	for i, version := range versions {
+		result[i] = &file_repository.FileVersion{
+			VersionId:      version.VersionID,
+			Size:           version.Size,
+			LastModified:   timestamppb.New(version.LastModified),
+			Etag:           version.ETag,
+			IsLatest:       version.IsLatest,
+			IsDeleteMarker: version.IsDeleteMarker,
+		}
+	}
+
+	return &file_repository.ListFileVersionsResponse{
+		Versions: result,
+	}, nil
+}
+
+func (s *Server) RestoreFileVersion(
+	ctx context.Context,
+	req *file_repository.FileVersionRequest,
+) (*file_repository.RestoreFileVersionResponse, error) {
+	versionID, err := s.storage.RestoreFileVersion(&fileapplication.RestoreFileVersionCommand{
+		FileVersionTarget: fileapplication.FileVersionTarget{
+			Bucket:    req.GetBucket(),
+			Path:      req.GetPath(),
+			VersionID: req.GetVersionId(),
+		},
+		CommandQuery: cqrs.CommandQuery{
+			Context:        ctx,
+			ContextTimeout: restoreVersionTimeout,
+		},
+	})
+	if err != nil {
+		return nil, statusError(err, req.GetBucket(), req.GetPath())
+	}
+	return &file_repository.RestoreFileVersionResponse{
+		Status:    http.StatusOK,
+		VersionId: versionID,
+	}, nil
+}
+
+func (s *Server) DeleteFileVersion(
+	ctx context.Context,
+	req *file_repository.FileVersionRequest,
+) (*file_repository.StatusResponse, error) {
+	err := s.storage.DeleteFileVersion(&fileapplication.DeleteFileVersionCommand{
+		FileVersionTarget: fileapplication.FileVersionTarget{
+			Bucket:    req.GetBucket(),
+			Path:      req.GetPath(),
+			VersionID: req.GetVersionId(),
+		},
+		// Default timeout will be used
+		CommandQuery: cqrs.CommandQuery{Context: ctx},
+	})
+	if err != nil {
+		return nil, statusError(err, req.GetBucket(), req.GetPath())
+	}
+	return &file_repository.StatusResponse{
+		Status: http.StatusOK,
+	}, nil
+}
@@ -0,0 +1,45 @@
+package http
+
+import (
+	"net/http"
+	"strings"
+	"vega_file_repository/packages/infrastructure/auth"
+)
+
+// Access to the bucket required by the method of the files endpoint
+var methodAccess = map[string]auth.Access{
+	http.MethodGet:    auth.AccessRead,
+	http.MethodHead:   auth.AccessRead,
+	http.MethodPut:    auth.AccessWrite,
+	http.MethodDelete: auth.AccessWrite,
+}
+
+// The same as authentication of the gRPC server: bearer token in "Authorization" header
+// and grants of the policy. Handlers mounted via Handle (e.g. of presigned URLs) aren't affected.
+func (s *Server) authorize(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if s.verifier == nil {
+			next(w, r)
+			return
+		}
+
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeError(w, auth.ErrMissingToken)
+			return
+		}
+		identity, err := s.verifier.Verify(strings.TrimSpace(token))
+		if err != nil {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeError(w, err)
+			return
+		}
+		if !s.policy.Allows(identity.Subject, r.PathValue("bucket"), methodAccess[r.Method]) {
+			writeError(w, auth.ErrPermissionDenied)
+			return
+		}
+
+		next(w, r.WithContext(auth.WithIdentity(r.Context(), identity)))
+	}
+}
@@ -0,0 +1,126 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	FileApplication "vega_file_repository/packages/application/file"
+	"vega_file_repository/packages/domain/entity"
+)
+
+// Invalid Range headers are ignored, so the whole file is sent instead
+var errInvalidRangeHeader = errors.New("invalid range header")
+
+func quoteETag(etag string) string {
+	return "\"" + etag + "\""
+}
+
+// Comparison is weak (as required for If-None-Match), list may also be "*"
+func etagMatches(list string, etag string) bool {
+	if etag == "" {
+		return false
+	}
+	for _, candidate := range strings.Split(list, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" {
+			return true
+		}
+		if strings.Trim(strings.TrimPrefix(candidate, "W/"), "\"") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// If-None-Match takes precedence over If-Modified-Since, the same as in RFC 9110
+func isNotModified(r *http.Request, stat *entity.FileStat) bool {
+	if list := r.Header.Get("If-None-Match"); list != "" {
+		return etagMatches(list, stat.ETag)
+	}
+	if since := r.Header.Get("If-Modified-Since"); since != "" && !stat.LastModified.IsZero() {
+		t, err := http.ParseTime(since)
+		return err == nil && !stat.LastModified.Truncate(time.Second).After(t)
+	}
+	return false
+}
+
+// Range is applied only if file wasn't changed since validator in If-Range was received
+func isRangeApplicable(r *http.Request, stat *entity.FileStat) bool {
+	validator := r.Header.Get("If-Range")
+	if validator == "" {
+		return true
+	}
+	// Only strong ETags can be used in If-Range
+	if strings.HasPrefix(validator, "\"") {
+		return stat.ETag != "" && strings.Trim(validator, "\"") == stat.ETag
+	}
+	t, err := http.ParseTime(validator)
+	return err == nil && stat.LastModified.Truncate(time.Second).Equal(t)
+}
+
+// Converts Range header into offset and length (0 means till the end of the file) of GetFileByPathQuery.
+// Only single range is supported, multiple ranges are treated as invalid header.
+func parseRange(header string, size int64) (offset int64, length int64, err error) {
+	spec, ok := strings.CutPrefix(header, "bytes=")
+	if !ok || strings.Contains(spec, ",") {
+		return 0, 0, errInvalidRangeHeader
+	}
+	first, last, ok := strings.Cut(strings.TrimSpace(spec), "-")
+	if !ok {
+		return 0, 0, errInvalidRangeHeader
+	}
+
+	// Suffix range: last N bytes of the file
+	if first == "" {
+		n, err := strconv.ParseInt(last, 10, 64)
+		if err != nil || n < 0 {
+			return 0, 0, errInvalidRangeHeader
+		}
+		if n == 0 || size == 0 {
+			return 0, 0, FileApplication.ErrRangeNotSatisfiable
+		}
+		n = min(n, size)
+		return size - n, n, nil
+	}
+
+	start, err := strconv.ParseInt(first, 10, 64)
+	if err != nil || start < 0 {
+		return 0, 0, errInvalidRangeHeader
+	}
+	if start >= size {
+		return 0, 0, FileApplication.ErrRangeNotSatisfiable
+	}
+	if last == "" {
+		return start, 0, nil
+	}
+
+	end, err := strconv.ParseInt(last, 10, 64)
+	if err != nil || end < start {
+		return 0, 0, errInvalidRangeHeader
+	}
+	return start, min(end, size-1) - start + 1, nil
+}
+
+// Same as HTTP conditional requests, but checked atomically with the write (see FileApplication.WritePreconditions)
+func preconditionsFromHeaders(header http.Header) (FileApplication.WritePreconditions, error) {
+	preconditions := FileApplication.WritePreconditions{
+		IfMatch: header.Get("If-Match"),
+	}
+	switch header.Get("If-None-Match") {
+	case "":
+	case "*":
+		preconditions.IfNoneMatch = true
+	default:
+		return preconditions, errors.New("only \"*\" is supported as If-None-Match of the upload")
+	}
+	if since := header.Get("If-Unmodified-Since"); since != "" {
+		t, err := http.ParseTime(since)
+		if err != nil {
+			return preconditions, errors.New("invalid If-Unmodified-Since: " + err.Error())
+		}
+		preconditions.IfUnmodifiedSince = t
+	}
+	return preconditions, nil
+}
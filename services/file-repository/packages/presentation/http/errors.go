@@ -0,0 +1,72 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	FileApplication "vega_file_repository/packages/application/file"
+	"vega_file_repository/packages/infrastructure/auth"
+
+	errs "github.com/abaxoth0/Vega/libs/go/packages/erorrs"
+	"github.com/abaxoth0/Vega/libs/go/packages/file"
+)
+
+// Errors which aren't errs.Status, all other errors are reported as internal ones
+var errorStatuses = []struct {
+	err    error
+	status int
+}{
+	{FileApplication.ErrFileDoesNotExist, http.StatusNotFound},
+	{FileApplication.ErrBucketDoesNotExist, http.StatusNotFound},
+	{FileApplication.ErrUploadDoesNotExist, http.StatusNotFound},
+	{FileApplication.ErrPreconditionFailed, http.StatusPreconditionFailed},
+	{FileApplication.ErrRangeNotSatisfiable, http.StatusRequestedRangeNotSatisfiable},
+	{FileApplication.ErrDestinationExists, http.StatusConflict},
+	{FileApplication.ErrPathConflict, http.StatusConflict},
+	{FileApplication.ErrQuotaExceeded, http.StatusInsufficientStorage},
+	{FileApplication.ErrPreconditionsNotSupported, http.StatusNotImplemented},
+	{FileApplication.ErrChecksumMismatch, http.StatusBadRequest},
+	{FileApplication.ErrInvalidChecksum, http.StatusBadRequest},
+	{FileApplication.ErrUnsupportedChecksumAlgorithm, http.StatusBadRequest},
+	{FileApplication.ErrConflictingPreconditions, http.StatusBadRequest},
+	{FileApplication.ErrInvalidRange, http.StatusBadRequest},
+	{FileApplication.ErrVersionOfDirectory, http.StatusBadRequest},
+	{file.ErrFileIsNotDirectory, http.StatusBadRequest},
+	{FileApplication.ErrInvalidPath, http.StatusBadRequest},
+	{FileApplication.ErrUploadOfDirectory, http.StatusBadRequest},
+	{FileApplication.ErrUpdateOfDirectory, http.StatusBadRequest},
+	{FileApplication.ErrInvalidContentSize, http.StatusBadRequest},
+	{FileApplication.ErrInvalidBucketName, http.StatusBadRequest},
+	{FileApplication.ErrReservedBucket, http.StatusBadRequest},
+	{file.ErrEmptyPath, http.StatusBadRequest},
+	{file.ErrInvalidPathFormat, http.StatusBadRequest},
+	{file.ErrMaxPathLengthExceeded, http.StatusBadRequest},
+	{file.ErrMaxPathSegmentLengthExceeded, http.StatusBadRequest},
+	{context.DeadlineExceeded, http.StatusGatewayTimeout},
+	{auth.ErrUnauthenticated, http.StatusUnauthorized},
+	{auth.ErrPermissionDenied, http.StatusForbidden},
+}
+
+func statusOf(err error) int {
+	var statusErr *errs.Status
+	if errors.As(err, &statusErr) {
+		return statusErr.Status()
+	}
+	for _, known := range errorStatuses {
+		if errors.Is(err, known.err) {
+			return known.status
+		}
+	}
+	return http.StatusInternalServerError
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	http.Error(w, err.Error(), statusOf(err))
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
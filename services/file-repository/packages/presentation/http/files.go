@@ -0,0 +1,316 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"time"
+	FileApplication "vega_file_repository/packages/application/file"
+	"vega_file_repository/packages/domain/entity"
+
+	cqrs "github.com/abaxoth0/Vega/libs/go/packages/CQRS"
+	errs "github.com/abaxoth0/Vega/libs/go/packages/erorrs"
+	"github.com/abaxoth0/Vega/libs/go/packages/file"
+)
+
+// The same as timeouts of gRPC transfers
+const (
+	downloadTimeout = time.Hour
+	uploadTimeout   = time.Hour
+)
+
+// Hex encoded SHA-256 digest of the content. If sent with upload, then content is stored
+// only if its digest matches. Sent with download if digest is known and the whole file is requested.
+const checksumHeader = "X-Checksum-Sha256"
+
+func filePath(r *http.Request) string {
+	return "/" + r.PathValue("path")
+}
+
+func setChecksumHeader(w http.ResponseWriter, checksum *entity.Checksum) {
+	if checksum != nil && checksum.Algorithm == FileApplication.ChecksumSHA256 {
+		w.Header().Set(checksumHeader, checksum.Value)
+	}
+}
+
+// Directories are listed as JSON (see listDirectory)
+func (s *Server) getFile(w http.ResponseWriter, r *http.Request) {
+	bucket, path := r.PathValue("bucket"), filePath(r)
+	if file.IsDirectory(path) {
+		s.listDirectory(w, r, bucket, path)
+		return
+	}
+	versionID := r.URL.Query().Get("version_id")
+
+	// Stat is required anyway to resolve conditional and ranged requests
+	stats, err := s.storage.StatFile(&FileApplication.StatFileQuery{
+		Bucket:       bucket,
+		Paths:        []string{path},
+		VersionID:    versionID,
+		CommandQuery: cqrs.CommandQuery{Context: r.Context()},
+	})
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	stat := &stats[0]
+	if !stat.Exists {
+		writeError(w, errs.StatusNotFound)
+		return
+	}
+
+	header := w.Header()
+	if stat.ETag != "" {
+		header.Set("ETag", quoteETag(stat.ETag))
+	}
+	if !stat.LastModified.IsZero() {
+		header.Set("Last-Modified", stat.LastModified.UTC().Format(http.TimeFormat))
+	}
+	header.Set("Accept-Ranges", "bytes")
+	if isNotModified(r, stat) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	contentType := stat.ContentType
+	if contentType == "" {
+		contentType = mime.TypeByExtension(filepath.Ext(path))
+	}
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	header.Set("Content-Type", contentType)
+
+	if r.Method == http.MethodHead {
+		header.Set("Content-Length", strconv.FormatInt(stat.Size, 10))
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	query := &FileApplication.GetFileByPathQuery{
+		Bucket:    bucket,
+		Path:      path,
+		VersionID: versionID,
+		CommandQuery: cqrs.CommandQuery{
+			Context:        r.Context(),
+			ContextTimeout: downloadTimeout,
+		},
+	}
+	ranged := false
+	if rangeHeader := r.Header.Get("Range"); rangeHeader != "" && isRangeApplicable(r, stat) {
+		offset, length, err := parseRange(rangeHeader, stat.Size)
+		switch {
+		case errors.Is(err, FileApplication.ErrRangeNotSatisfiable):
+			header.Set("Content-Range", fmt.Sprintf("bytes */%d", stat.Size))
+			writeError(w, err)
+			return
+		case err == nil:
+			query.Offset, query.Length = offset, length
+			ranged = true
+		}
+	}
+
+	stream, err := s.storage.GetFileByPath(query)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	defer stream.Cancel()
+
+	if ranged {
+		header.Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", stream.Range.Start, stream.Range.End, stream.Size))
+		header.Set("Content-Length", strconv.FormatInt(stream.Range.Length(), 10))
+		w.WriteHeader(http.StatusPartialContent)
+	} else {
+		setChecksumHeader(w, stream.Checksum)
+		header.Set("Content-Length", strconv.FormatInt(stream.Size, 10))
+		w.WriteHeader(http.StatusOK)
+	}
+
+	// Response is already started, so failure can be reported only by breaking it
+	io.Copy(w, stream.Content)
+}
+
+type fileInfoJSON struct {
+	Path         string     `json:"path"`
+	Size         int64      `json:"size"`
+	StoredSize   int64      `json:"stored_size"`
+	LastModified *time.Time `json:"last_modified,omitempty"`
+	ETag         string     `json:"etag,omitempty"`
+	IsDirectory  bool       `json:"is_directory"`
+}
+
+type directoryListingJSON struct {
+	Entries               []fileInfoJSON `json:"entries"`
+	NextContinuationToken string         `json:"next_continuation_token,omitempty"`
+	IsTruncated           bool           `json:"is_truncated"`
+}
+
+// Supports the same options as ListDirectoryQuery via "recursive", "limit" and "continuation_token" parameters
+func (s *Server) listDirectory(w http.ResponseWriter, r *http.Request, bucket string, path string) {
+	params := r.URL.Query()
+	query := &FileApplication.ListDirectoryQuery{
+		Bucket:            bucket,
+		Path:              path,
+		ContinuationToken: params.Get("continuation_token"),
+		CommandQuery:      cqrs.CommandQuery{Context: r.Context()},
+	}
+	if recursive := params.Get("recursive"); recursive != "" {
+		parsed, err := strconv.ParseBool(recursive)
+		if err != nil {
+			http.Error(w, "invalid recursive parameter: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		query.Recursive = parsed
+	}
+	if limit := params.Get("limit"); limit != "" {
+		parsed, err := strconv.Atoi(limit)
+		if err != nil {
+			http.Error(w, "invalid limit parameter: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		query.Limit = parsed
+	}
+
+	listing, err := s.storage.ListDirectory(query)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	body := directoryListingJSON{
+		Entries:               make([]fileInfoJSON, len(listing.Entries)),
+		NextContinuationToken: listing.NextContinuationToken,
+		IsTruncated:           listing.IsTruncated,
+	}
+	for i, entry := range listing.Entries {
+		body.Entries[i] = fileInfoJSON{
+			Path:        entry.Path,
+			Size:        entry.Size,
+			StoredSize:  entry.StoredSize,
+			ETag:        entry.ETag,
+			IsDirectory: entry.IsDirectory,
+		}
+		if !entry.LastModified.IsZero() {
+			body.Entries[i].LastModified = &entry.LastModified
+		}
+	}
+
+	writeJSON(w, http.StatusOK, body)
+}
+
+// Body is streamed into storage as is, so its size must be known beforehand.
+// PUT of the directory creates it (see Mkdir).
+func (s *Server) putFile(w http.ResponseWriter, r *http.Request) {
+	bucket, path := r.PathValue("bucket"), filePath(r)
+	if file.IsDirectory(path) {
+		err := s.storage.Mkdir(&FileApplication.MkdirCommand{
+			Bucket:       bucket,
+			Path:         path,
+			CommandQuery: cqrs.CommandQuery{Context: r.Context()},
+		})
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		return
+	}
+
+	if r.ContentLength < 0 {
+		http.Error(w, "Content-Length is required", http.StatusLengthRequired)
+		return
+	}
+	if r.ContentLength == 0 {
+		http.Error(w, "empty content can't be uploaded", http.StatusBadRequest)
+		return
+	}
+	preconditions, err := preconditionsFromHeaders(r.Header)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var checksum *entity.Checksum
+	if digest := r.Header.Get(checksumHeader); digest != "" {
+		checksum = &entity.Checksum{
+			Algorithm: FileApplication.ChecksumSHA256,
+			Value:     digest,
+		}
+	}
+
+	result, err := s.storage.UploadFile(&FileApplication.UploadFileCommand{
+		Bucket:        bucket,
+		Path:          path,
+		Content:       r.Body,
+		ContentSize:   r.ContentLength,
+		Checksum:      checksum,
+		Preconditions: preconditions,
+		CommandQuery: cqrs.CommandQuery{
+			Context:        r.Context(),
+			ContextTimeout: uploadTimeout,
+		},
+	})
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	if result.ETag != "" {
+		w.Header().Set("ETag", quoteETag(result.ETag))
+	}
+	setChecksumHeader(w, result.Checksum)
+	w.WriteHeader(http.StatusOK)
+}
+
+type deletionFailureJSON struct {
+	Path   string `json:"path"`
+	Reason string `json:"reason"`
+}
+
+type deletionReportJSON struct {
+	Deleted  int64                 `json:"deleted"`
+	Failures []deletionFailureJSON `json:"failures"`
+}
+
+// Directories are deleted with their subtrees only if "recursive" parameter is true.
+// If some of the objects weren't deleted, then report is sent with 207 status.
+func (s *Server) deleteFile(w http.ResponseWriter, r *http.Request) {
+	recursive := false
+	if param := r.URL.Query().Get("recursive"); param != "" {
+		parsed, err := strconv.ParseBool(param)
+		if err != nil {
+			http.Error(w, "invalid recursive parameter: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		recursive = parsed
+	}
+
+	report, err := s.storage.DeleteFiles(&FileApplication.DeleteFilesCommand{
+		Bucket:       r.PathValue("bucket"),
+		Paths:        []string{filePath(r)},
+		Recursive:    recursive,
+		CommandQuery: cqrs.CommandQuery{Context: r.Context()},
+	})
+	if err != nil && !errors.Is(err, FileApplication.ErrPartialDeletion) {
+		writeError(w, err)
+		return
+	}
+
+	body := deletionReportJSON{
+		Deleted:  report.DeletedCount(),
+		Failures: []deletionFailureJSON{},
+	}
+	for _, failure := range report.Failures() {
+		body.Failures = append(body.Failures, deletionFailureJSON{Path: failure.Path, Reason: failure.Reason})
+	}
+
+	status := http.StatusOK
+	if err != nil {
+		status = http.StatusMultiStatus
+	}
+	writeJSON(w, status, body)
+}
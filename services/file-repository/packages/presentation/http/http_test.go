@@ -0,0 +1,276 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	FileApplication "vega_file_repository/packages/application/file"
+	"vega_file_repository/packages/infrastructure/auth"
+	"vega_file_repository/packages/infrastructure/auth/authtest"
+	StorageConnection "vega_file_repository/packages/infrastructure/object-storage/connection"
+	"vega_file_repository/packages/infrastructure/object-storage/local"
+)
+
+const testBucket = "http-test"
+
+const testJWTKey = "0123456789abcdef0123456789abcdef"
+
+func TestHTTPServer(t *testing.T) {
+	storage := local.InitDriver()
+	if err := storage.Connect(&StorageConnection.Config{URL: t.TempDir()}); err != nil {
+		t.Fatalf("Connection failed: %v", err)
+	}
+	defer storage.Disconnect()
+
+	if err := storage.MakeBucket(&FileApplication.MakeBucketCommand{Name: testBucket}); err != nil {
+		t.Fatalf("Failed to create bucket: %v", err)
+	}
+
+	gateway, err := NewServer(storage)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+	server := httptest.NewServer(gateway.Handler())
+	defer server.Close()
+
+	send := func(t *testing.T, method string, path string, body []byte, headers map[string]string) (*http.Response, []byte) {
+		t.Helper()
+		var reader io.Reader
+		if body != nil {
+			reader = bytes.NewReader(body)
+		}
+		req, err := http.NewRequest(method, server.URL+"/buckets/"+testBucket+"/files"+path, reader)
+		if err != nil {
+			t.Fatalf("Failed to create request: %v", err)
+		}
+		for name, value := range headers {
+			req.Header.Set(name, value)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("Request failed: %v", err)
+		}
+		defer resp.Body.Close()
+		content, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("Failed to read response: %v", err)
+		}
+		return resp, content
+	}
+
+	content := []byte(strings.Repeat("0123456789", 100))
+
+	var etag string
+
+	t.Run("PUT", func(t *testing.T) {
+		resp, _ := send(t, http.MethodPut, "/dir/file.txt", content, nil)
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("Upload failed: %s", resp.Status)
+		}
+		etag = resp.Header.Get("ETag")
+		if etag == "" {
+			t.Fatalf("ETag of the uploaded file must be returned")
+		}
+
+		resp, _ = send(t, http.MethodPut, "/dir/file.txt", content, map[string]string{"If-None-Match": "*"})
+		if resp.StatusCode != http.StatusPreconditionFailed {
+			t.Errorf("Overwrite with If-None-Match: * must fail with 412, but got %s", resp.Status)
+		}
+		resp, _ = send(t, http.MethodPut, "/dir/file.txt", content, map[string]string{"If-Match": "\"stale\""})
+		if resp.StatusCode != http.StatusPreconditionFailed {
+			t.Errorf("Overwrite with stale If-Match must fail with 412, but got %s", resp.Status)
+		}
+		resp, _ = send(t, http.MethodPut, "/dir/file.txt", content, map[string]string{"If-Match": etag})
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("Overwrite with current If-Match must succeed, but got %s", resp.Status)
+		}
+		etag = resp.Header.Get("ETag")
+
+		resp, _ = send(t, http.MethodPut, "/dir/sub/", nil, nil)
+		if resp.StatusCode != http.StatusCreated {
+			t.Errorf("Directory creation failed: %s", resp.Status)
+		}
+	})
+
+	t.Run("GET", func(t *testing.T) {
+		resp, body := send(t, http.MethodGet, "/dir/file.txt", nil, nil)
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("Download failed: %s", resp.Status)
+		}
+		if !bytes.Equal(body, content) {
+			t.Errorf("Downloaded content doesn't match uploaded one")
+		}
+		if resp.Header.Get("ETag") != etag {
+			t.Errorf("Expected ETag %s, but got %s", etag, resp.Header.Get("ETag"))
+		}
+
+		resp, body = send(t, http.MethodGet, "/dir/file.txt", nil, map[string]string{"If-None-Match": etag})
+		if resp.StatusCode != http.StatusNotModified || len(body) != 0 {
+			t.Errorf("Request with current If-None-Match must return 304, but got %s", resp.Status)
+		}
+
+		resp, _ = send(t, http.MethodGet, "/missing.txt", nil, nil)
+		if resp.StatusCode != http.StatusNotFound {
+			t.Errorf("Download of missing file must fail with 404, but got %s", resp.Status)
+		}
+	})
+
+	t.Run("GET with Range", func(t *testing.T) {
+		cases := []struct {
+			header   string
+			expected []byte
+			rangeHdr string
+		}{
+			{"bytes=10-19", content[10:20], "bytes 10-19/1000"},
+			{"bytes=990-", content[990:], "bytes 990-999/1000"},
+			{"bytes=-5", content[995:], "bytes 995-999/1000"},
+			{"bytes=995-5000", content[995:], "bytes 995-999/1000"},
+		}
+		for _, c := range cases {
+			resp, body := send(t, http.MethodGet, "/dir/file.txt", nil, map[string]string{"Range": c.header})
+			if resp.StatusCode != http.StatusPartialContent {
+				t.Errorf("Range %s: expected 206, but got %s", c.header, resp.Status)
+				continue
+			}
+			if !bytes.Equal(body, c.expected) {
+				t.Errorf("Range %s: expected %q, but got %q", c.header, c.expected, body)
+			}
+			if resp.Header.Get("Content-Range") != c.rangeHdr {
+				t.Errorf("Range %s: expected Content-Range %s, but got %s", c.header, c.rangeHdr, resp.Header.Get("Content-Range"))
+			}
+		}
+
+		resp, _ := send(t, http.MethodGet, "/dir/file.txt", nil, map[string]string{"Range": "bytes=1000-"})
+		if resp.StatusCode != http.StatusRequestedRangeNotSatisfiable {
+			t.Errorf("Range beyond the end of file must fail with 416, but got %s", resp.Status)
+		}
+		if resp.Header.Get("Content-Range") != "bytes */1000" {
+			t.Errorf("Unsatisfiable range must be reported with file size, but got %s", resp.Header.Get("Content-Range"))
+		}
+
+		resp, body := send(t, http.MethodGet, "/dir/file.txt", nil, map[string]string{"Range": "bytes=0-1,5-6"})
+		if resp.StatusCode != http.StatusOK || !bytes.Equal(body, content) {
+			t.Errorf("Unsupported range must be ignored, but got %s", resp.Status)
+		}
+
+		resp, body = send(t, http.MethodGet, "/dir/file.txt", nil, map[string]string{"Range": "bytes=0-9", "If-Range": "\"stale\""})
+		if resp.StatusCode != http.StatusOK || !bytes.Equal(body, content) {
+			t.Errorf("Range with stale If-Range must be ignored, but got %s", resp.Status)
+		}
+	})
+
+	t.Run("Directory listing", func(t *testing.T) {
+		resp, body := send(t, http.MethodGet, "/dir/", nil, nil)
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("Listing failed: %s", resp.Status)
+		}
+		var listing directoryListingJSON
+		if err := json.Unmarshal(body, &listing); err != nil {
+			t.Fatalf("Failed to decode listing: %v", err)
+		}
+		paths := map[string]bool{}
+		for _, entry := range listing.Entries {
+			paths[entry.Path] = entry.IsDirectory
+		}
+		if isDir, ok := paths["/dir/file.txt"]; !ok || isDir {
+			t.Errorf("Listing must contain file /dir/file.txt: %+v", listing.Entries)
+		}
+		if isDir, ok := paths["/dir/sub/"]; !ok || !isDir {
+			t.Errorf("Listing must contain directory /dir/sub/: %+v", listing.Entries)
+		}
+
+		resp, _ = send(t, http.MethodGet, "/dir/?limit=abc", nil, nil)
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("Invalid limit must fail with 400, but got %s", resp.Status)
+		}
+	})
+
+	t.Run("DELETE", func(t *testing.T) {
+		resp, body := send(t, http.MethodDelete, "/dir/file.txt", nil, nil)
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("Deletion failed: %s", resp.Status)
+		}
+		var report deletionReportJSON
+		if err := json.Unmarshal(body, &report); err != nil {
+			t.Fatalf("Failed to decode report: %v", err)
+		}
+		if report.Deleted != 1 || len(report.Failures) != 0 {
+			t.Errorf("Expected single deleted file, but got %+v", report)
+		}
+
+		resp, _ = send(t, http.MethodGet, "/dir/file.txt", nil, nil)
+		if resp.StatusCode != http.StatusNotFound {
+			t.Errorf("Deleted file must not be found, but got %s", resp.Status)
+		}
+	})
+}
+
+func TestHTTPAuth(t *testing.T) {
+	storage := local.InitDriver()
+	if err := storage.Connect(&StorageConnection.Config{URL: t.TempDir()}); err != nil {
+		t.Fatalf("Connection failed: %v", err)
+	}
+	defer storage.Disconnect()
+
+	if err := storage.MakeBucket(&FileApplication.MakeBucketCommand{Name: testBucket}); err != nil {
+		t.Fatalf("Failed to create bucket: %v", err)
+	}
+
+	verifier, err := auth.NewVerifier(testJWTKey)
+	if err != nil {
+		t.Fatalf("Failed to create verifier: %v", err)
+	}
+	policy, err := auth.ParsePolicy([]byte(`{"subjects": {"reader": {"` + testBucket + `": "read"}, "writer": {"*": "write"}}}`))
+	if err != nil {
+		t.Fatalf("Failed to parse policy: %v", err)
+	}
+
+	gateway, err := NewServer(storage)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+	if err := gateway.EnableAuth(verifier, policy); err != nil {
+		t.Fatalf("Failed to enable auth: %v", err)
+	}
+	server := httptest.NewServer(gateway.Handler())
+	defer server.Close()
+
+	send := func(method string, token string) int {
+		req, err := http.NewRequest(method, server.URL+"/buckets/"+testBucket+"/files/file.txt", strings.NewReader("content"))
+		if err != nil {
+			t.Fatalf("Failed to create request: %v", err)
+		}
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("Request failed: %v", err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	cases := []struct {
+		method   string
+		token    string
+		expected int
+	}{
+		{http.MethodGet, "", http.StatusUnauthorized},
+		{http.MethodGet, authtest.Issue(testJWTKey, "writer", -time.Hour), http.StatusUnauthorized},
+		{http.MethodPut, authtest.Issue(testJWTKey, "reader", time.Minute), http.StatusForbidden},
+		{http.MethodPut, authtest.Issue(testJWTKey, "writer", time.Minute), http.StatusOK},
+		{http.MethodGet, authtest.Issue(testJWTKey, "reader", time.Minute), http.StatusOK},
+		{http.MethodDelete, authtest.Issue(testJWTKey, "reader", time.Minute), http.StatusForbidden},
+	}
+	for _, c := range cases {
+		if status := send(c.method, c.token); status != c.expected {
+			t.Errorf("%s with token %q: expected %d, but got %d", c.method, c.token, c.expected, status)
+		}
+	}
+}
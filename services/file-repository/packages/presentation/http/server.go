@@ -0,0 +1,107 @@
+// HTTP/REST gateway for clients which can't use gRPC (browsers, scripts, CDNs).
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+	"vega_file_repository/packages/infrastructure/auth"
+	objectstorage "vega_file_repository/packages/infrastructure/object-storage"
+)
+
+var ErrServerNotStarted = errors.New("Server is not started, hence can't be stopped.")
+
+// Files are addressed as /buckets/{bucket}/files/{path}, paths with trailing '/' are directories.
+const filesPattern = "/buckets/{bucket}/files/{path...}"
+
+// Bodies are streamed, so only headers must be received in time
+const readHeaderTimeout = time.Second * 10
+
+type Server struct {
+	listening bool
+	server    *http.Server
+	mux       *http.ServeMux
+	storage   objectstorage.ObjectStorageDriver
+	// Both are nil if authentication is disabled
+	verifier *auth.Verifier
+	policy   *auth.Policy
+}
+
+func NewServer(storage objectstorage.ObjectStorageDriver) (*Server, error) {
+	if storage == nil {
+		return nil, errors.New("storage is nil")
+	}
+
+	s := &Server{
+		mux:     http.NewServeMux(),
+		storage: storage,
+	}
+	// GET patterns match HEAD requests as well
+	s.mux.HandleFunc("GET "+filesPattern, s.authorize(s.getFile))
+	s.mux.HandleFunc("PUT "+filesPattern, s.authorize(s.putFile))
+	s.mux.HandleFunc("DELETE "+filesPattern, s.authorize(s.deleteFile))
+
+	return s, nil
+}
+
+// Requires requests to files to be authenticated via JWT and authorized according to the policy
+func (s *Server) EnableAuth(verifier *auth.Verifier, policy *auth.Policy) error {
+	if verifier == nil || policy == nil {
+		return errors.New("both verifier and policy are required")
+	}
+	s.verifier = verifier
+	s.policy = policy
+	return nil
+}
+
+// Mounts additional handler (e.g. of presigned URLs), must be called before server is started
+func (s *Server) Handle(pattern string, handler http.Handler) {
+	s.mux.Handle(pattern, handler)
+}
+
+func (s *Server) Handler() http.Handler {
+	return s.mux
+}
+
+func (s *Server) Start(port uint16) error {
+	s.server = &http.Server{
+		Addr:              ":" + strconv.Itoa(int(port)),
+		Handler:           s.mux,
+		ReadHeaderTimeout: readHeaderTimeout,
+	}
+
+	s.listening = true
+
+	if err := s.server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		s.listening = false
+		return err
+	}
+
+	return nil
+}
+
+func (s *Server) Stop() error {
+	if !s.listening {
+		return ErrServerNotStarted
+	}
+	return s.server.Close()
+}
+
+// Stops accepting new connections and waits until in-flight requests are finished.
+// Requests which aren't finished within timeout are cut off (as by Stop).
+func (s *Server) Shutdown(timeout time.Duration) error {
+	if !s.listening {
+		return ErrServerNotStarted
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	if err := s.server.Shutdown(ctx); err != nil {
+		s.server.Close()
+		return err
+	}
+	return nil
+}